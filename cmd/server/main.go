@@ -6,13 +6,18 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/robbiew/retrograde/internal/auth"
+	"github.com/robbiew/retrograde/internal/chat"
 	"github.com/robbiew/retrograde/internal/config"
 	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/events"
 	"github.com/robbiew/retrograde/internal/logging"
 	"github.com/robbiew/retrograde/internal/menu"
+	"github.com/robbiew/retrograde/internal/notify"
+	"github.com/robbiew/retrograde/internal/qwk"
 	"github.com/robbiew/retrograde/internal/security"
 	"github.com/robbiew/retrograde/internal/telnet"
 	"github.com/robbiew/retrograde/internal/tui"
@@ -34,6 +39,24 @@ func main() {
 			// runGuidedSetup returns nil on cancellation, so we only show success message on actual success
 			// (success message is handled inside runGuidedSetup)
 			return
+		case "console", "-console", "--console":
+			if err := runConsole(); err != nil {
+				fmt.Printf("SysOp console failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "qwknet", "-qwknet", "--qwknet":
+			if err := runQWKNet(); err != nil {
+				fmt.Printf("QWKnet exchange failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "stats", "-stats", "--stats":
+			if err := runStats(os.Args[2:]); err != nil {
+				fmt.Printf("Statistics failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
 		}
 	}
 
@@ -185,6 +208,104 @@ func runConfigEditor() {
 	}
 }
 
+// runQWKNet performs a single QWKnet toss/scan cycle and exits. Intended to
+// be run from cron (or a timed event) after packets are exchanged.
+func runQWKNet() error {
+	cfg, err := config.LoadConfig("")
+	defer config.CloseDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	result, err := qwk.NewExchange(cfg, config.GetDatabase()).Run()
+	if result != nil {
+		details := fmt.Sprintf("mode=%s imported=%d exported=%d skipped=%d", cfg.Networking.QWKNet.Mode, result.Imported, result.Exported, result.Skipped)
+		logging.LogEvent(0, "SYSTEM", "", "QWKNET", details)
+		for _, packet := range result.Packets {
+			fmt.Printf("Processed %s\n", packet)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("QWKnet: %d imported, %d exported, %d skipped (no mapped area)\n", result.Imported, result.Exported, result.Skipped)
+	return nil
+}
+
+// runStats prints the daily system statistics for the sysop. An optional
+// argument sets how many days to report (default 14).
+func runStats(args []string) error {
+	days := 14
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of days %q", args[0])
+		}
+		days = n
+	}
+
+	_, err := config.LoadConfig("")
+	defer config.CloseDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	stats, err := config.GetDatabase().GetDailyStats(days)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-10s %7s %9s %7s %7s %9s %7s\n", "Date", "Calls", "New Users", "Posts", "Uploads", "Downloads", "Minutes")
+	var total database.DailyStats
+	for _, day := range stats {
+		fmt.Printf("%-10s %7d %9d %7d %7d %9d %7d\n", day.Date, day.Calls, day.NewUsers, day.Posts, day.Uploads, day.Downloads, day.Minutes)
+		total.Calls += day.Calls
+		total.NewUsers += day.NewUsers
+		total.Posts += day.Posts
+		total.Uploads += day.Uploads
+		total.Downloads += day.Downloads
+		total.Minutes += day.Minutes
+	}
+	fmt.Printf("%-10s %7d %9d %7d %7d %9d %7d\n", "Total", total.Calls, total.NewUsers, total.Posts, total.Uploads, total.Downloads, total.Minutes)
+	return nil
+}
+
+// runConsole attaches this terminal to the sysop console of a running server
+func runConsole() error {
+	cfg, err := config.LoadConfig("")
+	config.CloseDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	port := cfg.Configuration.SysOpChat.ConsolePort
+	if port <= 0 {
+		return fmt.Errorf("the sysop console is disabled (Configuration -> SysOp Chat -> Console Port)")
+	}
+	return chat.ConnectConsole(consoleAddr(port), os.Stdin, os.Stdout)
+}
+
+func consoleAddr(port int) string {
+	return fmt.Sprintf("127.0.0.1:%d", port)
+}
+
+// endStaleSessions closes node sessions left open by a server that did not
+// shut down cleanly, logging who was on at the time
+func endStaleSessions(db database.Database) {
+	stale, err := db.EndStaleBBSSessions(time.Now())
+	if err != nil {
+		fmt.Printf("Warning: could not clear stale node sessions: %v\n", err)
+		return
+	}
+	for _, s := range stale {
+		username := fmt.Sprintf("user #%d", s.UserID)
+		if user, err := db.GetUserByID(s.UserID); err == nil && user != nil {
+			username = user.Username
+		}
+		logging.LogEvent(s.NodeNumber, username, s.IPAddress.String, "DISCONNECT",
+			fmt.Sprintf("Session on node %d ended by server restart (last active %s)", s.NodeNumber, s.LastActivity))
+	}
+}
+
 func runConfigEditorFromServer(cfg *config.Config) error {
 	if err := tui.RunConfigEditorTUI(cfg); err != nil {
 		return fmt.Errorf("error running configuration editor: %w", err)
@@ -242,7 +363,12 @@ func runServer() {
 		}
 	}
 
+	logging.SetLogDirectory(cfg.Configuration.Paths.Logs)
 	logging.InitializeNodeManager(cfg.Servers.GeneralSettings.MaxNodes)
+	if db := config.GetDatabase(); db != nil {
+		endStaleSessions(db)
+		logging.GetNodeManager().SetStore(db)
+	}
 	security.InitializeSecurity(cfg)
 	go security.CleanupSecurityData()
 
@@ -263,6 +389,25 @@ func runServer() {
 	fmt.Printf("Server listening on port %d\n", cfg.Servers.Telnet.Port)
 	fmt.Printf("Connect with: telnet localhost %d\n", cfg.Servers.Telnet.Port)
 	fmt.Printf("Maximum nodes: %d\n", cfg.Servers.GeneralSettings.MaxNodes)
+
+	if port := cfg.Configuration.SysOpChat.ConsolePort; port > 0 {
+		console, err := chat.ServeConsole(consoleAddr(port), cfg.Configuration.General.SysOpName)
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			defer console.Close()
+			fmt.Printf("SysOp console on %s (run: retrograde console)\n", consoleAddr(port))
+		}
+	}
+	notify.Start()
+	defer notify.Stop()
+	var scheduler *events.Scheduler
+	if db := config.GetDatabase(); db != nil && cfg.Events.Enabled {
+		scheduler = events.NewScheduler(db)
+		scheduler.Start()
+		defer scheduler.Stop()
+		fmt.Println("Timed events enabled")
+	}
 	fmt.Println("Press Ctrl+C to stop the server")
 
 	for {
@@ -276,15 +421,27 @@ func runServer() {
 		allowed, reason := security.CheckConnectionSecurity(conn, cfg)
 
 		if !allowed {
-			fmt.Printf("Connection blocked from %s: %s\n", security.GetIPFromConn(conn), reason)
+			ip := security.GetIPFromConn(conn)
+			fmt.Printf("Connection blocked from %s: %s\n", ip, reason)
+			notify.SecurityBlock(cfg, ip, reason)
 			fmt.Fprintf(conn, "\r\nConnection temporarily unavailable.\r\nPlease try again later.\r\n")
 			conn.Close()
 			continue
 		}
 
+		if scheduler != nil {
+			if event, locked := scheduler.Lockout(); locked {
+				fmt.Fprintf(conn, "\r\nSystem event \"%s\" is about to run.\r\nPlease try again later.\r\n", event)
+				conn.Close()
+				continue
+			}
+		}
+
 		fmt.Fprintf(conn, "\r                    \r")
 
-		nodeID := logging.GetNodeManager().GetAvailableNode()
+		// Reserve the node here, before the next Accept, so concurrent
+		// connections can never be handed the same node
+		nodeID := logging.AssignNodeWithLogging(conn, "Guest")
 		if nodeID == -1 {
 			fmt.Fprintf(conn, "Sorry, all %d nodes are currently in use.\r\nPlease try again later.\r\n", cfg.Servers.GeneralSettings.MaxNodes)
 			conn.Close()
@@ -310,12 +467,6 @@ func handleConnection(conn net.Conn, cfg *config.Config, nodeID int) {
 		ipAddr = tcpAddr.IP.String()
 	}
 
-	// Assign node and log connection
-	actualNodeID := logging.AssignNodeWithLogging(conn, "Guest")
-	if actualNodeID != nodeID {
-		fmt.Printf("Warning: Node ID mismatch - expected %d, got %d\n", nodeID, actualNodeID)
-	}
-
 	// Create a session for this connection
 	session := &config.TelnetSession{
 		Alias:         "Guest",                   // Default for unauthenticated users
@@ -344,6 +495,10 @@ func handleConnection(conn net.Conn, cfg *config.Config, nodeID int) {
 	// NOW that security is cleared, send telnet options to enable character mode
 	negotiateTelnetOptions(writer)
 
+	// Work out the terminal's emulation and character set before anything is drawn
+	terminal := io.DetectTerminal(time.Second)
+	terminal.Apply(session)
+
 	// Bypass main menu and proceed directly to login for telnet connections
 	userRecord, err := auth.LoginPrompt(io, session, cfg)
 	if err != nil {
@@ -358,16 +513,25 @@ func handleConnection(conn net.Conn, cfg *config.Config, nodeID int) {
 	// Login successful - update session with user info
 	session.Alias = userRecord.Username
 	session.SecurityLevel = userRecord.SecurityLevel
+	if userRecord.LastLogin != nil {
+		session.PreviousLogin = *userRecord.LastLogin
+	}
+	if db := config.GetDatabase(); db != nil {
+		if level, err := db.GetSecurityLevelByLevel(userRecord.SecurityLevel); err == nil && level != nil && level.MinsPerDay > 0 {
+			session.TimeLeft = level.MinsPerDay
+		}
+	}
 	// Update node manager with new username
 	if nm := logging.GetNodeManager(); nm != nil && session.NodeNumber > 0 {
-		if conn, exists := nm.Connections[session.NodeNumber]; exists {
-			conn.Username = userRecord.Username
-		}
+		nm.SetUser(session.NodeNumber, userRecord.ID, userRecord.Username)
 	}
 
 	// Set default message area for the user
 	db := config.GetDatabase()
 	if db != nil {
+		applyTerminalPreferences(io, db, userRecord.ID, terminal.Encoding != "")
+		menu.ApplySessionPreferences(db, userRecord.ID, session)
+
 		if err := session.SetDefaultMessageArea(db); err != nil {
 			// Log error but don't fail login
 			fmt.Printf("Warning: could not set default message area: %v\n", err)
@@ -377,6 +541,7 @@ func handleConnection(conn net.Conn, cfg *config.Config, nodeID int) {
 	// Load and execute start menu
 	if db != nil {
 		executor := menu.NewMenuExecutor(db, io)
+		defer executor.Close()
 		ctx := &menu.ExecutionContext{
 			UserID:   userRecord.ID,
 			Username: userRecord.Username,
@@ -387,9 +552,39 @@ func handleConnection(conn net.Conn, cfg *config.Config, nodeID int) {
 		if startMenu == "" {
 			startMenu = "MAIN"
 		}
-		if err := executor.ExecuteMenu(startMenu, ctx); err != nil {
-			io.Printf("Menu error: %v\r\n", err)
+		executor.RecordLogon(ctx)
+		if executor.RunSequence(ctx, database.SequenceLogon) != menu.ResultHangup {
+			if err := executor.ExecuteMenu(startMenu, ctx); err != nil {
+				io.Printf("Menu error: %v\r\n", err)
+			}
 		}
+		// Carrier loss, a timeout or a menu error ends up here without a
+		// logoff; one made by a command is not repeated
+		executor.Logoff(ctx, menu.LogoffCarrierLost, "")
+	}
+}
+
+// applyTerminalPreferences applies the caller's saved terminal overrides.
+// ANSI callers whose terminal didn't reveal its character set and who have
+// no saved choice are asked once.
+func applyTerminalPreferences(io *telnet.TelnetIO, db database.Database, userID int64, detected bool) {
+	details, err := db.GetUserDetails(userID)
+	if err != nil {
+		fmt.Printf("Warning: could not load user details: %v\n", err)
+		return
+	}
+	telnet.ApplyPreferences(io.Session, details)
+	if _, saved := details[config.EncodingPreference]; saved || detected || io.Session.Emulation == ui.EmulationASCII {
+		return
+	}
+
+	encoding, err := io.ChooseEncoding()
+	if err != nil {
+		return
+	}
+	io.Session.Encoding = encoding
+	if err := db.UpsertUserDetail(userID, config.EncodingPreference, encoding); err != nil {
+		fmt.Printf("Warning: could not save encoding preference: %v\n", err)
 	}
 }
 
@@ -405,6 +600,7 @@ func negotiateTelnetOptions(writer *bufio.Writer) {
 		SUPPRESS_GO_AHEAD = 3   // Suppress Go Ahead option
 		LINEMODE          = 34  // Line mode option
 		NAWS              = 31  // Negotiate About Window Size option
+		TTYPE             = 24  // Terminal Type option
 	)
 
 	// Send telnet negotiations to enable character mode
@@ -434,6 +630,11 @@ func negotiateTelnetOptions(writer *bufio.Writer) {
 	writer.WriteByte(DO)
 	writer.WriteByte(NAWS)
 
+	// Ask client to DO TTYPE (report its terminal type)
+	writer.WriteByte(IAC)
+	writer.WriteByte(DO)
+	writer.WriteByte(TTYPE)
+
 	writer.Flush()
 
 	// Give client time to process negotiations
@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"crypto/pbkdf2"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -12,7 +15,10 @@ import (
 	"time"
 
 	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
 	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/mci"
+	"github.com/robbiew/retrograde/internal/notify"
 	"github.com/robbiew/retrograde/internal/security"
 	"github.com/robbiew/retrograde/internal/telnet"
 	"github.com/robbiew/retrograde/internal/ui"
@@ -128,7 +134,7 @@ func AuthenticateUser(username, password string) (*UserRecord, error) {
 	}
 
 	// Verify password
-	if !VerifyPassword(password, userRecord.Password.Hash) {
+	if !CheckPassword(password, userRecord.Password) {
 		return nil, fmt.Errorf("invalid password")
 	}
 
@@ -155,6 +161,66 @@ func VerifyPassword(password, hash string) bool {
 	return HashPassword(password) == hash
 }
 
+// Password hashing algorithms for Configuration.Auth.PasswordAlgorithm
+const (
+	PasswordAlgoSHA256 = "sha256"        // HashPassword's salted SHA-256
+	PasswordAlgoPBKDF2 = "pbkdf2-sha256" // PBKDF2 with a random salt per password
+)
+
+// pbkdf2Iterations is the PBKDF2 work factor for new passwords
+const pbkdf2Iterations = 100000
+
+// SupportedPasswordAlgorithm reports whether algo can hash new passwords
+func SupportedPasswordAlgorithm(algo string) bool {
+	return algo == PasswordAlgoSHA256 || algo == PasswordAlgoPBKDF2
+}
+
+// NewPasswordDigest hashes password with the given algorithm. An empty
+// algorithm means sha256.
+func NewPasswordDigest(password, algo string) (PasswordDigest, error) {
+	digest := PasswordDigest{Algorithm: algo, UpdatedAt: time.Now().UTC()}
+	switch algo {
+	case "", PasswordAlgoSHA256:
+		digest.Algorithm = PasswordAlgoSHA256
+		digest.Hash = HashPassword(password)
+		digest.Salt = "retrograde_salt_2025"
+	case PasswordAlgoPBKDF2:
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return PasswordDigest{}, fmt.Errorf("could not generate salt: %w", err)
+		}
+		key, err := pbkdf2.Key(sha256.New, password, salt, pbkdf2Iterations, 32)
+		if err != nil {
+			return PasswordDigest{}, fmt.Errorf("could not hash password: %w", err)
+		}
+		digest.Hash = hex.EncodeToString(key)
+		digest.Salt = hex.EncodeToString(salt)
+	default:
+		return PasswordDigest{}, fmt.Errorf("unsupported password algorithm %q", algo)
+	}
+	return digest, nil
+}
+
+// CheckPassword verifies a password against a stored digest of any supported
+// algorithm. Digests without an algorithm are sha256.
+func CheckPassword(password string, digest PasswordDigest) bool {
+	switch digest.Algorithm {
+	case "", PasswordAlgoSHA256:
+		return VerifyPassword(password, digest.Hash)
+	case PasswordAlgoPBKDF2:
+		salt, err := hex.DecodeString(digest.Salt)
+		if err != nil {
+			return false
+		}
+		key, err := pbkdf2.Key(sha256.New, password, salt, pbkdf2Iterations, 32)
+		if err != nil {
+			return false
+		}
+		return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(key)), []byte(digest.Hash)) == 1
+	}
+	return false
+}
+
 // GetUser loads a user by username
 func GetUser(username string) (*UserRecord, error) {
 	userRecord, err := getStorage().GetUserByUsername(username)
@@ -185,7 +251,7 @@ func LoginPrompt(io *telnet.TelnetIO, session *config.TelnetSession, cfg *config
 	io.ClearScreen()
 
 	// Display login art
-	if err := ui.PrintAnsiArt(io, "login"); err != nil {
+	if err := mci.PrintArt(io, "login", &mci.Context{Session: session, Config: cfg}); err != nil {
 		io.Print(" Welcome to the BBS\r\n")
 	}
 
@@ -227,7 +293,7 @@ func LoginPrompt(io *telnet.TelnetIO, session *config.TelnetSession, cfg *config
 				if err != nil {
 					// Registration failed or cancelled, restart login prompt
 					io.ClearScreen()
-					if err := ui.PrintAnsiArt(io, "login"); err != nil {
+					if err := mci.PrintArt(io, "login", &mci.Context{Session: session, Config: cfg}); err != nil {
 						io.Print(" Welcome to the BBS\r\n")
 					}
 					io.Print("\r\n\r\n")
@@ -264,7 +330,7 @@ func LoginPrompt(io *telnet.TelnetIO, session *config.TelnetSession, cfg *config
 					if err != nil {
 						// Registration failed or cancelled, restart login prompt
 						io.ClearScreen()
-						if err := ui.PrintAnsiArt(io, "login"); err != nil {
+						if err := mci.PrintArt(io, "login", &mci.Context{Session: session, Config: cfg}); err != nil {
 							io.Print(" Welcome to the BBS\r\n")
 						}
 						io.Print("\r\n\r\n")
@@ -353,7 +419,7 @@ func RegisterPrompt(io *telnet.TelnetIO, session *config.TelnetSession, cfg *con
 	io.ClearScreen()
 
 	// Display new user art
-	if err := ui.PrintAnsiArt(io, "newuser"); err != nil {
+	if err := mci.PrintArt(io, "newuser", &mci.Context{Session: session, Config: cfg}); err != nil {
 		// Show error to user
 		fmt.Printf("Failed to load art: %v\n", err)
 	}
@@ -709,8 +775,12 @@ func RegisterPrompt(io *telnet.TelnetIO, session *config.TelnetSession, cfg *con
 		return nil, fmt.Errorf("registration cancelled")
 	}
 
-	// Create user account
-	err = CreateUser(username, password, email, config.SecurityLevelRegular, userDetails)
+	// Create user account at the configured new user level
+	level := cfg.Configuration.NewUsers.SecurityLevel
+	if level <= 0 {
+		level = config.SecurityLevelRegular
+	}
+	err = CreateUser(username, password, email, level, userDetails)
 	if err != nil {
 		logging.LogEvent(session.NodeNumber, username, session.IPAddress, "REGISTER_FAILED", "could not create user")
 		io.Print(ui.Ansi.Red + "Something went wrong creating your account. Please contact the sysop." + ui.Ansi.Reset + "\r\n")
@@ -727,6 +797,17 @@ func RegisterPrompt(io *telnet.TelnetIO, session *config.TelnetSession, cfg *con
 
 	// Log successful registration and auto-login
 	logging.LogEvent(session.NodeNumber, username, session.IPAddress, "REGISTER_SUCCESS", "new account created")
+	notify.NewUser(cfg, session.NodeNumber, username, userDetails["locations"])
+	if db := config.GetDatabase(); db != nil {
+		if err := db.IncrementDailyStat(time.Now().Format("2006-01-02"), database.StatNewUsers, 1); err != nil {
+			fmt.Printf("Warning: could not update daily stats: %v\n", err)
+		}
+		if cfg.Configuration.NewUsers.RequireValidation {
+			if err := db.QueueValidation(user.ID); err != nil {
+				fmt.Printf("Warning: could not queue user for validation: %v\n", err)
+			}
+		}
+	}
 	logging.LogLogin(session.NodeNumber, user.Username, session.IPAddress)
 
 	io.Printf(ui.Ansi.GreenHi+"\r\n\r\n Account created successfully. Welcome, %s!\r\n"+ui.Ansi.Reset, username)
@@ -734,7 +815,7 @@ func RegisterPrompt(io *telnet.TelnetIO, session *config.TelnetSession, cfg *con
 
 	// Show new user welcome screen
 	io.ClearScreen()
-	if err := ui.PrintAnsiArt(io, "welcome"); err != nil {
+	if err := mci.PrintArt(io, "welcome", &mci.Context{Session: session, Config: cfg}); err != nil {
 		io.Print("\r\n Welcome to the BBS!\r\n\r\n")
 	}
 	ui.Pause(io)
@@ -751,7 +832,7 @@ func ShowAccountConfirmation(io *telnet.TelnetIO, username *string, email *strin
 		io.ClearScreen()
 
 		// Display confirmation art
-		if err := ui.PrintAnsiArt(io, "confirm"); err != nil {
+		if err := mci.PrintArt(io, "confirm", &mci.Context{Session: session, Config: cfg}); err != nil {
 			io.Print("\r\n Account Confirmation\r\n")
 		}
 
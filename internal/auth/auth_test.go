@@ -0,0 +1,31 @@
+package auth
+
+import "testing"
+
+func TestPasswordDigestsVerifyWithTheirAlgorithm(t *testing.T) {
+	for _, algo := range []string{"", PasswordAlgoSHA256, PasswordAlgoPBKDF2} {
+		digest, err := NewPasswordDigest("s3cret", algo)
+		if err != nil {
+			t.Fatalf("NewPasswordDigest(%q): %v", algo, err)
+		}
+		if !CheckPassword("s3cret", digest) {
+			t.Fatalf("%q: expected the password to verify", algo)
+		}
+		if CheckPassword("wrong", digest) {
+			t.Fatalf("%q: expected a wrong password to fail", algo)
+		}
+	}
+
+	legacy := PasswordDigest{Hash: HashPassword("s3cret")}
+	if !CheckPassword("s3cret", legacy) {
+		t.Fatal("expected digests without an algorithm to verify as sha256")
+	}
+	first, _ := NewPasswordDigest("s3cret", PasswordAlgoPBKDF2)
+	second, _ := NewPasswordDigest("s3cret", PasswordAlgoPBKDF2)
+	if first.Salt == second.Salt || first.Hash == second.Hash {
+		t.Fatal("expected each pbkdf2 digest to get its own salt")
+	}
+	if _, err := NewPasswordDigest("s3cret", "md5"); err == nil {
+		t.Fatal("expected an unsupported algorithm to be rejected")
+	}
+}
@@ -39,7 +39,7 @@ type Storage interface {
 	// UpsertUserDetail stores or updates a user detail field.
 	UpsertUserDetail(userID int64, attrib, value string) error
 
-	// DeleteApplication removes the application for the user.
+	// DeleteApplication removes the user's entry from the validation queue.
 	DeleteApplication(userID int64) error
 }
 
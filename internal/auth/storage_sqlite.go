@@ -259,21 +259,9 @@ func (s *sqliteStorage) DeleteApplication(userID int64) error {
 		return fmt.Errorf("auth: invalid user id")
 	}
 
-	// Get the current user record
-	user, err := s.db.GetUserByID(userID)
-	if err != nil {
-		return fmt.Errorf("auth: failed to get user for application delete: %w", err)
-	}
-	if user == nil {
-		return ErrUserNotFound
-	}
-
-	// Clear the application fields (none in current schema)
-	// Application fields have been removed from the user table
-
-	// Update the user record
-	if err := s.db.UpdateUser(user); err != nil {
-		return fmt.Errorf("auth: failed to update user with cleared application data: %w", err)
+	// The application is the user's entry in the validation queue
+	if err := s.db.DeleteValidation(userID); err != nil {
+		return fmt.Errorf("auth: failed to delete application: %w", err)
 	}
 	return nil
 }
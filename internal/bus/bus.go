@@ -0,0 +1,130 @@
+// Package bus provides a small in-process publish/subscribe bus used to pass
+// events between connected sessions (teleconference, node messages,
+// broadcasts).
+package bus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Well-known topic prefixes
+const (
+	TopicBroadcast = "broadcast" // System-wide announcements
+)
+
+// Event is a single message published on the bus
+type Event struct {
+	Topic    string
+	Kind     string // Publisher defined, e.g. "say", "action", "join"
+	FromNode int
+	FromUser string
+	ToNode   int // Optional target node for directed events
+	Text     string
+	Time     time.Time
+}
+
+// Bus delivers events to subscribers by topic
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+// Subscription receives events for a single topic on C
+type Subscription struct {
+	Topic string
+	C     <-chan Event
+
+	ch   chan Event
+	bus  *Bus
+	once sync.Once
+}
+
+var defaultBus = New()
+
+// Default returns the process-wide bus shared by all sessions
+func Default() *Bus {
+	return defaultBus
+}
+
+// New creates an empty bus
+func New() *Bus {
+	return &Bus{subs: make(map[string]map[*Subscription]struct{})}
+}
+
+// Subscribe registers interest in a topic. buffer sets how many undelivered
+// events may queue before new events are dropped for this subscriber.
+func (b *Bus) Subscribe(topic string, buffer int) *Subscription {
+	if buffer < 1 {
+		buffer = 1
+	}
+	topic = normalizeTopic(topic)
+	ch := make(chan Event, buffer)
+	sub := &Subscription{Topic: topic, C: ch, ch: ch, bus: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[*Subscription]struct{})
+	}
+	b.subs[topic][sub] = struct{}{}
+	return sub
+}
+
+// Publish sends an event to every subscriber of topic without blocking.
+// Returns the number of subscribers the event was delivered to; slow
+// subscribers with a full buffer miss the event.
+func (b *Bus) Publish(topic string, ev Event) int {
+	topic = normalizeTopic(topic)
+	ev.Topic = topic
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	delivered := 0
+	for sub := range b.subs[topic] {
+		select {
+		case sub.ch <- ev:
+			delivered++
+		default:
+		}
+	}
+	return delivered
+}
+
+// Subscribers returns the number of active subscribers for a topic
+func (b *Bus) Subscribers(topic string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs[normalizeTopic(topic)])
+}
+
+// Close unsubscribes and closes the subscription channel. Safe to call more
+// than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		if subs := s.bus.subs[s.Topic]; subs != nil {
+			delete(subs, s)
+			if len(subs) == 0 {
+				delete(s.bus.subs, s.Topic)
+			}
+		}
+		close(s.ch)
+	})
+}
+
+func normalizeTopic(topic string) string {
+	return strings.ToLower(strings.TrimSpace(topic))
+}
+
+// NodeTopic returns the topic used for events directed at a single node
+func NodeTopic(node int) string {
+	return fmt.Sprintf("node:%d", node)
+}
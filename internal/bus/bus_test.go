@@ -0,0 +1,52 @@
+package bus
+
+import "testing"
+
+func TestPublishDeliversToTopicSubscribers(t *testing.T) {
+	b := New()
+	a := b.Subscribe("chat:main", 4)
+	other := b.Subscribe("chat:other", 4)
+	defer a.Close()
+	defer other.Close()
+
+	if n := b.Publish("CHAT:Main", Event{Kind: "say", Text: "hi"}); n != 1 {
+		t.Fatalf("expected delivery to 1 subscriber, got %d", n)
+	}
+
+	ev := <-a.C
+	if ev.Text != "hi" || ev.Topic != "chat:main" || ev.Time.IsZero() {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	select {
+	case ev := <-other.C:
+		t.Fatalf("unexpected event on other topic: %+v", ev)
+	default:
+	}
+}
+
+func TestPublishDropsForFullSubscriber(t *testing.T) {
+	b := New()
+	sub := b.Subscribe("slow", 1)
+	defer sub.Close()
+
+	if n := b.Publish("slow", Event{Text: "one"}); n != 1 {
+		t.Fatalf("expected first event delivered, got %d", n)
+	}
+	if n := b.Publish("slow", Event{Text: "two"}); n != 0 {
+		t.Fatalf("expected second event dropped, got %d", n)
+	}
+}
+
+func TestCloseUnsubscribes(t *testing.T) {
+	b := New()
+	sub := b.Subscribe(NodeTopic(3), 1)
+	sub.Close()
+	sub.Close()
+
+	if n := b.Subscribers(NodeTopic(3)); n != 0 {
+		t.Fatalf("expected no subscribers after close, got %d", n)
+	}
+	if _, ok := <-sub.C; ok {
+		t.Fatalf("expected closed channel")
+	}
+}
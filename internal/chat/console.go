@@ -0,0 +1,205 @@
+package chat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/cancelreader"
+	"github.com/robbiew/retrograde/internal/bus"
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/telnet"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// consoleWindowLines is how many sysop window lines the console redraws
+const consoleWindowLines = 14
+
+// ServeConsole listens for local sysop console connections on addr. The
+// console shows the sysop window (pages and -C messages) and opens
+// split-screen chat with any node. Bind it to a loopback address only.
+func ServeConsole(addr, sysopName string) (net.Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start sysop console: %w", err)
+	}
+	if sysopName == "" {
+		sysopName = "SysOp"
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go runConsole(conn, sysopName)
+		}
+	}()
+	return ln, nil
+}
+
+func runConsole(conn net.Conn, sysopName string) {
+	defer conn.Close()
+
+	session := &config.TelnetSession{Alias: sysopName, Conn: conn, Connected: true, Width: 80, Height: 24, SecurityLevel: config.SecurityLevelSysOp, Encoding: config.EncodingUTF8}
+	tio := &telnet.TelnetIO{Reader: bufio.NewReader(conn), Writer: bufio.NewWriter(conn), Session: session}
+
+	window := bus.Default().Subscribe(TopicSysOpWindow, 64)
+	defer window.Close()
+
+	desk := Desk()
+	drawConsole(tio, desk)
+
+	for {
+		select {
+		case ev, ok := <-window.C:
+			if !ok {
+				return
+			}
+			if ev.Kind == KindPage {
+				tio.Print("\a")
+			}
+			drawConsole(tio, desk)
+			continue
+		default:
+		}
+
+		seq, err := tio.ReadKeySequence(250 * time.Millisecond)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			return
+		}
+
+		switch strings.ToUpper(seq) {
+		case "Q", "\x1b":
+			tio.Print(ui.Ansi.Reset + ui.Ansi.EraseScreen + ui.MoveCursorSequence(1, 1))
+			return
+		case "C":
+			node := promptConsoleNode(tio, desk)
+			if node > 0 {
+				consoleChat(tio, desk, node, sysopName)
+			}
+			drawConsole(tio, desk)
+		default:
+			drawConsole(tio, desk)
+		}
+	}
+}
+
+// promptConsoleNode asks which node to chat with, defaulting to the oldest page
+func promptConsoleNode(tio *telnet.TelnetIO, desk *SysOpDesk) int {
+	initial := ""
+	if pages := desk.Pages(); len(pages) > 0 {
+		initial = strconv.Itoa(pages[0].Node)
+	}
+	tio.Print(ui.MoveCursorSequence(1, 24) + ui.Ansi.EraseLine)
+	value, err := ui.PromptSimple(tio, " Chat with node: ", 3, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, initial)
+	if err != nil {
+		return 0
+	}
+	node, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || node < 1 {
+		return 0
+	}
+	return node
+}
+
+func consoleChat(tio *telnet.TelnetIO, desk *SysOpDesk, node int, sysopName string) {
+	in := bus.Default().Subscribe(SysOpChatTopic(node, true), 256)
+	defer in.Close()
+
+	if err := desk.OpenChat(node, sysopName); err != nil {
+		tio.Print(ui.MoveCursorSequence(1, 24) + ui.Ansi.EraseLine + ui.Ansi.RedHi + " " + err.Error() + ui.Ansi.Reset)
+		time.Sleep(2 * time.Second)
+		return
+	}
+	defer desk.CloseChat(node)
+
+	callerName := fmt.Sprintf("Node %d", node)
+	if nm := logging.GetNodeManager(); nm != nil {
+		if conn, ok := nm.Snapshot(node); ok && conn.Username != "" {
+			callerName = conn.Username
+		}
+	}
+
+	c := &SplitChat{IO: tio, Bus: bus.Default(), In: in, Node: node, SysOpName: sysopName, CallerName: callerName, IsSysOp: true}
+	c.Run()
+}
+
+func drawConsole(tio *telnet.TelnetIO, desk *SysOpDesk) {
+	var out strings.Builder
+	out.WriteString(ui.Ansi.Reset + ui.Ansi.EraseScreen + ui.MoveCursorSequence(1, 1))
+	out.WriteString(ui.Ansi.BgBlue + ui.Ansi.WhiteHi + fmt.Sprintf(" %-78s ", "Retrograde SysOp Console") + ui.Ansi.Reset + "\r\n\r\n")
+
+	out.WriteString(ui.Ansi.CyanHi + " Waiting pages:\r\n" + ui.Ansi.Reset)
+	pages := desk.Pages()
+	if len(pages) == 0 {
+		out.WriteString(ui.Ansi.BlackHi + "   none\r\n" + ui.Ansi.Reset)
+	}
+	for _, p := range pages {
+		out.WriteString(fmt.Sprintf(ui.Ansi.YellowHi+"   Node %-3d %-20s %s  %s\r\n"+ui.Ansi.Reset,
+			p.Node, truncate(p.User, 20), p.Time.Format("15:04"), truncate(p.Reason, 40)))
+	}
+
+	out.WriteString("\r\n" + ui.Ansi.CyanHi + " SysOp window:\r\n" + ui.Ansi.Reset)
+	window := desk.Window()
+	if len(window) > consoleWindowLines-len(pages) {
+		window = window[len(window)-max(1, consoleWindowLines-len(pages)):]
+	}
+	for _, ev := range window {
+		out.WriteString(fmt.Sprintf(ui.Ansi.White+"   %s "+ui.Ansi.Green+"%s"+ui.Ansi.Reset+"\r\n",
+			ev.Time.Format("15:04"), truncate(ui.StripANSI(ui.StripPipeCodes(ev.Text)), 70)))
+	}
+
+	out.WriteString(ui.MoveCursorSequence(1, 24) + ui.Ansi.Cyan + " [C]hat with a node  [Q]uit" + ui.Ansi.Reset)
+	tio.Print(out.String())
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
+
+// ConnectConsole attaches the local terminal to a running server's sysop
+// console until the console closes the connection
+func ConnectConsole(addr string, stdin *os.File, stdout io.Writer) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to sysop console at %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	if term.IsTerminal(stdin.Fd()) {
+		state, err := term.MakeRaw(stdin.Fd())
+		if err != nil {
+			return fmt.Errorf("failed to set raw terminal mode: %w", err)
+		}
+		defer term.Restore(stdin.Fd(), state)
+	}
+
+	// A cancelable reader keeps the stdin copier from swallowing keystrokes
+	// meant for whoever owns the terminal after the console closes
+	input, err := cancelreader.NewReader(stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read terminal input: %w", err)
+	}
+	defer input.Close()
+
+	go io.Copy(conn, input)
+	_, err = io.Copy(stdout, conn)
+	input.Cancel()
+	return err
+}
@@ -0,0 +1,234 @@
+package chat
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/bus"
+	"github.com/robbiew/retrograde/internal/telnet"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// splitChatPollInterval controls how often the chat loop checks for remote
+// keystrokes while waiting on local input
+const splitChatPollInterval = 50 * time.Millisecond
+
+// SplitChat runs one side of a two-way sysop chat. The screen is split into
+// a SysOp pane on top and a caller pane below; each pane is its own ANSI
+// scroll region so text in one never scrolls the other.
+type SplitChat struct {
+	IO         *telnet.TelnetIO
+	Bus        *bus.Bus
+	In         *bus.Subscription // This side's SysOpChatTopic, subscribed before the chat opens
+	Node       int
+	SysOpName  string
+	CallerName string
+	IsSysOp    bool        // True on the console side
+	Transcript *Transcript // Optional; records both sides a line at a time
+
+	width, height int
+	panes         [2]*chatPane // 0 = sysop (top), 1 = caller (bottom)
+}
+
+type chatPane struct {
+	top, bottom int
+	row, col    int
+	color       string
+	line        []rune
+}
+
+// Run shows the chat screen until either side presses ESC or the remote
+// side goes away. The opposite side is always told when this side leaves.
+func (c *SplitChat) Run() error {
+	defer c.send(KindChatClose, "")
+
+	c.layout()
+	c.drawFrame()
+	defer c.restore()
+
+	local, remote := c.panes[1], c.panes[0]
+	if c.IsSysOp {
+		local, remote = c.panes[0], c.panes[1]
+	}
+	c.moveTo(local)
+
+	for {
+		for drained := false; !drained; {
+			select {
+			case ev, ok := <-c.In.C:
+				if !ok {
+					return nil
+				}
+				switch ev.Kind {
+				case KindChatKey:
+					for _, r := range ev.Text {
+						c.apply(remote, r, !c.IsSysOp)
+					}
+					c.moveTo(local)
+				case KindChatClose:
+					c.notice("The other side has ended the chat.")
+					return nil
+				}
+			default:
+				drained = true
+			}
+		}
+
+		seq, err := c.IO.ReadKeySequence(splitChatPollInterval)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		var r rune
+		switch {
+		case seq == "\x1b":
+			return nil
+		case seq == "\r" || seq == "\r\n" || seq == "\n":
+			r = '\r'
+		case seq == "\b" || seq == "\x7f":
+			r = '\b'
+		case len(seq) == 1 && seq[0] >= 32 && seq[0] < 127:
+			r = rune(seq[0])
+		default:
+			continue
+		}
+		c.apply(local, r, c.IsSysOp)
+		c.send(KindChatKey, string(r))
+	}
+}
+
+func (c *SplitChat) send(kind, text string) {
+	c.Bus.Publish(SysOpChatTopic(c.Node, !c.IsSysOp), bus.Event{Kind: kind, FromNode: c.Node, ToNode: c.Node, Text: text})
+}
+
+func (c *SplitChat) layout() {
+	c.width, c.height = 80, 24
+	if s := c.IO.Session; s != nil {
+		if s.Width > 0 {
+			c.width = s.Width
+		}
+		if s.Height > 0 {
+			c.height = s.Height
+		}
+	}
+	divider := c.height / 2
+	c.panes[0] = &chatPane{top: 1, bottom: divider - 1, row: 1, col: 1, color: ui.Ansi.YellowHi}
+	c.panes[1] = &chatPane{top: divider + 1, bottom: c.height, row: divider + 1, col: 1, color: ui.Ansi.CyanHi}
+}
+
+func (c *SplitChat) drawFrame() {
+	divider := c.height / 2
+	label := fmt.Sprintf(" %s  <->  %s   (ESC ends chat) ", c.SysOpName, c.CallerName)
+	fill := max(0, c.width-len(label)-2)
+	bar := "==" + label + strings.Repeat("=", fill)
+	c.IO.Print(ui.Ansi.Reset + ui.Esc + "r" + ui.Ansi.EraseScreen +
+		ui.MoveCursorSequence(1, divider) + ui.Ansi.Blue + bar + ui.Ansi.Reset)
+}
+
+func (c *SplitChat) restore() {
+	c.IO.Print(ui.Esc + "r" + ui.Ansi.Reset + ui.Ansi.EraseScreen + ui.MoveCursorSequence(1, 1))
+}
+
+// notice shows a status line on the divider
+func (c *SplitChat) notice(text string) {
+	c.IO.Print(ui.Esc + "r" + ui.MoveCursorSequence(1, c.height/2) + ui.Ansi.EraseLine + ui.Ansi.RedHi + " " + text + ui.Ansi.Reset)
+	time.Sleep(2 * time.Second)
+}
+
+// moveTo parks the cursor at the end of a pane's current line
+func (c *SplitChat) moveTo(p *chatPane) {
+	c.IO.Print(fmt.Sprintf(ui.Esc+"%d;%dr", p.top, p.bottom) + ui.MoveCursorSequence(p.col, p.row) + p.color)
+}
+
+// apply renders one keystroke in a pane. Completed lines go to the
+// transcript, attributed to whoever owns the pane.
+func (c *SplitChat) apply(p *chatPane, r rune, sysopSpeaking bool) {
+	c.moveTo(p)
+
+	switch r {
+	case '\r':
+		c.endLine(p, sysopSpeaking)
+		return
+	case '\b':
+		if len(p.line) > 0 && p.col > 1 {
+			p.line = p.line[:len(p.line)-1]
+			p.col--
+			c.IO.Print("\b \b")
+		}
+		return
+	}
+
+	c.IO.Print(string(r))
+	p.line = append(p.line, r)
+	p.col++
+	if p.col > c.width {
+		c.endLine(p, sysopSpeaking)
+	}
+}
+
+func (c *SplitChat) endLine(p *chatPane, sysopSpeaking bool) {
+	if c.Transcript != nil {
+		speaker := c.CallerName
+		if sysopSpeaking {
+			speaker = c.SysOpName
+		}
+		c.Transcript.Line(speaker, string(p.line))
+	}
+	p.line = p.line[:0]
+	p.col = 1
+	// At the bottom margin the newline scrolls only this pane's region
+	if p.row < p.bottom {
+		p.row++
+	}
+	c.IO.Print("\r\n")
+}
+
+// Transcript records a sysop chat to a file in the logs directory
+type Transcript struct {
+	file *os.File
+	Path string
+}
+
+// OpenTranscript creates a new transcript file for a chat with node
+func OpenTranscript(dir string, node int, user string) (*Transcript, error) {
+	if dir == "" {
+		dir = "logs"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("chat-%s-node%02d.log", now.Format("20060102-150405"), node))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chat transcript: %w", err)
+	}
+
+	fmt.Fprintf(f, "SysOp chat with %s on node %d, started %s\n\n", user, node, now.Format("2006-01-02 15:04:05"))
+	return &Transcript{file: f, Path: path}, nil
+}
+
+// Line appends a completed line of chat
+func (t *Transcript) Line(speaker, text string) {
+	if t == nil || t.file == nil {
+		return
+	}
+	fmt.Fprintf(t.file, "[%s] %s: %s\n", time.Now().Format("15:04:05"), speaker, text)
+}
+
+// Close finishes the transcript
+func (t *Transcript) Close() error {
+	if t == nil || t.file == nil {
+		return nil
+	}
+	fmt.Fprintf(t.file, "\nChat ended %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	return t.file.Close()
+}
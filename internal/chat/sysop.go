@@ -0,0 +1,194 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/bus"
+)
+
+// TopicSysOpWindow carries pages and -C messages to attached sysop consoles
+const TopicSysOpWindow = "sysop"
+
+// WindowSize is the number of sysop window events kept for newly attached consoles
+const WindowSize = 100
+
+// Event kinds used for sysop paging and split-screen chat
+const (
+	KindPage       = "page"
+	KindPageCancel = "page-cancel"
+	KindWindow     = "window"
+	KindChatOpen   = "chat-open"
+	KindChatKey    = "chat-key"
+	KindChatClose  = "chat-close"
+)
+
+var ErrChatBusy = errors.New("node is already in sysop chat")
+
+// Page is an outstanding request from a caller to chat with the sysop
+type Page struct {
+	Node   int
+	User   string
+	Reason string
+	Time   time.Time
+}
+
+// SysOpDesk tracks pages, the sysop window and which nodes are in sysop chat
+type SysOpDesk struct {
+	bus *bus.Bus
+
+	mu     sync.Mutex
+	pages  map[int]Page
+	chats  map[int]bool
+	window []bus.Event
+}
+
+var defaultDesk = NewSysOpDesk(bus.Default())
+
+// Desk returns the process-wide sysop desk
+func Desk() *SysOpDesk {
+	return defaultDesk
+}
+
+// NewSysOpDesk creates a sysop desk publishing on b
+func NewSysOpDesk(b *bus.Bus) *SysOpDesk {
+	return &SysOpDesk{
+		bus:   b,
+		pages: make(map[int]Page),
+		chats: make(map[int]bool),
+	}
+}
+
+// SysOpChatTopic returns the topic a side of a sysop chat listens on.
+// toSysOp selects the console side; otherwise the caller's side.
+func SysOpChatTopic(node int, toSysOp bool) string {
+	if toSysOp {
+		return fmt.Sprintf("sysopchat:%d:sysop", node)
+	}
+	return fmt.Sprintf("sysopchat:%d:caller", node)
+}
+
+// Page records a page from node and notifies attached consoles
+func (d *SysOpDesk) Page(node int, user, reason string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pages[node] = Page{Node: node, User: user, Reason: reason, Time: time.Now()}
+	text := fmt.Sprintf("%s on node %d is paging you", user, node)
+	if reason != "" {
+		text += ": " + reason
+	}
+	d.publishWindowLocked(bus.Event{Kind: KindPage, FromNode: node, FromUser: user, Text: text})
+}
+
+// CancelPage withdraws an unanswered page
+func (d *SysOpDesk) CancelPage(node int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	page, ok := d.pages[node]
+	if !ok {
+		return
+	}
+	delete(d.pages, node)
+	d.publishWindowLocked(bus.Event{Kind: KindPageCancel, FromNode: node, FromUser: page.User,
+		Text: fmt.Sprintf("%s on node %d stopped paging.", page.User, node)})
+}
+
+// Pages lists outstanding pages, oldest first
+func (d *SysOpDesk) Pages() []Page {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]Page, 0, len(d.pages))
+	for _, p := range d.pages {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out
+}
+
+// WindowMessage shows a line on the sysop window (-C)
+func (d *SysOpDesk) WindowMessage(node int, user, text string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.publishWindowLocked(bus.Event{Kind: KindWindow, FromNode: node, FromUser: user, Text: strings.TrimSpace(text)})
+}
+
+// Window returns recent sysop window events, oldest first
+func (d *SysOpDesk) Window() []bus.Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]bus.Event(nil), d.window...)
+}
+
+// OpenChat starts a sysop chat with node. The caller's session is told via
+// its chat topic and drops into split-screen chat at its next prompt.
+func (d *SysOpDesk) OpenChat(node int, sysop string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.chats[node] {
+		return ErrChatBusy
+	}
+	if d.bus.Subscribers(SysOpChatTopic(node, false)) == 0 {
+		return fmt.Errorf("no caller on node %d", node)
+	}
+	d.chats[node] = true
+	delete(d.pages, node)
+	d.bus.Publish(SysOpChatTopic(node, false), bus.Event{Kind: KindChatOpen, FromUser: sysop, ToNode: node})
+	return nil
+}
+
+// CloseChat marks node's sysop chat as finished
+func (d *SysOpDesk) CloseChat(node int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.chats, node)
+}
+
+// InChat reports whether node is in sysop chat
+func (d *SysOpDesk) InChat(node int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.chats[node]
+}
+
+func (d *SysOpDesk) publishWindowLocked(ev bus.Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	d.window = append(d.window, ev)
+	if len(d.window) > WindowSize {
+		d.window = append([]bus.Event(nil), d.window[len(d.window)-WindowSize:]...)
+	}
+	d.bus.Publish(TopicSysOpWindow, ev)
+}
+
+// WithinHours reports whether now falls inside the HH:MM window from start to
+// end. The window may wrap past midnight; a blank or zero-length window is
+// always open.
+func WithinHours(start, end string, now time.Time) bool {
+	s, okStart := parseClock(start)
+	e, okEnd := parseClock(end)
+	if !okStart || !okEnd || s == e {
+		return true
+	}
+	m := now.Hour()*60 + now.Minute()
+	if s < e {
+		return m >= s && m < e
+	}
+	return m >= s || m < e
+}
+
+func parseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
@@ -0,0 +1,93 @@
+package chat
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/bus"
+)
+
+func TestWithinHours(t *testing.T) {
+	at := func(hh, mm int) time.Time { return time.Date(2025, 1, 1, hh, mm, 0, 0, time.Local) }
+
+	cases := []struct {
+		start, end string
+		now        time.Time
+		want       bool
+	}{
+		{"08:00", "22:00", at(12, 0), true},
+		{"08:00", "22:00", at(22, 0), false},
+		{"08:00", "22:00", at(7, 59), false},
+		{"22:00", "02:00", at(23, 30), true},
+		{"22:00", "02:00", at(1, 0), true},
+		{"22:00", "02:00", at(12, 0), false},
+		{"", "", at(3, 0), true},
+		{"09:00", "09:00", at(3, 0), true},
+	}
+	for _, c := range cases {
+		if got := WithinHours(c.start, c.end, c.now); got != c.want {
+			t.Errorf("WithinHours(%q, %q, %s) = %v, want %v", c.start, c.end, c.now.Format("15:04"), got, c.want)
+		}
+	}
+}
+
+func TestPageAndOpenChat(t *testing.T) {
+	b := bus.New()
+	desk := NewSysOpDesk(b)
+	window := b.Subscribe(TopicSysOpWindow, 4)
+	defer window.Close()
+
+	desk.Page(3, "Alice", "help with upload")
+	if ev := next(t, window); ev.Kind != KindPage || !strings.Contains(ev.Text, "help with upload") {
+		t.Fatalf("unexpected page event: %+v", ev)
+	}
+	if pages := desk.Pages(); len(pages) != 1 || pages[0].Node != 3 {
+		t.Fatalf("unexpected pages: %+v", pages)
+	}
+
+	// No session is listening on node 3 yet
+	if err := desk.OpenChat(3, "SysOp"); err == nil {
+		t.Fatalf("expected error opening chat with no caller")
+	}
+
+	caller := b.Subscribe(SysOpChatTopic(3, false), 4)
+	defer caller.Close()
+	if err := desk.OpenChat(3, "SysOp"); err != nil {
+		t.Fatalf("OpenChat error: %v", err)
+	}
+	if ev := next(t, caller); ev.Kind != KindChatOpen {
+		t.Fatalf("expected chat-open, got %+v", ev)
+	}
+	if len(desk.Pages()) != 0 {
+		t.Fatalf("expected page cleared once answered")
+	}
+	if err := desk.OpenChat(3, "SysOp"); err != ErrChatBusy {
+		t.Fatalf("expected ErrChatBusy, got %v", err)
+	}
+	desk.CloseChat(3)
+	if desk.InChat(3) {
+		t.Fatalf("expected chat closed")
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	tr, err := OpenTranscript(t.TempDir(), 2, "Bob")
+	if err != nil {
+		t.Fatalf("OpenTranscript error: %v", err)
+	}
+	tr.Line("SysOp", "hello Bob")
+	tr.Line("Bob", "hi!")
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	data, err := os.ReadFile(tr.Path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if !strings.Contains(string(data), "SysOp: hello Bob") || !strings.Contains(string(data), "Bob: hi!") {
+		t.Fatalf("unexpected transcript:\n%s", data)
+	}
+}
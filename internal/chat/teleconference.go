@@ -0,0 +1,306 @@
+// Package chat implements the multi-node teleconference (NG). Channel traffic
+// travels over the shared session bus; this package tracks who is in which
+// channel, keeps a scrollback buffer per channel and applies moderation.
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/bus"
+)
+
+// DefaultChannel is joined when no channel is given
+const DefaultChannel = "main"
+
+// ScrollbackSize is the number of events kept per channel
+const ScrollbackSize = 50
+
+// Event kinds published on channel and node topics
+const (
+	KindSay     = "say"
+	KindAction  = "action"
+	KindJoin    = "join"
+	KindPart    = "part"
+	KindSystem  = "system"
+	KindWhisper = "whisper"
+	KindKick    = "kick"
+)
+
+var (
+	ErrNotInChat     = errors.New("not in teleconference")
+	ErrMuted         = errors.New("you have been muted")
+	ErrUnknownTarget = errors.New("no such user in teleconference")
+	ErrBadChannel    = errors.New("invalid channel name")
+)
+
+// Member is a node currently in the teleconference
+type Member struct {
+	Node     int
+	User     string
+	Channel  string
+	Muted    bool
+	JoinedAt time.Time
+}
+
+// Teleconference holds channel membership and scrollback for all nodes
+type Teleconference struct {
+	bus *bus.Bus
+
+	mu         sync.Mutex
+	members    map[int]*Member
+	muted      map[string]bool // Lower-cased usernames muted by a sysop
+	scrollback map[string][]bus.Event
+}
+
+var defaultTeleconference = New(bus.Default())
+
+// Default returns the process-wide teleconference
+func Default() *Teleconference {
+	return defaultTeleconference
+}
+
+// New creates a teleconference publishing on b
+func New(b *bus.Bus) *Teleconference {
+	return &Teleconference{
+		bus:        b,
+		members:    make(map[int]*Member),
+		muted:      make(map[string]bool),
+		scrollback: make(map[string][]bus.Event),
+	}
+}
+
+// ChannelTopic returns the bus topic for a channel
+func ChannelTopic(channel string) string {
+	return "chat:" + channel
+}
+
+// NormalizeChannel validates and lower-cases a channel name
+func NormalizeChannel(name string) (string, error) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	if name == "" {
+		return DefaultChannel, nil
+	}
+	if len(name) > 20 {
+		return "", ErrBadChannel
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return "", ErrBadChannel
+		}
+	}
+	return name, nil
+}
+
+// Join places a node in channel, leaving any channel it was already in. The
+// returned subscription receives channel traffic (including the node's own
+// join notice) and the scrollback holds recent history from before the join.
+func (t *Teleconference) Join(node int, user, channel string) (*bus.Subscription, []bus.Event, error) {
+	channel, err := NormalizeChannel(channel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if prev, ok := t.members[node]; ok {
+		if prev.Channel == channel {
+			return t.bus.Subscribe(ChannelTopic(channel), 64), t.history(channel), nil
+		}
+		t.publishLocked(prev.Channel, bus.Event{Kind: KindPart, FromNode: node, FromUser: prev.User,
+			Text: fmt.Sprintf("%s has left #%s.", prev.User, prev.Channel)})
+	}
+
+	history := t.history(channel)
+	member := &Member{Node: node, User: user, Channel: channel, Muted: t.muted[strings.ToLower(user)], JoinedAt: time.Now()}
+	t.members[node] = member
+
+	sub := t.bus.Subscribe(ChannelTopic(channel), 64)
+	t.publishLocked(channel, bus.Event{Kind: KindJoin, FromNode: node, FromUser: user,
+		Text: fmt.Sprintf("%s has joined #%s.", user, channel)})
+	return sub, history, nil
+}
+
+// Part removes a node from the teleconference. Safe to call when the node
+// has already left or been kicked.
+func (t *Teleconference) Part(node int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	member, ok := t.members[node]
+	if !ok {
+		return
+	}
+	delete(t.members, node)
+	t.publishLocked(member.Channel, bus.Event{Kind: KindPart, FromNode: node, FromUser: member.User,
+		Text: fmt.Sprintf("%s has left #%s.", member.User, member.Channel)})
+}
+
+// Say publishes a line of chat from node to its channel
+func (t *Teleconference) Say(node int, text string) error {
+	return t.speak(node, KindSay, text)
+}
+
+// Action publishes a /me action from node to its channel
+func (t *Teleconference) Action(node int, text string) error {
+	return t.speak(node, KindAction, text)
+}
+
+func (t *Teleconference) speak(node int, kind, text string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	member, ok := t.members[node]
+	if !ok {
+		return ErrNotInChat
+	}
+	if member.Muted {
+		return ErrMuted
+	}
+	t.publishLocked(member.Channel, bus.Event{Kind: kind, FromNode: node, FromUser: member.User, Text: text})
+	return nil
+}
+
+// Whisper sends a private line to another member, found by node number or
+// user name. Whispers are not kept in scrollback.
+func (t *Teleconference) Whisper(node int, target, text string) (Member, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	from, ok := t.members[node]
+	if !ok {
+		return Member{}, ErrNotInChat
+	}
+	to, ok := t.findLocked(target)
+	if !ok {
+		return Member{}, ErrUnknownTarget
+	}
+	t.bus.Publish(bus.NodeTopic(to.Node), bus.Event{Kind: KindWhisper, FromNode: node, FromUser: from.User, ToNode: to.Node, Text: text})
+	return *to, nil
+}
+
+// Kick removes a member from the teleconference. The kicked node is told
+// through its node topic and the channel sees a system notice.
+func (t *Teleconference) Kick(byUser, target string) (Member, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	member, ok := t.findLocked(target)
+	if !ok {
+		return Member{}, ErrUnknownTarget
+	}
+	kicked := *member
+	delete(t.members, member.Node)
+
+	t.bus.Publish(bus.NodeTopic(kicked.Node), bus.Event{Kind: KindKick, FromUser: byUser, ToNode: kicked.Node,
+		Text: fmt.Sprintf("You have been removed from the teleconference by %s.", byUser)})
+	t.publishLocked(kicked.Channel, bus.Event{Kind: KindSystem, FromUser: byUser,
+		Text: fmt.Sprintf("%s was removed from #%s by %s.", kicked.User, kicked.Channel, byUser)})
+	return kicked, nil
+}
+
+// SetMuted mutes or unmutes a member. Mutes stick to the user name so
+// leaving and rejoining does not clear them.
+func (t *Teleconference) SetMuted(byUser, target string, muted bool) (Member, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	member, ok := t.findLocked(target)
+	if !ok {
+		return Member{}, ErrUnknownTarget
+	}
+	member.Muted = muted
+	if muted {
+		t.muted[strings.ToLower(member.User)] = true
+	} else {
+		delete(t.muted, strings.ToLower(member.User))
+	}
+
+	verb := "unmuted"
+	if muted {
+		verb = "muted"
+	}
+	t.publishLocked(member.Channel, bus.Event{Kind: KindSystem, FromUser: byUser,
+		Text: fmt.Sprintf("%s was %s by %s.", member.User, verb, byUser)})
+	return *member, nil
+}
+
+// Who lists members of a channel ordered by node number
+func (t *Teleconference) Who(channel string) []Member {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out []Member
+	for _, m := range t.members {
+		if m.Channel == channel {
+			out = append(out, *m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Node < out[j].Node })
+	return out
+}
+
+// Channels returns the number of members in each active channel
+func (t *Teleconference) Channels() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, m := range t.members {
+		counts[m.Channel]++
+	}
+	return counts
+}
+
+// Member returns the membership record for a node
+func (t *Teleconference) Member(node int) (Member, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	m, ok := t.members[node]
+	if !ok {
+		return Member{}, false
+	}
+	return *m, true
+}
+
+func (t *Teleconference) findLocked(target string) (*Member, bool) {
+	target = strings.TrimSpace(target)
+	if node, err := strconv.Atoi(target); err == nil {
+		m, ok := t.members[node]
+		return m, ok
+	}
+	for _, m := range t.members {
+		if strings.EqualFold(m.User, target) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+func (t *Teleconference) history(channel string) []bus.Event {
+	return append([]bus.Event(nil), t.scrollback[channel]...)
+}
+
+// publishLocked records an event in the channel's scrollback and publishes
+// it. Caller must hold t.mu.
+func (t *Teleconference) publishLocked(channel string, ev bus.Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	ev.Topic = ChannelTopic(channel)
+
+	buf := append(t.scrollback[channel], ev)
+	if len(buf) > ScrollbackSize {
+		buf = append([]bus.Event(nil), buf[len(buf)-ScrollbackSize:]...)
+	}
+	t.scrollback[channel] = buf
+
+	t.bus.Publish(ChannelTopic(channel), ev)
+}
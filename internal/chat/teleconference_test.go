@@ -0,0 +1,108 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/robbiew/retrograde/internal/bus"
+)
+
+func next(t *testing.T, sub *bus.Subscription) bus.Event {
+	t.Helper()
+	select {
+	case ev := <-sub.C:
+		return ev
+	default:
+		t.Fatalf("expected an event on %s", sub.Topic)
+	}
+	return bus.Event{}
+}
+
+func TestJoinSayAndScrollback(t *testing.T) {
+	tc := New(bus.New())
+
+	alice, history, err := tc.Join(1, "Alice", "")
+	if err != nil {
+		t.Fatalf("Join error: %v", err)
+	}
+	defer alice.Close()
+	if len(history) != 0 {
+		t.Fatalf("expected empty scrollback, got %d", len(history))
+	}
+	if ev := next(t, alice); ev.Kind != KindJoin {
+		t.Fatalf("expected own join notice, got %+v", ev)
+	}
+
+	if err := tc.Say(1, "hello"); err != nil {
+		t.Fatalf("Say error: %v", err)
+	}
+	if ev := next(t, alice); ev.Kind != KindSay || ev.Text != "hello" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	bob, history, err := tc.Join(2, "Bob", "#Main")
+	if err != nil {
+		t.Fatalf("Join error: %v", err)
+	}
+	defer bob.Close()
+	if len(history) != 2 || history[1].Text != "hello" {
+		t.Fatalf("expected scrollback with join and hello, got %+v", history)
+	}
+	if ev := next(t, alice); ev.Kind != KindJoin || ev.FromUser != "Bob" {
+		t.Fatalf("expected Bob's join notice, got %+v", ev)
+	}
+	if who := tc.Who(DefaultChannel); len(who) != 2 || who[0].Node != 1 {
+		t.Fatalf("unexpected members: %+v", who)
+	}
+}
+
+func TestWhisperKickAndMute(t *testing.T) {
+	b := bus.New()
+	tc := New(b)
+
+	chanSub, _, _ := tc.Join(1, "SysOp", "main")
+	defer chanSub.Close()
+	bobSub, _, _ := tc.Join(2, "Bob", "main")
+	defer bobSub.Close()
+	bobDirect := b.Subscribe(bus.NodeTopic(2), 4)
+	defer bobDirect.Close()
+
+	if _, err := tc.Whisper(1, "bob", "psst"); err != nil {
+		t.Fatalf("Whisper error: %v", err)
+	}
+	if ev := next(t, bobDirect); ev.Kind != KindWhisper || ev.Text != "psst" {
+		t.Fatalf("unexpected whisper: %+v", ev)
+	}
+
+	if _, err := tc.SetMuted("SysOp", "2", true); err != nil {
+		t.Fatalf("SetMuted error: %v", err)
+	}
+	if err := tc.Say(2, "spam"); err != ErrMuted {
+		t.Fatalf("expected ErrMuted, got %v", err)
+	}
+
+	if _, err := tc.Kick("SysOp", "Bob"); err != nil {
+		t.Fatalf("Kick error: %v", err)
+	}
+	if ev := next(t, bobDirect); ev.Kind != KindKick {
+		t.Fatalf("expected kick event, got %+v", ev)
+	}
+	if _, ok := tc.Member(2); ok {
+		t.Fatalf("expected Bob removed after kick")
+	}
+
+	// Mutes survive rejoining
+	rejoin, _, _ := tc.Join(2, "Bob", "main")
+	defer rejoin.Close()
+	if err := tc.Say(2, "back"); err != ErrMuted {
+		t.Fatalf("expected mute to persist, got %v", err)
+	}
+}
+
+func TestNormalizeChannel(t *testing.T) {
+	if name, err := NormalizeChannel(" #Retro-Games "); err != nil || name != "retro-games" {
+		t.Fatalf("expected retro-games, got %q (%v)", name, err)
+	}
+	if _, err := NormalizeChannel("bad name"); err != ErrBadChannel {
+		t.Fatalf("expected ErrBadChannel, got %v", err)
+	}
+}
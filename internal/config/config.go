@@ -206,6 +206,57 @@ func mapValueToConfig(cfg *Config, v database.ConfigValue) {
 		return
 	}
 
+	// Configuration.SysOp_Chat
+	if section == "Configuration.SysOp_Chat" {
+		switch key {
+		case "Page_Start":
+			cfg.Configuration.SysOpChat.PageStart = value
+		case "Page_End":
+			cfg.Configuration.SysOpChat.PageEnd = value
+		case "Page_Seconds":
+			cfg.Configuration.SysOpChat.PageSeconds = parseIntValue(value)
+		case "Console_Port":
+			cfg.Configuration.SysOpChat.ConsolePort = parseIntValue(value)
+		case "Feedback_Area":
+			cfg.Configuration.SysOpChat.FeedbackArea = value
+		}
+		return
+	}
+
+	// Configuration.Logoff
+	if section == "Configuration.Logoff" {
+		switch key {
+		case "Display_File":
+			cfg.Configuration.Logoff.DisplayFile = value
+		case "Hangup_Delay":
+			cfg.Configuration.Logoff.HangupDelay = parseIntValue(value)
+		case "Update_Stats":
+			cfg.Configuration.Logoff.UpdateStats = parseBoolValue(value)
+		case "Flush_Last_Reads":
+			cfg.Configuration.Logoff.FlushLastReads = parseBoolValue(value)
+		}
+		return
+	}
+
+	// Configuration.Social
+	if section == "Configuration.Social" {
+		switch key {
+		case "Automessage_ACS":
+			cfg.Configuration.Social.AutomessageACS = value
+		case "Automessage_Lines":
+			cfg.Configuration.Social.AutomessageLines = parseIntValue(value)
+		case "OneLiner_Count":
+			cfg.Configuration.Social.OneLinerCount = parseIntValue(value)
+		case "OneLiner_Length":
+			cfg.Configuration.Social.OneLinerLength = parseIntValue(value)
+		case "OneLiner_ACS":
+			cfg.Configuration.Social.OneLinerACS = value
+		case "Rumor_ACS":
+			cfg.Configuration.Social.RumorACS = value
+		}
+		return
+	}
+
 	// Configuration.New_Users
 	if section == "Configuration.New_Users" {
 		if cfg.Configuration.NewUsers.RegistrationFields == nil {
@@ -224,6 +275,12 @@ func mapValueToConfig(cfg *Config, v database.ConfigValue) {
 			cfg.Configuration.NewUsers.AskFirstName = parseBoolValue(value)
 		case key == "Ask_Last_Name":
 			cfg.Configuration.NewUsers.AskLastName = parseBoolValue(value)
+		case key == "Security_Level":
+			cfg.Configuration.NewUsers.SecurityLevel = parseIntValue(value)
+		case key == "Require_Validation":
+			cfg.Configuration.NewUsers.RequireValidation = parseBoolValue(value)
+		case key == "Validated_Level":
+			cfg.Configuration.NewUsers.ValidatedLevel = parseIntValue(value)
 		case key == "RegistrationFormEnabledFields":
 			cfg.Configuration.NewUsers.RegistrationFormEnabledFields = parseListValue(value)
 		case strings.HasPrefix(key, "RegistrationField."):
@@ -351,6 +408,40 @@ func mapValueToConfig(cfg *Config, v database.ConfigValue) {
 		return
 	}
 
+	// Networking.QWKNet
+	if section == "Networking.QWKNet" {
+		switch key {
+		case "QWKNet":
+			cfg.Networking.QWKNet.Enabled = parseBoolValue(value)
+		case "Mode":
+			cfg.Networking.QWKNet.Mode = value
+		case "PacketID":
+			cfg.Networking.QWKNet.PacketID = value
+		case "HubPacketID":
+			cfg.Networking.QWKNet.HubPacketID = value
+		case "Inbound":
+			cfg.Networking.QWKNet.InboundPath = value
+		case "Outbound":
+			cfg.Networking.QWKNet.OutboundPath = value
+		case "HubNodes":
+			cfg.Networking.QWKNet.HubNodes = parseListValue(value)
+		}
+		return
+	}
+
+	// Events
+	if section == "Events" {
+		switch key {
+		case "Enabled":
+			cfg.Events.Enabled = parseBoolValue(value)
+		case "Backup_Path":
+			cfg.Events.BackupPath = value
+		case "Backup_Keep":
+			cfg.Events.BackupKeep = parseIntValue(value)
+		}
+		return
+	}
+
 	// Other.Discord
 	if section == "Other.Discord" {
 		switch key {
@@ -367,6 +458,32 @@ func mapValueToConfig(cfg *Config, v database.ConfigValue) {
 		}
 		return
 	}
+
+	// Other.Webhook
+	if section == "Other.Webhook" {
+		switch key {
+		case "Enabled":
+			cfg.Other.Webhook.Enabled = parseBoolValue(value)
+		case "URL":
+			cfg.Other.Webhook.URL = value
+		}
+		return
+	}
+
+	// Other.Notify
+	if section == "Other.Notify" {
+		switch key {
+		case "NewUsers":
+			cfg.Other.Notify.NewUsers = parseBoolValue(value)
+		case "SysOpPages":
+			cfg.Other.Notify.SysOpPages = parseBoolValue(value)
+		case "SecurityBlocks":
+			cfg.Other.Notify.SecurityBlocks = parseBoolValue(value)
+		case "PostAreas":
+			cfg.Other.Notify.PostAreas = parseListValue(value)
+		}
+		return
+	}
 }
 
 // configToValues converts Config struct to slice of database.ConfigValue
@@ -393,12 +510,42 @@ func configToValues(cfg *Config) []database.ConfigValue {
 		database.ConfigValue{Section: "Configuration.General", Key: "Timeout_Minutes", Value: strconv.Itoa(cfg.Configuration.General.TimeoutMinutes), ValueType: "int"},
 	)
 
+	// Configuration.SysOp_Chat
+	values = append(values,
+		database.ConfigValue{Section: "Configuration.SysOp_Chat", Key: "Page_Start", Value: cfg.Configuration.SysOpChat.PageStart, ValueType: "string"},
+		database.ConfigValue{Section: "Configuration.SysOp_Chat", Key: "Page_End", Value: cfg.Configuration.SysOpChat.PageEnd, ValueType: "string"},
+		database.ConfigValue{Section: "Configuration.SysOp_Chat", Key: "Page_Seconds", Value: strconv.Itoa(cfg.Configuration.SysOpChat.PageSeconds), ValueType: "int"},
+		database.ConfigValue{Section: "Configuration.SysOp_Chat", Key: "Console_Port", Value: strconv.Itoa(cfg.Configuration.SysOpChat.ConsolePort), ValueType: "int"},
+		database.ConfigValue{Section: "Configuration.SysOp_Chat", Key: "Feedback_Area", Value: cfg.Configuration.SysOpChat.FeedbackArea, ValueType: "string"},
+	)
+
+	// Configuration.Logoff
+	values = append(values,
+		database.ConfigValue{Section: "Configuration.Logoff", Key: "Display_File", Value: cfg.Configuration.Logoff.DisplayFile, ValueType: "string"},
+		database.ConfigValue{Section: "Configuration.Logoff", Key: "Hangup_Delay", Value: strconv.Itoa(cfg.Configuration.Logoff.HangupDelay), ValueType: "int"},
+		database.ConfigValue{Section: "Configuration.Logoff", Key: "Update_Stats", Value: formatBoolValue(cfg.Configuration.Logoff.UpdateStats), ValueType: "bool"},
+		database.ConfigValue{Section: "Configuration.Logoff", Key: "Flush_Last_Reads", Value: formatBoolValue(cfg.Configuration.Logoff.FlushLastReads), ValueType: "bool"},
+	)
+
+	// Configuration.Social
+	values = append(values,
+		database.ConfigValue{Section: "Configuration.Social", Key: "Automessage_ACS", Value: cfg.Configuration.Social.AutomessageACS, ValueType: "string"},
+		database.ConfigValue{Section: "Configuration.Social", Key: "Automessage_Lines", Value: strconv.Itoa(cfg.Configuration.Social.AutomessageLines), ValueType: "int"},
+		database.ConfigValue{Section: "Configuration.Social", Key: "OneLiner_Count", Value: strconv.Itoa(cfg.Configuration.Social.OneLinerCount), ValueType: "int"},
+		database.ConfigValue{Section: "Configuration.Social", Key: "OneLiner_Length", Value: strconv.Itoa(cfg.Configuration.Social.OneLinerLength), ValueType: "int"},
+		database.ConfigValue{Section: "Configuration.Social", Key: "OneLiner_ACS", Value: cfg.Configuration.Social.OneLinerACS, ValueType: "string"},
+		database.ConfigValue{Section: "Configuration.Social", Key: "Rumor_ACS", Value: cfg.Configuration.Social.RumorACS, ValueType: "string"},
+	)
+
 	// Configuration.New_Users
 	values = append(values,
 		database.ConfigValue{Section: "Configuration.New_Users", Key: "Allow_New", Value: formatBoolValue(cfg.Configuration.NewUsers.AllowNew), ValueType: "bool"},
 		database.ConfigValue{Section: "Configuration.New_Users", Key: "Ask_Location", Value: formatBoolValue(cfg.Configuration.NewUsers.AskLocation), ValueType: "bool"},
 		database.ConfigValue{Section: "Configuration.New_Users", Key: "Ask_First_Name", Value: formatBoolValue(cfg.Configuration.NewUsers.AskFirstName), ValueType: "bool"},
 		database.ConfigValue{Section: "Configuration.New_Users", Key: "Ask_Last_Name", Value: formatBoolValue(cfg.Configuration.NewUsers.AskLastName), ValueType: "bool"},
+		database.ConfigValue{Section: "Configuration.New_Users", Key: "Security_Level", Value: strconv.Itoa(cfg.Configuration.NewUsers.SecurityLevel), ValueType: "int"},
+		database.ConfigValue{Section: "Configuration.New_Users", Key: "Require_Validation", Value: formatBoolValue(cfg.Configuration.NewUsers.RequireValidation), ValueType: "bool"},
+		database.ConfigValue{Section: "Configuration.New_Users", Key: "Validated_Level", Value: strconv.Itoa(cfg.Configuration.NewUsers.ValidatedLevel), ValueType: "int"},
 		database.ConfigValue{Section: "Configuration.New_Users", Key: "RegistrationFormEnabledFields", Value: formatListValue(cfg.Configuration.NewUsers.RegistrationFormEnabledFields), ValueType: "list"},
 	)
 
@@ -509,6 +656,24 @@ func configToValues(cfg *Config) []database.ConfigValue {
 		database.ConfigValue{Section: "Servers.Security", Subsection: "Logs", Key: "SecurityLogFile", Value: cfg.Servers.Security.Logs.SecurityLogFile, ValueType: "path"},
 	)
 
+	// Networking.QWKNet
+	values = append(values,
+		database.ConfigValue{Section: "Networking.QWKNet", Key: "QWKNet", Value: formatBoolValue(cfg.Networking.QWKNet.Enabled), ValueType: "bool"},
+		database.ConfigValue{Section: "Networking.QWKNet", Key: "Mode", Value: cfg.Networking.QWKNet.Mode, ValueType: "string"},
+		database.ConfigValue{Section: "Networking.QWKNet", Key: "PacketID", Value: cfg.Networking.QWKNet.PacketID, ValueType: "string"},
+		database.ConfigValue{Section: "Networking.QWKNet", Key: "HubPacketID", Value: cfg.Networking.QWKNet.HubPacketID, ValueType: "string"},
+		database.ConfigValue{Section: "Networking.QWKNet", Key: "Inbound", Value: cfg.Networking.QWKNet.InboundPath, ValueType: "path"},
+		database.ConfigValue{Section: "Networking.QWKNet", Key: "Outbound", Value: cfg.Networking.QWKNet.OutboundPath, ValueType: "path"},
+		database.ConfigValue{Section: "Networking.QWKNet", Key: "HubNodes", Value: formatListValue(cfg.Networking.QWKNet.HubNodes), ValueType: "list"},
+	)
+
+	// Events
+	values = append(values,
+		database.ConfigValue{Section: "Events", Key: "Enabled", Value: formatBoolValue(cfg.Events.Enabled), ValueType: "bool"},
+		database.ConfigValue{Section: "Events", Key: "Backup_Path", Value: cfg.Events.BackupPath, ValueType: "path"},
+		database.ConfigValue{Section: "Events", Key: "Backup_Keep", Value: strconv.Itoa(cfg.Events.BackupKeep), ValueType: "int"},
+	)
+
 	// Other.Discord
 	values = append(values,
 		database.ConfigValue{Section: "Other.Discord", Key: "Discord", Value: formatBoolValue(cfg.Other.Discord.Enabled), ValueType: "bool"},
@@ -518,6 +683,20 @@ func configToValues(cfg *Config) []database.ConfigValue {
 		database.ConfigValue{Section: "Other.Discord", Key: "DiscordWebhookURL", Value: cfg.Other.Discord.WebhookURL, ValueType: "string"},
 	)
 
+	// Other.Webhook
+	values = append(values,
+		database.ConfigValue{Section: "Other.Webhook", Key: "Enabled", Value: formatBoolValue(cfg.Other.Webhook.Enabled), ValueType: "bool"},
+		database.ConfigValue{Section: "Other.Webhook", Key: "URL", Value: cfg.Other.Webhook.URL, ValueType: "string"},
+	)
+
+	// Other.Notify
+	values = append(values,
+		database.ConfigValue{Section: "Other.Notify", Key: "NewUsers", Value: formatBoolValue(cfg.Other.Notify.NewUsers), ValueType: "bool"},
+		database.ConfigValue{Section: "Other.Notify", Key: "SysOpPages", Value: formatBoolValue(cfg.Other.Notify.SysOpPages), ValueType: "bool"},
+		database.ConfigValue{Section: "Other.Notify", Key: "SecurityBlocks", Value: formatBoolValue(cfg.Other.Notify.SecurityBlocks), ValueType: "bool"},
+		database.ConfigValue{Section: "Other.Notify", Key: "PostAreas", Value: formatListValue(cfg.Other.Notify.PostAreas), ValueType: "list"},
+	)
+
 	return values
 }
 
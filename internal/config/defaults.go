@@ -33,12 +33,36 @@ func GetDefaultConfig() *Config {
 	cfg.Configuration.General.SysOpName = "SysOp"
 	cfg.Configuration.General.TimeoutMinutes = 3
 
+	// Configuration.SysOpChat
+	cfg.Configuration.SysOpChat.PageStart = "08:00"
+	cfg.Configuration.SysOpChat.PageEnd = "22:00"
+	cfg.Configuration.SysOpChat.PageSeconds = 30
+	cfg.Configuration.SysOpChat.ConsolePort = 2324
+	cfg.Configuration.SysOpChat.FeedbackArea = ""
+
+	// Configuration.Logoff
+	cfg.Configuration.Logoff.DisplayFile = "LOGOFF"
+	cfg.Configuration.Logoff.HangupDelay = 2
+	cfg.Configuration.Logoff.UpdateStats = true
+	cfg.Configuration.Logoff.FlushLastReads = true
+
+	// Configuration.Social
+	cfg.Configuration.Social.AutomessageACS = "20"
+	cfg.Configuration.Social.AutomessageLines = 5
+	cfg.Configuration.Social.OneLinerCount = 10
+	cfg.Configuration.Social.OneLinerLength = 60
+	cfg.Configuration.Social.OneLinerACS = ""
+	cfg.Configuration.Social.RumorACS = ""
+
 	// Configuration.NewUsers
 	cfg.Configuration.NewUsers.AllowNew = true
 	cfg.Configuration.NewUsers.AskLocation = true
 	cfg.Configuration.NewUsers.AskFirstName = true
 	cfg.Configuration.NewUsers.AskLastName = true
 	cfg.Configuration.NewUsers.AskEmail = true
+	cfg.Configuration.NewUsers.SecurityLevel = SecurityLevelRegular
+	cfg.Configuration.NewUsers.RequireValidation = false
+	cfg.Configuration.NewUsers.ValidatedLevel = SecurityLevelRegular
 	cfg.Configuration.NewUsers.RegistrationFormEnabledFields = []string{
 		"Username",
 		"Password",
@@ -111,6 +135,20 @@ func GetDefaultConfig() *Config {
 	cfg.Servers.Security.Logs.LogSecurityEvents = true
 	cfg.Servers.Security.Logs.SecurityLogFile = "security.log"
 
+	// Networking.QWKNet
+	cfg.Networking.QWKNet.Enabled = false
+	cfg.Networking.QWKNet.Mode = QWKNetModeNode
+	cfg.Networking.QWKNet.PacketID = "RETRO"
+	cfg.Networking.QWKNet.HubPacketID = ""
+	cfg.Networking.QWKNet.InboundPath = filepath.Join(cwd, "qwknet", "in")
+	cfg.Networking.QWKNet.OutboundPath = filepath.Join(cwd, "qwknet", "out")
+	cfg.Networking.QWKNet.HubNodes = []string{}
+
+	// Events
+	cfg.Events.Enabled = true
+	cfg.Events.BackupPath = filepath.Join(cwd, "data", "backups")
+	cfg.Events.BackupKeep = 7
+
 	// Other.Discord
 	cfg.Other.Discord.Enabled = false
 	cfg.Other.Discord.InviteURL = "https://discord.gg/your-invite"
@@ -118,6 +156,16 @@ func GetDefaultConfig() *Config {
 	cfg.Other.Discord.Username = "Retrograde Bot"
 	cfg.Other.Discord.WebhookURL = "https://discord.com/api/webhooks/YOUR_WEBHOOK_ID/YOUR_WEBHOOK_TOKEN"
 
+	// Other.Webhook
+	cfg.Other.Webhook.Enabled = false
+	cfg.Other.Webhook.URL = ""
+
+	// Other.Notify
+	cfg.Other.Notify.NewUsers = true
+	cfg.Other.Notify.SysOpPages = true
+	cfg.Other.Notify.SecurityBlocks = false
+	cfg.Other.Notify.PostAreas = []string{}
+
 	normalizeSecurityFileReferences(cfg)
 
 	return cfg
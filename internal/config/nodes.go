@@ -0,0 +1,423 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/database"
+)
+
+// NodeConnection tracks individual connection details
+type NodeConnection struct {
+	NodeNumber     int
+	UserID         int64 // Zero until the caller signs on
+	Username       string
+	IPAddress      string
+	ConnectTime    time.Time
+	LastActivity   time.Time
+	Connected      bool
+	Activity       string        // What the user is currently doing, shown in node listings
+	CustomActivity string        // User-set activity string (NW); overrides Activity when not empty
+	Available      bool          // Accepting pages and node messages (NA)
+	Stealth        bool          // Hidden from node listings (NT)
+	TimeLeft       int           // Minutes the caller had left, recorded at logoff
+	Messages       []NodeMessage // Node messages waiting to be shown to this node
+}
+
+// NodeMessage is a message or page sent from one node to another
+type NodeMessage struct {
+	FromNode int
+	FromUser string
+	Text     string
+	Page     bool // Chat page rather than a plain message
+	SentAt   time.Time
+}
+
+// activityFlushInterval is how often a node's last activity time is written
+// when nothing else about the node has changed
+const activityFlushInterval = time.Minute
+
+// NodeStatusStore persists node status so other processes, and the server
+// after a restart, can see who is on. database.Database satisfies it.
+type NodeStatusStore interface {
+	CreateBBSSession(session *database.BBSSessionRecord) (int64, error)
+	UpdateBBSSession(session *database.BBSSessionRecord) error
+}
+
+// NodeManager manages all active connections. It is safe for concurrent use;
+// callers only ever see copies of node state.
+type NodeManager struct {
+	mu        sync.Mutex // Guards maxNodes, nodes, persisted and store
+	maxNodes  int
+	nodes     map[int]*NodeConnection
+	persisted map[int]persistedNode // What was last written for each node
+
+	// storeMu is taken before mu while a node's status is written, so writes
+	// never overlap and each one records the node's latest state. store is
+	// set holding both locks, so either one is enough to read it.
+	storeMu  sync.Mutex
+	store    NodeStatusStore
+	sessions map[int]*database.BBSSessionRecord // Open bbs_sessions rows by node
+}
+
+// persistedNode is the part of a node's state kept in its bbs_sessions row
+type persistedNode struct {
+	userID   int64
+	activity string
+	timeLeft int
+	at       time.Time
+}
+
+// NewNodeManager creates a node manager for maxNodes nodes
+func NewNodeManager(maxNodes int) *NodeManager {
+	return &NodeManager{
+		maxNodes:  maxNodes,
+		nodes:     make(map[int]*NodeConnection),
+		persisted: make(map[int]persistedNode),
+		sessions:  make(map[int]*database.BBSSessionRecord),
+	}
+}
+
+// SetStore sets where node status is persisted. Only signed-on callers are
+// recorded, since each bbs_sessions row belongs to a user.
+func (nm *NodeManager) SetStore(store NodeStatusStore) {
+	nm.storeMu.Lock()
+	defer nm.storeMu.Unlock()
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	nm.store = store
+}
+
+// AssignNode reserves the lowest free node for a connection and returns its
+// number, or -1 if all nodes are in use. Finding and claiming the node is a
+// single step, so two connections can never be given the same node.
+func (nm *NodeManager) AssignNode(conn net.Conn, username string) int {
+	ipAddr := conn.RemoteAddr().String()
+	if tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		ipAddr = tcpAddr.IP.String()
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	for nodeID := 1; nodeID <= nm.maxNodes; nodeID++ {
+		if existing, exists := nm.nodes[nodeID]; exists && existing.Connected {
+			continue
+		}
+		now := time.Now()
+		nm.nodes[nodeID] = &NodeConnection{
+			NodeNumber:   nodeID,
+			Username:     username,
+			IPAddress:    ipAddr,
+			ConnectTime:  now,
+			LastActivity: now,
+			Connected:    true,
+			Activity:     "Logging in.",
+			Available:    true,
+		}
+		return nodeID
+	}
+	return -1 // No nodes available
+}
+
+// SetUser records who signed on to a node and opens its bbs_sessions row
+func (nm *NodeManager) SetUser(nodeID int, userID int64, username string) {
+	nm.change(nodeID, func(conn *NodeConnection) {
+		conn.UserID = userID
+		conn.Username = username
+	})
+}
+
+// ReleaseNode frees a node and returns the state it was released in. The
+// boolean is false if the node was not in use.
+func (nm *NodeManager) ReleaseNode(nodeID int) (NodeConnection, bool) {
+	nm.storeMu.Lock()
+	defer nm.storeMu.Unlock()
+
+	nm.mu.Lock()
+	conn, exists := nm.nodes[nodeID]
+	if !exists || !conn.Connected {
+		nm.mu.Unlock()
+		return NodeConnection{}, false
+	}
+	released := conn.snapshot()
+	conn.Connected = false
+	conn.Messages = nil
+	ended := conn.snapshot()
+	delete(nm.persisted, nodeID)
+	nm.mu.Unlock()
+
+	nm.persistLocked(ended)
+	return released, true
+}
+
+// Snapshot returns a copy of a connected node's state
+func (nm *NodeManager) Snapshot(nodeID int) (NodeConnection, bool) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	conn, exists := nm.nodes[nodeID]
+	if !exists || !conn.Connected {
+		return NodeConnection{}, false
+	}
+	return conn.snapshot(), true
+}
+
+// ActiveConnections returns copies of all connected nodes ordered by node number
+func (nm *NodeManager) ActiveConnections() []NodeConnection {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	var conns []NodeConnection
+	for _, conn := range nm.nodes {
+		if conn.Connected {
+			conns = append(conns, conn.snapshot())
+		}
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].NodeNumber < conns[j].NodeNumber })
+	return conns
+}
+
+// GetActiveNodes returns the active node numbers in ascending order
+func (nm *NodeManager) GetActiveNodes() []int {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	var active []int
+	for nodeNum, conn := range nm.nodes {
+		if conn.Connected {
+			active = append(active, nodeNum)
+		}
+	}
+	sort.Ints(active)
+	return active
+}
+
+// IsNodeActive returns whether a node is currently active
+func (nm *NodeManager) IsNodeActive(nodeNum int) bool {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	conn, exists := nm.nodes[nodeNum]
+	return exists && conn.Connected
+}
+
+// GetNodeCount returns the total number of configured nodes
+func (nm *NodeManager) GetNodeCount() int {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	return nm.maxNodes
+}
+
+// GetActiveNodeCount returns the number of currently active nodes
+func (nm *NodeManager) GetActiveNodeCount() int {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	count := 0
+	for _, conn := range nm.nodes {
+		if conn.Connected {
+			count++
+		}
+	}
+	return count
+}
+
+// UpdateActivity updates the last activity time for a node
+func (nm *NodeManager) UpdateActivity(nodeID int) {
+	nm.change(nodeID, func(conn *NodeConnection) {})
+}
+
+// SetActivity records what the user on a node is currently doing
+func (nm *NodeManager) SetActivity(nodeID int, activity string) {
+	nm.change(nodeID, func(conn *NodeConnection) {
+		conn.Activity = activity
+	})
+}
+
+// LogOff records that the caller on a node is logging off and how many
+// minutes they had left. The bbs_sessions row is closed when the node is
+// released.
+func (nm *NodeManager) LogOff(nodeID int, timeLeft int) {
+	nm.change(nodeID, func(conn *NodeConnection) {
+		conn.Activity = "Logging off."
+		conn.CustomActivity = ""
+		conn.TimeLeft = timeLeft
+	})
+}
+
+// SetCustomActivity sets (or clears, when empty) a node's custom activity string
+func (nm *NodeManager) SetCustomActivity(nodeID int, activity string) {
+	nm.change(nodeID, func(conn *NodeConnection) {
+		conn.CustomActivity = activity
+	})
+}
+
+// ToggleAvailable flips a node's page availability and returns the new state
+func (nm *NodeManager) ToggleAvailable(nodeID int) bool {
+	available := false
+	nm.change(nodeID, func(conn *NodeConnection) {
+		conn.Available = !conn.Available
+		available = conn.Available
+	})
+	return available
+}
+
+// SetStealth hides or shows a node in node listings
+func (nm *NodeManager) SetStealth(nodeID int, stealth bool) {
+	nm.change(nodeID, func(conn *NodeConnection) {
+		conn.Stealth = stealth
+	})
+}
+
+// SendMessage queues a message for delivery to another node. Unavailable
+// nodes only accept messages when force is set (e.g. sent by a sysop).
+func (nm *NodeManager) SendMessage(toNode int, msg NodeMessage, force bool) error {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	conn, exists := nm.nodes[toNode]
+	if !exists || !conn.Connected {
+		return fmt.Errorf("node %d is not active", toNode)
+	}
+	if !conn.Available && !force {
+		return fmt.Errorf("node %d is not available", toNode)
+	}
+	if msg.SentAt.IsZero() {
+		msg.SentAt = time.Now()
+	}
+	conn.Messages = append(conn.Messages, msg)
+	return nil
+}
+
+// TakeMessages returns and clears the pending messages for a node
+func (nm *NodeManager) TakeMessages(nodeID int) []NodeMessage {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	conn, exists := nm.nodes[nodeID]
+	if !exists || len(conn.Messages) == 0 {
+		return nil
+	}
+	msgs := conn.Messages
+	conn.Messages = nil
+	return msgs
+}
+
+// DisplayActivity returns the activity string shown to other users
+func (c *NodeConnection) DisplayActivity() string {
+	if c.CustomActivity != "" {
+		return c.CustomActivity
+	}
+	return c.Activity
+}
+
+// snapshot copies a connection without its message queue
+func (c *NodeConnection) snapshot() NodeConnection {
+	s := *c
+	s.Messages = nil
+	return s
+}
+
+// change applies fn to a connected node and stamps its activity time. The
+// node is only written when its user, activity or time left changed, or its
+// activity time has not been written for activityFlushInterval.
+func (nm *NodeManager) change(nodeID int, fn func(conn *NodeConnection)) {
+	nm.mu.Lock()
+	conn, exists := nm.nodes[nodeID]
+	if !exists || !conn.Connected {
+		nm.mu.Unlock()
+		return
+	}
+	fn(conn)
+	conn.LastActivity = time.Now()
+	stale := nm.staleLocked(conn)
+	nm.mu.Unlock()
+
+	if stale {
+		nm.persistNode(nodeID)
+	}
+}
+
+// staleLocked reports whether a node's row is behind its state. The caller
+// holds mu.
+func (nm *NodeManager) staleLocked(conn *NodeConnection) bool {
+	if nm.store == nil {
+		return false
+	}
+	last, ok := nm.persisted[conn.NodeNumber]
+	return !ok || last.userID != conn.UserID || last.activity != conn.DisplayActivity() ||
+		last.timeLeft != conn.TimeLeft || conn.LastActivity.Sub(last.at) >= activityFlushInterval
+}
+
+// persistNode writes a node's latest state, unless another write already has
+func (nm *NodeManager) persistNode(nodeID int) {
+	nm.storeMu.Lock()
+	defer nm.storeMu.Unlock()
+
+	nm.mu.Lock()
+	conn, exists := nm.nodes[nodeID]
+	if !exists || !conn.Connected || !nm.staleLocked(conn) {
+		nm.mu.Unlock()
+		return
+	}
+	snap := conn.snapshot()
+	nm.persisted[nodeID] = persistedNode{
+		userID:   snap.UserID,
+		activity: snap.DisplayActivity(),
+		timeLeft: snap.TimeLeft,
+		at:       snap.LastActivity,
+	}
+	nm.mu.Unlock()
+
+	nm.persistLocked(snap)
+}
+
+// persistLocked writes a node's status to its bbs_sessions row, opening the
+// row when a user signs on and closing it when the node is released. The
+// caller holds storeMu.
+func (nm *NodeManager) persistLocked(conn NodeConnection) {
+	if nm.store == nil {
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	record := nm.sessions[conn.NodeNumber]
+
+	// A different user on the node means the old row was never closed
+	if record != nil && (!conn.Connected || record.UserID != conn.UserID) {
+		record.Status = "ended"
+		record.LastActivity = now
+		if err := nm.store.UpdateBBSSession(record); err != nil {
+			fmt.Printf("Warning: failed to end session for node %d: %v\n", conn.NodeNumber, err)
+		}
+		delete(nm.sessions, conn.NodeNumber)
+		record = nil
+	}
+	if !conn.Connected || conn.UserID <= 0 {
+		return
+	}
+
+	if record == nil {
+		record = &database.BBSSessionRecord{
+			UserID:       conn.UserID,
+			NodeNumber:   conn.NodeNumber,
+			SessionStart: conn.ConnectTime.UTC().Format(time.RFC3339Nano),
+			Status:       "active",
+			IPAddress:    sql.NullString{String: conn.IPAddress, Valid: conn.IPAddress != ""},
+		}
+	}
+	record.LastActivity = now
+	record.TimeLeft = conn.TimeLeft
+	record.Activity = sql.NullString{String: conn.DisplayActivity(), Valid: conn.DisplayActivity() != ""}
+
+	if record.ID == 0 {
+		id, err := nm.store.CreateBBSSession(record)
+		if err != nil {
+			fmt.Printf("Warning: failed to record session for node %d: %v\n", conn.NodeNumber, err)
+			return
+		}
+		record.ID = id
+		nm.sessions[conn.NodeNumber] = record
+		return
+	}
+	if err := nm.store.UpdateBBSSession(record); err != nil {
+		fmt.Printf("Warning: failed to update session for node %d: %v\n", conn.NodeNumber, err)
+	}
+}
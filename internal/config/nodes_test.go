@@ -0,0 +1,263 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/database"
+)
+
+// testConn gives AssignNode a remote address without a socket
+type testConn struct {
+	net.Conn
+	addr *net.TCPAddr
+}
+
+func (c testConn) RemoteAddr() net.Addr { return c.addr }
+
+func newTestConn(i int) net.Conn {
+	return testConn{addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, byte(i)), Port: 2323}}
+}
+
+// memoryStore records bbs_sessions writes in memory
+type memoryStore struct {
+	mu       sync.Mutex
+	nextID   int64
+	writes   int
+	sessions map[int64]database.BBSSessionRecord
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{sessions: make(map[int64]database.BBSSessionRecord)}
+}
+
+func (s *memoryStore) CreateBBSSession(session *database.BBSSessionRecord) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	s.writes++
+	record := *session
+	record.ID = s.nextID
+	s.sessions[record.ID] = record
+	return record.ID, nil
+}
+
+func (s *memoryStore) UpdateBBSSession(session *database.BBSSessionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[session.ID]; !ok {
+		return fmt.Errorf("no session %d", session.ID)
+	}
+	s.writes++
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+func (s *memoryStore) active() []database.BBSSessionRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []database.BBSSessionRecord
+	for _, r := range s.sessions {
+		if r.Status == "active" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func TestAssignNodeNeverDuplicatesUnderContention(t *testing.T) {
+	const maxNodes = 8
+	nm := NewNodeManager(maxNodes)
+
+	var wg sync.WaitGroup
+	results := make(chan int, 64)
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results <- nm.AssignNode(newTestConn(i), "Guest")
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[int]bool)
+	rejected := 0
+	for node := range results {
+		if node == -1 {
+			rejected++
+			continue
+		}
+		if seen[node] {
+			t.Fatalf("node %d assigned twice", node)
+		}
+		seen[node] = true
+	}
+	if len(seen) != maxNodes || rejected != 64-maxNodes {
+		t.Fatalf("expected %d assigned and %d rejected, got %d and %d", maxNodes, 64-maxNodes, len(seen), rejected)
+	}
+	if got := nm.GetActiveNodeCount(); got != maxNodes {
+		t.Fatalf("expected %d active nodes, got %d", maxNodes, got)
+	}
+}
+
+func TestReleaseNodeFreesLowestNode(t *testing.T) {
+	nm := NewNodeManager(3)
+	for i := 1; i <= 3; i++ {
+		nm.AssignNode(newTestConn(i), "Guest")
+	}
+
+	conn, released := nm.ReleaseNode(2)
+	if !released || conn.NodeNumber != 2 || conn.IPAddress != "10.0.0.2" {
+		t.Fatalf("unexpected release result %+v, %v", conn, released)
+	}
+	if _, released := nm.ReleaseNode(2); released {
+		t.Fatalf("expected second release of node 2 to be a no-op")
+	}
+	if nm.IsNodeActive(2) {
+		t.Fatalf("expected node 2 to be inactive")
+	}
+	if node := nm.AssignNode(newTestConn(9), "Guest"); node != 2 {
+		t.Fatalf("expected freed node 2 to be reused, got %d", node)
+	}
+}
+
+func TestSnapshotIsACopy(t *testing.T) {
+	nm := NewNodeManager(2)
+	node := nm.AssignNode(newTestConn(1), "Guest")
+	nm.SetUser(node, 42, "Alice")
+	if err := nm.SendMessage(node, NodeMessage{FromNode: 2, Text: "hi"}, false); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	snap, ok := nm.Snapshot(node)
+	if !ok || snap.Username != "Alice" || snap.UserID != 42 {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+	if snap.Messages != nil {
+		t.Fatalf("snapshot should not expose the message queue")
+	}
+
+	snap.Username = "Mallory"
+	if again, _ := nm.Snapshot(node); again.Username != "Alice" {
+		t.Fatalf("modifying a snapshot changed the node: %q", again.Username)
+	}
+	if msgs := nm.TakeMessages(node); len(msgs) != 1 {
+		t.Fatalf("expected 1 queued message, got %d", len(msgs))
+	}
+	if _, ok := nm.Snapshot(99); ok {
+		t.Fatalf("expected no snapshot for an unknown node")
+	}
+}
+
+func TestNodeStatusPersistence(t *testing.T) {
+	store := newMemoryStore()
+	nm := NewNodeManager(2)
+	nm.SetStore(store)
+
+	node := nm.AssignNode(newTestConn(1), "Guest")
+	nm.SetActivity(node, "Logging in.")
+	if got := store.active(); len(got) != 0 {
+		t.Fatalf("guests should not be recorded, got %d sessions", len(got))
+	}
+
+	nm.SetUser(node, 7, "Bob")
+	nm.SetActivity(node, "Reading messages.")
+	active := store.active()
+	if len(active) != 1 {
+		t.Fatalf("expected 1 active session, got %d", len(active))
+	}
+	if active[0].UserID != 7 || active[0].NodeNumber != node || active[0].Activity.String != "Reading messages." || active[0].IPAddress.String != "10.0.0.1" {
+		t.Fatalf("unexpected session %+v", active[0])
+	}
+
+	nm.LogOff(node, 25)
+	if active := store.active(); len(active) != 1 || active[0].TimeLeft != 25 || active[0].Activity.String != "Logging off." {
+		t.Fatalf("logoff not recorded: %+v", active)
+	}
+
+	nm.ReleaseNode(node)
+	if got := store.active(); len(got) != 0 {
+		t.Fatalf("expected session to end on release, %d still active", len(got))
+	}
+	if len(store.sessions) != 1 {
+		t.Fatalf("expected one session row, got %d", len(store.sessions))
+	}
+}
+
+func TestNodeStatusWritesOnlyChanges(t *testing.T) {
+	store := newMemoryStore()
+	nm := NewNodeManager(1)
+	nm.SetStore(store)
+
+	node := nm.AssignNode(newTestConn(1), "Guest")
+	nm.SetUser(node, 7, "Bob")
+	nm.SetActivity(node, "Main menu.")
+	writes := store.writes
+
+	before, _ := nm.Snapshot(node)
+	time.Sleep(time.Millisecond)
+	nm.SetActivity(node, "Main menu.")
+	nm.UpdateActivity(node)
+	nm.ToggleAvailable(node)
+	nm.SetStealth(node, true)
+	if store.writes != writes {
+		t.Fatalf("expected no writes for unchanged status, got %d more", store.writes-writes)
+	}
+	after, _ := nm.Snapshot(node)
+	if !after.LastActivity.After(before.LastActivity) || after.Available || !after.Stealth {
+		t.Fatalf("expected activity time, availability and stealth updated in memory, got %+v", after)
+	}
+
+	nm.SetActivity(node, "Reading messages.")
+	if store.writes != writes+1 {
+		t.Fatalf("expected a new activity to be written, got %d writes", store.writes-writes)
+	}
+	nm.ReleaseNode(node)
+}
+
+func TestNodeManagerConcurrentUse(t *testing.T) {
+	const maxNodes = 6
+	store := newMemoryStore()
+	nm := NewNodeManager(maxNodes)
+	nm.SetStore(store)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 24; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for round := 0; round < 20; round++ {
+				node := nm.AssignNode(newTestConn(i), "Guest")
+				if node == -1 {
+					continue
+				}
+				nm.SetUser(node, int64(i+1), fmt.Sprintf("user%d", i))
+				nm.SetActivity(node, "Main menu.")
+				nm.SetCustomActivity(node, "busy")
+				nm.ToggleAvailable(node)
+				nm.SetStealth(node, round%2 == 0)
+				nm.SendMessage((node%maxNodes)+1, NodeMessage{FromNode: node, Text: "ping"}, true)
+				nm.TakeMessages(node)
+				nm.UpdateActivity(node)
+				for _, conn := range nm.ActiveConnections() {
+					_ = conn.DisplayActivity()
+				}
+				nm.GetActiveNodes()
+				nm.Snapshot(node)
+				nm.ReleaseNode(node)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := nm.GetActiveNodeCount(); got != 0 {
+		t.Fatalf("expected all nodes released, %d still active", got)
+	}
+	if got := store.active(); len(got) != 0 {
+		t.Fatalf("expected all sessions ended, %d still active", len(got))
+	}
+}
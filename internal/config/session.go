@@ -58,3 +58,17 @@ func (session *TelnetSession) GetCurrentMessageAreaName() string {
 	}
 	return session.CurrentMessageArea.Name
 }
+
+// MarkRead records that a message was read this call. Only the highest
+// message per area is kept; it is saved to user_lastread at logoff.
+func (session *TelnetSession) MarkRead(area string, msgNum int) {
+	if area == "" {
+		return
+	}
+	if session.LastRead == nil {
+		session.LastRead = make(map[string]int)
+	}
+	if msgNum > session.LastRead[area] {
+		session.LastRead[area] = msgNum
+	}
+}
@@ -26,10 +26,13 @@ type Config struct {
 
 // ConfigurationSection holds all Configuration.* settings
 type ConfigurationSection struct {
-	Paths    PathsConfig
-	General  GeneralConfig
-	NewUsers NewUsersConfig
-	Auth     AuthConfig
+	Paths     PathsConfig
+	General   GeneralConfig
+	NewUsers  NewUsersConfig
+	Auth      AuthConfig
+	SysOpChat SysOpChatConfig
+	Logoff    LogoffConfig
+	Social    SocialConfig
 }
 
 // PathsConfig holds system paths
@@ -52,6 +55,33 @@ type GeneralConfig struct {
 	TimeoutMinutes int
 }
 
+// SysOpChatConfig holds sysop paging (OC) and console settings
+type SysOpChatConfig struct {
+	PageStart    string // HH:MM the sysop becomes available; blank or equal to PageEnd means always
+	PageEnd      string // HH:MM the sysop stops taking pages (may wrap past midnight)
+	PageSeconds  int    // How long a page rings before offering feedback instead
+	ConsolePort  int    // Loopback port for the local sysop console; 0 disables it
+	FeedbackArea string // Message area file for feedback left when the sysop is unavailable
+}
+
+// LogoffConfig holds the events run when a caller logs off or drops carrier
+type LogoffConfig struct {
+	DisplayFile    string // Display file shown by G and HC; blank shows a plain goodbye
+	HangupDelay    int    // Seconds the goodbye stays on screen before hanging up
+	UpdateStats    bool   // Count time online in the caller's and daily stats and record the logoff time
+	FlushLastReads bool   // Save the message pointers the caller advanced this call
+}
+
+// SocialConfig holds the automessage, one-liner wall and rumor settings
+type SocialConfig struct {
+	AutomessageACS   string // ACS needed to write the automessage (UW)
+	AutomessageLines int    // Longest automessage, in lines
+	OneLinerCount    int    // One-liners shown on the wall
+	OneLinerLength   int    // Longest one-liner a caller can write
+	OneLinerACS      string // ACS needed to add to the wall; blank for everyone
+	RumorACS         string // ACS needed to add a rumor; blank for everyone
+}
+
 type RegistrationFieldConfig struct {
 	Enabled  bool
 	Required bool
@@ -69,6 +99,9 @@ type NewUsersConfig struct {
 	AskFirstName                  bool
 	AskLastName                   bool
 	AskEmail                      bool
+	SecurityLevel                 int  // Level new accounts are created at
+	RequireValidation             bool // Queue new accounts for sysop validation
+	ValidatedLevel                int  // Level a validated account is raised to
 	RegistrationFormEnabledFields []string
 	RegistrationFields            map[string]RegistrationFieldConfig
 	FormLayout                    map[string]FormLayoutConfig
@@ -150,9 +183,26 @@ type SecurityLogsConfig struct {
 
 // NetworkingSection holds networking configuration
 type NetworkingSection struct {
-	// Future network settings
+	QWKNet QWKNetConfig
+}
+
+// QWKNetConfig holds QWK network (QWKnet) packet exchange settings
+type QWKNetConfig struct {
+	Enabled      bool
+	Mode         string   // "node" (exchange with an upstream hub) or "hub" (serve downstream systems)
+	PacketID     string   // This system's QWK packet ID (BBSID), up to 8 characters
+	HubPacketID  string   // Upstream hub packet ID when running as a node
+	InboundPath  string   // Where hub QWK packets (node) or node REP packets (hub) arrive
+	OutboundPath string   // Where REP packets (node) or per-node QWK packets (hub) are written
+	HubNodes     []string // Downstream BBS accounts (by packet ID) served when running as a hub
 }
 
+// QWKNet modes
+const (
+	QWKNetModeNode = "node"
+	QWKNetModeHub  = "hub"
+)
+
 // EditorsSection holds editor configurations
 type EditorsSection struct {
 	UserEditor  EditorConfig
@@ -160,9 +210,10 @@ type EditorsSection struct {
 	MessageBase EditorConfig
 	FileBase    EditorConfig
 	Menus       EditorConfig
-	Logon       EditorConfig // future: Login Events
-	Logoff      EditorConfig // future: Logout Events
-	Timed       EditorConfig // future: Timed Events
+	Logon       EditorConfig // Logon Sequence
+	Logoff      EditorConfig // Logoff Sequence
+	Timed       EditorConfig // Timed Events
+	Voting      EditorConfig // Voting Booth
 }
 
 // EditorConfig holds configuration for a specific editor
@@ -170,14 +221,19 @@ type EditorConfig struct {
 	// Future editor settings
 }
 
-// EventsSection holds event configurations
+// EventsSection holds timed event scheduler settings. The events themselves
+// live in the timed_events table.
 type EventsSection struct {
-	// Future event settings
+	Enabled    bool   // Run timed events inside the server
+	BackupPath string // Directory database backups are written to
+	BackupKeep int    // Number of backups to keep; 0 keeps them all
 }
 
 // OtherSection holds miscellaneous settings
 type OtherSection struct {
 	Discord DiscordConfig
+	Webhook WebhookConfig
+	Notify  NotifyConfig
 }
 
 // DiscordConfig holds Discord integration settings
@@ -189,6 +245,21 @@ type DiscordConfig struct {
 	WebhookURL string
 }
 
+// WebhookConfig holds a generic JSON webhook that receives the same
+// notifications as Discord
+type WebhookConfig struct {
+	Enabled bool
+	URL     string
+}
+
+// NotifyConfig selects which BBS events are sent to Discord and the webhook
+type NotifyConfig struct {
+	NewUsers       bool     // New user registrations
+	SysOpPages     bool     // Callers paging the sysop (OC)
+	SecurityBlocks bool     // Connections refused by the security checks
+	PostAreas      []string // Message area files whose new posts are announced
+}
+
 // Struct to hold details about the program's initial state
 type ProgramState struct {
 	TerminalHeight int
@@ -196,12 +267,39 @@ type ProgramState struct {
 	StartTime      time.Time
 }
 
+// Terminal character sets. Text inside the BBS is CP437; sessions using UTF-8
+// have it transcoded on the way in and out.
+const (
+	EncodingCP437 = "cp437"
+	EncodingUTF8  = "utf8"
+
+	// User details holding a caller's terminal overrides
+	EncodingPreference  = "encoding"
+	EmulationPreference = "emulation"
+	ICEColorsPreference = "ice_colors"
+	WidthPreference     = "screen_width"
+	HeightPreference    = "screen_height"
+
+	// User preferences for how menus and the message editor behave
+	HotkeysPreference = "hotkeys"
+	ExpertPreference  = "expert"
+	EditorPreference  = "editor"
+
+	// User details holding call statistics
+	LastLogoffStat = "last_logoff"
+
+	// User details shown on a caller's profile
+	TaglineDetail   = "tagline"
+	SignatureDetail = "signature"
+)
+
 // TelnetSession holds connection state for each telnet user
 type TelnetSession struct {
 	Alias              string
 	SecurityLevel      int
 	TimeLeft           int
 	StartTime          time.Time
+	PreviousLogin      time.Time // When the caller last logged in before this call; zero on the first call
 	LastActivity       time.Time
 	NodeNumber         int
 	IPAddress          string
@@ -209,24 +307,16 @@ type TelnetSession struct {
 	Conn               net.Conn              // Add connection reference for timeout handling
 	Width              int                   // Terminal width from NAWS negotiation
 	Height             int                   // Terminal height from NAWS negotiation
+	WidthOverride      int                   // Width the caller set in their settings; 0 follows NAWS
+	HeightOverride     int                   // Height the caller set in their settings; 0 follows NAWS
+	ICEColors          bool                  // Terminal shows blink as bright backgrounds (CSI ?33h)
+	Encoding           string                // Terminal character set; empty means EncodingCP437
+	Emulation          string                // ansi, rip or ascii from the connect handshake or the user's override
+	TerminalType       string                // Terminal name reported through telnet TTYPE
 	CurrentMessageArea *database.MessageArea // Current message area for reading/posting
-}
-
-// NodeConnection tracks individual connection details
-type NodeConnection struct {
-	NodeNumber   int
-	Username     string
-	IPAddress    string
-	ConnectTime  time.Time
-	LastActivity time.Time
-	Connected    bool
-}
-
-// NodeManager manages all active connections
-type NodeManager struct {
-	MaxNodes    int
-	Connections map[int]*NodeConnection
-	NextNode    int
+	LastRead           map[string]int        // Highest message read this call, by message area file
+	HotkeysOff         bool                  // Hotkey menus read a whole line instead
+	Expert             bool                  // Menus are not drawn on entry, only their prompt
 }
 
 // LogEntry represents a log entry for the system
@@ -293,123 +383,3 @@ type SecurityEvent struct {
 	Details   string
 	Action    string // "REJECT", "ALLOW", "LOG"
 }
-
-// NodeManager methods
-
-// GetAvailableNode returns the next available node number, or -1 if all nodes are full
-func (nm *NodeManager) GetAvailableNode() int {
-	for i := 1; i <= nm.MaxNodes; i++ {
-		if conn, exists := nm.Connections[i]; !exists || !conn.Connected {
-			return i
-		}
-	}
-	return -1
-}
-
-// AddConnection adds a connection to the node manager
-func (nm *NodeManager) AddConnection(nodeNum int, conn net.Conn, username string) {
-	ipAddr := conn.RemoteAddr().String()
-	if tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
-		ipAddr = tcpAddr.IP.String()
-	}
-
-	nm.Connections[nodeNum] = &NodeConnection{
-		NodeNumber:   nodeNum,
-		Username:     username,
-		IPAddress:    ipAddr,
-		ConnectTime:  time.Now(),
-		LastActivity: time.Now(),
-		Connected:    true,
-	}
-}
-
-// RemoveConnection removes a connection from the node manager
-func (nm *NodeManager) RemoveConnection(nodeNum int) {
-	if conn, exists := nm.Connections[nodeNum]; exists {
-		conn.Connected = false
-	}
-}
-
-// GetConnection returns the connection for a specific node
-func (nm *NodeManager) GetConnection(nodeNum int) *NodeConnection {
-	return nm.Connections[nodeNum]
-}
-
-// GetActiveNodes returns a list of active node numbers
-func (nm *NodeManager) GetActiveNodes() []int {
-	var active []int
-	for nodeNum, conn := range nm.Connections {
-		if conn.Connected {
-			active = append(active, nodeNum)
-		}
-	}
-	return active
-}
-
-// IsNodeActive returns whether a node is currently active
-func (nm *NodeManager) IsNodeActive(nodeNum int) bool {
-	if conn, exists := nm.Connections[nodeNum]; exists {
-		return conn.Connected
-	}
-	return false
-}
-
-// GetNodeCount returns the total number of configured nodes
-func (nm *NodeManager) GetNodeCount() int {
-	return nm.MaxNodes
-}
-
-// AssignNode assigns a node to a connection and returns the node number
-func (nm *NodeManager) AssignNode(conn net.Conn, username string) int {
-	nodeID := nm.GetAvailableNode()
-	if nodeID == -1 {
-		return -1 // No nodes available
-	}
-
-	ipAddr := conn.RemoteAddr().String()
-	if tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
-		ipAddr = tcpAddr.IP.String()
-	}
-
-	nm.Connections[nodeID] = &NodeConnection{
-		NodeNumber:   nodeID,
-		Username:     username,
-		IPAddress:    ipAddr,
-		ConnectTime:  time.Now(),
-		LastActivity: time.Now(),
-		Connected:    true,
-	}
-
-	return nodeID
-}
-
-// ReleaseNode releases a node and marks it as disconnected
-func (nm *NodeManager) ReleaseNode(nodeID int) {
-	if conn, exists := nm.Connections[nodeID]; exists {
-		conn.Connected = false
-	}
-}
-
-// UpdateActivity updates the last activity time for a node
-func (nm *NodeManager) UpdateActivity(nodeID int) {
-	if conn, exists := nm.Connections[nodeID]; exists && conn.Connected {
-		conn.LastActivity = time.Now()
-	}
-}
-
-// GetNodeInfo returns information about a specific node
-func (nm *NodeManager) GetNodeInfo(nodeID int) (*NodeConnection, bool) {
-	conn, exists := nm.Connections[nodeID]
-	return conn, exists && conn.Connected
-}
-
-// GetActiveNodeCount returns the number of currently active nodes
-func (nm *NodeManager) GetActiveNodeCount() int {
-	count := 0
-	for _, conn := range nm.Connections {
-		if conn.Connected {
-			count++
-		}
-	}
-	return count
-}
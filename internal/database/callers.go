@@ -0,0 +1,201 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// userStatColumns and dailyStatColumns map counter names to their columns
+var (
+	userStatColumns = map[string]string{
+		StatCalls:     "calls",
+		StatPosts:     "posts",
+		StatUploads:   "uploads",
+		StatDownloads: "downloads",
+		StatMinutes:   "minutes",
+	}
+	dailyStatColumns = map[string]string{
+		StatCalls:     "calls",
+		StatNewUsers:  "new_users",
+		StatPosts:     "posts",
+		StatUploads:   "uploads",
+		StatDownloads: "downloads",
+		StatMinutes:   "minutes",
+	}
+)
+
+// RecordCallLogon adds a call to the caller history
+func (s *SQLiteDB) RecordCallLogon(call *CallerRecord) (int64, error) {
+	if call == nil {
+		return 0, fmt.Errorf("caller record cannot be nil")
+	}
+	if call.LogonAt == "" {
+		call.LogonAt = time.Now().Format(time.RFC3339)
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO callers (user_id, username, location, node, ip_address, logon_at, new_user)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, call.UserID, call.Username, call.Location, call.Node, call.IPAddress, call.LogonAt, call.NewUser)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record logon: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get caller ID: %w", err)
+	}
+	call.ID = id
+	return id, nil
+}
+
+// RecordCallLogoff completes a call in the caller history
+func (s *SQLiteDB) RecordCallLogoff(callID int64, minutes int) error {
+	_, err := s.db.Exec(`
+		UPDATE callers SET logoff_at = ?, minutes = ? WHERE id = ?
+	`, time.Now().Format(time.RFC3339), minutes, callID)
+	if err != nil {
+		return fmt.Errorf("failed to record logoff: %w", err)
+	}
+	return nil
+}
+
+// GetCallers returns up to limit calls made since the given RFC3339 time
+// (all calls when since is empty), newest first
+func (s *SQLiteDB) GetCallers(since string, limit int) ([]CallerRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id, username, location, node, ip_address, logon_at, logoff_at, minutes, new_user
+		FROM callers
+		WHERE logon_at >= ?
+		ORDER BY logon_at DESC, id DESC
+		LIMIT ?
+	`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query callers: %w", err)
+	}
+	defer rows.Close()
+
+	var calls []CallerRecord
+	for rows.Next() {
+		var call CallerRecord
+		var newUserInt int
+		if err := rows.Scan(&call.ID, &call.UserID, &call.Username, &call.Location, &call.Node, &call.IPAddress,
+			&call.LogonAt, &call.LogoffAt, &call.Minutes, &newUserInt); err != nil {
+			return nil, fmt.Errorf("failed to scan caller: %w", err)
+		}
+		call.NewUser = newUserInt != 0
+		calls = append(calls, call)
+	}
+
+	return calls, rows.Err()
+}
+
+// IncrementUserStat adds n to one of a user's counters
+func (s *SQLiteDB) IncrementUserStat(userID int64, stat string, n int) error {
+	column, ok := userStatColumns[stat]
+	if !ok {
+		return fmt.Errorf("unknown user stat %q", stat)
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO user_stats (user_id, `+column+`) VALUES (?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET `+column+` = `+column+` + excluded.`+column+`
+	`, userID, n)
+	if err != nil {
+		return fmt.Errorf("failed to update user stat %s: %w", stat, err)
+	}
+	return nil
+}
+
+// GetUserStats returns a user's counters, all zero for a user without any
+func (s *SQLiteDB) GetUserStats(userID int64) (*UserStats, error) {
+	stats := &UserStats{UserID: userID}
+	err := s.db.QueryRow(`
+		SELECT
+			COALESCE((SELECT calls FROM user_stats WHERE user_id = ?), 0),
+			COALESCE((SELECT posts FROM user_stats WHERE user_id = ?), 0),
+			COALESCE((SELECT uploads FROM user_stats WHERE user_id = ?), 0),
+			COALESCE((SELECT downloads FROM user_stats WHERE user_id = ?), 0),
+			COALESCE((SELECT minutes FROM user_stats WHERE user_id = ?), 0)
+	`, userID, userID, userID, userID, userID).Scan(&stats.Calls, &stats.Posts, &stats.Uploads, &stats.Downloads, &stats.Minutes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetTopUsers returns the limit users with the highest count for stat,
+// leaving out users whose count is zero
+func (s *SQLiteDB) GetTopUsers(stat string, limit int) ([]TopUser, error) {
+	column, ok := userStatColumns[stat]
+	if !ok {
+		return nil, fmt.Errorf("unknown user stat %q", stat)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT s.user_id, u.username, s.`+column+` AS value
+		FROM user_stats s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.`+column+` > 0
+		ORDER BY value DESC, u.username COLLATE NOCASE
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top users: %w", err)
+	}
+	defer rows.Close()
+
+	var top []TopUser
+	for rows.Next() {
+		var user TopUser
+		if err := rows.Scan(&user.UserID, &user.Username, &user.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan top user: %w", err)
+		}
+		top = append(top, user)
+	}
+
+	return top, rows.Err()
+}
+
+// IncrementDailyStat adds n to one of the system counters for day
+// (YYYY-MM-DD)
+func (s *SQLiteDB) IncrementDailyStat(day, stat string, n int) error {
+	column, ok := dailyStatColumns[stat]
+	if !ok {
+		return fmt.Errorf("unknown daily stat %q", stat)
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO daily_stats (date, `+column+`) VALUES (?, ?)
+		ON CONFLICT(date) DO UPDATE SET `+column+` = `+column+` + excluded.`+column+`
+	`, day, n)
+	if err != nil {
+		return fmt.Errorf("failed to update daily stat %s: %w", stat, err)
+	}
+	return nil
+}
+
+// GetDailyStats returns the system counters for the last days days that
+// saw any activity, newest first
+func (s *SQLiteDB) GetDailyStats(days int) ([]DailyStats, error) {
+	rows, err := s.db.Query(`
+		SELECT date, calls, new_users, posts, uploads, downloads, minutes
+		FROM daily_stats
+		WHERE date >= ?
+		ORDER BY date DESC
+	`, time.Now().AddDate(0, 0, 1-days).Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []DailyStats
+	for rows.Next() {
+		var day DailyStats
+		if err := rows.Scan(&day.Date, &day.Calls, &day.NewUsers, &day.Posts, &day.Uploads, &day.Downloads, &day.Minutes); err != nil {
+			return nil, fmt.Errorf("failed to scan daily stats: %w", err)
+		}
+		stats = append(stats, day)
+	}
+
+	return stats, rows.Err()
+}
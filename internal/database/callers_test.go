@@ -0,0 +1,85 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCallerHistoryAndTopUsers(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+
+	first, err := db.RecordCallLogon(&CallerRecord{UserID: alice, Username: "alice", Node: 1, NewUser: true})
+	if err != nil {
+		t.Fatalf("RecordCallLogon: %v", err)
+	}
+	if err := db.RecordCallLogoff(first, 12); err != nil {
+		t.Fatalf("RecordCallLogoff: %v", err)
+	}
+	second, err := db.RecordCallLogon(&CallerRecord{UserID: bob, Username: "bob", Location: "Dallas, TX", Node: 2})
+	if err != nil {
+		t.Fatalf("RecordCallLogon: %v", err)
+	}
+	if err := db.RecordCallLogoff(second, 30); err != nil {
+		t.Fatalf("RecordCallLogoff: %v", err)
+	}
+
+	calls, err := db.GetCallers("", 10)
+	if err != nil {
+		t.Fatalf("GetCallers: %v", err)
+	}
+	if len(calls) != 2 || calls[0].Username != "bob" || calls[1].Minutes != 12 || !calls[1].NewUser || calls[0].LogoffAt == "" {
+		t.Fatalf("expected both calls newest first, got %+v", calls)
+	}
+	if calls, err := db.GetCallers(time.Now().Add(time.Hour).Format(time.RFC3339), 10); err != nil || len(calls) != 0 {
+		t.Fatalf("expected no calls in the future, got %+v (%v)", calls, err)
+	}
+
+	for _, inc := range []struct {
+		user int64
+		stat string
+		n    int
+	}{{alice, StatPosts, 3}, {bob, StatPosts, 1}, {alice, StatCalls, 1}, {bob, StatCalls, 1}, {alice, StatPosts, 2},
+		{alice, StatMinutes, 12}, {bob, StatMinutes, 30}} {
+		if err := db.IncrementUserStat(inc.user, inc.stat, inc.n); err != nil {
+			t.Fatalf("IncrementUserStat: %v", err)
+		}
+	}
+	if err := db.IncrementUserStat(alice, "karma", 1); err == nil {
+		t.Fatal("expected an unknown stat to be rejected")
+	}
+
+	stats, err := db.GetUserStats(alice)
+	if err != nil || stats.Posts != 5 || stats.Calls != 1 || stats.Minutes != 12 {
+		t.Fatalf("unexpected stats %+v (%v)", stats, err)
+	}
+	top, err := db.GetTopUsers(StatPosts, 10)
+	if err != nil || len(top) != 2 || top[0].Username != "alice" || top[0].Value != 5 {
+		t.Fatalf("unexpected top posters %+v (%v)", top, err)
+	}
+	top, err = db.GetTopUsers(StatMinutes, 1)
+	if err != nil || len(top) != 1 || top[0].Username != "bob" || top[0].Value != 30 {
+		t.Fatalf("unexpected top time online %+v (%v)", top, err)
+	}
+	if top, err := db.GetTopUsers(StatUploads, 10); err != nil || len(top) != 0 {
+		t.Fatalf("expected users without uploads left out, got %+v (%v)", top, err)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	if err := db.IncrementDailyStat(today, StatCalls, 2); err != nil {
+		t.Fatalf("IncrementDailyStat: %v", err)
+	}
+	if err := db.IncrementDailyStat(today, StatNewUsers, 1); err != nil {
+		t.Fatalf("IncrementDailyStat: %v", err)
+	}
+	if err := db.IncrementDailyStat(time.Now().AddDate(0, 0, -30).Format("2006-01-02"), StatCalls, 5); err != nil {
+		t.Fatalf("IncrementDailyStat: %v", err)
+	}
+	days, err := db.GetDailyStats(7)
+	if err != nil || len(days) != 1 || days[0].Date != today || days[0].Calls != 2 || days[0].NewUsers != 1 {
+		t.Fatalf("unexpected daily stats %+v (%v)", days, err)
+	}
+}
@@ -36,6 +36,29 @@ const (
 	DisplayModeTitlesGenerated = "titles_generated"
 	DisplayModeHeaderGenerated = "header_generated"
 	DisplayModeThemeOnly       = "theme_only"
+
+	InputModeHotkey   = "hotkey"   // A single key runs the command
+	InputModeLine     = "line"     // Type a command and press Enter
+	InputModeLightbar = "lightbar" // Arrow between highlighted commands
+
+	EventActionCommand = "command" // Run an external command line
+	EventActionPack    = "pack"    // Reclaim the space of deleted messages in every message base
+	EventActionNetwork = "network" // Run the QWKnet toss/scan
+	EventActionBackup  = "backup"  // Copy the database to the backup directory
+	EventActionMessage = "message" // Post a message to a message area
+
+	SequenceLogon  = "logon"  // Steps run after a caller logs in
+	SequenceLogoff = "logoff" // Steps run before a caller says goodbye
+
+	StepDisplayFile = "display"      // Show a display file
+	StepLastCallers = "last_callers" // Show the last callers
+	StepOneLiners   = "oneliners"    // Show the one-liner wall
+	StepMailCheck   = "mail_check"   // Report new private mail
+	StepNewscan     = "newscan"      // Offer a scan for new messages
+	StepAutomessage = "automessage"  // Show the automessage
+	StepBulletins   = "bulletins"    // Show the bulletins
+	StepRumor       = "rumor"        // Show a random rumor
+	StepCommand     = "command"      // Run a menu command key
 )
 
 // Menu represents a menu in the BBS system
@@ -54,6 +77,9 @@ type Menu struct {
 	RightBracket        string
 	DisplayMode         string
 	NodeActivity        string
+	InputMode           string
+	LightbarFgColor     int // Highlighted command text, 0-15
+	LightbarBgColor     int // Highlighted command background, 0-7
 }
 
 // MenuCommand represents a command in a menu
@@ -72,6 +98,164 @@ type MenuCommand struct {
 	Hidden           bool
 }
 
+// TimedEvent is a system event run on a schedule by the server
+type TimedEvent struct {
+	ID             int
+	Name           string
+	Schedule       string // "daily HH:MM", "weekly DAYS HH:MM" or a five-field cron expression
+	Action         string // One of the EventAction* values
+	Options        string // Command line, or area;subject;text for a message
+	LockoutMinutes int    // Refuse logons this long before the event; 0 never locks out
+	WarnMinutes    int    // Warn online callers this long before the event; 0 never warns
+	Enabled        bool
+	LastRun        string // When the event last ran (RFC3339); empty if never
+	LastResult     string // Outcome of the last run
+}
+
+// SequenceStep is one step of the logon or logoff sequence
+type SequenceStep struct {
+	ID         int
+	Sequence   string // SequenceLogon or SequenceLogoff
+	Position   int    // Steps run in ascending position
+	StepType   string // One of the Step* values
+	Options    string // File name for display steps, "KEY options" for commands
+	ACS        string // Callers must pass this ACS for the step to run
+	OncePerDay bool   // Run at most once a day for each caller
+	Enabled    bool
+}
+
+// VotingTopic is a question callers vote on
+type VotingTopic struct {
+	ID          int
+	Title       string
+	Description string
+	VoteACS     string // Callers must pass this ACS to vote
+	AddACS      string // Callers must pass this ACS to add their own choice
+	CreatedBy   string
+	CreatedAt   string // RFC3339
+	Active      bool   // Inactive topics keep their results but take no votes
+}
+
+// VotingChoice is one answer to a voting topic
+type VotingChoice struct {
+	ID       int
+	TopicID  int
+	Position int // Choices are listed in ascending position
+	Text     string
+	AddedBy  string
+	Votes    int // Filled in by GetVotingChoices
+}
+
+// Vote records how one user voted on a topic
+type Vote struct {
+	TopicID  int
+	ChoiceID int
+	UserID   int64
+	Username string
+	Choice   string
+	VotedAt  string // RFC3339
+}
+
+// Automessage is the single message shown to callers at logon until
+// someone replaces it
+type Automessage struct {
+	UserID    int64
+	Author    string
+	Anonymous bool // Shown without the author's name, and cannot be replied to
+	Text      string
+	WrittenAt string // RFC3339
+}
+
+// OneLiner is one line on the one-liner wall
+type OneLiner struct {
+	ID       int
+	UserID   int64
+	Username string
+	Text     string
+	PostedAt string // RFC3339
+}
+
+// Rumor is an anonymous line shown at random
+type Rumor struct {
+	ID      int
+	Text    string
+	AddedBy string // Kept for the sysop; never shown to callers
+	AddedAt string // RFC3339
+}
+
+// Counters kept for each user (all but new users) and for each day (all of
+// them)
+const (
+	StatCalls     = "calls"
+	StatPosts     = "posts"
+	StatUploads   = "uploads"
+	StatDownloads = "downloads"
+	StatMinutes   = "minutes"   // Time online
+	StatNewUsers  = "new_users" // Daily only
+)
+
+// CallerRecord is one call in the caller history
+type CallerRecord struct {
+	ID        int64
+	UserID    int64
+	Username  string
+	Location  string
+	Node      int
+	IPAddress string
+	LogonAt   string // RFC3339
+	LogoffAt  string // RFC3339; empty while the caller is online
+	Minutes   int
+	NewUser   bool // The caller's first call
+}
+
+// UserStats holds a user's counters
+type UserStats struct {
+	UserID    int64
+	Calls     int
+	Posts     int
+	Uploads   int
+	Downloads int
+	Minutes   int
+}
+
+// TopUser is one line of a Top 10 list
+type TopUser struct {
+	UserID   int64
+	Username string
+	Value    int
+}
+
+// DailyStats holds the system counters for one day
+type DailyStats struct {
+	Date      string // YYYY-MM-DD, local time
+	Calls     int
+	NewUsers  int
+	Posts     int
+	Uploads   int
+	Downloads int
+	Minutes   int
+}
+
+// Validation statuses for new user accounts
+const (
+	ValidationPending  = "pending"
+	ValidationApproved = "approved"
+	ValidationRejected = "rejected"
+)
+
+// ValidationRecord is a new user account waiting for, or given, validation
+type ValidationRecord struct {
+	UserID      int64
+	Username    string
+	Email       string
+	Location    string
+	Level       int    // The user's current security level
+	RequestedAt string // RFC3339
+	Status      string
+	DecidedAt   string // RFC3339; empty while pending
+	DecidedBy   string
+}
+
 // Conference represents a high-level message conference
 type Conference struct {
 	ID          int
@@ -96,6 +280,7 @@ type MessageArea struct {
 	Address        string
 	ConferenceID   int
 	ConferenceName string
+	QWKConference  int
 }
 
 // Database interface defines all database operations
@@ -118,11 +303,21 @@ type Database interface {
 	WithTransaction(fn func(*sql.Tx) error) error
 	UpsertUserDetail(userID int64, attrib, value string) error
 	GetUserDetails(userID int64) (map[string]string, error)
+	SetUserLastRead(userID int64, msgbase string, messageID int) error
+	GetUserLastReads(userID int64) (map[string]int, error)
+	SetUserPreference(pref *UserPreferenceRecord) error
+	GetUserPreferences(userID int64) (map[string]UserPreferenceRecord, error)
 	IncrementFailedAttempts(userID int64, now time.Time, maxAttempts int, lockMinutes int) (int, *time.Time, error)
 	ResetFailedAttempts(userID int64, now time.Time) error
 	UpdatePassword(userID int64, hash, algo, salt string, now time.Time) error
 	InsertAuthAudit(entry *AuthAuditEntry) error
 
+	// Node session operations
+	CreateBBSSession(session *BBSSessionRecord) (int64, error)
+	UpdateBBSSession(session *BBSSessionRecord) error
+	GetActiveBBSSessions() ([]BBSSessionRecord, error)
+	EndStaleBBSSessions(now time.Time) ([]BBSSessionRecord, error)
+
 	// Security level operations
 	CreateSecurityLevel(level *SecurityLevelRecord) (int64, error)
 	GetSecurityLevelByID(id int64) (*SecurityLevelRecord, error)
@@ -157,7 +352,60 @@ type Database interface {
 	UpdateMessageArea(area *MessageArea) error
 	DeleteMessageArea(id int64) error
 
+	// Timed event operations
+	CreateTimedEvent(event *TimedEvent) (int64, error)
+	GetAllTimedEvents() ([]TimedEvent, error)
+	UpdateTimedEvent(event *TimedEvent) error
+	RecordTimedEventRun(id int, ranAt, result string) error
+	DeleteTimedEvent(id int64) error
+
+	// Logon and logoff sequence operations
+	CreateSequenceStep(step *SequenceStep) (int64, error)
+	GetSequenceSteps(sequence string) ([]SequenceStep, error)
+	UpdateSequenceStep(step *SequenceStep) error
+	DeleteSequenceStep(id int64) error
+
+	// Voting booth operations
+	CreateVotingTopic(topic *VotingTopic) (int64, error)
+	CreateVotingTopicWithChoices(topic *VotingTopic, choices []VotingChoice) (int64, error)
+	GetAllVotingTopics() ([]VotingTopic, error)
+	UpdateVotingTopic(topic *VotingTopic) error
+	DeleteVotingTopic(id int64) error
+	CreateVotingChoice(choice *VotingChoice) (int64, error)
+	GetVotingChoices(topicID int) ([]VotingChoice, error)
+	UpdateVotingChoice(choice *VotingChoice) error
+	DeleteVotingChoice(id int64) error
+	CastVote(topicID, choiceID int, userID int64) error
+	GetUserVotes(userID int64) (map[int]int, error)
+	GetTopicVotes(topicID int) ([]Vote, error)
+
+	// Automessage, one-liner and rumor operations
+	GetAutomessage() (*Automessage, error)
+	SetAutomessage(msg *Automessage) error
+	AddOneLiner(line *OneLiner) error
+	GetOneLiners(limit int) ([]OneLiner, error)
+	AddRumor(rumor *Rumor) error
+	GetRandomRumor() (*Rumor, error)
+
+	// Caller history and statistics operations
+	RecordCallLogon(call *CallerRecord) (int64, error)
+	RecordCallLogoff(callID int64, minutes int) error
+	GetCallers(since string, limit int) ([]CallerRecord, error)
+	IncrementUserStat(userID int64, stat string, n int) error
+	GetUserStats(userID int64) (*UserStats, error)
+	GetTopUsers(stat string, limit int) ([]TopUser, error)
+	IncrementDailyStat(day, stat string, n int) error
+	GetDailyStats(days int) ([]DailyStats, error)
+
+	// New user validation operations
+	QueueValidation(userID int64) error
+	GetPendingValidations() ([]ValidationRecord, error)
+	GetValidation(userID int64) (*ValidationRecord, error)
+	ResolveValidation(userID int64, status, decidedBy string, level int) error
+	DeleteValidation(userID int64) error
+
 	// Database management
+	BackupTo(path string) error
 	InitializeSchema() error
 	Close() error
 }
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"fmt"
+)
+
+// CreateTimedEvent inserts a new timed event
+func (s *SQLiteDB) CreateTimedEvent(event *TimedEvent) (int64, error) {
+	if event == nil {
+		return 0, fmt.Errorf("timed event cannot be nil")
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO timed_events (name, schedule, action, options, lockout_minutes, warn_minutes, enabled)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, event.Name, event.Schedule, event.Action, event.Options, event.LockoutMinutes, event.WarnMinutes, event.Enabled)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create timed event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get timed event ID: %w", err)
+	}
+
+	event.ID = int(id)
+	return id, nil
+}
+
+// GetAllTimedEvents returns all timed events ordered by name
+func (s *SQLiteDB) GetAllTimedEvents() ([]TimedEvent, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, schedule, action, options, lockout_minutes, warn_minutes, enabled, last_run, last_result
+		FROM timed_events
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query timed events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []TimedEvent
+	for rows.Next() {
+		var event TimedEvent
+		var enabledInt int
+		if err := rows.Scan(&event.ID, &event.Name, &event.Schedule, &event.Action, &event.Options,
+			&event.LockoutMinutes, &event.WarnMinutes, &enabledInt, &event.LastRun, &event.LastResult); err != nil {
+			return nil, fmt.Errorf("failed to scan timed event: %w", err)
+		}
+		event.Enabled = enabledInt != 0
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// UpdateTimedEvent saves an event's definition. The last run is left alone.
+func (s *SQLiteDB) UpdateTimedEvent(event *TimedEvent) error {
+	if event == nil {
+		return fmt.Errorf("timed event cannot be nil")
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE timed_events
+		SET name = ?, schedule = ?, action = ?, options = ?, lockout_minutes = ?, warn_minutes = ?, enabled = ?
+		WHERE id = ?
+	`, event.Name, event.Schedule, event.Action, event.Options, event.LockoutMinutes, event.WarnMinutes, event.Enabled, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update timed event: %w", err)
+	}
+
+	return nil
+}
+
+// RecordTimedEventRun stores when an event last ran and how it went
+func (s *SQLiteDB) RecordTimedEventRun(id int, ranAt, result string) error {
+	_, err := s.db.Exec(`UPDATE timed_events SET last_run = ?, last_result = ? WHERE id = ?`, ranAt, result, id)
+	if err != nil {
+		return fmt.Errorf("failed to record timed event run: %w", err)
+	}
+	return nil
+}
+
+// DeleteTimedEvent removes a timed event by ID
+func (s *SQLiteDB) DeleteTimedEvent(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM timed_events WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete timed event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTimedEventLifecycle(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	event := &TimedEvent{Name: "Nightly backup", Schedule: "daily 03:00", Action: EventActionBackup, LockoutMinutes: 5, Enabled: true}
+	if _, err := db.CreateTimedEvent(event); err != nil {
+		t.Fatalf("CreateTimedEvent: %v", err)
+	}
+	if err := db.RecordTimedEventRun(event.ID, "2026-10-18T03:00:00Z", "ok"); err != nil {
+		t.Fatalf("RecordTimedEventRun: %v", err)
+	}
+
+	event.Schedule = "weekly sun 04:00"
+	event.Enabled = false
+	if err := db.UpdateTimedEvent(event); err != nil {
+		t.Fatalf("UpdateTimedEvent: %v", err)
+	}
+
+	events, err := db.GetAllTimedEvents()
+	if err != nil {
+		t.Fatalf("GetAllTimedEvents: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	got := events[0]
+	if got.Schedule != "weekly sun 04:00" || got.Enabled || got.LockoutMinutes != 5 || got.LastRun != "2026-10-18T03:00:00Z" || got.LastResult != "ok" {
+		t.Fatalf("unexpected event %+v", got)
+	}
+
+	if err := db.DeleteTimedEvent(int64(event.ID)); err != nil {
+		t.Fatalf("DeleteTimedEvent: %v", err)
+	}
+	if events, _ := db.GetAllTimedEvents(); len(events) != 0 {
+		t.Fatalf("event still present after delete: %+v", events)
+	}
+}
+
+func TestBackupTo(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+	createTestUser(t, db, "backedup")
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := db.BackupTo(path); err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Fatalf("backup missing or empty: %v", err)
+	}
+
+	copyDB, err := OpenSQLite(ConnectionConfig{Path: path})
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	defer copyDB.Close()
+	if user, err := copyDB.GetUserByUsername("backedup"); err != nil || user == nil {
+		t.Fatalf("user missing from backup: %v", err)
+	}
+}
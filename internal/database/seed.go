@@ -60,6 +60,9 @@ func SeedDefaultMainMenu(db Database) error {
 		Name:                "MainMenu",
 		Titles:              []string{"|05-= |13Retrograde BBS |05=-", "|07-|06- |14Main Menu |06-|07-"},
 		DisplayMode:         DisplayModeTitlesGenerated,
+		InputMode:           InputModeHotkey,
+		LightbarFgColor:     15,
+		LightbarBgColor:     1,
 		Prompt:              " |08[ |14M|06ain |14M|06enu |08] |05CMD|13?: ",
 		ACSRequired:         "",
 		GenericColumns:      3,
@@ -195,6 +198,9 @@ func seedDefaultMsgMenu(db Database) error {
 		Name:                "MsgMenu",
 		Titles:              []string{"|05-= |13Retrograde BBS |05=-", "|07-|06- |14Message Menu |06-|07-"},
 		DisplayMode:         DisplayModeTitlesGenerated,
+		InputMode:           InputModeHotkey,
+		LightbarFgColor:     15,
+		LightbarBgColor:     1,
 		Prompt:              " |08[ |14M|06essage |14M|06enu |08] |05CMD|13?: ",
 		ACSRequired:         "",
 		GenericColumns:      2,
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"fmt"
+)
+
+// CreateSequenceStep inserts a new logon or logoff sequence step
+func (s *SQLiteDB) CreateSequenceStep(step *SequenceStep) (int64, error) {
+	if step == nil {
+		return 0, fmt.Errorf("sequence step cannot be nil")
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO sequence_steps (sequence, position, step_type, options, acs, once_per_day, enabled)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, step.Sequence, step.Position, step.StepType, step.Options, step.ACS, step.OncePerDay, step.Enabled)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sequence step: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sequence step ID: %w", err)
+	}
+
+	step.ID = int(id)
+	return id, nil
+}
+
+// GetSequenceSteps returns the steps of a sequence in the order they run
+func (s *SQLiteDB) GetSequenceSteps(sequence string) ([]SequenceStep, error) {
+	rows, err := s.db.Query(`
+		SELECT id, sequence, position, step_type, options, acs, once_per_day, enabled
+		FROM sequence_steps
+		WHERE sequence = ?
+		ORDER BY position, id
+	`, sequence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sequence steps: %w", err)
+	}
+	defer rows.Close()
+
+	var steps []SequenceStep
+	for rows.Next() {
+		var step SequenceStep
+		var onceInt, enabledInt int
+		if err := rows.Scan(&step.ID, &step.Sequence, &step.Position, &step.StepType, &step.Options,
+			&step.ACS, &onceInt, &enabledInt); err != nil {
+			return nil, fmt.Errorf("failed to scan sequence step: %w", err)
+		}
+		step.OncePerDay = onceInt != 0
+		step.Enabled = enabledInt != 0
+		steps = append(steps, step)
+	}
+
+	return steps, rows.Err()
+}
+
+// UpdateSequenceStep saves a sequence step
+func (s *SQLiteDB) UpdateSequenceStep(step *SequenceStep) error {
+	if step == nil {
+		return fmt.Errorf("sequence step cannot be nil")
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE sequence_steps
+		SET sequence = ?, position = ?, step_type = ?, options = ?, acs = ?, once_per_day = ?, enabled = ?
+		WHERE id = ?
+	`, step.Sequence, step.Position, step.StepType, step.Options, step.ACS, step.OncePerDay, step.Enabled, step.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update sequence step: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSequenceStep removes a sequence step by ID
+func (s *SQLiteDB) DeleteSequenceStep(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM sequence_steps WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete sequence step: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package database
+
+import "testing"
+
+func TestSequenceStepsKeepOrder(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	steps := []SequenceStep{
+		{Sequence: SequenceLogon, Position: 2, StepType: StepMailCheck, Enabled: true},
+		{Sequence: SequenceLogon, Position: 1, StepType: StepDisplayFile, Options: "LOGON", OncePerDay: true, Enabled: true},
+		{Sequence: SequenceLogoff, Position: 1, StepType: StepCommand, Options: "-L Bye!", ACS: "s20", Enabled: true},
+	}
+	for i := range steps {
+		if _, err := db.CreateSequenceStep(&steps[i]); err != nil {
+			t.Fatalf("CreateSequenceStep: %v", err)
+		}
+	}
+
+	logon, err := db.GetSequenceSteps(SequenceLogon)
+	if err != nil {
+		t.Fatalf("GetSequenceSteps: %v", err)
+	}
+	if len(logon) != 2 || logon[0].StepType != StepDisplayFile || !logon[0].OncePerDay || logon[1].StepType != StepMailCheck {
+		t.Fatalf("unexpected logon steps %+v", logon)
+	}
+
+	logon[1].Position = 0
+	logon[1].Enabled = false
+	if err := db.UpdateSequenceStep(&logon[1]); err != nil {
+		t.Fatalf("UpdateSequenceStep: %v", err)
+	}
+	logon, _ = db.GetSequenceSteps(SequenceLogon)
+	if logon[0].StepType != StepMailCheck || logon[0].Enabled {
+		t.Fatalf("update not applied: %+v", logon)
+	}
+
+	if err := db.DeleteSequenceStep(int64(steps[2].ID)); err != nil {
+		t.Fatalf("DeleteSequenceStep: %v", err)
+	}
+	if logoff, _ := db.GetSequenceSteps(SequenceLogoff); len(logoff) != 0 {
+		t.Fatalf("expected no logoff steps, got %+v", logoff)
+	}
+}
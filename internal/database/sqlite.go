@@ -38,6 +38,15 @@ func sanitizeDisplayMode(value string) string {
 	}
 }
 
+func sanitizeInputMode(value string) string {
+	switch value {
+	case InputModeLine, InputModeLightbar:
+		return value
+	default:
+		return InputModeHotkey
+	}
+}
+
 func normalizeMenuDefaults(menu *Menu) {
 	if menu == nil {
 		return
@@ -45,6 +54,7 @@ func normalizeMenuDefaults(menu *Menu) {
 	menu.LeftBracket = clampBracket(menu.LeftBracket, "[")
 	menu.RightBracket = clampBracket(menu.RightBracket, "]")
 	menu.DisplayMode = sanitizeDisplayMode(menu.DisplayMode)
+	menu.InputMode = sanitizeInputMode(menu.InputMode)
 }
 
 // OpenSQLite opens or creates a SQLite database
@@ -145,7 +155,10 @@ func (s *SQLiteDB) InitializeSchema() error {
 			left_bracket TEXT DEFAULT '[',
 			right_bracket TEXT DEFAULT ']',
 			display_mode TEXT DEFAULT 'titles_generated',
-			node_activity TEXT DEFAULT ''
+			node_activity TEXT DEFAULT '',
+			input_mode TEXT DEFAULT 'hotkey',
+			lightbar_fg_color INTEGER DEFAULT 15,
+			lightbar_bg_color INTEGER DEFAULT 1
 		)
 	`)
 	if err != nil {
@@ -210,6 +223,7 @@ func (s *SQLiteDB) InitializeSchema() error {
 			real_names BOOLEAN NOT NULL DEFAULT 0,
 			address TEXT,
 			conference_id INTEGER NOT NULL DEFAULT 0,
+			qwk_conference INTEGER NOT NULL DEFAULT 0,
 			FOREIGN KEY (conference_id) REFERENCES conferences(id)
 		)
 	`)
@@ -222,6 +236,184 @@ func (s *SQLiteDB) InitializeSchema() error {
 			return fmt.Errorf("failed to add conference_id column to message_areas: %w", err)
 		}
 	}
+	if _, err := tx.Exec(`ALTER TABLE message_areas ADD COLUMN qwk_conference INTEGER NOT NULL DEFAULT 0`); err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "duplicate column name") {
+			return fmt.Errorf("failed to add qwk_conference column to message_areas: %w", err)
+		}
+	}
+
+	// Create timed_events table
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS timed_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			schedule TEXT NOT NULL,
+			action TEXT NOT NULL,
+			options TEXT NOT NULL DEFAULT '',
+			lockout_minutes INTEGER NOT NULL DEFAULT 0,
+			warn_minutes INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			last_run TEXT NOT NULL DEFAULT '',
+			last_result TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create timed_events: %w", err)
+	}
+
+	// Create sequence_steps table
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS sequence_steps (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			sequence TEXT NOT NULL,
+			position INTEGER NOT NULL DEFAULT 0,
+			step_type TEXT NOT NULL,
+			options TEXT NOT NULL DEFAULT '',
+			acs TEXT NOT NULL DEFAULT '',
+			once_per_day BOOLEAN NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT 1
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sequence_steps: %w", err)
+	}
+
+	// Create voting booth tables
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS voting_topics (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			title TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			vote_acs TEXT NOT NULL DEFAULT '',
+			add_acs TEXT NOT NULL DEFAULT '',
+			created_by TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL DEFAULT '',
+			active BOOLEAN NOT NULL DEFAULT 1
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create voting_topics: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS voting_choices (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			topic_id INTEGER NOT NULL,
+			position INTEGER NOT NULL DEFAULT 0,
+			text TEXT NOT NULL,
+			added_by TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create voting_choices: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS votes (
+			topic_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			choice_id INTEGER NOT NULL,
+			voted_at TEXT NOT NULL,
+			PRIMARY KEY (topic_id, user_id)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create votes: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS automessage (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			user_id INTEGER NOT NULL DEFAULT 0,
+			author TEXT NOT NULL DEFAULT '',
+			anonymous BOOLEAN NOT NULL DEFAULT 0,
+			text TEXT NOT NULL DEFAULT '',
+			written_at TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create automessage: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS oneliners (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL DEFAULT 0,
+			username TEXT NOT NULL DEFAULT '',
+			text TEXT NOT NULL,
+			posted_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create oneliners: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS rumors (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			text TEXT NOT NULL,
+			added_by TEXT NOT NULL DEFAULT '',
+			added_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create rumors: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS callers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			username TEXT NOT NULL,
+			location TEXT NOT NULL DEFAULT '',
+			node INTEGER NOT NULL DEFAULT 0,
+			ip_address TEXT NOT NULL DEFAULT '',
+			logon_at TEXT NOT NULL,
+			logoff_at TEXT NOT NULL DEFAULT '',
+			minutes INTEGER NOT NULL DEFAULT 0,
+			new_user BOOLEAN NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create callers: %w", err)
+	}
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_callers_logon ON callers(logon_at)`)
+	if err != nil {
+		return fmt.Errorf("failed to create callers index: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS user_stats (
+			user_id INTEGER PRIMARY KEY,
+			calls INTEGER NOT NULL DEFAULT 0,
+			posts INTEGER NOT NULL DEFAULT 0,
+			uploads INTEGER NOT NULL DEFAULT 0,
+			downloads INTEGER NOT NULL DEFAULT 0,
+			minutes INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create user_stats: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS daily_stats (
+			date TEXT PRIMARY KEY,
+			calls INTEGER NOT NULL DEFAULT 0,
+			new_users INTEGER NOT NULL DEFAULT 0,
+			posts INTEGER NOT NULL DEFAULT 0,
+			uploads INTEGER NOT NULL DEFAULT 0,
+			downloads INTEGER NOT NULL DEFAULT 0,
+			minutes INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create daily_stats: %w", err)
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS user_validations (
+			user_id INTEGER PRIMARY KEY,
+			requested_at TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			decided_at TEXT NOT NULL DEFAULT '',
+			decided_by TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create user_validations: %w", err)
+	}
 
 	// Ensure new columns exist for legacy databases
 	if _, err := tx.Exec(`ALTER TABLE menus ADD COLUMN left_bracket TEXT DEFAULT '['`); err != nil {
@@ -244,6 +436,21 @@ func (s *SQLiteDB) InitializeSchema() error {
 			return fmt.Errorf("failed to add node_activity column: %w", err)
 		}
 	}
+	if _, err := tx.Exec(`ALTER TABLE menus ADD COLUMN input_mode TEXT DEFAULT 'hotkey'`); err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "duplicate column name") {
+			return fmt.Errorf("failed to add input_mode column: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`ALTER TABLE menus ADD COLUMN lightbar_fg_color INTEGER DEFAULT 15`); err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "duplicate column name") {
+			return fmt.Errorf("failed to add lightbar_fg_color column: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`ALTER TABLE menus ADD COLUMN lightbar_bg_color INTEGER DEFAULT 1`); err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "duplicate column name") {
+			return fmt.Errorf("failed to add lightbar_bg_color column: %w", err)
+		}
+	}
 	if _, err := tx.Exec(`ALTER TABLE menu_commands ADD COLUMN long_description TEXT`); err != nil {
 		if !strings.Contains(strings.ToLower(err.Error()), "duplicate column name") {
 			return fmt.Errorf("failed to add long_description column: %w", err)
@@ -259,6 +466,11 @@ func (s *SQLiteDB) InitializeSchema() error {
 			return fmt.Errorf("failed to add hidden column: %w", err)
 		}
 	}
+	if _, err := tx.Exec(`ALTER TABLE user_stats ADD COLUMN minutes INTEGER NOT NULL DEFAULT 0`); err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "duplicate column name") {
+			return fmt.Errorf("failed to add minutes column: %w", err)
+		}
+	}
 
 	if err := tx.Commit(); err != nil {
 		return err
@@ -273,6 +485,15 @@ func (s *SQLiteDB) InitializeSchema() error {
 	return SeedDefaultMainMenu(s)
 }
 
+// BackupTo writes a consistent copy of the database to path, which must not
+// already exist
+func (s *SQLiteDB) BackupTo(path string) error {
+	if _, err := s.db.Exec(`VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
 // Close closes the database connection
 func (s *SQLiteDB) Close() error {
 	return s.db.Close()
@@ -464,9 +685,9 @@ func (s *SQLiteDB) CreateMenu(menu *Menu) (int64, error) {
 	normalizeMenuDefaults(menu)
 
 	result, err := s.db.Exec(`
-		INSERT INTO menus (name, titles, prompt, acs_required, generic_columns, generic_bracket_color, generic_command_color, generic_desc_color, clear_screen, left_bracket, right_bracket, display_mode, node_activity)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		menu.Name, string(titlesJSON), menu.Prompt, menu.ACSRequired, menu.GenericColumns, menu.GenericBracketColor, menu.GenericCommandColor, menu.GenericDescColor, menu.ClearScreen, menu.LeftBracket, menu.RightBracket, menu.DisplayMode, menu.NodeActivity)
+		INSERT INTO menus (name, titles, prompt, acs_required, generic_columns, generic_bracket_color, generic_command_color, generic_desc_color, clear_screen, left_bracket, right_bracket, display_mode, node_activity, input_mode, lightbar_fg_color, lightbar_bg_color)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		menu.Name, string(titlesJSON), menu.Prompt, menu.ACSRequired, menu.GenericColumns, menu.GenericBracketColor, menu.GenericCommandColor, menu.GenericDescColor, menu.ClearScreen, menu.LeftBracket, menu.RightBracket, menu.DisplayMode, menu.NodeActivity, menu.InputMode, menu.LightbarFgColor, menu.LightbarBgColor)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create menu: %w", err)
 	}
@@ -485,9 +706,9 @@ func (s *SQLiteDB) GetMenuByName(name string) (*Menu, error) {
 	var titlesJSON string
 
 	err := s.db.QueryRow(`
-		SELECT id, name, titles, prompt, acs_required, generic_columns, generic_bracket_color, generic_command_color, generic_desc_color, clear_screen, left_bracket, right_bracket, display_mode, node_activity
+		SELECT id, name, titles, prompt, acs_required, generic_columns, generic_bracket_color, generic_command_color, generic_desc_color, clear_screen, left_bracket, right_bracket, display_mode, node_activity, input_mode, lightbar_fg_color, lightbar_bg_color
 		FROM menus WHERE name = ?`, name).Scan(
-		&menu.ID, &menu.Name, &titlesJSON, &menu.Prompt, &menu.ACSRequired, &menu.GenericColumns, &menu.GenericBracketColor, &menu.GenericCommandColor, &menu.GenericDescColor, &menu.ClearScreen, &menu.LeftBracket, &menu.RightBracket, &menu.DisplayMode, &menu.NodeActivity)
+		&menu.ID, &menu.Name, &titlesJSON, &menu.Prompt, &menu.ACSRequired, &menu.GenericColumns, &menu.GenericBracketColor, &menu.GenericCommandColor, &menu.GenericDescColor, &menu.ClearScreen, &menu.LeftBracket, &menu.RightBracket, &menu.DisplayMode, &menu.NodeActivity, &menu.InputMode, &menu.LightbarFgColor, &menu.LightbarBgColor)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("menu not found: %s", name)
 	}
@@ -510,9 +731,9 @@ func (s *SQLiteDB) GetMenuByID(id int64) (*Menu, error) {
 	var titlesJSON string
 
 	err := s.db.QueryRow(`
-		SELECT id, name, titles, prompt, acs_required, generic_columns, generic_bracket_color, generic_command_color, generic_desc_color, clear_screen, left_bracket, right_bracket, display_mode, node_activity
+		SELECT id, name, titles, prompt, acs_required, generic_columns, generic_bracket_color, generic_command_color, generic_desc_color, clear_screen, left_bracket, right_bracket, display_mode, node_activity, input_mode, lightbar_fg_color, lightbar_bg_color
 		FROM menus WHERE id = ?`, id).Scan(
-		&menu.ID, &menu.Name, &titlesJSON, &menu.Prompt, &menu.ACSRequired, &menu.GenericColumns, &menu.GenericBracketColor, &menu.GenericCommandColor, &menu.GenericDescColor, &menu.ClearScreen, &menu.LeftBracket, &menu.RightBracket, &menu.DisplayMode, &menu.NodeActivity)
+		&menu.ID, &menu.Name, &titlesJSON, &menu.Prompt, &menu.ACSRequired, &menu.GenericColumns, &menu.GenericBracketColor, &menu.GenericCommandColor, &menu.GenericDescColor, &menu.ClearScreen, &menu.LeftBracket, &menu.RightBracket, &menu.DisplayMode, &menu.NodeActivity, &menu.InputMode, &menu.LightbarFgColor, &menu.LightbarBgColor)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("menu not found: %d", id)
 	}
@@ -532,7 +753,7 @@ func (s *SQLiteDB) GetMenuByID(id int64) (*Menu, error) {
 // GetAllMenus retrieves all menus
 func (s *SQLiteDB) GetAllMenus() ([]Menu, error) {
 	rows, err := s.db.Query(`
-		SELECT id, name, titles, prompt, acs_required, generic_columns, generic_bracket_color, generic_command_color, generic_desc_color, clear_screen, left_bracket, right_bracket, display_mode, node_activity
+		SELECT id, name, titles, prompt, acs_required, generic_columns, generic_bracket_color, generic_command_color, generic_desc_color, clear_screen, left_bracket, right_bracket, display_mode, node_activity, input_mode, lightbar_fg_color, lightbar_bg_color
 		FROM menus ORDER BY name`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query menus: %w", err)
@@ -543,7 +764,7 @@ func (s *SQLiteDB) GetAllMenus() ([]Menu, error) {
 	for rows.Next() {
 		var menu Menu
 		var titlesJSON string
-		err := rows.Scan(&menu.ID, &menu.Name, &titlesJSON, &menu.Prompt, &menu.ACSRequired, &menu.GenericColumns, &menu.GenericBracketColor, &menu.GenericCommandColor, &menu.GenericDescColor, &menu.ClearScreen, &menu.LeftBracket, &menu.RightBracket, &menu.DisplayMode, &menu.NodeActivity)
+		err := rows.Scan(&menu.ID, &menu.Name, &titlesJSON, &menu.Prompt, &menu.ACSRequired, &menu.GenericColumns, &menu.GenericBracketColor, &menu.GenericCommandColor, &menu.GenericDescColor, &menu.ClearScreen, &menu.LeftBracket, &menu.RightBracket, &menu.DisplayMode, &menu.NodeActivity, &menu.InputMode, &menu.LightbarFgColor, &menu.LightbarBgColor)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan menu: %w", err)
 		}
@@ -569,9 +790,9 @@ func (s *SQLiteDB) UpdateMenu(menu *Menu) error {
 	normalizeMenuDefaults(menu)
 
 	_, err = s.db.Exec(`
-		UPDATE menus SET name = ?, titles = ?, prompt = ?, acs_required = ?, generic_columns = ?, generic_bracket_color = ?, generic_command_color = ?, generic_desc_color = ?, clear_screen = ?, left_bracket = ?, right_bracket = ?, display_mode = ?, node_activity = ?
+		UPDATE menus SET name = ?, titles = ?, prompt = ?, acs_required = ?, generic_columns = ?, generic_bracket_color = ?, generic_command_color = ?, generic_desc_color = ?, clear_screen = ?, left_bracket = ?, right_bracket = ?, display_mode = ?, node_activity = ?, input_mode = ?, lightbar_fg_color = ?, lightbar_bg_color = ?
 		WHERE id = ?`,
-		menu.Name, string(titlesJSON), menu.Prompt, menu.ACSRequired, menu.GenericColumns, menu.GenericBracketColor, menu.GenericCommandColor, menu.GenericDescColor, menu.ClearScreen, menu.LeftBracket, menu.RightBracket, menu.DisplayMode, menu.NodeActivity, menu.ID)
+		menu.Name, string(titlesJSON), menu.Prompt, menu.ACSRequired, menu.GenericColumns, menu.GenericBracketColor, menu.GenericCommandColor, menu.GenericDescColor, menu.ClearScreen, menu.LeftBracket, menu.RightBracket, menu.DisplayMode, menu.NodeActivity, menu.InputMode, menu.LightbarFgColor, menu.LightbarBgColor, menu.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update menu: %w", err)
 	}
@@ -804,9 +1025,9 @@ func (s *SQLiteDB) CreateMessageArea(area *MessageArea) (int64, error) {
 	}
 
 	result, err := s.db.Exec(`
-		INSERT INTO message_areas (name, file, path, read_sec_level, write_sec_level, area_type, echo_tag, real_names, address, conference_id)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, area.Name, area.File, area.Path, area.ReadSecLevel, area.WriteSecLevel, area.AreaType, area.EchoTag, area.RealNames, area.Address, area.ConferenceID)
+		INSERT INTO message_areas (name, file, path, read_sec_level, write_sec_level, area_type, echo_tag, real_names, address, conference_id, qwk_conference)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, area.Name, area.File, area.Path, area.ReadSecLevel, area.WriteSecLevel, area.AreaType, area.EchoTag, area.RealNames, area.Address, area.ConferenceID, area.QWKConference)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create message area: %w", err)
 	}
@@ -827,11 +1048,11 @@ func (s *SQLiteDB) GetMessageAreaByID(id int64) (*MessageArea, error) {
 	var conferenceName sql.NullString
 
 	err := s.db.QueryRow(`
-		SELECT ma.id, ma.name, ma.file, ma.path, ma.read_sec_level, ma.write_sec_level, ma.area_type, ma.echo_tag, ma.real_names, ma.address, ma.conference_id, c.name
+		SELECT ma.id, ma.name, ma.file, ma.path, ma.read_sec_level, ma.write_sec_level, ma.area_type, ma.echo_tag, ma.real_names, ma.address, ma.conference_id, ma.qwk_conference, c.name
 		FROM message_areas ma
 		LEFT JOIN conferences c ON c.id = ma.conference_id
 		WHERE ma.id = ?
-	`, id).Scan(&area.ID, &area.Name, &area.File, &area.Path, &area.ReadSecLevel, &area.WriteSecLevel, &area.AreaType, &area.EchoTag, &realNamesInt, &area.Address, &area.ConferenceID, &conferenceName)
+	`, id).Scan(&area.ID, &area.Name, &area.File, &area.Path, &area.ReadSecLevel, &area.WriteSecLevel, &area.AreaType, &area.EchoTag, &realNamesInt, &area.Address, &area.ConferenceID, &area.QWKConference, &conferenceName)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("message area not found: %d", id)
 	}
@@ -847,7 +1068,7 @@ func (s *SQLiteDB) GetMessageAreaByID(id int64) (*MessageArea, error) {
 // GetAllMessageAreas returns all message areas ordered by name
 func (s *SQLiteDB) GetAllMessageAreas() ([]MessageArea, error) {
 	rows, err := s.db.Query(`
-		SELECT ma.id, ma.name, ma.file, ma.path, ma.read_sec_level, ma.write_sec_level, ma.area_type, ma.echo_tag, ma.real_names, ma.address, ma.conference_id, COALESCE(c.name, '')
+		SELECT ma.id, ma.name, ma.file, ma.path, ma.read_sec_level, ma.write_sec_level, ma.area_type, ma.echo_tag, ma.real_names, ma.address, ma.conference_id, ma.qwk_conference, COALESCE(c.name, '')
 		FROM message_areas ma
 		LEFT JOIN conferences c ON c.id = ma.conference_id
 		ORDER BY ma.name
@@ -862,7 +1083,7 @@ func (s *SQLiteDB) GetAllMessageAreas() ([]MessageArea, error) {
 		var area MessageArea
 		var realNamesInt int
 		var conferenceName string
-		if err := rows.Scan(&area.ID, &area.Name, &area.File, &area.Path, &area.ReadSecLevel, &area.WriteSecLevel, &area.AreaType, &area.EchoTag, &realNamesInt, &area.Address, &area.ConferenceID, &conferenceName); err != nil {
+		if err := rows.Scan(&area.ID, &area.Name, &area.File, &area.Path, &area.ReadSecLevel, &area.WriteSecLevel, &area.AreaType, &area.EchoTag, &realNamesInt, &area.Address, &area.ConferenceID, &area.QWKConference, &conferenceName); err != nil {
 			return nil, fmt.Errorf("failed to scan message area: %w", err)
 		}
 		area.RealNames = realNamesInt != 0
@@ -885,9 +1106,9 @@ func (s *SQLiteDB) UpdateMessageArea(area *MessageArea) error {
 
 	_, err := s.db.Exec(`
 		UPDATE message_areas
-		SET name = ?, file = ?, path = ?, read_sec_level = ?, write_sec_level = ?, area_type = ?, echo_tag = ?, real_names = ?, address = ?, conference_id = ?
+		SET name = ?, file = ?, path = ?, read_sec_level = ?, write_sec_level = ?, area_type = ?, echo_tag = ?, real_names = ?, address = ?, conference_id = ?, qwk_conference = ?
 		WHERE id = ?
-	`, area.Name, area.File, area.Path, area.ReadSecLevel, area.WriteSecLevel, area.AreaType, area.EchoTag, area.RealNames, area.Address, area.ConferenceID, area.ID)
+	`, area.Name, area.File, area.Path, area.ReadSecLevel, area.WriteSecLevel, area.AreaType, area.EchoTag, area.RealNames, area.Address, area.ConferenceID, area.QWKConference, area.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update message area: %w", err)
 	}
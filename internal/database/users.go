@@ -76,6 +76,7 @@ type BBSSessionRecord struct {
 	ConnectionType sql.NullString
 	CurrentArea    sql.NullString
 	CurrentMenu    sql.NullString
+	Activity       sql.NullString
 }
 
 // BBSConfigRecord represents a row in the bbs_config table.
@@ -154,6 +155,7 @@ func (s *SQLiteDB) InitializeUserSchema() error {
 			connection_type TEXT,
 			current_area TEXT,
 			current_menu TEXT,
+			activity TEXT,
 			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 		)`,
 		`CREATE TABLE IF NOT EXISTS bbs_config (
@@ -227,6 +229,12 @@ func (s *SQLiteDB) InitializeUserSchema() error {
 		}
 	}
 
+	if _, err := tx.Exec(`ALTER TABLE bbs_sessions ADD COLUMN activity TEXT`); err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "duplicate column name") {
+			return fmt.Errorf("failed to add activity column to bbs_sessions: %w", err)
+		}
+	}
+
 	indexStatements := []string{
 		`CREATE INDEX IF NOT EXISTS idx_users_username ON users(username)`,
 		`CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)`,
@@ -386,6 +394,8 @@ func deleteUserExec(ex execer, userID int64) error {
 		`DELETE FROM user_lastread WHERE user_id = ?`,
 		`DELETE FROM bbs_sessions WHERE user_id = ?`,
 		`DELETE FROM user_preferences WHERE user_id = ?`,
+		`DELETE FROM user_stats WHERE user_id = ?`,
+		`DELETE FROM user_validations WHERE user_id = ?`,
 		`DELETE FROM users WHERE id = ?`,
 	}
 
@@ -588,6 +598,54 @@ func (s *SQLiteDB) GetUserDetails(userID int64) (map[string]string, error) {
 	return details, nil
 }
 
+// SetUserLastRead advances a user's last read pointer for a message base.
+// A pointer never moves backwards.
+func (s *SQLiteDB) SetUserLastRead(userID int64, msgbase string, messageID int) error {
+	_, err := s.db.Exec(`
+		INSERT INTO user_lastread (user_id, msgbase, last_message_id)
+		VALUES (?, ?, ?)
+		ON CONFLICT(user_id, msgbase) DO UPDATE SET
+			last_message_id = MAX(COALESCE(last_message_id, 0), excluded.last_message_id)`,
+		userID,
+		msgbase,
+		messageID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set last read pointer: %w", err)
+	}
+	return nil
+}
+
+// GetUserLastReads returns a user's last read pointers by message base.
+func (s *SQLiteDB) GetUserLastReads(userID int64) (map[string]int, error) {
+	rows, err := s.db.Query(`
+		SELECT msgbase, COALESCE(last_message_id, 0)
+		FROM user_lastread
+		WHERE user_id = ?`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last read pointers: %w", err)
+	}
+	defer rows.Close()
+
+	pointers := make(map[string]int)
+	for rows.Next() {
+		var msgbase string
+		var messageID int
+		if err := rows.Scan(&msgbase, &messageID); err != nil {
+			return nil, fmt.Errorf("failed to scan last read pointer: %w", err)
+		}
+		pointers[msgbase] = messageID
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating last read pointers: %w", err)
+	}
+
+	return pointers, nil
+}
+
 // IncrementFailedAttempts increases the failed login count and optionally sets a lockout.
 func (s *SQLiteDB) IncrementFailedAttempts(userID int64, now time.Time, maxAttempts int, lockMinutes int) (int, *time.Time, error) {
 	tx, err := s.db.Begin()
@@ -734,8 +792,8 @@ func nullOrInt64(value sql.NullInt64) interface{} {
 // CreateBBSSession creates a new BBS session record.
 func (s *SQLiteDB) CreateBBSSession(session *BBSSessionRecord) (int64, error) {
 	result, err := s.db.Exec(`
-		INSERT INTO bbs_sessions (user_id, node_number, session_start, last_activity, time_left, calls_today, status, ip_address, connection_type, current_area, current_menu)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		INSERT INTO bbs_sessions (user_id, node_number, session_start, last_activity, time_left, calls_today, status, ip_address, connection_type, current_area, current_menu, activity)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		session.UserID,
 		session.NodeNumber,
 		session.SessionStart,
@@ -747,6 +805,7 @@ func (s *SQLiteDB) CreateBBSSession(session *BBSSessionRecord) (int64, error) {
 		nullOrString(session.ConnectionType),
 		nullOrString(session.CurrentArea),
 		nullOrString(session.CurrentMenu),
+		nullOrString(session.Activity),
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create BBS session: %w", err)
@@ -757,11 +816,62 @@ func (s *SQLiteDB) CreateBBSSession(session *BBSSessionRecord) (int64, error) {
 // GetBBSSessionByUser retrieves the active BBS session for a user.
 func (s *SQLiteDB) GetBBSSessionByUser(userID int64) (*BBSSessionRecord, error) {
 	row := s.db.QueryRow(`
-		SELECT id, user_id, node_number, session_start, last_activity, time_left, calls_today, status, ip_address, connection_type, current_area, current_menu
+		SELECT `+bbsSessionColumns+`
 		FROM bbs_sessions
 		WHERE user_id = ? AND status = 'active'`,
 		userID,
 	)
+	session, err := scanBBSSession(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get BBS session: %w", err)
+	}
+	return session, nil
+}
+
+// GetActiveBBSSessions lists the active BBS sessions ordered by node number.
+func (s *SQLiteDB) GetActiveBBSSessions() ([]BBSSessionRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT ` + bbsSessionColumns + `
+		FROM bbs_sessions
+		WHERE status = 'active'
+		ORDER BY node_number, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list BBS sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []BBSSessionRecord
+	for rows.Next() {
+		session, err := scanBBSSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan BBS session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+// EndStaleBBSSessions marks every active BBS session as ended and returns the
+// sessions it closed. Called at startup, when any session still marked active
+// was left behind by a server that did not shut down cleanly.
+func (s *SQLiteDB) EndStaleBBSSessions(now time.Time) ([]BBSSessionRecord, error) {
+	sessions, err := s.GetActiveBBSSessions()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Exec(`UPDATE bbs_sessions SET status = 'ended', last_activity = ? WHERE status = 'active'`,
+		now.UTC().Format(sqliteTimeFormat)); err != nil {
+		return nil, fmt.Errorf("failed to end stale BBS sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+const bbsSessionColumns = `id, user_id, node_number, session_start, last_activity, time_left, calls_today, status, ip_address, connection_type, current_area, current_menu, activity`
+
+func scanBBSSession(row interface{ Scan(dest ...any) error }) (*BBSSessionRecord, error) {
 	var session BBSSessionRecord
 	if err := row.Scan(
 		&session.ID,
@@ -776,11 +886,9 @@ func (s *SQLiteDB) GetBBSSessionByUser(userID int64) (*BBSSessionRecord, error)
 		&session.ConnectionType,
 		&session.CurrentArea,
 		&session.CurrentMenu,
+		&session.Activity,
 	); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to get BBS session: %w", err)
+		return nil, err
 	}
 	return &session, nil
 }
@@ -789,7 +897,7 @@ func (s *SQLiteDB) GetBBSSessionByUser(userID int64) (*BBSSessionRecord, error)
 func (s *SQLiteDB) UpdateBBSSession(session *BBSSessionRecord) error {
 	_, err := s.db.Exec(`
 		UPDATE bbs_sessions
-		SET last_activity = ?, time_left = ?, calls_today = ?, status = ?, current_area = ?, current_menu = ?
+		SET last_activity = ?, time_left = ?, calls_today = ?, status = ?, current_area = ?, current_menu = ?, activity = ?
 		WHERE id = ?`,
 		session.LastActivity,
 		session.TimeLeft,
@@ -797,6 +905,7 @@ func (s *SQLiteDB) UpdateBBSSession(session *BBSSessionRecord) error {
 		session.Status,
 		nullOrString(session.CurrentArea),
 		nullOrString(session.CurrentMenu),
+		nullOrString(session.Activity),
 		session.ID,
 	)
 	return err
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBBSSessionLifecycle(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	userID := createTestUser(t, db, "sessionuser")
+	now := time.Now().UTC().Format(sqliteTimeFormat)
+
+	id, err := db.CreateBBSSession(&BBSSessionRecord{
+		UserID:       userID,
+		NodeNumber:   3,
+		SessionStart: now,
+		LastActivity: now,
+		Status:       "active",
+		IPAddress:    NullString("127.0.0.1"),
+		Activity:     NullString("Main menu."),
+	})
+	if err != nil {
+		t.Fatalf("CreateBBSSession: %v", err)
+	}
+
+	active, err := db.GetActiveBBSSessions()
+	if err != nil {
+		t.Fatalf("GetActiveBBSSessions: %v", err)
+	}
+	if len(active) != 1 || active[0].ID != id || active[0].Activity.String != "Main menu." {
+		t.Fatalf("unexpected active sessions %+v", active)
+	}
+
+	session := active[0]
+	session.Activity = NullString("Reading mail.")
+	if err := db.UpdateBBSSession(&session); err != nil {
+		t.Fatalf("UpdateBBSSession: %v", err)
+	}
+	got, err := db.GetBBSSessionByUser(userID)
+	if err != nil || got == nil || got.Activity.String != "Reading mail." {
+		t.Fatalf("expected updated activity, got %+v (err %v)", got, err)
+	}
+
+	stale, err := db.EndStaleBBSSessions(time.Now())
+	if err != nil {
+		t.Fatalf("EndStaleBBSSessions: %v", err)
+	}
+	if len(stale) != 1 || stale[0].NodeNumber != 3 {
+		t.Fatalf("expected the node 3 session to be ended, got %+v", stale)
+	}
+	if active, _ := db.GetActiveBBSSessions(); len(active) != 0 {
+		t.Fatalf("expected no active sessions after cleanup, got %d", len(active))
+	}
+	if got, _ := db.GetBBSSessionByUser(userID); got != nil {
+		t.Fatalf("expected no active session for user, got %+v", got)
+	}
+}
+
+func TestUserLastReadOnlyAdvances(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	userID := createTestUser(t, db, "reader")
+	for _, id := range []int{12, 30, 7} {
+		if err := db.SetUserLastRead(userID, "general", id); err != nil {
+			t.Fatalf("SetUserLastRead(%d): %v", id, err)
+		}
+	}
+
+	pointers, err := db.GetUserLastReads(userID)
+	if err != nil {
+		t.Fatalf("GetUserLastReads: %v", err)
+	}
+	if pointers["general"] != 30 {
+		t.Errorf("last read %d, want 30", pointers["general"])
+	}
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const validationColumns = `
+	v.user_id, u.username, COALESCE(u.email, ''), COALESCE(u.locations, ''), u.security_level,
+	v.requested_at, v.status, v.decided_at, v.decided_by`
+
+func scanValidation(row interface{ Scan(...any) error }) (*ValidationRecord, error) {
+	var v ValidationRecord
+	if err := row.Scan(&v.UserID, &v.Username, &v.Email, &v.Location, &v.Level,
+		&v.RequestedAt, &v.Status, &v.DecidedAt, &v.DecidedBy); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// QueueValidation puts a user in the validation queue, or back into it
+func (s *SQLiteDB) QueueValidation(userID int64) error {
+	_, err := s.db.Exec(`
+		INSERT INTO user_validations (user_id, requested_at, status) VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			requested_at = excluded.requested_at, status = excluded.status, decided_at = '', decided_by = ''
+	`, userID, time.Now().Format(time.RFC3339), ValidationPending)
+	if err != nil {
+		return fmt.Errorf("failed to queue user for validation: %w", err)
+	}
+	return nil
+}
+
+// GetPendingValidations returns the users waiting for validation, oldest first
+func (s *SQLiteDB) GetPendingValidations() ([]ValidationRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT `+validationColumns+`
+		FROM user_validations v
+		JOIN users u ON u.id = v.user_id
+		WHERE v.status = ?
+		ORDER BY v.requested_at, v.user_id
+	`, ValidationPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query validations: %w", err)
+	}
+	defer rows.Close()
+
+	var validations []ValidationRecord
+	for rows.Next() {
+		v, err := scanValidation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan validation: %w", err)
+		}
+		validations = append(validations, *v)
+	}
+	return validations, rows.Err()
+}
+
+// GetValidation returns a user's validation, or nil when they were never queued
+func (s *SQLiteDB) GetValidation(userID int64) (*ValidationRecord, error) {
+	v, err := scanValidation(s.db.QueryRow(`
+		SELECT `+validationColumns+`
+		FROM user_validations v
+		JOIN users u ON u.id = v.user_id
+		WHERE v.user_id = ?
+	`, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validation: %w", err)
+	}
+	return v, nil
+}
+
+// ResolveValidation records the decision on a user's validation and, when
+// level is above zero, sets the user's security level to it
+func (s *SQLiteDB) ResolveValidation(userID int64, status, decidedBy string, level int) error {
+	if status != ValidationApproved && status != ValidationRejected {
+		return fmt.Errorf("invalid validation status %q", status)
+	}
+	now := time.Now().Format(time.RFC3339)
+	return s.WithTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			INSERT INTO user_validations (user_id, requested_at, status, decided_at, decided_by) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(user_id) DO UPDATE SET
+				status = excluded.status, decided_at = excluded.decided_at, decided_by = excluded.decided_by
+		`, userID, now, status, now, decidedBy); err != nil {
+			return fmt.Errorf("failed to resolve validation: %w", err)
+		}
+		if level > 0 {
+			if _, err := tx.Exec(`UPDATE users SET security_level = ? WHERE id = ?`, level, userID); err != nil {
+				return fmt.Errorf("failed to update security level: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteValidation removes a user from the validation queue and its history
+func (s *SQLiteDB) DeleteValidation(userID int64) error {
+	if _, err := s.db.Exec(`DELETE FROM user_validations WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete validation: %w", err)
+	}
+	return nil
+}
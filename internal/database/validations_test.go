@@ -0,0 +1,72 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestValidationQueue(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+
+	if v, err := db.GetValidation(alice); err != nil || v != nil {
+		t.Fatalf("expected no validation before queueing, got %+v (%v)", v, err)
+	}
+	for _, id := range []int64{alice, bob} {
+		if err := db.QueueValidation(id); err != nil {
+			t.Fatalf("QueueValidation: %v", err)
+		}
+	}
+
+	pending, err := db.GetPendingValidations()
+	if err != nil {
+		t.Fatalf("GetPendingValidations: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Username != "alice" || pending[0].Status != ValidationPending {
+		t.Fatalf("expected both users pending, got %+v", pending)
+	}
+
+	if err := db.ResolveValidation(alice, ValidationApproved, "SysOp", 20); err != nil {
+		t.Fatalf("ResolveValidation: %v", err)
+	}
+	if err := db.ResolveValidation(bob, ValidationRejected, "SysOp", 0); err != nil {
+		t.Fatalf("ResolveValidation: %v", err)
+	}
+	if err := db.ResolveValidation(bob, "maybe", "SysOp", 0); err == nil {
+		t.Fatalf("expected an invalid status to be refused")
+	}
+
+	if pending, err := db.GetPendingValidations(); err != nil || len(pending) != 0 {
+		t.Fatalf("expected an empty queue, got %+v (%v)", pending, err)
+	}
+	v, err := db.GetValidation(alice)
+	if err != nil || v == nil {
+		t.Fatalf("GetValidation: %+v (%v)", v, err)
+	}
+	if v.Status != ValidationApproved || v.DecidedBy != "SysOp" || v.DecidedAt == "" || v.Level != 20 {
+		t.Fatalf("expected alice approved at level 20, got %+v", v)
+	}
+	user, err := db.GetUserByID(bob)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if v, _ := db.GetValidation(bob); v == nil || v.Status != ValidationRejected || v.Level != user.SecurityLevel {
+		t.Fatalf("expected bob rejected at an unchanged level, got %+v", v)
+	}
+
+	if err := db.DeleteValidation(bob); err != nil {
+		t.Fatalf("DeleteValidation: %v", err)
+	}
+	if v, err := db.GetValidation(bob); err != nil || v != nil {
+		t.Fatalf("expected bob's validation deleted, got %+v (%v)", v, err)
+	}
+	if err := db.WithTransaction(func(tx *sql.Tx) error { return db.DeleteUserTx(tx, alice) }); err != nil {
+		t.Fatalf("DeleteUserTx: %v", err)
+	}
+	if v, err := db.GetValidation(alice); err != nil || v != nil {
+		t.Fatalf("expected the validation to go with the user, got %+v (%v)", v, err)
+	}
+}
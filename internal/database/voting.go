@@ -0,0 +1,298 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// CreateVotingTopic inserts a new voting topic
+func (s *SQLiteDB) CreateVotingTopic(topic *VotingTopic) (int64, error) {
+	if topic == nil {
+		return 0, fmt.Errorf("voting topic cannot be nil")
+	}
+	if topic.CreatedAt == "" {
+		topic.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO voting_topics (title, description, vote_acs, add_acs, created_by, created_at, active)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, topic.Title, topic.Description, topic.VoteACS, topic.AddACS, topic.CreatedBy, topic.CreatedAt, topic.Active)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create voting topic: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get voting topic ID: %w", err)
+	}
+
+	topic.ID = int(id)
+	return id, nil
+}
+
+// CreateVotingTopicWithChoices inserts a new voting topic and its choices
+// together, so a failure never leaves a topic without its choices. Each
+// choice's TopicID and ID are filled in.
+func (s *SQLiteDB) CreateVotingTopicWithChoices(topic *VotingTopic, choices []VotingChoice) (int64, error) {
+	if topic == nil {
+		return 0, fmt.Errorf("voting topic cannot be nil")
+	}
+	if topic.CreatedAt == "" {
+		topic.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO voting_topics (title, description, vote_acs, add_acs, created_by, created_at, active)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, topic.Title, topic.Description, topic.VoteACS, topic.AddACS, topic.CreatedBy, topic.CreatedAt, topic.Active)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create voting topic: %w", err)
+	}
+	topicID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get voting topic ID: %w", err)
+	}
+
+	for i := range choices {
+		choice := &choices[i]
+		choice.TopicID = int(topicID)
+		result, err := tx.Exec(`
+			INSERT INTO voting_choices (topic_id, position, text, added_by)
+			VALUES (?, ?, ?, ?)
+		`, choice.TopicID, choice.Position, choice.Text, choice.AddedBy)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create voting choice: %w", err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get voting choice ID: %w", err)
+		}
+		choice.ID = int(id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	topic.ID = int(topicID)
+	return topicID, nil
+}
+
+// GetAllVotingTopics returns every voting topic, oldest first, so topic
+// numbers shown to callers stay stable as topics are added
+func (s *SQLiteDB) GetAllVotingTopics() ([]VotingTopic, error) {
+	rows, err := s.db.Query(`
+		SELECT id, title, description, vote_acs, add_acs, created_by, created_at, active
+		FROM voting_topics
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query voting topics: %w", err)
+	}
+	defer rows.Close()
+
+	var topics []VotingTopic
+	for rows.Next() {
+		var topic VotingTopic
+		var activeInt int
+		if err := rows.Scan(&topic.ID, &topic.Title, &topic.Description, &topic.VoteACS, &topic.AddACS,
+			&topic.CreatedBy, &topic.CreatedAt, &activeInt); err != nil {
+			return nil, fmt.Errorf("failed to scan voting topic: %w", err)
+		}
+		topic.Active = activeInt != 0
+		topics = append(topics, topic)
+	}
+
+	return topics, rows.Err()
+}
+
+// UpdateVotingTopic saves a voting topic
+func (s *SQLiteDB) UpdateVotingTopic(topic *VotingTopic) error {
+	if topic == nil {
+		return fmt.Errorf("voting topic cannot be nil")
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE voting_topics
+		SET title = ?, description = ?, vote_acs = ?, add_acs = ?, active = ?
+		WHERE id = ?
+	`, topic.Title, topic.Description, topic.VoteACS, topic.AddACS, topic.Active, topic.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update voting topic: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteVotingTopic removes a voting topic with its choices and votes
+func (s *SQLiteDB) DeleteVotingTopic(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		`DELETE FROM votes WHERE topic_id = ?`,
+		`DELETE FROM voting_choices WHERE topic_id = ?`,
+		`DELETE FROM voting_topics WHERE id = ?`,
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return fmt.Errorf("failed to delete voting topic: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// CreateVotingChoice adds a choice to a topic
+func (s *SQLiteDB) CreateVotingChoice(choice *VotingChoice) (int64, error) {
+	if choice == nil {
+		return 0, fmt.Errorf("voting choice cannot be nil")
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO voting_choices (topic_id, position, text, added_by)
+		VALUES (?, ?, ?, ?)
+	`, choice.TopicID, choice.Position, choice.Text, choice.AddedBy)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create voting choice: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get voting choice ID: %w", err)
+	}
+
+	choice.ID = int(id)
+	return id, nil
+}
+
+// GetVotingChoices returns a topic's choices in order with their vote counts
+func (s *SQLiteDB) GetVotingChoices(topicID int) ([]VotingChoice, error) {
+	rows, err := s.db.Query(`
+		SELECT c.id, c.topic_id, c.position, c.text, c.added_by, COUNT(v.user_id)
+		FROM voting_choices c
+		LEFT JOIN votes v ON v.choice_id = c.id
+		WHERE c.topic_id = ?
+		GROUP BY c.id
+		ORDER BY c.position, c.id
+	`, topicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query voting choices: %w", err)
+	}
+	defer rows.Close()
+
+	var choices []VotingChoice
+	for rows.Next() {
+		var choice VotingChoice
+		if err := rows.Scan(&choice.ID, &choice.TopicID, &choice.Position, &choice.Text, &choice.AddedBy, &choice.Votes); err != nil {
+			return nil, fmt.Errorf("failed to scan voting choice: %w", err)
+		}
+		choices = append(choices, choice)
+	}
+
+	return choices, rows.Err()
+}
+
+// UpdateVotingChoice saves a choice's text and position
+func (s *SQLiteDB) UpdateVotingChoice(choice *VotingChoice) error {
+	if choice == nil {
+		return fmt.Errorf("voting choice cannot be nil")
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE voting_choices SET position = ?, text = ? WHERE id = ?
+	`, choice.Position, choice.Text, choice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update voting choice: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteVotingChoice removes a choice and the votes cast for it
+func (s *SQLiteDB) DeleteVotingChoice(id int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM votes WHERE choice_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete votes for choice: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM voting_choices WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete voting choice: %w", err)
+	}
+	return tx.Commit()
+}
+
+// CastVote records a user's vote on a topic, replacing any earlier vote.
+// The choice must belong to the topic.
+func (s *SQLiteDB) CastVote(topicID, choiceID int, userID int64) error {
+	result, err := s.db.Exec(`
+		INSERT INTO votes (topic_id, user_id, choice_id, voted_at)
+		SELECT topic_id, ?, id, ? FROM voting_choices WHERE id = ? AND topic_id = ?
+		ON CONFLICT(topic_id, user_id) DO UPDATE SET choice_id = excluded.choice_id, voted_at = excluded.voted_at
+	`, userID, time.Now().Format(time.RFC3339), choiceID, topicID)
+	if err != nil {
+		return fmt.Errorf("failed to cast vote: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("choice %d is not part of voting topic %d", choiceID, topicID)
+	}
+	return nil
+}
+
+// GetUserVotes returns the choice a user voted for, by topic ID
+func (s *SQLiteDB) GetUserVotes(userID int64) (map[int]int, error) {
+	rows, err := s.db.Query(`SELECT topic_id, choice_id FROM votes WHERE user_id = ?`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query votes: %w", err)
+	}
+	defer rows.Close()
+
+	votes := make(map[int]int)
+	for rows.Next() {
+		var topicID, choiceID int
+		if err := rows.Scan(&topicID, &choiceID); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		votes[topicID] = choiceID
+	}
+
+	return votes, rows.Err()
+}
+
+// GetTopicVotes returns who voted for what on a topic, ordered by user name
+func (s *SQLiteDB) GetTopicVotes(topicID int) ([]Vote, error) {
+	rows, err := s.db.Query(`
+		SELECT v.topic_id, v.choice_id, v.user_id, COALESCE(u.username, ''), COALESCE(c.text, ''), v.voted_at
+		FROM votes v
+		LEFT JOIN users u ON u.id = v.user_id
+		LEFT JOIN voting_choices c ON c.id = v.choice_id
+		WHERE v.topic_id = ?
+		ORDER BY u.username COLLATE NOCASE
+	`, topicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query topic votes: %w", err)
+	}
+	defer rows.Close()
+
+	var votes []Vote
+	for rows.Next() {
+		var vote Vote
+		if err := rows.Scan(&vote.TopicID, &vote.ChoiceID, &vote.UserID, &vote.Username, &vote.Choice, &vote.VotedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan topic vote: %w", err)
+		}
+		votes = append(votes, vote)
+	}
+
+	return votes, rows.Err()
+}
@@ -0,0 +1,118 @@
+package database
+
+import "testing"
+
+func TestVotesAreCountedAndReplaced(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+
+	topic := VotingTopic{Title: "Best terminal?", Active: true}
+	if _, err := db.CreateVotingTopic(&topic); err != nil {
+		t.Fatalf("CreateVotingTopic: %v", err)
+	}
+	yes := VotingChoice{TopicID: topic.ID, Position: 1, Text: "SyncTERM"}
+	no := VotingChoice{TopicID: topic.ID, Position: 2, Text: "NetRunner"}
+	for _, c := range []*VotingChoice{&yes, &no} {
+		if _, err := db.CreateVotingChoice(c); err != nil {
+			t.Fatalf("CreateVotingChoice: %v", err)
+		}
+	}
+
+	if err := db.CastVote(topic.ID, no.ID, alice); err != nil {
+		t.Fatalf("CastVote: %v", err)
+	}
+	if err := db.CastVote(topic.ID, no.ID, bob); err != nil {
+		t.Fatalf("CastVote: %v", err)
+	}
+	// Voting again changes the vote rather than adding one
+	if err := db.CastVote(topic.ID, yes.ID, alice); err != nil {
+		t.Fatalf("CastVote: %v", err)
+	}
+
+	choices, err := db.GetVotingChoices(topic.ID)
+	if err != nil {
+		t.Fatalf("GetVotingChoices: %v", err)
+	}
+	if len(choices) != 2 || choices[0].Votes != 1 || choices[1].Votes != 1 {
+		t.Fatalf("unexpected choices %+v", choices)
+	}
+
+	votes, err := db.GetTopicVotes(topic.ID)
+	if err != nil {
+		t.Fatalf("GetTopicVotes: %v", err)
+	}
+	if len(votes) != 2 || votes[0].Username != "alice" || votes[0].Choice != "SyncTERM" || votes[1].Choice != "NetRunner" {
+		t.Fatalf("unexpected votes %+v", votes)
+	}
+	if mine, _ := db.GetUserVotes(alice); mine[topic.ID] != yes.ID {
+		t.Fatalf("expected alice's vote for %d, got %v", yes.ID, mine)
+	}
+
+	if err := db.DeleteVotingChoice(int64(no.ID)); err != nil {
+		t.Fatalf("DeleteVotingChoice: %v", err)
+	}
+	if mine, _ := db.GetUserVotes(bob); len(mine) != 0 {
+		t.Fatalf("expected bob's vote removed with the choice, got %v", mine)
+	}
+
+	if err := db.DeleteVotingTopic(int64(topic.ID)); err != nil {
+		t.Fatalf("DeleteVotingTopic: %v", err)
+	}
+	if topics, _ := db.GetAllVotingTopics(); len(topics) != 0 {
+		t.Fatalf("expected no topics, got %+v", topics)
+	}
+	if mine, _ := db.GetUserVotes(alice); len(mine) != 0 {
+		t.Fatalf("expected votes removed with the topic, got %v", mine)
+	}
+}
+
+func TestVotingTopicWithChoicesAndForeignChoice(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	alice := createTestUser(t, db, "alice")
+
+	first := VotingTopic{Title: "Best terminal?", Active: true}
+	choices := []VotingChoice{{Position: 1, Text: "SyncTERM"}, {Position: 2, Text: "NetRunner"}}
+	if _, err := db.CreateVotingTopicWithChoices(&first, choices); err != nil {
+		t.Fatalf("CreateVotingTopicWithChoices: %v", err)
+	}
+	if first.ID == 0 || choices[0].TopicID != first.ID || choices[1].ID == 0 {
+		t.Fatalf("IDs not filled in: topic %+v, choices %+v", first, choices)
+	}
+	if got, _ := db.GetVotingChoices(first.ID); len(got) != 2 {
+		t.Fatalf("expected 2 choices, got %+v", got)
+	}
+
+	second := VotingTopic{Title: "Best editor?", Active: true}
+	other := []VotingChoice{{Position: 1, Text: "QEdit"}, {Position: 2, Text: "TheDraw"}}
+	if _, err := db.CreateVotingTopicWithChoices(&second, other); err != nil {
+		t.Fatalf("CreateVotingTopicWithChoices: %v", err)
+	}
+
+	// A choice from another topic is refused and records nothing
+	if err := db.CastVote(first.ID, other[0].ID, alice); err == nil {
+		t.Fatalf("expected an error voting with another topic's choice")
+	}
+	if err := db.CastVote(first.ID, 9999, alice); err == nil {
+		t.Fatalf("expected an error voting with an unknown choice")
+	}
+	if mine, _ := db.GetUserVotes(alice); len(mine) != 0 {
+		t.Fatalf("expected no votes, got %v", mine)
+	}
+
+	// A failed choice insert leaves no topic behind
+	if _, err := db.db.Exec(`DROP TABLE voting_choices`); err != nil {
+		t.Fatalf("drop voting_choices: %v", err)
+	}
+	third := VotingTopic{Title: "Best modem?", Active: true}
+	if _, err := db.CreateVotingTopicWithChoices(&third, []VotingChoice{{Position: 1, Text: "USR"}}); err == nil {
+		t.Fatalf("expected an error creating choices")
+	}
+	if topics, _ := db.GetAllVotingTopics(); len(topics) != 2 {
+		t.Fatalf("expected the failed topic rolled back, got %+v", topics)
+	}
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetAutomessage returns the current automessage, or nil if none was written
+func (s *SQLiteDB) GetAutomessage() (*Automessage, error) {
+	var msg Automessage
+	var anonymousInt int
+	err := s.db.QueryRow(`
+		SELECT user_id, author, anonymous, text, written_at FROM automessage WHERE id = 1
+	`).Scan(&msg.UserID, &msg.Author, &anonymousInt, &msg.Text, &msg.WrittenAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get automessage: %w", err)
+	}
+	msg.Anonymous = anonymousInt != 0
+	return &msg, nil
+}
+
+// SetAutomessage replaces the automessage
+func (s *SQLiteDB) SetAutomessage(msg *Automessage) error {
+	if msg == nil {
+		return fmt.Errorf("automessage cannot be nil")
+	}
+	if msg.WrittenAt == "" {
+		msg.WrittenAt = time.Now().Format(time.RFC3339)
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO automessage (id, user_id, author, anonymous, text, written_at)
+		VALUES (1, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET user_id = excluded.user_id, author = excluded.author,
+			anonymous = excluded.anonymous, text = excluded.text, written_at = excluded.written_at
+	`, msg.UserID, msg.Author, msg.Anonymous, msg.Text, msg.WrittenAt)
+	if err != nil {
+		return fmt.Errorf("failed to save automessage: %w", err)
+	}
+	return nil
+}
+
+// AddOneLiner adds a line to the one-liner wall
+func (s *SQLiteDB) AddOneLiner(line *OneLiner) error {
+	if line == nil {
+		return fmt.Errorf("one-liner cannot be nil")
+	}
+	if line.PostedAt == "" {
+		line.PostedAt = time.Now().Format(time.RFC3339)
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO oneliners (user_id, username, text, posted_at) VALUES (?, ?, ?, ?)
+	`, line.UserID, line.Username, line.Text, line.PostedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add one-liner: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get one-liner ID: %w", err)
+	}
+	line.ID = int(id)
+	return nil
+}
+
+// GetOneLiners returns the newest limit one-liners, oldest first so the wall
+// reads top to bottom
+func (s *SQLiteDB) GetOneLiners(limit int) ([]OneLiner, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id, username, text, posted_at FROM (
+			SELECT id, user_id, username, text, posted_at FROM oneliners ORDER BY id DESC LIMIT ?
+		) ORDER BY id
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query one-liners: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []OneLiner
+	for rows.Next() {
+		var line OneLiner
+		if err := rows.Scan(&line.ID, &line.UserID, &line.Username, &line.Text, &line.PostedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan one-liner: %w", err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// AddRumor adds a rumor
+func (s *SQLiteDB) AddRumor(rumor *Rumor) error {
+	if rumor == nil {
+		return fmt.Errorf("rumor cannot be nil")
+	}
+	if rumor.AddedAt == "" {
+		rumor.AddedAt = time.Now().Format(time.RFC3339)
+	}
+
+	result, err := s.db.Exec(`
+		INSERT INTO rumors (text, added_by, added_at) VALUES (?, ?, ?)
+	`, rumor.Text, rumor.AddedBy, rumor.AddedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add rumor: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get rumor ID: %w", err)
+	}
+	rumor.ID = int(id)
+	return nil
+}
+
+// GetRandomRumor returns a rumor picked at random, or nil if there are none
+func (s *SQLiteDB) GetRandomRumor() (*Rumor, error) {
+	var rumor Rumor
+	err := s.db.QueryRow(`
+		SELECT id, text, added_by, added_at FROM rumors ORDER BY RANDOM() LIMIT 1
+	`).Scan(&rumor.ID, &rumor.Text, &rumor.AddedBy, &rumor.AddedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rumor: %w", err)
+	}
+	return &rumor, nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import "testing"
+
+func TestAutomessageIsReplacedAndWallKeepsNewest(t *testing.T) {
+	db := setupTestSQLiteDB(t)
+	defer db.Close()
+
+	if msg, err := db.GetAutomessage(); err != nil || msg != nil {
+		t.Fatalf("expected no automessage, got %+v (%v)", msg, err)
+	}
+	if err := db.SetAutomessage(&Automessage{UserID: 1, Author: "alice", Text: "first"}); err != nil {
+		t.Fatalf("SetAutomessage: %v", err)
+	}
+	if err := db.SetAutomessage(&Automessage{UserID: 2, Author: "bob", Anonymous: true, Text: "second"}); err != nil {
+		t.Fatalf("SetAutomessage: %v", err)
+	}
+	msg, err := db.GetAutomessage()
+	if err != nil || msg == nil || msg.Author != "bob" || !msg.Anonymous || msg.Text != "second" || msg.WrittenAt == "" {
+		t.Fatalf("unexpected automessage %+v (%v)", msg, err)
+	}
+
+	for _, text := range []string{"one", "two", "three"} {
+		if err := db.AddOneLiner(&OneLiner{Username: "alice", Text: text}); err != nil {
+			t.Fatalf("AddOneLiner: %v", err)
+		}
+	}
+	lines, err := db.GetOneLiners(2)
+	if err != nil {
+		t.Fatalf("GetOneLiners: %v", err)
+	}
+	if len(lines) != 2 || lines[0].Text != "two" || lines[1].Text != "three" {
+		t.Fatalf("expected the two newest lines oldest first, got %+v", lines)
+	}
+
+	if rumor, err := db.GetRandomRumor(); err != nil || rumor != nil {
+		t.Fatalf("expected no rumor, got %+v (%v)", rumor, err)
+	}
+	if err := db.AddRumor(&Rumor{Text: "The sysop runs OS/2", AddedBy: "bob"}); err != nil {
+		t.Fatalf("AddRumor: %v", err)
+	}
+	if rumor, err := db.GetRandomRumor(); err != nil || rumor == nil || rumor.Text != "The sysop runs OS/2" {
+		t.Fatalf("unexpected rumor %+v (%v)", rumor, err)
+	}
+}
@@ -0,0 +1,200 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/jam"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/qwk"
+)
+
+// commandTimeout bounds how long an external command event may run
+const commandTimeout = 30 * time.Minute
+
+// Action runs one kind of event and returns a short summary for the log
+type Action func(cfg *config.Config, db database.Database, event database.TimedEvent) (string, error)
+
+var actions = map[string]Action{
+	database.EventActionCommand: runCommand,
+	database.EventActionPack:    packMessageBases,
+	database.EventActionNetwork: runNetwork,
+	database.EventActionBackup:  backupDatabase,
+	database.EventActionMessage: postMessage,
+}
+
+// runCommand runs Options through the system shell from the BBS directory
+func runCommand(cfg *config.Config, db database.Database, event database.TimedEvent) (string, error) {
+	line := strings.TrimSpace(event.Options)
+	if line == "" {
+		return "", fmt.Errorf("no command to run")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", line)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", line)
+	}
+	cmd.Dir = cfg.Configuration.Paths.System
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if last := lastLine(out); last != "" {
+			return "", fmt.Errorf("%w: %s", err, last)
+		}
+		return "", err
+	}
+	return "command completed", nil
+}
+
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// packMessageBases packs every message area's JAM base. JAM has no locking,
+// so a message saved while a base is packed would be lost: the pack is
+// skipped while anyone is online.
+func packMessageBases(cfg *config.Config, db database.Database, event database.TimedEvent) (string, error) {
+	if nm := logging.GetNodeManager(); nm != nil {
+		if online := nm.GetActiveNodeCount(); online > 0 {
+			return "", fmt.Errorf("skipped: %d caller(s) online", online)
+		}
+	}
+
+	areas, err := db.GetAllMessageAreas()
+	if err != nil {
+		return "", fmt.Errorf("failed to load message areas: %w", err)
+	}
+
+	var packed int
+	var reclaimed int64
+	var failed []string
+	for _, area := range areas {
+		basePath := filepath.Join(area.Path, area.File)
+		if _, err := os.Stat(basePath + ".jhr"); err != nil {
+			continue
+		}
+		saved, err := jam.Pack(basePath)
+		if err != nil {
+			failed = append(failed, area.File)
+			continue
+		}
+		packed++
+		reclaimed += saved
+	}
+
+	summary := fmt.Sprintf("packed %d bases, reclaimed %d bytes", packed, reclaimed)
+	if len(failed) > 0 {
+		return "", fmt.Errorf("%s; failed: %s", summary, strings.Join(failed, ", "))
+	}
+	return summary, nil
+}
+
+// runNetwork tosses and scans QWKnet mail
+func runNetwork(cfg *config.Config, db database.Database, event database.TimedEvent) (string, error) {
+	result, err := qwk.NewExchange(cfg, db).Run()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("QWKnet %d imported, %d exported, %d skipped", result.Imported, result.Exported, result.Skipped), nil
+}
+
+// backupDatabase copies the database into Options (or the configured backup
+// directory) and prunes old copies
+func backupDatabase(cfg *config.Config, db database.Database, event database.TimedEvent) (string, error) {
+	dir := strings.TrimSpace(event.Options)
+	if dir == "" {
+		dir = cfg.Events.BackupPath
+	}
+	if dir == "" {
+		return "", fmt.Errorf("no backup directory configured")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	path := filepath.Join(dir, "retrograde-"+time.Now().Format("20060102-1504")+".db")
+	if err := db.BackupTo(path); err != nil {
+		return "", err
+	}
+	pruneBackups(dir, cfg.Events.BackupKeep)
+	return "backed up to " + path, nil
+}
+
+// pruneBackups removes all but the newest keep backups
+func pruneBackups(dir string, keep int) {
+	if keep <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "retrograde-*.db"))
+	if err != nil || len(matches) <= keep {
+		return
+	}
+	// The timestamp in the name sorts oldest first
+	sort.Strings(matches)
+	for _, old := range matches[:len(matches)-keep] {
+		os.Remove(old)
+	}
+}
+
+// postMessage posts a message from the sysop to All. Options are
+// "area;subject;text" where area is a message area file name and ~ starts a
+// new line.
+func postMessage(cfg *config.Config, db database.Database, event database.TimedEvent) (string, error) {
+	parts := strings.SplitN(event.Options, ";", 3)
+	if len(parts) != 3 || strings.TrimSpace(parts[2]) == "" {
+		return "", fmt.Errorf("message events need options \"area;subject;text\"")
+	}
+	file := strings.TrimSpace(parts[0])
+
+	areas, err := db.GetAllMessageAreas()
+	if err != nil {
+		return "", fmt.Errorf("failed to load message areas: %w", err)
+	}
+	var area *database.MessageArea
+	for i := range areas {
+		if strings.EqualFold(areas[i].File, file) {
+			area = &areas[i]
+			break
+		}
+	}
+	if area == nil {
+		return "", fmt.Errorf("message area %q not found", file)
+	}
+
+	if err := os.MkdirAll(area.Path, 0755); err != nil {
+		return "", fmt.Errorf("failed to create message directory: %w", err)
+	}
+	base, err := jam.Open(filepath.Join(area.Path, area.File))
+	if err != nil {
+		return "", fmt.Errorf("failed to open message area: %w", err)
+	}
+	defer base.Close()
+
+	msg := jam.NewMessage()
+	msg.Header = &jam.MessageHeader{Attribute: jam.MSG_LOCAL | jam.MSG_TYPELOCAL}
+	msg.From = cfg.Configuration.General.SysOpName
+	if msg.From == "" {
+		msg.From = "SysOp"
+	}
+	msg.To = "All"
+	msg.Subject = strings.TrimSpace(parts[1])
+	msg.Text = strings.ReplaceAll(parts[2], "~", "\n")
+	if _, err := base.WriteMessage(msg); err != nil {
+		return "", fmt.Errorf("failed to post message: %w", err)
+	}
+	return "posted to " + area.Name, nil
+}
@@ -0,0 +1,188 @@
+// Package events runs timed events: maintenance and announcements the server
+// performs on its own at scheduled times.
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed event schedule, matched a minute at a time like cron.
+// Times are in the server's local time zone.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseSchedule parses an event schedule. It accepts:
+//
+//	daily HH:MM
+//	weekly sun,wed HH:MM
+//	a five-field cron expression (minute hour day-of-month month day-of-week)
+//
+// Cron fields take *, lists, ranges and steps (*/15, 1-5, mon-fri).
+func ParseSchedule(spec string) (*Schedule, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("schedule is empty")
+	}
+
+	switch fields[0] {
+	case "daily":
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("daily schedules look like \"daily HH:MM\"")
+		}
+		hour, minute, err := parseClock(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		fields = []string{minute, hour, "*", "*", "*"}
+	case "weekly":
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("weekly schedules look like \"weekly sun,wed HH:MM\"")
+		}
+		hour, minute, err := parseClock(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		fields = []string{minute, hour, "*", "*", fields[1]}
+	}
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected daily, weekly or five cron fields, got %q", spec)
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, _, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, _, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, s.domAny, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, _, err = parseField(fields[3], 1, 12, nil); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, s.dowAny, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	// 7 is Sunday too
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseClock splits HH:MM into cron hour and minute fields
+func parseClock(clock string) (string, string, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	return strconv.Itoa(t.Hour()), strconv.Itoa(t.Minute()), nil
+}
+
+// parseField turns one cron field into a bitset of matching values and
+// reports whether the field was a bare *
+func parseField(field string, min, max int, names []string) (uint64, bool, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if rangePart, stepPart, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, false, fmt.Errorf("invalid step %q", stepPart)
+			}
+			part, step = rangePart, n
+		}
+
+		lo, hi := min, max
+		if part != "*" {
+			first, last, isRange := strings.Cut(part, "-")
+			var err error
+			if lo, err = parseValue(first, min, max, names); err != nil {
+				return 0, false, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseValue(last, min, max, names); err != nil {
+					return 0, false, err
+				}
+			} else if step > 1 {
+				hi = max
+			}
+			if hi < lo {
+				return 0, false, fmt.Errorf("range %q runs backwards", part)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, field == "*", nil
+}
+
+func parseValue(value string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.HasPrefix(value, name) {
+			return i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", value, min, max)
+	}
+	return n, nil
+}
+
+// Next returns the first matching minute after the given time, or the zero
+// time if the schedule never matches (e.g. February 30th)
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches follows cron: when both day fields are restricted, either one
+// matching is enough
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
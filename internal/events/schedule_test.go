@@ -0,0 +1,41 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// Sunday, October 18th 2026
+	from := time.Date(2026, 10, 18, 2, 30, 0, 0, time.Local)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"daily 03:00", time.Date(2026, 10, 18, 3, 0, 0, 0, time.Local)},
+		{"daily 02:30", time.Date(2026, 10, 19, 2, 30, 0, 0, time.Local)},
+		{"weekly wed,fri 04:15", time.Date(2026, 10, 21, 4, 15, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2026, 10, 18, 2, 45, 0, 0, time.Local)},
+		{"0 9-17 * * mon-fri", time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)},
+		{"0 0 1 1 *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.Local)},
+		{"30 2 * * 7", time.Date(2026, 10, 25, 2, 30, 0, 0, time.Local)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		sched, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q): %v", tt.spec, err)
+		}
+		if got := sched.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: next run %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseScheduleRejectsBadInput(t *testing.T) {
+	for _, spec := range []string{"", "daily", "daily 25:00", "weekly 03:00", "* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/bus"
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+)
+
+// tickInterval is how often the scheduler looks for due events
+const tickInterval = 15 * time.Second
+
+// Scheduler runs timed events inside the server process. Events missed while
+// the server was down are not made up; each runs at its next scheduled time.
+type Scheduler struct {
+	db       database.Database
+	now      func() time.Time
+	announce func(text string)
+	started  time.Time
+
+	runMu   sync.Mutex // One event runs at a time
+	mu      sync.Mutex
+	lockout string
+	warned  map[int]time.Time
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+var (
+	runningMu sync.Mutex
+	running   *Scheduler
+)
+
+// NewScheduler creates a scheduler over the timed_events table
+func NewScheduler(db database.Database) *Scheduler {
+	s := &Scheduler{
+		db:       db,
+		now:      time.Now,
+		announce: broadcast,
+		warned:   make(map[int]time.Time),
+	}
+	s.started = s.now()
+	return s
+}
+
+// Running returns the scheduler the server started, or nil
+func Running() *Scheduler {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	return running
+}
+
+// Start checks for due events in the background until Stop is called
+func (s *Scheduler) Start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	runningMu.Lock()
+	running = s
+	runningMu.Unlock()
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		for {
+			s.tick(s.now())
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends the background loop, waiting for a running event to finish
+func (s *Scheduler) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	runningMu.Lock()
+	if running == s {
+		running = nil
+	}
+	runningMu.Unlock()
+}
+
+// Lockout reports the event new callers are being kept out for, if any
+func (s *Scheduler) Lockout() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lockout, s.lockout != ""
+}
+
+// NextRun returns when an event will next run, or the zero time if its
+// schedule never matches
+func (s *Scheduler) NextRun(event database.TimedEvent) (time.Time, error) {
+	sched, err := ParseSchedule(event.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(s.reference(event)), nil
+}
+
+// RunNow runs an event immediately, whether or not it is enabled
+func (s *Scheduler) RunNow(id int) (string, error) {
+	events, err := s.db.GetAllTimedEvents()
+	if err != nil {
+		return "", err
+	}
+	for _, event := range events {
+		if event.ID == id {
+			return s.run(s.loadConfig(), event, s.now()), nil
+		}
+	}
+	return "", fmt.Errorf("timed event %d not found", id)
+}
+
+// tick runs the events that are due, warns callers about upcoming ones and
+// updates the lockout
+func (s *Scheduler) tick(now time.Time) {
+	cfg := s.loadConfig()
+	if !cfg.Events.Enabled {
+		s.setLockout("")
+		return
+	}
+	events, err := s.db.GetAllTimedEvents()
+	if err != nil {
+		fmt.Printf("Warning: could not load timed events: %v\n", err)
+		return
+	}
+
+	lockout := ""
+	for _, event := range events {
+		if !event.Enabled {
+			continue
+		}
+		next, err := s.NextRun(event)
+		if err != nil || next.IsZero() {
+			continue
+		}
+		if !next.After(now) {
+			s.run(cfg, event, now)
+			continue
+		}
+
+		until := next.Sub(now)
+		if lockout == "" && event.LockoutMinutes > 0 && until <= time.Duration(event.LockoutMinutes)*time.Minute {
+			lockout = event.Name
+		}
+		if event.WarnMinutes > 0 && until <= time.Duration(event.WarnMinutes)*time.Minute && !s.warned[event.ID].Equal(next) {
+			s.warned[event.ID] = next
+			minutes := int((until + time.Minute - 1) / time.Minute)
+			s.announce(fmt.Sprintf("System event \"%s\" starts at %s (in %d min).", event.Name, next.Format("15:04"), minutes))
+		}
+	}
+	s.setLockout(lockout)
+}
+
+// run performs one event and records the outcome
+func (s *Scheduler) run(cfg *config.Config, event database.TimedEvent, now time.Time) string {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	// A pack must not have callers logging on while it runs
+	if event.LockoutMinutes > 0 || event.Action == database.EventActionPack {
+		s.setLockout(event.Name)
+		defer s.setLockout("")
+	}
+
+	var result string
+	if action, ok := actions[event.Action]; !ok {
+		result = fmt.Sprintf("failed: unknown action %q", event.Action)
+	} else if summary, err := action(cfg, s.db, event); err != nil {
+		result = "failed: " + err.Error()
+	} else {
+		result = summary
+	}
+
+	if err := s.db.RecordTimedEventRun(event.ID, now.Format(time.RFC3339), result); err != nil {
+		fmt.Printf("Warning: could not record run of %s: %v\n", event.Name, err)
+	}
+	logging.LogEvent(0, "SYSTEM", "", "EVENT", fmt.Sprintf("%s: %s", event.Name, result))
+	return result
+}
+
+// reference is the time the next run is counted from: the last run, or when
+// the scheduler started if the event has not run since
+func (s *Scheduler) reference(event database.TimedEvent) time.Time {
+	last, err := time.Parse(time.RFC3339, event.LastRun)
+	if err != nil || last.Before(s.started) {
+		return s.started
+	}
+	return last
+}
+
+func (s *Scheduler) loadConfig() *config.Config {
+	if cfg, err := config.LoadConfigFromDB(s.db); err == nil {
+		return cfg
+	}
+	return config.GetDefaultConfig()
+}
+
+func (s *Scheduler) setLockout(name string) {
+	s.mu.Lock()
+	s.lockout = name
+	s.mu.Unlock()
+}
+
+// broadcast warns every online caller
+func broadcast(text string) {
+	bus.Default().Publish(bus.TopicBroadcast, bus.Event{FromUser: "System", Text: text})
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+)
+
+// fakeEventDB keeps timed events in memory
+type fakeEventDB struct {
+	database.Database
+	mu     sync.Mutex
+	events []database.TimedEvent
+}
+
+func (db *fakeEventDB) GetAllConfigValues() ([]database.ConfigValue, error) {
+	return nil, nil
+}
+
+func (db *fakeEventDB) GetAllTimedEvents() ([]database.TimedEvent, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return append([]database.TimedEvent(nil), db.events...), nil
+}
+
+func (db *fakeEventDB) RecordTimedEventRun(id int, ranAt, result string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for i := range db.events {
+		if db.events[i].ID == id {
+			db.events[i].LastRun = ranAt
+			db.events[i].LastResult = result
+		}
+	}
+	return nil
+}
+
+func TestSchedulerRunsWarnsAndLocksOut(t *testing.T) {
+	logging.SetLogDirectory(t.TempDir())
+
+	var runs int
+	var lockedDuringRun bool
+	var s *Scheduler
+	actions["test"] = func(cfg *config.Config, db database.Database, event database.TimedEvent) (string, error) {
+		runs++
+		_, lockedDuringRun = s.Lockout()
+		return "done", nil
+	}
+	defer delete(actions, "test")
+
+	db := &fakeEventDB{events: []database.TimedEvent{
+		{ID: 1, Name: "Maintenance", Schedule: "daily 03:00", Action: "test", LockoutMinutes: 5, WarnMinutes: 10, Enabled: true},
+		{ID: 2, Name: "Disabled", Schedule: "daily 02:55", Action: "test", Enabled: false},
+	}}
+	start := time.Date(2026, 10, 18, 2, 0, 0, 0, time.Local)
+	s = NewScheduler(db)
+	s.started = start
+	var warnings []string
+	s.announce = func(text string) { warnings = append(warnings, text) }
+
+	s.tick(start.Add(45 * time.Minute))
+	if len(warnings) != 0 || runs != 0 {
+		t.Fatalf("nothing should happen at 02:45, got %d warnings and %d runs", len(warnings), runs)
+	}
+
+	s.tick(start.Add(52 * time.Minute))
+	s.tick(start.Add(53 * time.Minute))
+	if len(warnings) != 1 {
+		t.Fatalf("expected one warning, got %v", warnings)
+	}
+	if _, locked := s.Lockout(); locked {
+		t.Fatalf("should not be locked out 8 minutes ahead")
+	}
+
+	s.tick(start.Add(56 * time.Minute))
+	if name, locked := s.Lockout(); !locked || name != "Maintenance" {
+		t.Fatalf("expected lockout for Maintenance, got %q %v", name, locked)
+	}
+
+	s.tick(start.Add(60*time.Minute + 10*time.Second))
+	if runs != 1 || !lockedDuringRun {
+		t.Fatalf("expected one run under lockout, got %d runs (locked %v)", runs, lockedDuringRun)
+	}
+	if _, locked := s.Lockout(); locked {
+		t.Fatalf("lockout should end after the run")
+	}
+	if got := db.events[0]; got.LastResult != "done" || got.LastRun == "" {
+		t.Fatalf("run not recorded: %+v", got)
+	}
+
+	s.tick(start.Add(61 * time.Minute))
+	if runs != 1 {
+		t.Fatalf("event ran again in the same day: %d runs", runs)
+	}
+	next, err := s.NextRun(db.events[0])
+	if err != nil || !next.Equal(time.Date(2026, 10, 19, 3, 0, 0, 0, time.Local)) {
+		t.Fatalf("unexpected next run %v (%v)", next, err)
+	}
+}
+
+func TestSchedulerSkipsEventsMissedWhileDown(t *testing.T) {
+	logging.SetLogDirectory(t.TempDir())
+
+	db := &fakeEventDB{events: []database.TimedEvent{
+		{ID: 1, Name: "Backup", Schedule: "daily 03:00", Action: "missing", Enabled: true, LastRun: "2026-10-16T03:00:00Z"},
+	}}
+	s := NewScheduler(db)
+	s.started = time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+
+	s.tick(s.started.Add(time.Minute))
+	if db.events[0].LastResult != "" {
+		t.Fatalf("missed event should not run at startup, got %q", db.events[0].LastResult)
+	}
+
+	if result, err := s.RunNow(1); err != nil || result != `failed: unknown action "missing"` {
+		t.Fatalf("RunNow: %q, %v", result, err)
+	}
+}
+
+func TestPackSkippedWhileCallersOnline(t *testing.T) {
+	logging.InitializeNodeManager(2)
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	node := logging.GetNodeManager().AssignNode(server, "alice")
+
+	_, err := packMessageBases(config.GetDefaultConfig(), &fakeEventDB{}, database.TimedEvent{Action: database.EventActionPack})
+	if err == nil || !strings.Contains(err.Error(), "1 caller(s) online") {
+		t.Fatalf("expected the pack to be skipped, got %v", err)
+	}
+	logging.GetNodeManager().ReleaseNode(node)
+}
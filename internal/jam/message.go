@@ -354,14 +354,33 @@ func (j *JAMBase) DeleteMessage(msgNum int) error {
 	hdr.TxtLen = 0
 
 	// Update header
+	if err := j.UpdateMessageHeader(msgNum, hdr); err != nil {
+		return err
+	}
+
+	// Update fixed header
+	j.fixedHeader.ActiveMsgs--
+	j.fixedHeader.ModCounter++
+	return j.writeFixedHeader()
+}
+
+// UpdateMessageHeader rewrites the fixed part of an existing message header
+// in place (attributes, counters, dates). Subfields are left untouched.
+func (j *JAMBase) UpdateMessageHeader(msgNum int, hdr *MessageHeader) error {
+	if !j.isOpen {
+		return ErrBaseNotOpen
+	}
+
 	idx, err := j.ReadIndexRecord(msgNum)
 	if err != nil {
 		return err
 	}
 
-	j.jhrFile.Seek(int64(idx.HdrOffset), 0)
+	if _, err := j.jhrFile.Seek(int64(idx.HdrOffset), 0); err != nil {
+		return fmt.Errorf("failed to seek to message header: %w", err)
+	}
 
-	// Write fixed part only (to update attributes)
+	// Write fixed part only
 	binary.Write(j.jhrFile, binary.LittleEndian, hdr.Signature)
 	binary.Write(j.jhrFile, binary.LittleEndian, hdr.Revision)
 	binary.Write(j.jhrFile, binary.LittleEndian, hdr.ReservedWord)
@@ -383,8 +402,5 @@ func (j *JAMBase) DeleteMessage(msgNum int) error {
 	binary.Write(j.jhrFile, binary.LittleEndian, hdr.PasswordCRC)
 	binary.Write(j.jhrFile, binary.LittleEndian, hdr.Cost)
 
-	// Update fixed header
-	j.fixedHeader.ActiveMsgs--
-	j.fixedHeader.ModCounter++
-	return j.writeFixedHeader()
+	return nil
 }
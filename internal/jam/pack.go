@@ -0,0 +1,117 @@
+package jam
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Pack rewrites the base without the text of deleted messages and returns
+// how many bytes were reclaimed. Headers and message numbers are kept, so
+// lastread pointers stay valid. The base must not be open elsewhere.
+func Pack(basePath string) (int64, error) {
+	src, err := Open(basePath)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	before, err := src.jdtFile.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat .jdt file: %w", err)
+	}
+
+	tmpPath := basePath + ".pack"
+	removeBase(tmpPath)
+	dst := &JAMBase{BasePath: tmpPath}
+	if err := dst.Create(); err != nil {
+		return 0, err
+	}
+	if err := copyMessages(src, dst); err != nil {
+		dst.Close()
+		removeBase(tmpPath)
+		return 0, err
+	}
+
+	after, err := dst.jdtFile.Stat()
+	if err != nil {
+		dst.Close()
+		removeBase(tmpPath)
+		return 0, fmt.Errorf("failed to stat packed .jdt file: %w", err)
+	}
+	dst.Close()
+	src.Close()
+
+	for _, ext := range []string{".jhr", ".jdt", ".jdx"} {
+		if err := os.Rename(tmpPath+ext, basePath+ext); err != nil {
+			return 0, fmt.Errorf("failed to replace %s file: %w", ext, err)
+		}
+	}
+	os.Remove(tmpPath + ".jlr")
+
+	return before.Size() - after.Size(), nil
+}
+
+// copyMessages copies every header of src into dst, with the text of live
+// messages only
+func copyMessages(src, dst *JAMBase) error {
+	count, err := src.GetMessageCount()
+	if err != nil {
+		return err
+	}
+
+	for msgNum := 1; msgNum <= count; msgNum++ {
+		idx, err := src.ReadIndexRecord(msgNum)
+		if errors.Is(err, ErrNotFound) {
+			// An unused slot; keep it so later messages keep their numbers
+			if err := dst.WriteIndexRecord(msgNum, &IndexRecord{ToCRC: 0xFFFFFFFF, HdrOffset: 0xFFFFFFFF}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read index of message %d: %w", msgNum, err)
+		}
+		hdr, err := src.ReadMessageHeader(msgNum)
+		if err != nil {
+			return fmt.Errorf("failed to read message %d: %w", msgNum, err)
+		}
+
+		text := make([]byte, hdr.TxtLen)
+		if hdr.Attribute&MSG_DELETED != 0 {
+			text = nil
+		} else if _, err := src.jdtFile.ReadAt(text, int64(hdr.Offset)); err != nil {
+			return fmt.Errorf("failed to read text of message %d: %w", msgNum, err)
+		}
+
+		offset, err := dst.jdtFile.Seek(0, io.SeekEnd)
+		if err != nil {
+			return fmt.Errorf("failed to seek packed .jdt file: %w", err)
+		}
+		if _, err := dst.jdtFile.Write(text); err != nil {
+			return fmt.Errorf("failed to write text of message %d: %w", msgNum, err)
+		}
+		hdr.Offset = uint32(offset)
+		hdr.TxtLen = uint32(len(text))
+
+		hdrOffset, err := dst.WriteMessageHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if err := dst.WriteIndexRecord(msgNum, &IndexRecord{ToCRC: idx.ToCRC, HdrOffset: hdrOffset}); err != nil {
+			return err
+		}
+	}
+
+	header := *src.fixedHeader
+	header.ModCounter++
+	dst.fixedHeader = &header
+	return dst.writeFixedHeader()
+}
+
+func removeBase(basePath string) {
+	for _, ext := range []string{".jhr", ".jdt", ".jdx", ".jlr"} {
+		os.Remove(basePath + ext)
+	}
+}
@@ -0,0 +1,54 @@
+package jam
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackKeepsNumbering(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), "general")
+	base, err := Open(basePath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, subject := range []string{"one", "two", "three"} {
+		msg := NewMessage()
+		msg.From, msg.To, msg.Subject = "Sysop", "All", subject
+		msg.Text = strings.Repeat(subject+" ", 200)
+		if _, err := base.WriteMessage(msg); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+	}
+	if err := base.DeleteMessage(2); err != nil {
+		t.Fatalf("DeleteMessage: %v", err)
+	}
+	base.Close()
+
+	reclaimed, err := Pack(basePath)
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+	if reclaimed != int64(len("two ")*200) {
+		t.Errorf("reclaimed %d bytes, want %d", reclaimed, len("two ")*200)
+	}
+
+	base, err = Open(basePath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer base.Close()
+	if count, _ := base.GetMessageCount(); count != 3 {
+		t.Fatalf("message count %d after pack, want 3", count)
+	}
+	msg, err := base.ReadMessage(3)
+	if err != nil || msg.Subject != "three" || !strings.HasPrefix(msg.Text, "three three") {
+		t.Fatalf("message 3 after pack = %+v, %v", msg, err)
+	}
+	if deleted, err := base.ReadMessage(2); err != nil || !deleted.IsDeleted() {
+		t.Fatalf("message 2 after pack = %+v, %v", deleted, err)
+	}
+	if base.GetActiveMessageCount() != 2 {
+		t.Errorf("active messages %d, want 2", base.GetActiveMessageCount())
+	}
+}
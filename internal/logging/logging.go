@@ -18,8 +18,25 @@ type NodeConnection = config.NodeConnection
 var (
 	nodeManager *NodeManager
 	logMutex    sync.Mutex
+	logDir      = "logs"
 )
 
+// SetLogDirectory sets where log files and chat transcripts are written
+func SetLogDirectory(dir string) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	if dir != "" {
+		logDir = dir
+	}
+}
+
+// LogDirectory returns the directory log files are written to
+func LogDirectory() string {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	return logDir
+}
+
 // GetNodeManager returns the global node manager instance
 func GetNodeManager() *NodeManager {
 	return nodeManager
@@ -27,11 +44,7 @@ func GetNodeManager() *NodeManager {
 
 // InitializeNodeManager creates and initializes the global node manager
 func InitializeNodeManager(maxNodes int) {
-	nodeManager = &NodeManager{
-		MaxNodes:    maxNodes,
-		Connections: make(map[int]*NodeConnection),
-		NextNode:    1,
-	}
+	nodeManager = config.NewNodeManager(maxNodes)
 }
 
 // AssignNodeWithLogging assigns a node to a connection, logs it, and returns the node number
@@ -49,12 +62,10 @@ func AssignNodeWithLogging(conn net.Conn, username string) int {
 
 // ReleaseNodeWithLogging releases a node, logs the disconnection, and marks it as disconnected
 func ReleaseNodeWithLogging(nodeID int) {
-	nm := GetNodeManager()
-	if conn, exists := nm.Connections[nodeID]; exists && conn.Connected {
+	if conn, released := GetNodeManager().ReleaseNode(nodeID); released {
 		LogEvent(nodeID, conn.Username, conn.IPAddress, "DISCONNECT",
 			fmt.Sprintf("Disconnected from node %d after %v",
 				nodeID, time.Since(conn.ConnectTime).Round(time.Second)))
-		nm.ReleaseNode(nodeID)
 	}
 }
 
@@ -64,7 +75,6 @@ func LogEvent(nodeID int, username, ipAddress, action, details string) {
 	defer logMutex.Unlock()
 
 	// Create logs directory if it doesn't exist
-	logDir := "logs"
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		fmt.Printf("Error creating logs directory: %v\n", err)
 		return
@@ -158,7 +168,7 @@ func LogAdminAction(nodeID int, adminUser, ipAddress, action, details string) {
 
 // GetLogFilePath returns the path to today's log file
 func GetLogFilePath() string {
-	logDir := "logs"
+	logDir := LogDirectory()
 	now := time.Now()
 	logFileName := fmt.Sprintf("%s.log", now.Format("2006-01-02"))
 	return filepath.Join(logDir, logFileName)
@@ -166,7 +176,7 @@ func GetLogFilePath() string {
 
 // ListLogFiles returns a list of all available log files
 func ListLogFiles() ([]string, error) {
-	logDir := "logs"
+	logDir := LogDirectory()
 	files, err := os.ReadDir(logDir)
 	if err != nil {
 		return nil, err
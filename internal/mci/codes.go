@@ -0,0 +1,174 @@
+package mci
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// Built-in data codes. Other packages add theirs with Register.
+//
+//	User:    UN handle, UR real name, UL location, US security level, UE email,
+//	         UF first login date, LO last login date
+//	System:  BN BBS name, SN sysop name, BL BBS location
+//	Node:    ND node number, NU nodes in use, NM total nodes
+//	Area:    MB message base, MN message base number, MC conference
+//	Time:    TL minutes left, TO minutes online, TI time, DA date, DW day of week
+//	Screen:  CL clear screen, CR new line, TW terminal width, TH terminal height
+var builtins = map[string]Func{
+	"UN": func(c *Context) string {
+		if c != nil && c.Session != nil {
+			return c.Session.Alias
+		}
+		return ""
+	},
+	"UR": func(c *Context) string {
+		user := c.User()
+		if user == nil {
+			return ""
+		}
+		return strings.TrimSpace(user.FirstName.String + " " + user.LastName.String)
+	},
+	"UL": func(c *Context) string {
+		if user := c.User(); user != nil {
+			return user.Locations.String
+		}
+		return ""
+	},
+	"US": func(c *Context) string {
+		if c != nil && c.Session != nil {
+			return strconv.Itoa(c.Session.SecurityLevel)
+		}
+		return ""
+	},
+	"UE": func(c *Context) string {
+		if user := c.User(); user != nil {
+			return user.Email.String
+		}
+		return ""
+	},
+	"UF": func(c *Context) string {
+		if user := c.User(); user != nil {
+			return formatDate(user.CreatedDate)
+		}
+		return ""
+	},
+	"LO": func(c *Context) string {
+		if user := c.User(); user != nil {
+			return formatDate(user.LastLogin.String)
+		}
+		return ""
+	},
+
+	"BN": func(c *Context) string {
+		if cfg := c.Configuration(); cfg != nil {
+			return cfg.Configuration.General.BBSName
+		}
+		return ""
+	},
+	"SN": func(c *Context) string {
+		if cfg := c.Configuration(); cfg != nil {
+			return cfg.Configuration.General.SysOpName
+		}
+		return ""
+	},
+	"BL": func(c *Context) string {
+		if cfg := c.Configuration(); cfg != nil {
+			return cfg.Configuration.General.BBSLocation
+		}
+		return ""
+	},
+
+	"ND": func(c *Context) string {
+		if c != nil && c.Session != nil {
+			return strconv.Itoa(c.Session.NodeNumber)
+		}
+		return ""
+	},
+	"NU": func(c *Context) string {
+		if nm := logging.GetNodeManager(); nm != nil {
+			return strconv.Itoa(nm.GetActiveNodeCount())
+		}
+		return "0"
+	},
+	"NM": func(c *Context) string {
+		if nm := logging.GetNodeManager(); nm != nil {
+			return strconv.Itoa(nm.GetNodeCount())
+		}
+		return "0"
+	},
+
+	"MB": func(c *Context) string {
+		if c != nil && c.Session != nil && c.Session.CurrentMessageArea != nil {
+			return c.Session.CurrentMessageArea.Name
+		}
+		return ""
+	},
+	"MN": func(c *Context) string {
+		if c != nil && c.Session != nil && c.Session.CurrentMessageArea != nil {
+			return strconv.Itoa(c.Session.CurrentMessageArea.ID)
+		}
+		return ""
+	},
+	"MC": func(c *Context) string {
+		if c != nil && c.Session != nil && c.Session.CurrentMessageArea != nil {
+			return c.Session.CurrentMessageArea.ConferenceName
+		}
+		return ""
+	},
+
+	"TL": func(c *Context) string {
+		if c == nil || c.Session == nil {
+			return ""
+		}
+		left := c.Session.TimeLeft - int(time.Since(c.Session.StartTime).Minutes())
+		return strconv.Itoa(max(0, left))
+	},
+	"TO": func(c *Context) string {
+		if c == nil || c.Session == nil || c.Session.StartTime.IsZero() {
+			return "0"
+		}
+		return strconv.Itoa(int(time.Since(c.Session.StartTime).Minutes()))
+	},
+	"TI": func(c *Context) string { return time.Now().Format("15:04") },
+	"DA": func(c *Context) string { return time.Now().Format("01/02/06") },
+	"DW": func(c *Context) string { return time.Now().Format("Monday") },
+
+	"CL": func(c *Context) string { return ui.Ansi.EraseScreen + ui.Ansi.CursorTopLeft },
+	"CR": func(c *Context) string { return "\r\n" },
+	"TW": func(c *Context) string {
+		if c != nil && c.Session != nil && c.Session.Width > 0 {
+			return strconv.Itoa(c.Session.Width)
+		}
+		return "80"
+	},
+	"TH": func(c *Context) string {
+		if c != nil && c.Session != nil && c.Session.Height > 0 {
+			return strconv.Itoa(c.Session.Height)
+		}
+		return "24"
+	},
+}
+
+func init() {
+	for code, fn := range builtins {
+		Register(code, fn)
+	}
+}
+
+// formatDate shows a stored timestamp as MM/DD/YY, or as-is if it doesn't parse
+func formatDate(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Local().Format("01/02/06")
+		}
+	}
+	return value
+}
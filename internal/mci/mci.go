@@ -0,0 +1,252 @@
+// Package mci expands Renegade/Mystic-style MCI codes in prompts, menu titles
+// and display files.
+//
+// Codes start with a pipe:
+//
+//	|00-|15   foreground color      |16-|23   background color
+//	|UN |BN   two-letter data codes (see codes.go); subsystems add their own with Register
+//	|[X##     move to column ##     |[Y##     move to row ##
+//	|[A## |[B## |[C## |[D##         move up, down, right or left ## cells
+//	|$R## |$L## |$C##               pad the next data code right, left or centered to ## columns
+//	|$T##                           trim the next data code to ## columns
+//	|$D##c                          repeat character c ## times
+//
+// Anything that is not a known code is left as-is.
+package mci
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// Func produces the text for a data code
+type Func func(ctx *Context) string
+
+// Context carries what data codes draw from. Any field may be nil; codes
+// whose data is missing expand to an empty string.
+type Context struct {
+	Session *config.TelnetSession
+	UserID  int64
+	DB      database.Database
+	Config  *config.Config // Loaded from DB on first use when nil
+
+	user       *database.UserRecord
+	userLoaded bool
+	cfgLoaded  bool
+}
+
+// User returns the signed-on user's record, loading it once
+func (c *Context) User() *database.UserRecord {
+	if c == nil {
+		return nil
+	}
+	if !c.userLoaded {
+		c.userLoaded = true
+		if c.DB != nil && c.UserID > 0 {
+			if user, err := c.DB.GetUserByID(c.UserID); err == nil {
+				c.user = user
+			}
+		}
+	}
+	return c.user
+}
+
+// Configuration returns the system configuration, loading it once
+func (c *Context) Configuration() *config.Config {
+	if c == nil {
+		return nil
+	}
+	if c.Config == nil && !c.cfgLoaded {
+		c.cfgLoaded = true
+		if c.DB != nil {
+			if cfg, err := config.LoadConfigFromDB(c.DB); err == nil {
+				c.Config = cfg
+			}
+		}
+	}
+	return c.Config
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Func)
+)
+
+// Register adds a data code, replacing any existing code with the same name.
+// Codes are a letter followed by a letter or digit and are written in upper
+// case in text; Register accepts either case.
+func Register(code string, fn Func) error {
+	code = strings.ToUpper(code)
+	if len(code) != 2 || !isLetter(code[0]) || !isAlnum(code[1]) {
+		return fmt.Errorf("invalid MCI code %q: want a letter followed by a letter or digit", code)
+	}
+	if fn == nil {
+		return fmt.Errorf("MCI code %s has no function", code)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[code] = fn
+	return nil
+}
+
+// Codes lists the registered data codes in alphabetical order
+func Codes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	codes := make([]string, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func lookup(code string) (Func, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	fn, ok := registry[code]
+	return fn, ok
+}
+
+// pad is a pending |$ padding instruction for the next data code
+type pad struct {
+	mode  byte // 'R', 'L', 'C' or 'T'
+	width int
+}
+
+// Expand replaces MCI codes in text
+func Expand(text string, ctx *Context) string {
+	if !strings.Contains(text, "|") {
+		return text
+	}
+
+	var out strings.Builder
+	var colors ui.PipeColors
+	var pending *pad
+
+	for i := 0; i < len(text); {
+		if text[i] != '|' || i+2 >= len(text) {
+			out.WriteByte(text[i])
+			i++
+			continue
+		}
+		a, b := text[i+1], text[i+2]
+
+		switch {
+		case isDigit(a) && isDigit(b):
+			if seq, ok := colors.Code(int(a-'0')*10 + int(b-'0')); ok {
+				out.WriteString(seq)
+				i += 3
+				continue
+			}
+
+		case a == '[' && i+4 < len(text) && isDigit(text[i+3]) && isDigit(text[i+4]):
+			if seq, ok := cursor(b, atoi2(text[i+3:i+5])); ok {
+				out.WriteString(seq)
+				i += 5
+				continue
+			}
+
+		case a == '$' && i+4 < len(text) && isDigit(text[i+3]) && isDigit(text[i+4]):
+			n := atoi2(text[i+3 : i+5])
+			switch b {
+			case 'R', 'L', 'C', 'T':
+				pending = &pad{mode: b, width: n}
+				i += 5
+				continue
+			case 'D':
+				if i+5 < len(text) {
+					r, size := utf8.DecodeRuneInString(text[i+5:])
+					out.WriteString(strings.Repeat(string(r), n))
+					i += 5 + size
+					continue
+				}
+			}
+
+		case isUpper(a) && (isUpper(b) || isDigit(b)):
+			if fn, ok := lookup(text[i+1 : i+3]); ok {
+				out.WriteString(applyPad(fn(ctx), pending))
+				pending = nil
+				i += 3
+				continue
+			}
+		}
+
+		out.WriteByte('|')
+		i++
+	}
+	return out.String()
+}
+
+// PrintArt loads a theme file and prints it with MCI codes expanded. Terminals
+// with iCE color support get the .icy variant when there is one.
+func PrintArt(term ui.InteractiveTerminal, filename string, ctx *Context) error {
+	ice := ctx != nil && ctx.Session != nil && ctx.Session.ICEColors
+	content, sauce, err := ui.LoadArt(filename, ice)
+	if err != nil {
+		return err
+	}
+	if err := term.Print(ui.WithICEColors(Expand(content, ctx), sauce, ice)); err != nil {
+		return fmt.Errorf("failed to print ANSI art: %w", err)
+	}
+	return nil
+}
+
+func cursor(dir byte, n int) (string, bool) {
+	switch dir {
+	case 'X':
+		return fmt.Sprintf("%s%dG", ui.Esc, max(1, n)), true
+	case 'Y':
+		return fmt.Sprintf("%s%dd", ui.Esc, max(1, n)), true
+	case 'A', 'B', 'C', 'D':
+		if n == 0 {
+			return "", true
+		}
+		return fmt.Sprintf("%s%d%c", ui.Esc, n, dir), true
+	}
+	return "", false
+}
+
+func applyPad(value string, p *pad) string {
+	if p == nil {
+		return value
+	}
+	visible := utf8.RuneCountInString(ui.StripANSI(value))
+	if p.mode == 'T' {
+		if visible > p.width {
+			return string([]rune(ui.StripANSI(value))[:p.width])
+		}
+		return value
+	}
+	gap := p.width - visible
+	if gap <= 0 {
+		return value
+	}
+	switch p.mode {
+	case 'L':
+		return strings.Repeat(" ", gap) + value
+	case 'C':
+		left := gap / 2
+		return strings.Repeat(" ", left) + value + strings.Repeat(" ", gap-left)
+	default:
+		return value + strings.Repeat(" ", gap)
+	}
+}
+
+func atoi2(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isUpper(c byte) bool  { return c >= 'A' && c <= 'Z' }
+func isLetter(c byte) bool { return isUpper(c) || (c >= 'a' && c <= 'z') }
+func isAlnum(c byte) bool  { return isLetter(c) || isDigit(c) }
@@ -0,0 +1,114 @@
+package mci
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+func testContext() *Context {
+	cfg := config.GetDefaultConfig()
+	cfg.Configuration.General.BBSName = "Retro Board"
+	cfg.Configuration.General.SysOpName = "Robbie"
+	return &Context{
+		Session: &config.TelnetSession{
+			Alias:              "Alice",
+			SecurityLevel:      20,
+			TimeLeft:           60,
+			StartTime:          time.Now(),
+			NodeNumber:         3,
+			CurrentMessageArea: &database.MessageArea{ID: 4, Name: "General", ConferenceName: "Local"},
+		},
+		Config: cfg,
+	}
+}
+
+func TestExpandDataCodes(t *testing.T) {
+	got := Expand("Hi |UN on |BN node |ND in |MB (|TL left), sysop |SN", testContext())
+	want := "Hi Alice on Retro Board node 3 in General (60 left), sysop Robbie"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestExpandLeavesUnknownCodes(t *testing.T) {
+	for _, text := range []string{"a|ZZb", "pipe | alone", "lower |un", "trailing |", "|9", "|99"} {
+		if got := Expand(text, testContext()); got != text {
+			t.Fatalf("Expand(%q) = %q, want unchanged", text, got)
+		}
+	}
+}
+
+func TestExpandColors(t *testing.T) {
+	got := Expand("|20|15X|07Y", nil)
+	want := ui.Ansi.BgBlue + ui.Ansi.Reset + ui.Ansi.BgBlue + ui.Ansi.WhiteHi + "X" + ui.Ansi.Reset + ui.Ansi.BgBlue + ui.Ansi.White + "Y"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if ui.ParsePipeColorCodes("|20|15X|07Y") != want {
+		t.Fatalf("ParsePipeColorCodes disagrees with Expand on background colors")
+	}
+}
+
+func TestExpandPadding(t *testing.T) {
+	ctx := testContext()
+	cases := map[string]string{
+		"[|$R08|UN]": "[Alice   ]",
+		"[|$L08|UN]": "[   Alice]",
+		"[|$C09|UN]": "[  Alice  ]",
+		"[|$T03|UN]": "[Ali]",
+		"|$D05-":     "-----",
+		"[|$R02|UN]": "[Alice]",
+	}
+	for text, want := range cases {
+		if got := Expand(text, ctx); got != want {
+			t.Fatalf("Expand(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestExpandCursor(t *testing.T) {
+	got := Expand("|[X10|[Y05|[A02|[D01", nil)
+	want := ui.Esc + "10G" + ui.Esc + "5d" + ui.Esc + "2A" + ui.Esc + "1D"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestRegisterPluginCode(t *testing.T) {
+	if err := Register("q1", func(ctx *Context) string { return "queued" }); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if got := Expand("|Q1", nil); got != "queued" {
+		t.Fatalf("got %q, want plugin output", got)
+	}
+	found := false
+	for _, code := range Codes() {
+		if code == "Q1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected Q1 in Codes()")
+	}
+
+	for _, bad := range []string{"1Q", "Q", "QQQ", "Q-"} {
+		if err := Register(bad, func(ctx *Context) string { return "" }); err == nil {
+			t.Fatalf("expected Register(%q) to fail", bad)
+		}
+	}
+}
+
+func TestNilContextCodesAreEmpty(t *testing.T) {
+	got := Expand("[|UN|UR|BN|MB]", nil)
+	if got != "[]" {
+		t.Fatalf("got %q, want empty codes", got)
+	}
+	if !strings.Contains(Expand("|TI", nil), ":") {
+		t.Fatalf("expected a clock time for |TI")
+	}
+}
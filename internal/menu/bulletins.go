@@ -0,0 +1,294 @@
+package menu
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// Default file names for OS: the bulletin menu, and the prefix of the
+// numbered bulletins (BULLET1, BULLET2, ...)
+const (
+	defaultBulletinMenu   = "BULLETIN"
+	defaultBulletinPrefix = "BULLET"
+)
+
+// bulletin is one numbered bulletin file
+type bulletin struct {
+	Number  int
+	Title   string
+	Path    string
+	Updated time.Time
+}
+
+// isNew reports whether the bulletin changed after since. Everything is new
+// to a caller who has never logged in before.
+func (b bulletin) isNew(since time.Time) bool {
+	return since.IsZero() || b.Updated.After(since)
+}
+
+// parseBulletinOptions splits OS options into the menu file and bulletin
+// prefix, filling in the defaults
+func parseBulletinOptions(options string) (menuName, prefix string) {
+	menuName, prefix, _ = strings.Cut(options, ";")
+	menuName, prefix = strings.TrimSpace(menuName), strings.TrimSpace(prefix)
+	if menuName == "" {
+		menuName = defaultBulletinMenu
+	}
+	if prefix == "" {
+		prefix = defaultBulletinPrefix
+	}
+	return menuName, prefix
+}
+
+// bulletinDirs returns where bulletins are kept: the current conference's
+// own directory first, then the shared one
+func bulletinDirs(ctx *ExecutionContext) []string {
+	themeDir := "theme"
+	if ctx.Executor != nil {
+		themeDir = ctx.Executor.getThemeBaseDir()
+	}
+	shared := filepath.Join(themeDir, "bulletins")
+	if ctx.Session != nil && ctx.Session.CurrentMessageArea != nil && ctx.Session.CurrentMessageArea.ConferenceID > 0 {
+		return []string{filepath.Join(shared, strconv.Itoa(ctx.Session.CurrentMessageArea.ConferenceID)), shared}
+	}
+	return []string{shared}
+}
+
+// findBulletins lists the numbered bulletins with the given prefix from the
+// first directory that has any, in number order
+func findBulletins(ctx *ExecutionContext, dirs []string, prefix string) (string, []bulletin) {
+	pattern := regexp.MustCompile(`^(?i)` + regexp.QuoteMeta(prefix) + `(\d+)\.[a-z0-9]+$`)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		seen := make(map[int]bool)
+		var bulletins []bulletin
+		for _, entry := range entries {
+			m := pattern.FindStringSubmatch(entry.Name())
+			if entry.IsDir() || m == nil {
+				continue
+			}
+			number, err := strconv.Atoi(m[1])
+			if err != nil || number <= 0 || seen[number] {
+				continue
+			}
+			path := findExactDisplayFile(ctx, prefix+m[1], dir)
+			if path == "" {
+				continue
+			}
+			seen[number] = true
+			bulletins = append(bulletins, readBulletin(number, path))
+		}
+		if len(bulletins) > 0 {
+			sort.Slice(bulletins, func(i, j int) bool { return bulletins[i].Number < bulletins[j].Number })
+			return dir, bulletins
+		}
+	}
+	return "", nil
+}
+
+// findExactDisplayFile finds name with one of the session's display
+// extensions. Unlike findDisplayFile it never picks a numbered variant, so
+// BULLET1 cannot turn up when BULLET is asked for.
+func findExactDisplayFile(ctx *ExecutionContext, name string, dirs ...string) string {
+	extensions := displayExtensions(ctx)
+	if sessionEmulation(ctx) == ui.EmulationASCII {
+		extensions = append(extensions, ui.DisplayExtensions(ui.EmulationANSI)...)
+	}
+	for _, ext := range extensions {
+		if path := resolveDisplayFile(ctx, name+ext, dirs...); path != "" {
+			return path
+		}
+	}
+	return ""
+}
+
+// readBulletin fills in a bulletin's title and date. The title is the SAUCE
+// title, or the first line of a plain text file.
+func readBulletin(number int, path string) bulletin {
+	b := bulletin{Number: number, Path: path, Title: fmt.Sprintf("Bulletin %d", number)}
+	if info, err := os.Stat(path); err == nil {
+		b.Updated = info.ModTime()
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return b
+	}
+	sauce, body := ui.ParseSauce(content)
+	if sauce != nil && strings.TrimSpace(sauce.Title) != "" {
+		b.Title = strings.TrimSpace(sauce.Title)
+		return b
+	}
+	if bytes.IndexByte(body, 0x1b) >= 0 {
+		return b
+	}
+	for _, line := range strings.Split(string(body), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			b.Title = line
+			break
+		}
+	}
+	return b
+}
+
+// handleBulletins handles OS: show the bulletin menu and read bulletins until
+// the caller quits.
+// Options: <main bulletin;sub-bulletin> - the menu file (default BULLETIN)
+// and the prefix of the numbered bulletins (default BULLET)
+func handleBulletins(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.IO == nil {
+		return fmt.Errorf("bulletins require an execution context with IO")
+	}
+	io := ctx.IO
+	menuName, prefix := parseBulletinOptions(options)
+	dirs := bulletinDirs(ctx)
+	dir, bulletins := findBulletins(ctx, dirs, prefix)
+	if len(bulletins) == 0 {
+		io.Print(ui.Ansi.Yellow + "\r\n There are no bulletins.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+	since := previousLogin(ctx)
+
+	showMenu := true
+	for {
+		if showMenu {
+			printBulletinMenu(ctx, findExactDisplayFile(ctx, menuName, dir), bulletins, since)
+		}
+		showMenu = false
+
+		io.Print("\r\n")
+		answer, err := ui.PromptSimple(io, " Bulletin # (N)ew, (L)ist, (Q)uit: ", 3, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil
+			}
+			return err
+		}
+		answer = strings.ToUpper(strings.TrimSpace(answer))
+		switch answer {
+		case "", "Q":
+			io.Print("\r\n")
+			return nil
+		case "L", "?":
+			showMenu = true
+			continue
+		case "N":
+			if !showNewBulletins(ctx, bulletins, since) {
+				io.Print(ui.Ansi.Yellow + "\r\n\r\n No bulletins have changed since your last call.\r\n" + ui.Ansi.Reset)
+				continue
+			}
+			showMenu = true
+			continue
+		}
+
+		number, err := strconv.Atoi(answer)
+		found := false
+		for _, b := range bulletins {
+			if err == nil && b.Number == number {
+				if err := showBulletin(ctx, b); err != nil {
+					return err
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			io.Print(ui.Ansi.RedHi + "\r\n\r\n No such bulletin.\r\n" + ui.Ansi.Reset)
+			continue
+		}
+		showMenu = true
+	}
+}
+
+// printBulletinMenu shows the sysop's bulletin menu file, or a generated list
+// with changed bulletins flagged
+func printBulletinMenu(ctx *ExecutionContext, menuPath string, bulletins []bulletin, since time.Time) {
+	io := ctx.IO
+	io.ClearScreen()
+	if menuPath != "" {
+		if _, err := displayFile(ctx, menuPath, true, displayFileFlags{}); err == nil {
+			return
+		}
+	}
+
+	io.Print(ui.Ansi.WhiteHi + "\r\n Bulletins\r\n\r\n" + ui.Ansi.Reset)
+	io.Print(ui.Ansi.Cyan + fmt.Sprintf(" %3s  %-50s %-8s\r\n", "#", "Title", "Updated") + ui.Ansi.Reset)
+	for _, b := range bulletins {
+		flag := ""
+		if b.isNew(since) {
+			flag = ui.Ansi.RedHi + " NEW"
+		}
+		io.Printf(ui.Ansi.YellowHi+" %3d  "+ui.Ansi.WhiteHi+"%-50.50s "+ui.Ansi.Cyan+"%-8s%s\r\n"+ui.Ansi.Reset,
+			b.Number, b.Title, b.Updated.Format("01/02/06"), flag)
+	}
+}
+
+// showBulletin displays one bulletin, pausing each screen
+func showBulletin(ctx *ExecutionContext, b bulletin) error {
+	ctx.IO.ClearScreen()
+	if _, err := displayFile(ctx, b.Path, true, displayFileFlags{pause: true}); err != nil {
+		return err
+	}
+	ui.Pause(ctx.IO)
+	return nil
+}
+
+// showNewBulletins displays every bulletin changed since the caller's last
+// call, reporting false if there were none
+func showNewBulletins(ctx *ExecutionContext, bulletins []bulletin, since time.Time) bool {
+	shown := false
+	for _, b := range bulletins {
+		if !b.isNew(since) {
+			continue
+		}
+		if err := showBulletin(ctx, b); err != nil {
+			fmt.Printf("Warning: could not show bulletin %s: %v\n", b.Path, err)
+			continue
+		}
+		shown = true
+	}
+	return shown
+}
+
+func previousLogin(ctx *ExecutionContext) time.Time {
+	if ctx.Session == nil {
+		return time.Time{}
+	}
+	return ctx.Session.PreviousLogin
+}
+
+// runBulletinsStep offers the bulletins changed since the caller's last call
+// at logon. Options are the same as OS.
+func runBulletinsStep(ctx *ExecutionContext, options string) (CmdResult, error) {
+	_, prefix := parseBulletinOptions(options)
+	_, bulletins := findBulletins(ctx, bulletinDirs(ctx), prefix)
+	since := previousLogin(ctx)
+	count := 0
+	for _, b := range bulletins {
+		if b.isNew(since) {
+			count++
+		}
+	}
+	if count == 0 {
+		return ResultContinue, nil
+	}
+
+	read, err := askYesNo(ctx, fmt.Sprintf(" %d bulletin(s) changed since your last call. Read them now?", count), true)
+	if err != nil || !read {
+		return ResultContinue, err
+	}
+	showNewBulletins(ctx, bulletins, since)
+	return ResultContinue, nil
+}
@@ -0,0 +1,60 @@
+package menu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+)
+
+func TestFindBulletinsPrefersTheConferenceDirectory(t *testing.T) {
+	shared := t.TempDir()
+	conference := filepath.Join(shared, "2")
+	if err := os.Mkdir(conference, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(dir, name, content string, modified time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lastCall := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	write(shared, "BULLET1.ANS", "Shared", lastCall)
+	write(conference, "BULLETIN.ANS", "menu", lastCall)
+	write(conference, "bullet10.asc", "\r\n  System News\r\nBody", lastCall.Add(time.Hour))
+	write(conference, "BULLET2.ANS", "\x1b[1mArt", lastCall.Add(-time.Hour))
+	write(conference, "BULLET2.ASC", "Plain", lastCall.Add(-time.Hour))
+
+	ctx := &ExecutionContext{Session: &config.TelnetSession{
+		CurrentMessageArea: &database.MessageArea{ConferenceID: 2},
+		PreviousLogin:      lastCall,
+	}}
+	dir, bulletins := findBulletins(ctx, []string{conference, shared}, "BULLET")
+	if dir != conference || len(bulletins) != 2 {
+		t.Fatalf("expected two conference bulletins, got %q %+v", dir, bulletins)
+	}
+	if bulletins[0].Number != 2 || filepath.Base(bulletins[0].Path) != "BULLET2.ANS" || bulletins[0].Title != "Bulletin 2" {
+		t.Fatalf("expected the ANSI bulletin 2 with a numbered title, got %+v", bulletins[0])
+	}
+	if bulletins[1].Number != 10 || bulletins[1].Title != "System News" {
+		t.Fatalf("expected bulletin 10 titled from its first line, got %+v", bulletins[1])
+	}
+	if bulletins[0].isNew(ctx.Session.PreviousLogin) || !bulletins[1].isNew(ctx.Session.PreviousLogin) {
+		t.Fatalf("expected only bulletin 10 to be new since the last call")
+	}
+	if path := findExactDisplayFile(ctx, "BULLETIN", dir); filepath.Base(path) != "BULLETIN.ANS" {
+		t.Fatalf("expected the bulletin menu, got %q", path)
+	}
+
+	if menuName, prefix := parseBulletinOptions(" NEWS ; NEWS "); menuName != "NEWS" || prefix != "NEWS" {
+		t.Fatalf("unexpected options %q %q", menuName, prefix)
+	}
+}
@@ -0,0 +1,213 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// How many calls the last callers list falls back to when nobody has called
+// today, and how many users a Top 10 list shows
+const (
+	lastCallersCount = 10
+	topUsersCount    = 10
+)
+
+// topLists are the Top 10 lists OB offers, by option letter
+var topLists = []struct {
+	Key   string
+	Stat  string
+	Title string
+	Unit  string
+}{
+	{"C", database.StatCalls, "Top 10 Callers", "Calls"},
+	{"P", database.StatPosts, "Top 10 Posters", "Posts"},
+	{"U", database.StatUploads, "Top 10 Uploaders", "Uploads"},
+	{"D", database.StatDownloads, "Top 10 Downloaders", "Downloads"},
+	{"T", database.StatMinutes, "Top 10 Time Online", "Minutes"},
+}
+
+// RecordLogon adds the caller to the caller history and counts the call.
+// The server calls it once the caller has logged in; Logoff completes the
+// record.
+func (e *MenuExecutor) RecordLogon(ctx *ExecutionContext) {
+	if e.db == nil || ctx == nil || ctx.Session == nil || ctx.UserID <= 0 {
+		return
+	}
+	session := ctx.Session
+	call := &database.CallerRecord{
+		UserID:    ctx.UserID,
+		Username:  ctx.Username,
+		Node:      session.NodeNumber,
+		IPAddress: session.IPAddress,
+		NewUser:   session.PreviousLogin.IsZero(),
+	}
+	if details, err := e.db.GetUserDetails(ctx.UserID); err == nil {
+		call.Location = details["locations"]
+	}
+	id, err := e.db.RecordCallLogon(call)
+	if err != nil {
+		fmt.Printf("Warning: could not record logon: %v\n", err)
+		return
+	}
+	e.callID = id
+	countStat(e.db, ctx.UserID, database.StatCalls, 1)
+}
+
+// recordLogoff completes the caller history record RecordLogon started
+func (e *MenuExecutor) recordLogoff(online int) {
+	if e.callID == 0 {
+		return
+	}
+	if err := e.db.RecordCallLogoff(e.callID, online); err != nil {
+		fmt.Printf("Warning: could not record logoff: %v\n", err)
+	}
+}
+
+// countStat adds n to one of the user's counters and to today's system
+// counter. A userID of 0 only counts the day.
+func countStat(db database.Database, userID int64, stat string, n int) {
+	if db == nil {
+		return
+	}
+	if userID > 0 && stat != database.StatNewUsers {
+		if err := db.IncrementUserStat(userID, stat, n); err != nil {
+			fmt.Printf("Warning: could not update user stats: %v\n", err)
+		}
+	}
+	if err := db.IncrementDailyStat(time.Now().Format("2006-01-02"), stat, n); err != nil {
+		fmt.Printf("Warning: could not update daily stats: %v\n", err)
+	}
+}
+
+// handleLastCallers handles OL: list today's callers, or the last ten calls
+// when nobody has called yet today.
+// Options: <display file> - shown above the list instead of the heading
+func handleLastCallers(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.IO == nil || ctx.Executor == nil || ctx.Executor.db == nil {
+		return fmt.Errorf("last callers require an execution context with IO and a database")
+	}
+	db := ctx.Executor.db
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	title, today := "Today's Callers", true
+	calls, err := db.GetCallers(midnight.Format(time.RFC3339), 100)
+	if err == nil && len(calls) == 0 {
+		title, today = "Last Callers", false
+		calls, err = db.GetCallers("", lastCallersCount)
+	}
+	if err != nil {
+		return err
+	}
+
+	io := ctx.IO
+	io.ClearScreen()
+	header := ""
+	if name := strings.TrimSpace(options); name != "" {
+		header = findDisplayFile(ctx, name)
+	}
+	if header == "" {
+		io.Print(ui.Ansi.WhiteHi + "\r\n " + title + "\r\n\r\n" + ui.Ansi.Reset)
+	} else if _, err := displayFile(ctx, header, true, displayFileFlags{}); err != nil {
+		return err
+	}
+
+	if len(calls) == 0 {
+		io.Print(ui.Ansi.Yellow + " Nobody has called yet.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+	io.Print(ui.Ansi.Cyan + fmt.Sprintf(" %-4s %-20s %-24s %-8s %-8s %4s\r\n", "Node", "User", "Location", "On", "Off", "Mins") + ui.Ansi.Reset)
+	io.Print(ui.Ansi.Cyan + " " + strings.Repeat("\xc4", 73) + "\r\n" + ui.Ansi.Reset)
+	for _, call := range calls {
+		off := callTime(call.LogoffAt, today)
+		if call.LogoffAt == "" {
+			off = "Online"
+		}
+		flag := ""
+		if call.NewUser {
+			flag = ui.Ansi.RedHi + " NEW"
+		}
+		io.Printf(ui.Ansi.YellowHi+" %4d "+ui.Ansi.WhiteHi+"%-20.20s "+ui.Ansi.Cyan+"%-24.24s "+ui.Ansi.White+"%-8s %-8s %4d%s\r\n"+ui.Ansi.Reset,
+			call.Node, call.Username, call.Location, callTime(call.LogonAt, today), off, call.Minutes, flag)
+	}
+	ui.Pause(io)
+	return nil
+}
+
+// callTime formats a caller history time as a time of day for today's list,
+// or a date otherwise
+func callTime(value string, today bool) string {
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return ""
+	}
+	if today {
+		return at.Local().Format("15:04")
+	}
+	return at.Local().Format("01/02/06")
+}
+
+// handleUserStats handles OB: show the Top 10 lists.
+// Options: <C|P|U|D|T> - show that list (calls, posts, uploads, downloads or
+// time online) instead of asking
+func handleUserStats(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.IO == nil || ctx.Executor == nil || ctx.Executor.db == nil {
+		return fmt.Errorf("user statistics require an execution context with IO and a database")
+	}
+	io := ctx.IO
+	if key := strings.ToUpper(strings.TrimSpace(options)); key != "" {
+		return showTopList(ctx, key)
+	}
+
+	for {
+		io.Print("\r\n")
+		answer, err := ui.PromptSimple(io, " (C)alls (P)osts (U)ploads (D)ownloads (T)ime online (Q)uit: ", 1, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil
+			}
+			return err
+		}
+		answer = strings.ToUpper(strings.TrimSpace(answer))
+		if answer == "" || answer == "Q" {
+			io.Print("\r\n")
+			return nil
+		}
+		if err := showTopList(ctx, answer); err != nil {
+			return err
+		}
+	}
+}
+
+// showTopList prints the Top 10 list picked by key
+func showTopList(ctx *ExecutionContext, key string) error {
+	io := ctx.IO
+	for _, list := range topLists {
+		if list.Key != key {
+			continue
+		}
+		top, err := ctx.Executor.db.GetTopUsers(list.Stat, topUsersCount)
+		if err != nil {
+			return err
+		}
+		io.ClearScreen()
+		io.Print(ui.Ansi.WhiteHi + "\r\n " + list.Title + "\r\n\r\n" + ui.Ansi.Reset)
+		if len(top) == 0 {
+			io.Print(ui.Ansi.Yellow + " Nobody is on this list yet.\r\n" + ui.Ansi.Reset)
+		} else {
+			io.Print(ui.Ansi.Cyan + fmt.Sprintf(" %3s  %-30s %9s\r\n", "#", "User", list.Unit) + ui.Ansi.Reset)
+		}
+		for i, user := range top {
+			io.Printf(ui.Ansi.YellowHi+" %3d  "+ui.Ansi.WhiteHi+"%-30.30s "+ui.Ansi.Cyan+"%9d\r\n"+ui.Ansi.Reset, i+1, user.Username, user.Value)
+		}
+		ui.Pause(io)
+		return nil
+	}
+	io.Print(ui.Ansi.RedHi + "\r\n\r\n No such list.\r\n" + ui.Ansi.Reset)
+	return nil
+}
@@ -1,13 +1,12 @@
 package menu
 
 import (
+	"errors"
 	"fmt"
 	"strings"
-	"time"
 	"unicode"
 
 	"github.com/robbiew/retrograde/internal/config"
-	"github.com/robbiew/retrograde/internal/logging"
 	"github.com/robbiew/retrograde/internal/telnet"
 	"github.com/robbiew/retrograde/internal/ui"
 )
@@ -15,6 +14,42 @@ import (
 // CmdKeyHandler is the function signature for command key handlers
 type CmdKeyHandler func(ctx *ExecutionContext, options string) error
 
+// CmdResult tells the executor what to do after a command runs
+type CmdResult int
+
+const (
+	ResultContinue  CmdResult = iota // Run the next linked command
+	ResultStopChain                  // Skip the remaining linked commands
+	ResultRedisplay                  // Skip the remaining linked commands and redraw the menu
+	ResultExitMenu                   // Leave the menu, e.g. after goto, gosub or return
+	ResultHangup                     // End the caller's session
+)
+
+// String returns the result's name, for logs and test failures
+func (r CmdResult) String() string {
+	switch r {
+	case ResultContinue:
+		return "continue"
+	case ResultStopChain:
+		return "stop-chain"
+	case ResultRedisplay:
+		return "redisplay"
+	case ResultExitMenu:
+		return "exit-menu"
+	case ResultHangup:
+		return "hangup"
+	}
+	return fmt.Sprintf("CmdResult(%d)", int(r))
+}
+
+// CmdKeyResultHandler is a handler that tells the executor how to carry on:
+// gates stop the linked command chain, navigation leaves the menu and logoff
+// commands hang up
+type CmdKeyResultHandler func(ctx *ExecutionContext, options string) (CmdResult, error)
+
+// errUserLogout is what older handlers return to end the session
+var errUserLogout = errors.New("user_logout")
+
 // ExecutionContext holds the context for executing a command
 type ExecutionContext struct {
 	UserID      int64
@@ -23,6 +58,7 @@ type ExecutionContext struct {
 	Session     *config.TelnetSession
 	Executor    *MenuExecutor
 	AdvanceRows func(lines int)
+	Input       string // Number typed for a # command, empty otherwise
 	// Add more context as needed: session, database, etc.
 }
 
@@ -35,18 +71,19 @@ type CmdKeyDefinition struct {
 	NodeActivity string // Default node activity text shown to other users
 	Implemented  bool   // Whether this command is fully implemented
 	Handler      CmdKeyHandler
+	Result       CmdKeyResultHandler // Used instead of Handler when set
 }
 
 // CmdKeyRegistry holds the registered command key handlers
 type CmdKeyRegistry struct {
-	handlers    map[string]CmdKeyHandler
+	handlers    map[string]CmdKeyResultHandler
 	definitions map[string]*CmdKeyDefinition
 }
 
 // NewCmdKeyRegistry creates a new command key registry
 func NewCmdKeyRegistry() *CmdKeyRegistry {
 	r := &CmdKeyRegistry{
-		handlers:    make(map[string]CmdKeyHandler),
+		handlers:    make(map[string]CmdKeyResultHandler),
 		definitions: make(map[string]*CmdKeyDefinition),
 	}
 	r.registerDefaults()
@@ -57,15 +94,49 @@ func NewCmdKeyRegistry() *CmdKeyRegistry {
 func (r *CmdKeyRegistry) Register(def *CmdKeyDefinition) {
 	key := strings.ToUpper(def.CmdKey)
 	def.NodeActivity = computeNodeActivity(def)
-	r.handlers[key] = def.Handler
+	handler := def.Result
+	if handler == nil {
+		handler = AdaptHandler(def.Handler)
+	}
+	r.handlers[key] = handler
 	r.definitions[key] = def
 }
 
+// AdaptHandler turns a handler that only returns an error into a result
+// handler. Success continues the chain, and the sentinel errors older
+// handlers used become results: errRedisplayMenu redraws the menu and
+// "user_logout" hangs up.
+func AdaptHandler(handler CmdKeyHandler) CmdKeyResultHandler {
+	return func(ctx *ExecutionContext, options string) (CmdResult, error) {
+		err := handler(ctx, options)
+		switch {
+		case err == nil:
+			return ResultContinue, nil
+		case errors.Is(err, errRedisplayMenu):
+			return ResultRedisplay, nil
+		case errors.Is(err, errUserLogout) || err.Error() == errUserLogout.Error():
+			return ResultHangup, nil
+		}
+		return ResultContinue, err
+	}
+}
+
+// WithResult adapts an error-only handler that always ends with result when
+// it succeeds
+func WithResult(handler CmdKeyHandler, result CmdResult) CmdKeyResultHandler {
+	return func(ctx *ExecutionContext, options string) (CmdResult, error) {
+		if err := handler(ctx, options); err != nil {
+			return ResultContinue, err
+		}
+		return result, nil
+	}
+}
+
 // Execute executes a command key with the given context and options
-func (r *CmdKeyRegistry) Execute(cmdKey string, ctx *ExecutionContext, options string) error {
+func (r *CmdKeyRegistry) Execute(cmdKey string, ctx *ExecutionContext, options string) (CmdResult, error) {
 	handler, exists := r.handlers[strings.ToUpper(cmdKey)]
 	if !exists {
-		return fmt.Errorf("unknown command key: %s", cmdKey)
+		return ResultContinue, fmt.Errorf("unknown command key: %s", cmdKey)
 	}
 	return handler(ctx, options)
 }
@@ -111,41 +182,6 @@ func (r *CmdKeyRegistry) registerDefaults() {
 	registerMiscCommands(r)
 }
 
-func handleGoToMenu(ctx *ExecutionContext, options string) error {
-	if ctx == nil {
-		return fmt.Errorf("go to menu command requires an execution context")
-	}
-
-	target := strings.TrimSpace(options)
-	if target == "" {
-		return fmt.Errorf("go to menu command requires a target menu name in options")
-	}
-
-	if ctx.Executor == nil {
-		return fmt.Errorf("go to menu command cannot run without a menu executor")
-	}
-
-	return ctx.Executor.ExecuteMenu(target, ctx)
-}
-
-// handleGoodbye handles the G command (logout)
-func handleGoodbye(ctx *ExecutionContext, options string) error {
-	// Log the logout
-	logging.LogLogout(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress)
-
-	// Display goodbye message
-	ctx.IO.Printf("Goodbye, %s!\r\n", ctx.Username)
-	time.Sleep(2 * time.Second) // Give time to read message
-
-	// Disconnect the user
-	ctx.Session.Connected = false
-	if ctx.Session.Conn != nil {
-		ctx.Session.Conn.Close()
-	}
-
-	return nil
-}
-
 // handleDisplayLine renders a single line of text honouring pipe color codes.
 func handleDisplayLine(ctx *ExecutionContext, options string) error {
 	if ctx == nil || ctx.IO == nil {
@@ -153,7 +189,7 @@ func handleDisplayLine(ctx *ExecutionContext, options string) error {
 	}
 
 	normalized := strings.ReplaceAll(options, "~", "\r\n")
-	colored := ui.ParsePipeColorCodes(normalized)
+	colored := expandMCI(ctx, normalized)
 
 	var out strings.Builder
 	out.WriteString("\r\n") // move below the menu prompt
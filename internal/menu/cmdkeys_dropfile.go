@@ -15,7 +15,7 @@ func registerDropfileCommands(r *CmdKeyRegistry) {
 
 	for _, def := range defs {
 		d := def
-		if d.Handler == nil {
+		if d.Handler == nil && d.Result == nil {
 			d.Handler = handleNotImplemented
 		}
 		r.Register(&d)
@@ -27,7 +27,7 @@ func registerFileCommands(r *CmdKeyRegistry) {
 
 	for _, def := range defs {
 		d := def
-		if d.Handler == nil {
+		if d.Handler == nil && d.Result == nil {
 			d.Handler = handleNotImplemented
 		}
 		r.Register(&d)
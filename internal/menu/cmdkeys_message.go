@@ -6,7 +6,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
 	"github.com/robbiew/retrograde/internal/jam"
+	"github.com/robbiew/retrograde/internal/notify"
+	"github.com/robbiew/retrograde/internal/telnet"
 	"github.com/robbiew/retrograde/internal/ui"
 )
 
@@ -52,7 +56,7 @@ func registerMessageCommands(r *CmdKeyRegistry) {
 
 	for _, def := range defs {
 		d := def
-		if d.Handler == nil {
+		if d.Handler == nil && d.Result == nil {
 			d.Handler = handleNotImplemented
 		}
 		r.Register(&d)
@@ -118,34 +122,13 @@ func handlePostMessage(ctx *ExecutionContext, options string) error {
 	}
 
 	// Get message text using the simple text editor
-	io.Print("\r\n" + ui.Ansi.Yellow + " Enter your message (empty line to end):\r\n\r\n" + ui.Ansi.Reset)
-
-	var lines []string
-	lineNumber := 1
-
-	for {
-		io.Printf("%2d: ", lineNumber)
-		line, err := ui.PromptSimple(io, "", 75, ui.Ansi.Green, ui.Ansi.White, ui.Ansi.BgBlack, "")
-		if err != nil {
-			if err.Error() == "ESC_PRESSED" {
-				io.ClearScreen()
-				return nil
-			}
-			return err
-		}
-
-		if strings.TrimSpace(line) == "" {
-			break
-		}
-
-		lines = append(lines, line)
-		lineNumber++
-
-		// Limit message length
-		if len(lines) >= 100 {
-			io.Print(ui.Ansi.Yellow + "\r\n Maximum message length reached.\r\n" + ui.Ansi.Reset)
-			break
-		}
+	lines, escaped, err := readMessageLines(io)
+	if err != nil {
+		return err
+	}
+	if escaped {
+		io.ClearScreen()
+		return nil
 	}
 
 	if len(lines) == 0 {
@@ -215,6 +198,13 @@ func handlePostMessage(ctx *ExecutionContext, options string) error {
 		return nil
 	}
 
+	if ctx.Executor != nil && ctx.Executor.db != nil {
+		countStat(ctx.Executor.db, ctx.UserID, database.StatPosts, 1)
+		if cfg, err := config.LoadConfigFromDB(ctx.Executor.db); err == nil {
+			notify.NewPost(cfg, *session.CurrentMessageArea, msgNum, message.From, message.To, message.Subject, message.IsPrivate())
+		}
+	}
+
 	io.Printf(ui.Ansi.GreenHi+"\r\n Message #%d posted successfully!\r\n"+ui.Ansi.Reset, msgNum)
 	ui.Pause(io)
 	io.ClearScreen()
@@ -328,6 +318,7 @@ func handleReadMessages(ctx *ExecutionContext, options string) error {
 
 		// Mark message as read
 		jamBase.MarkMessageRead(ctx.Username, currentMsg)
+		session.MarkRead(session.CurrentMessageArea.File, currentMsg)
 
 		// Show prompt
 		io.Print(ui.Ansi.Cyan + " (N)ext, (P)revious, (Q)uit: " + ui.Ansi.Reset)
@@ -363,3 +354,40 @@ func handleReadMessages(ctx *ExecutionContext, options string) error {
 
 	return nil
 }
+
+// readMessageLines runs the simple line editor until an empty line is
+// entered. escaped is true when the user abandoned the message with ESC.
+func readMessageLines(io *telnet.TelnetIO) (lines []string, escaped bool, err error) {
+	io.Print("\r\n" + ui.Ansi.Yellow + " Enter your message (empty line to end):\r\n\r\n" + ui.Ansi.Reset)
+	return readTextLines(io, 100, 75)
+}
+
+// readTextLines reads numbered lines of up to width characters until an
+// empty line is entered or maxLines have been written
+func readTextLines(io *telnet.TelnetIO, maxLines, width int) (lines []string, escaped bool, err error) {
+	lineNumber := 1
+	for {
+		io.Printf("%2d: ", lineNumber)
+		line, err := ui.PromptSimple(io, "", width, ui.Ansi.Green, ui.Ansi.White, ui.Ansi.BgBlack, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil, true, nil
+			}
+			return nil, false, err
+		}
+
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+
+		lines = append(lines, line)
+		lineNumber++
+
+		// Limit message length
+		if len(lines) >= maxLines {
+			io.Print(ui.Ansi.Yellow + "\r\n Maximum message length reached.\r\n" + ui.Ansi.Reset)
+			break
+		}
+	}
+	return lines, false, nil
+}
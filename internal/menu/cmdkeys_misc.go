@@ -17,22 +17,24 @@ func registerMiscCommands(r *CmdKeyRegistry) {
 		{CmdKey: "$-", Name: "Increase Debit", Description: "Increase a user's debit balance", Category: "Credit"},
 
 		// Hangup / Logoff
-		{CmdKey: "HC", Name: "Careful Logoff", Description: "Prompt and then log off if confirmed", Category: "Hangup"},
-		{CmdKey: "HI", Name: "Immediate Logoff", Description: "Log off immediately", Category: "Hangup"},
-		{CmdKey: "HM", Name: "Display & Logoff", Description: "Display a string and log off the user", Category: "Hangup"},
+		{CmdKey: "HC", Name: "Careful Logoff", Description: "Prompt and then log off if confirmed", Category: "Hangup", NodeActivity: "Logging off.", Implemented: true, Result: handleCarefulLogoff},
+		{CmdKey: "HI", Name: "Immediate Logoff", Description: "Log off immediately", Category: "Hangup", NodeActivity: "Logging off.", Implemented: true, Result: handleImmediateLogoff},
+		{CmdKey: "HM", Name: "Display & Logoff", Description: "Display a string and log off the user", Category: "Hangup", NodeActivity: "Logging off.", Implemented: true, Result: handleMessageLogoff},
 
 		// Automessage
-		{CmdKey: "UA", Name: "Reply to Automessage", Description: "Reply to the current automessage author", Category: "Automessage"},
-		{CmdKey: "UR", Name: "Display Automessage", Description: "Display the current automessage", Category: "Automessage"},
-		{CmdKey: "UW", Name: "Write Automessage", Description: "Write a new automessage", Category: "Automessage"},
+		{CmdKey: "UA", Name: "Reply to Automessage", Description: "Reply to the current automessage author", Category: "Automessage", Implemented: true, Handler: handleReplyAutomessage},
+		{CmdKey: "UR", Name: "Display Automessage", Description: "Display the current automessage", Category: "Automessage", Implemented: true, Handler: handleReadAutomessage},
+		{CmdKey: "UW", Name: "Write Automessage", Description: "Write a new automessage", Category: "Automessage", NodeActivity: "Writing the automessage.", Implemented: true, Handler: handleWriteAutomessage},
+		{CmdKey: "UO", Name: "One-Liner Wall", Description: "Show the one-liner wall and offer to add a line", Category: "Automessage", NodeActivity: "Reading the one-liners.", Implemented: true, Handler: handleOneLiners},
+		{CmdKey: "UM", Name: "Rumor", Description: "Show a random rumor, or add one with the option A", Category: "Automessage", Implemented: true, Handler: handleRumor},
 
 		// System
-		{CmdKey: "G", Name: "Goodbye / Logoff", Description: "Log off the BBS", Category: "System", NodeActivity: "Logging off.", Implemented: true, Handler: handleGoodbye},
+		{CmdKey: "G", Name: "Goodbye / Logoff", Description: "Log off the BBS", Category: "System", NodeActivity: "Logging off.", Implemented: true, Result: handleGoodbye},
 	}
 
 	for _, def := range defs {
 		d := def
-		if d.Handler == nil {
+		if d.Handler == nil && d.Result == nil {
 			d.Handler = handleNotImplemented
 		}
 		r.Register(&d)
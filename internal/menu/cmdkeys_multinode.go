@@ -1,24 +1,360 @@
 package menu
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
 // registerMultinodeCommands registers all multinode/chat commands
 func registerMultinodeCommands(r *CmdKeyRegistry) {
 	defs := []CmdKeyDefinition{
 		// Multinode
-		{CmdKey: "NA", Name: "Toggle Page Availability", Description: "Toggle whether this node can be paged", Category: "Multinode"},
+		{CmdKey: "NA", Name: "Toggle Page Availability", Description: "Toggle whether this node can be paged", Category: "Multinode", Handler: handleToggleAvailability, Implemented: true},
 		{CmdKey: "ND", Name: "Hangup Node", Description: "Disconnect another node", Category: "Multinode"},
-		{CmdKey: "NG", Name: "Join Group Chat", Description: "Join the multi-node group chat", Category: "Multinode"},
-		{CmdKey: "NO", Name: "View All Nodes", Description: "Display users on all nodes", Category: "Multinode"},
-		{CmdKey: "NP", Name: "Page Node", Description: "Page another node for chat", Category: "Multinode"},
-		{CmdKey: "NS", Name: "Send Node Message", Description: "Send a message to another node", Category: "Multinode"},
-		{CmdKey: "NT", Name: "Toggle Stealth Mode", Description: "Toggle stealth mode on or off", Category: "Multinode"},
-		{CmdKey: "NW", Name: "Set Activity String", Description: "Display a string under node activity", Category: "Multinode"},
+		{CmdKey: "NG", Name: "Join Group Chat", Description: "Join the multi-node group chat", Category: "Multinode", NodeActivity: "In group chat.", Handler: handleGroupChat, Implemented: true},
+		{CmdKey: "NO", Name: "View All Nodes", Description: "Display users on all nodes", Category: "Multinode", Handler: handleWhosOnline, Implemented: true},
+		{CmdKey: "NP", Name: "Page Node", Description: "Page another node for chat", Category: "Multinode", Handler: handlePageNode, Implemented: true},
+		{CmdKey: "NS", Name: "Send Node Message", Description: "Send a message to another node", Category: "Multinode", Handler: handleSendNodeMessage, Implemented: true},
+		{CmdKey: "NT", Name: "Toggle Stealth Mode", Description: "Toggle stealth mode on or off", Category: "Multinode", Handler: handleToggleStealth, Implemented: true},
+		{CmdKey: "NW", Name: "Set Activity String", Description: "Display a string under node activity", Category: "Multinode", NodeActivity: "Setting node activity.", Handler: handleSetActivityString, Implemented: true},
 	}
 
 	for _, def := range defs {
 		d := def
-		if d.Handler == nil {
+		if d.Handler == nil && d.Result == nil {
 			d.Handler = handleNotImplemented
 		}
 		r.Register(&d)
 	}
 }
+
+// handleWhosOnline handles the NO command (who's online)
+func handleWhosOnline(ctx *ExecutionContext, options string) error {
+	nm := logging.GetNodeManager()
+	if nm == nil {
+		return fmt.Errorf("node manager not initialized")
+	}
+
+	active := make(map[int]config.NodeConnection)
+	for _, conn := range nm.ActiveConnections() {
+		active[conn.NodeNumber] = conn
+	}
+
+	isSysOp := ctx.Session != nil && ctx.Session.SecurityLevel >= config.SecurityLevelSysOp
+	ownNode := 0
+	if ctx.Session != nil {
+		ownNode = ctx.Session.NodeNumber
+	}
+
+	var out strings.Builder
+	out.WriteString("\r\n")
+	out.WriteString(ui.Ansi.CyanHi + " Node  User                  Activity                                 Avail\r\n" + ui.Ansi.Reset)
+	out.WriteString(ui.Ansi.Blue + " ----  --------------------  ---------------------------------------  -----\r\n" + ui.Ansi.Reset)
+
+	lines := 3
+	for node := 1; node <= nm.GetNodeCount(); node++ {
+		conn, online := active[node]
+		if online && conn.Stealth && !isSysOp && node != ownNode {
+			online = false
+		}
+
+		if !online {
+			out.WriteString(fmt.Sprintf(ui.Ansi.White+" %4d  "+ui.Ansi.BlackHi+"%-20s  %-39s"+ui.Ansi.Reset+"\r\n",
+				node, "-", "Waiting for caller."))
+			lines++
+			continue
+		}
+
+		user := conn.Username
+		if conn.Stealth {
+			user += " (S)"
+		}
+		avail := "Yes"
+		if !conn.Available {
+			avail = "No"
+		}
+		out.WriteString(fmt.Sprintf(ui.Ansi.WhiteHi+" %4d  "+ui.Ansi.YellowHi+"%-20s  "+ui.Ansi.Cyan+"%-39s  "+ui.Ansi.White+"%s"+ui.Ansi.Reset+"\r\n",
+			node, truncateText(user, 20), truncateText(conn.DisplayActivity(), 39), avail))
+		lines++
+	}
+
+	if err := ctx.IO.Print(out.String()); err != nil {
+		return err
+	}
+	if ctx.AdvanceRows != nil {
+		ctx.AdvanceRows(lines)
+	}
+	return nil
+}
+
+// handleSendNodeMessage handles the NS command.
+// Options: <node number> <;message to send>
+func handleSendNodeMessage(ctx *ExecutionContext, options string) error {
+	return sendToNode(ctx, options, false)
+}
+
+// handlePageNode handles the NP command.
+// Options: <node number>
+func handlePageNode(ctx *ExecutionContext, options string) error {
+	return sendToNode(ctx, options, true)
+}
+
+func sendToNode(ctx *ExecutionContext, options string, page bool) error {
+	nm := logging.GetNodeManager()
+	if nm == nil {
+		return fmt.Errorf("node manager not initialized")
+	}
+
+	nodePart, text, _ := strings.Cut(options, ";")
+	nodePart = strings.TrimSpace(nodePart)
+	text = strings.TrimSpace(text)
+
+	ctx.IO.Print("\r\n")
+	if nodePart == "" {
+		input, err := ui.PromptSimple(ctx.IO, " Node number: ", 3, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil
+			}
+			return err
+		}
+		nodePart = strings.TrimSpace(input)
+		ctx.IO.Print("\r\n")
+	}
+
+	node, err := strconv.Atoi(nodePart)
+	if err != nil || node < 1 || node > nm.GetNodeCount() {
+		return showNodeNotice(ctx, ui.Ansi.RedHi+" Invalid node number.")
+	}
+	if ctx.Session != nil && node == ctx.Session.NodeNumber {
+		return showNodeNotice(ctx, ui.Ansi.RedHi+" That's your own node.")
+	}
+
+	isSysOp := ctx.Session != nil && ctx.Session.SecurityLevel >= config.SecurityLevelSysOp
+	target, found := findActiveNode(nm, node)
+	if !found || (target.Stealth && !isSysOp) {
+		return showNodeNotice(ctx, ui.Ansi.RedHi+fmt.Sprintf(" Node %d is not active.", node))
+	}
+
+	if !page && text == "" {
+		input, err := ui.PromptSimple(ctx.IO, " Message: ", 60, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil
+			}
+			return err
+		}
+		text = strings.TrimSpace(input)
+		ctx.IO.Print("\r\n")
+		if text == "" {
+			return nil
+		}
+	}
+
+	fromNode := 0
+	if ctx.Session != nil {
+		fromNode = ctx.Session.NodeNumber
+	}
+	msg := config.NodeMessage{FromNode: fromNode, FromUser: ctx.Username, Text: text, Page: page}
+	if err := nm.SendMessage(node, msg, isSysOp); err != nil {
+		return showNodeNotice(ctx, ui.Ansi.RedHi+fmt.Sprintf(" %s is not available right now.", target.Username))
+	}
+
+	if page {
+		return showNodeNotice(ctx, ui.Ansi.GreenHi+fmt.Sprintf(" Paging %s on node %d...", target.Username, node))
+	}
+	return showNodeNotice(ctx, ui.Ansi.GreenHi+fmt.Sprintf(" Message sent to %s on node %d.", target.Username, node))
+}
+
+// handleToggleAvailability handles the NA command
+func handleToggleAvailability(ctx *ExecutionContext, options string) error {
+	nm := logging.GetNodeManager()
+	if nm == nil || ctx.Session == nil {
+		return fmt.Errorf("node manager not initialized")
+	}
+
+	if nm.ToggleAvailable(ctx.Session.NodeNumber) {
+		return showNodeNotice(ctx, ui.Ansi.GreenHi+" You are now available for pages and node messages.")
+	}
+	return showNodeNotice(ctx, ui.Ansi.YellowHi+" You are now unavailable for pages and node messages.")
+}
+
+// handleToggleStealth handles the NT command. Only security levels flagged
+// as invisible may hide from the node listing.
+func handleToggleStealth(ctx *ExecutionContext, options string) error {
+	nm := logging.GetNodeManager()
+	if nm == nil || ctx.Session == nil {
+		return fmt.Errorf("node manager not initialized")
+	}
+
+	var db database.Database
+	if ctx.Executor != nil {
+		db = ctx.Executor.db
+	}
+	level := securityLevelFor(db, ctx.Session.SecurityLevel)
+	if level == nil || !level.Invisible {
+		return showNodeNotice(ctx, ui.Ansi.RedHi+" Your security level does not permit stealth mode.")
+	}
+
+	current, _ := findActiveNode(nm, ctx.Session.NodeNumber)
+	stealth := !current.Stealth
+	nm.SetStealth(ctx.Session.NodeNumber, stealth)
+	logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "STEALTH", fmt.Sprintf("Stealth mode %s", onOff(stealth)))
+
+	if stealth {
+		return showNodeNotice(ctx, ui.Ansi.GreenHi+" Stealth mode is now ON.")
+	}
+	return showNodeNotice(ctx, ui.Ansi.YellowHi+" Stealth mode is now OFF.")
+}
+
+// handleSetActivityString handles the NW command.
+// Options: [string] - prompts when empty; an empty answer clears the string.
+func handleSetActivityString(ctx *ExecutionContext, options string) error {
+	nm := logging.GetNodeManager()
+	if nm == nil || ctx.Session == nil {
+		return fmt.Errorf("node manager not initialized")
+	}
+
+	activity := strings.TrimSpace(options)
+	if activity == "" {
+		ctx.IO.Print("\r\n")
+		input, err := ui.PromptSimple(ctx.IO, " Activity: ", 39, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil
+			}
+			return err
+		}
+		ctx.IO.Print("\r\n")
+		activity = strings.TrimSpace(ui.StripPipeCodes(input))
+		if ctx.AdvanceRows != nil {
+			ctx.AdvanceRows(2)
+		}
+	}
+
+	nm.SetCustomActivity(ctx.Session.NodeNumber, truncateText(activity, 39))
+	if activity == "" {
+		return showNodeNotice(ctx, ui.Ansi.Yellow+" Activity string cleared.")
+	}
+	return showNodeNotice(ctx, ui.Ansi.GreenHi+" Activity string set.")
+}
+
+// updateNodeActivity records the current activity for the caller's node
+func updateNodeActivity(ctx *ExecutionContext, activity string) {
+	if ctx == nil || ctx.Session == nil || strings.TrimSpace(activity) == "" {
+		return
+	}
+	if nm := logging.GetNodeManager(); nm != nil {
+		nm.SetActivity(ctx.Session.NodeNumber, strings.TrimSpace(activity))
+	}
+}
+
+// deliverNodeMessages prints any pending node messages, pages and system
+// broadcasts for the caller's node. Returns true if anything was displayed.
+func deliverNodeMessages(ctx *ExecutionContext) bool {
+	if ctx == nil || ctx.Session == nil || ctx.IO == nil {
+		return false
+	}
+
+	lines := pendingNodeLines(ctx)
+	if len(lines) == 0 {
+		return false
+	}
+
+	var out strings.Builder
+	out.WriteString("\r\n")
+	for _, line := range lines {
+		out.WriteString(line + ui.Ansi.Reset + "\r\n")
+	}
+	ctx.IO.Print(out.String())
+	if ctx.AdvanceRows != nil {
+		ctx.AdvanceRows(len(lines) + 1)
+	}
+	return true
+}
+
+// pendingNodeLines collects queued node messages and broadcasts as
+// formatted display lines
+func pendingNodeLines(ctx *ExecutionContext) []string {
+	var lines []string
+
+	if nm := logging.GetNodeManager(); nm != nil {
+		for _, msg := range nm.TakeMessages(ctx.Session.NodeNumber) {
+			if msg.Page {
+				lines = append(lines, fmt.Sprintf("\a"+ui.Ansi.YellowHi+" %s on node %d is paging you for chat!", msg.FromUser, msg.FromNode))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf(ui.Ansi.CyanHi+" Message from %s (node %d): "+ui.Ansi.WhiteHi+"%s", msg.FromUser, msg.FromNode, msg.Text))
+		}
+	}
+
+	if ctx.Executor != nil && ctx.Executor.broadcasts != nil {
+		for {
+			select {
+			case ev, ok := <-ctx.Executor.broadcasts.C:
+				if !ok {
+					return lines
+				}
+				lines = append(lines, fmt.Sprintf(ui.Ansi.MagentaHi+" *** Broadcast from %s: "+ui.Ansi.WhiteHi+"%s", ev.FromUser, ev.Text))
+				continue
+			default:
+			}
+			break
+		}
+	}
+
+	return lines
+}
+
+// findActiveNode returns a copy of an active node's status
+func findActiveNode(nm *config.NodeManager, node int) (config.NodeConnection, bool) {
+	for _, conn := range nm.ActiveConnections() {
+		if conn.NodeNumber == node {
+			return conn, true
+		}
+	}
+	return config.NodeConnection{}, false
+}
+
+// securityLevelFor returns the highest defined security level at or below level
+func securityLevelFor(db database.Database, level int) *database.SecurityLevelRecord {
+	if db == nil {
+		return nil
+	}
+	levels, err := db.GetAllSecurityLevels()
+	if err != nil {
+		return nil
+	}
+	return levelFor(levels, level)
+}
+
+func showNodeNotice(ctx *ExecutionContext, text string) error {
+	if err := ctx.IO.Print("\r\n" + text + ui.Ansi.Reset + "\r\n"); err != nil {
+		return err
+	}
+	if ctx.AdvanceRows != nil {
+		ctx.AdvanceRows(2)
+	}
+	return nil
+}
+
+func truncateText(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
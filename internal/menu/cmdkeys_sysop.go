@@ -7,14 +7,14 @@ func registerSysopCommands(r *CmdKeyRegistry) {
 		{CmdKey: "*B", Name: "Edit Message Bases", Description: "Enter the message base editor", Category: "Sysop"},
 		{CmdKey: "*C", Name: "Change User Account", Description: "Switch to another user's account", Category: "Sysop"},
 		{CmdKey: "*D", Name: "Mini-DOS Shell", Description: "Enter the Mini-DOS environment", Category: "Sysop"},
-		{CmdKey: "*E", Name: "Edit Events", Description: "Enter the event editor", Category: "Sysop"},
+		{CmdKey: "*E", Name: "Edit Events", Description: "List timed events, toggle them or run one now", Category: "Sysop", Implemented: true, Handler: handleEditEvents},
 		{CmdKey: "*F", Name: "Edit File Bases", Description: "Enter the file base editor", Category: "Sysop"},
 		{CmdKey: "*L", Name: "View SysOp Log", Description: "Display the SysOp log for a day", Category: "Sysop"},
 		{CmdKey: "*N", Name: "Edit Text File", Description: "Edit a text file", Category: "Sysop"},
 		{CmdKey: "*P", Name: "System Configuration", Description: "Enter the system configuration editor", Category: "Sysop"},
 		{CmdKey: "*R", Name: "Conference Editor", Description: "Enter the conference editor", Category: "Sysop"},
 		{CmdKey: "*U", Name: "User Editor", Description: "Enter the user editor", Category: "Sysop"},
-		{CmdKey: "*V", Name: "Voting Editor", Description: "Enter the voting editor", Category: "Sysop"},
+		{CmdKey: "*V", Name: "Voting Editor", Description: "Enter the voting editor", Category: "Sysop", Handler: handleVotingEditor, Implemented: true},
 		{CmdKey: "*X", Name: "Protocol Editor", Description: "Enter the protocol editor", Category: "Sysop"},
 		{CmdKey: "*Z", Name: "Activity Log", Description: "Display the system activity log", Category: "Sysop"},
 		{CmdKey: "*1", Name: "Edit Files in Base", Description: "Edit files in the current file base", Category: "Sysop"},
@@ -33,7 +33,7 @@ func registerSysopCommands(r *CmdKeyRegistry) {
 
 	for _, def := range defs {
 		d := def
-		if d.Handler == nil {
+		if d.Handler == nil && d.Result == nil {
 			d.Handler = handleNotImplemented
 		}
 		r.Register(&d)
@@ -4,18 +4,18 @@ package menu
 func registerVotingCommands(r *CmdKeyRegistry) {
 	defs := []CmdKeyDefinition{
 		// Voting
-		{CmdKey: "VA", Name: "Add Voting Topic", Description: "Add a new voting topic", Category: "Voting"},
-		{CmdKey: "VL", Name: "List Voting Topics", Description: "List available voting topics", Category: "Voting"},
-		{CmdKey: "VR", Name: "View Voting Results", Description: "View results for a voting topic", Category: "Voting"},
-		{CmdKey: "VT", Name: "Track User Vote", Description: "Track how a user voted", Category: "Voting"},
-		{CmdKey: "VU", Name: "View Topic Voters", Description: "View users who voted on a topic", Category: "Voting"},
-		{CmdKey: "VV", Name: "Vote on All Topics", Description: "Vote on all un-voted topics", Category: "Voting"},
-		{CmdKey: "V#", Name: "Vote on Topic", Description: "Vote on a specific topic number", Category: "Voting"},
+		{CmdKey: "VA", Name: "Add Voting Topic", Description: "Add a new voting topic", Category: "Voting", Handler: handleAddTopic, Implemented: true},
+		{CmdKey: "VL", Name: "List Voting Topics", Description: "List available voting topics", Category: "Voting", Handler: handleListTopics, Implemented: true},
+		{CmdKey: "VR", Name: "View Voting Results", Description: "View results for a voting topic", Category: "Voting", Handler: handleViewResults, Implemented: true},
+		{CmdKey: "VT", Name: "Track User Vote", Description: "Track how a user voted", Category: "Voting", Handler: handleTrackUserVote, Implemented: true},
+		{CmdKey: "VU", Name: "View Topic Voters", Description: "View users who voted on a topic", Category: "Voting", Handler: handleTopicVoters, Implemented: true},
+		{CmdKey: "VV", Name: "Vote on All Topics", Description: "Vote on all un-voted topics", Category: "Voting", Handler: handleVoteOnAll, Implemented: true},
+		{CmdKey: "V#", Name: "Vote on Topic", Description: "Vote on a specific topic number", Category: "Voting", Handler: handleVoteOnTopic, Implemented: true},
 	}
 
 	for _, def := range defs {
 		d := def
-		if d.Handler == nil {
+		if d.Handler == nil && d.Result == nil {
 			d.Handler = handleNotImplemented
 		}
 		r.Register(&d)
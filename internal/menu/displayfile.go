@@ -0,0 +1,259 @@
+package menu
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/telnet"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// displayFileFlags are the options accepted after a display file name
+type displayFileFlags struct {
+	pause   bool // Pause at the end of each screen
+	noAbort bool // Ignore keys pressed during display
+	baud    int  // Emulated line speed in bits per second; 0 is full speed
+}
+
+// handleDisplayFile handles the -F command (display a file with MCI codes expanded).
+// Options: [filename] <;flags> - P pauses each screen, N disables hotkey
+// abort, Bnnnn emulates a modem of nnnn baud
+func handleDisplayFile(ctx *ExecutionContext, options string) error {
+	return displayFileCommand(ctx, options, true)
+}
+
+// handleDisplayFileLiteral handles the /F command (display a file as-is).
+// Options: the same as -F
+func handleDisplayFileLiteral(ctx *ExecutionContext, options string) error {
+	return displayFileCommand(ctx, options, false)
+}
+
+func displayFileCommand(ctx *ExecutionContext, options string, expand bool) error {
+	if ctx == nil || ctx.IO == nil {
+		return fmt.Errorf("display file command requires an execution context with IO")
+	}
+
+	name, flagText, _ := strings.Cut(options, ";")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	flags := parseDisplayFileFlags(flagText)
+
+	path := findDisplayFile(ctx, name)
+	if path == "" {
+		ctx.IO.Print(ui.Ansi.RedHi + fmt.Sprintf("\r\n File %s not found.\r\n", name) + ui.Ansi.Reset)
+		if ctx.AdvanceRows != nil {
+			ctx.AdvanceRows(2)
+		}
+		return nil
+	}
+
+	ctx.IO.Print("\r\n")
+	lines, err := displayFile(ctx, path, expand, flags)
+	if ctx.AdvanceRows != nil {
+		ctx.AdvanceRows(lines + 1)
+	}
+	return err
+}
+
+func parseDisplayFileFlags(text string) displayFileFlags {
+	var flags displayFileFlags
+	for _, field := range strings.FieldsFunc(strings.ToUpper(text), func(r rune) bool { return r == ' ' || r == ',' || r == ';' }) {
+		switch {
+		case field == "P":
+			flags.pause = true
+		case field == "N":
+			flags.noAbort = true
+		case strings.HasPrefix(field, "B"):
+			if baud, err := strconv.Atoi(field[1:]); err == nil && baud > 0 {
+				flags.baud = baud
+			}
+		}
+	}
+	return flags
+}
+
+// findDisplayFile resolves a display file name through the theme's text
+// directory, then the theme itself
+func findDisplayFile(ctx *ExecutionContext, name string) string {
+	themeDir := "theme"
+	if ctx.Executor != nil {
+		themeDir = ctx.Executor.getThemeBaseDir()
+	}
+	return resolveDisplayFile(ctx, name, filepath.Join(themeDir, "text"), themeDir)
+}
+
+// resolveDisplayFile looks a display file up for the session's terminal. ASCII
+// callers with no plain text variant get the ANSI one, which the session
+// writer reduces to plain text.
+func resolveDisplayFile(ctx *ExecutionContext, name string, dirs ...string) string {
+	query := ui.DisplayFileQuery{
+		Name:          name,
+		Dirs:          dirs,
+		Extensions:    displayExtensions(ctx),
+		SecurityLevel: sessionSecurityLevel(ctx),
+	}
+	if path, ok := ui.ResolveDisplayFile(query); ok {
+		return path
+	}
+	if sessionEmulation(ctx) == ui.EmulationASCII {
+		query.Extensions = ui.DisplayExtensions(ui.EmulationANSI)
+		path, _ := ui.ResolveDisplayFile(query)
+		return path
+	}
+	return ""
+}
+
+// displayExtensions returns the display file variants for the session's terminal
+func displayExtensions(ctx *ExecutionContext) []string {
+	emulation := sessionEmulation(ctx)
+	if emulation == ui.EmulationANSI && ctx != nil && ctx.Session != nil && ctx.Session.Encoding == config.EncodingUTF8 {
+		emulation = ui.EmulationUTF8
+	}
+	extensions := ui.DisplayExtensions(emulation)
+	if sessionICEColors(ctx) {
+		extensions = append([]string{ui.IcyExtension}, extensions...)
+	}
+	return extensions
+}
+
+// sessionEmulation returns the session's terminal emulation, ANSI when unknown
+func sessionEmulation(ctx *ExecutionContext) string {
+	if ctx == nil || ctx.Session == nil || ctx.Session.Emulation == "" {
+		return ui.EmulationANSI
+	}
+	return ctx.Session.Emulation
+}
+
+func sessionICEColors(ctx *ExecutionContext) bool {
+	return ctx != nil && ctx.Session != nil && ctx.Session.ICEColors && ctx.Session.Emulation != ui.EmulationASCII
+}
+
+func sessionSecurityLevel(ctx *ExecutionContext) int {
+	if ctx == nil || ctx.Session == nil {
+		return 0
+	}
+	return ctx.Session.SecurityLevel
+}
+
+// displayFile shows a file a line at a time and returns how many lines were
+// shown. Unless flags.noAbort is set, ESC, Q, N, Ctrl-C or space stops it.
+func displayFile(ctx *ExecutionContext, path string, expand bool, flags displayFileFlags) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read display file %s: %w", path, err)
+	}
+	sauce, body := ui.ParseSauce(content)
+	data := string(body)
+	if expand {
+		data = expandMCI(ctx, data)
+	}
+	if sessionICEColors(ctx) && sauce.ICEColors() {
+		ctx.IO.Print(ui.ICEColorsOn)
+		defer ctx.IO.Print(ui.ICEColorsOff)
+	}
+	data = strings.ReplaceAll(strings.ReplaceAll(data, "\r\n", "\n"), "\n", "\r\n")
+
+	io := ctx.IO
+	pageSize := 23
+	if ctx.Session != nil && ctx.Session.Height > 1 {
+		pageSize = ctx.Session.Height - 1
+	}
+
+	shown, onPage := 0, 0
+	nonstop := !flags.pause
+	for _, line := range strings.SplitAfter(data, "\r\n") {
+		if line == "" {
+			continue
+		}
+		if err := writeAtBaud(io, line, flags.baud); err != nil {
+			return shown, err
+		}
+		shown++
+		onPage++
+
+		if !flags.noAbort && displayAborted(io) {
+			io.Print(ui.Ansi.Reset + "\r\n")
+			return shown, nil
+		}
+
+		if !nonstop && onPage >= pageSize {
+			more, stop, err := morePrompt(io)
+			if err != nil {
+				return shown, err
+			}
+			if !more {
+				return shown, nil
+			}
+			nonstop = stop
+			onPage = 0
+		}
+	}
+	io.Print(ui.Ansi.Reset)
+	return shown, nil
+}
+
+// writeAtBaud writes text, pacing it to the given line speed when baud > 0
+func writeAtBaud(io *telnet.TelnetIO, text string, baud int) error {
+	if baud <= 0 {
+		return io.Print(text)
+	}
+	// Ten bits per character on an 8-N-1 line, sent in 20ms slices
+	const slice = 20 * time.Millisecond
+	perSlice := max(1, baud/10/50)
+	for len(text) > 0 {
+		n := min(perSlice, len(text))
+		if err := io.Print(text[:n]); err != nil {
+			return err
+		}
+		text = text[n:]
+		time.Sleep(slice)
+	}
+	return nil
+}
+
+// displayAborted reports whether the caller pressed an abort key. It only
+// polls when there is a connection to set a read deadline on.
+func displayAborted(io *telnet.TelnetIO) bool {
+	if io.Session == nil || io.Session.Conn == nil {
+		return false
+	}
+	seq, err := io.ReadKeySequence(time.Millisecond)
+	if err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return false
+		}
+		return true
+	}
+	switch strings.ToUpper(seq) {
+	case "\x1b", "Q", "N", " ", "\x03":
+		return true
+	}
+	return false
+}
+
+// morePrompt asks whether to continue after a full screen. It returns
+// whether to keep going and whether to stop pausing.
+func morePrompt(io *telnet.TelnetIO) (more bool, nonstop bool, err error) {
+	io.Print(ui.Ansi.Reset + ui.Ansi.Cyan + "More? " + ui.Ansi.WhiteHi + "(Y)es, (N)o, (=)Nonstop" + ui.Ansi.Reset)
+	key, err := io.GetKeyPressUpper()
+	io.Print("\r" + ui.Ansi.EraseLine)
+	if err != nil {
+		return false, false, err
+	}
+	switch key {
+	case 'N', 'Q', 27:
+		return false, false, nil
+	case '=':
+		return true, true, nil
+	default:
+		return true, false, nil
+	}
+}
@@ -5,13 +5,15 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
+	"github.com/robbiew/retrograde/internal/bus"
 	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/mci"
 	"github.com/robbiew/retrograde/internal/telnet"
 	"github.com/robbiew/retrograde/internal/ui"
 )
@@ -22,6 +24,15 @@ type MenuExecutor struct {
 	registry   *CmdKeyRegistry
 	io         *telnet.TelnetIO
 	currentRow int
+	lightbar   []lightbarItem    // Generated command items drawn for the current menu
+	selected   int               // Highlighted lightbar item
+	menuStack  []string          // Menus to return to, pushed by gosub
+	jump       *menuJump         // Menu change requested by the last command
+	broadcasts *bus.Subscription // System-wide announcements for this session
+	sysopChat  *bus.Subscription // Sysop chat requests and keystrokes for this node
+	logoff     sync.Once         // Guards the logoff pipeline so it runs once
+	loggingOff bool              // Set while the logoff sequence runs
+	callID     int64             // Caller history record for this call
 }
 
 // NewMenuExecutor creates a new menu executor
@@ -31,6 +42,17 @@ func NewMenuExecutor(db database.Database, io *telnet.TelnetIO) *MenuExecutor {
 		registry:   NewCmdKeyRegistry(),
 		io:         io,
 		currentRow: 1,
+		broadcasts: bus.Default().Subscribe(bus.TopicBroadcast, 16),
+	}
+}
+
+// Close releases the executor's bus subscriptions. Call when the session ends.
+func (e *MenuExecutor) Close() {
+	if e.broadcasts != nil {
+		e.broadcasts.Close()
+	}
+	if e.sysopChat != nil {
+		e.sysopChat.Close()
 	}
 }
 
@@ -38,19 +60,47 @@ func NewMenuExecutor(db database.Database, io *telnet.TelnetIO) *MenuExecutor {
 
 var errNoKeyTimeout = errors.New("menu_no_key_timeout")
 
+// nodeMessagePollInterval controls how often an idle prompt checks for
+// incoming node messages
+const nodeMessagePollInterval = time.Second
+
 var specialKeyLiterals = func() map[string]struct{} {
 	keys := map[string]struct{}{
-		"FIRSTCMD": {},
-		"ANYKEY":   {},
-		"NOKEY":    {},
-		"ENTER":    {},
-		"ESC":      {},
-		"TAB":      {},
+		"FIRSTCMD":  {},
+		"EVERYTIME": {},
+		"ANYKEY":    {},
+		"NOKEY":     {},
+		"ENTER":     {},
+		"ESC":       {},
+		"TAB":       {},
 	}
 	return keys
 }()
 
+// ExecuteMenu runs menuName and follows goto, gosub and return commands
+// until the caller leaves the menu system
 func (e *MenuExecutor) ExecuteMenu(menuName string, ctx *ExecutionContext) error {
+	ctx = e.prepareContext(ctx)
+
+	e.menuStack = nil
+	current := menuName
+	for {
+		result, err := e.runMenu(current, ctx)
+		if err != nil {
+			return err
+		}
+		jump := e.jump
+		e.jump = nil
+		if result == ResultHangup || jump == nil {
+			return nil
+		}
+		current = e.resolveJump(current, jump)
+	}
+}
+
+// prepareContext binds ctx to this executor, filling in the IO, session and
+// row tracking commands rely on
+func (e *MenuExecutor) prepareContext(ctx *ExecutionContext) *ExecutionContext {
 	if ctx == nil {
 		ctx = &ExecutionContext{}
 	}
@@ -70,39 +120,79 @@ func (e *MenuExecutor) ExecuteMenu(menuName string, ctx *ExecutionContext) error
 			e.currentRow += lines
 		}
 	}
+	e.watchSysOpChat(ctx)
+	return ctx
+}
+
+// runMenu shows a menu and runs its commands until one leaves it, returning
+// ResultExitMenu or ResultHangup
+func (e *MenuExecutor) runMenu(menuName string, ctx *ExecutionContext) (CmdResult, error) {
+	e.currentRow = 1
+	e.selected = 0
 
 	menu, err := e.lookupMenuByName(menuName)
 	if err != nil {
-		return err
+		return ResultContinue, err
 	}
 
 	commands, err := e.db.GetMenuCommands(menu.ID)
 	if err != nil {
-		return fmt.Errorf("failed to load menu commands for %s: %w", menuName, err)
+		return ResultContinue, fmt.Errorf("failed to load menu commands for %s: %w", menuName, err)
 	}
 
-	// Execute FIRSTCMD commands before displaying the menu
+	// Execute FIRSTCMD commands each time the menu is entered, including on
+	// return from a gosub
 	firstCommands := e.findCommands(commands, "FIRSTCMD")
 	if len(firstCommands) > 0 {
-		exitMenu, execErr := e.runCommands(firstCommands, ctx)
+		result, execErr := e.runCommands(firstCommands, ctx)
 		if execErr != nil {
-			return execErr
+			return ResultContinue, execErr
 		}
-		if exitMenu {
-			return nil
+		if leavesMenu(result) {
+			return result, nil
 		}
 	}
 
 	// Pre-calculate special command groups
+	everyTimeCommands := e.findCommands(commands, "EVERYTIME")
 	anyKeyCommands := e.findCommands(commands, "ANYKEY")
 	noKeyCommands := e.findCommands(commands, "NOKEY")
 	noKeyTimeout := e.resolveNoKeyTimeout(noKeyCommands)
 
-	// Display generic menu if applicable
-	e.displayGenericMenu(menu, commands, ctx)
+	// Display generic menu if applicable; expert callers only get the
+	// prompt until a command redraws the menu
+	if ctx.Session != nil && ctx.Session.Expert {
+		e.lightbar = nil
+		e.clearScreen(menu.ClearScreen)
+	} else {
+		e.displayGenericMenu(menu, commands, ctx)
+	}
+
+	menuActivity := strings.TrimSpace(menu.NodeActivity)
+	if menuActivity == "" {
+		menuActivity = fmt.Sprintf("Browsing the %s menu.", menu.Name)
+	}
 
 	// Main menu loop
 	for {
+		updateNodeActivity(ctx, menuActivity)
+		deliverNodeMessages(ctx)
+
+		// EVERYTIME commands run before each prompt
+		if len(everyTimeCommands) > 0 {
+			result, execErr := e.runCommands(everyTimeCommands, ctx)
+			if execErr != nil {
+				return ResultContinue, execErr
+			}
+			if leavesMenu(result) {
+				return result, nil
+			}
+			if result == ResultRedisplay {
+				e.currentRow = 1
+				e.displayGenericMenu(menu, commands, ctx)
+			}
+		}
+
 		// Position prompt at next available row after menu display
 		height := 24
 		if ctx != nil && ctx.Session != nil && ctx.Session.Height > 0 {
@@ -110,55 +200,59 @@ func (e *MenuExecutor) ExecuteMenu(menuName string, ctx *ExecutionContext) error
 		}
 		promptRow := min(height, e.currentRow+1)
 		e.io.Print(ui.MoveCursorSequence(1, promptRow))
-		parsedPrompt := ui.ParsePipeColorCodes(menu.Prompt)
+		parsedPrompt := expandMCI(ctx, menu.Prompt)
 		e.io.Print(parsedPrompt)
 
-		// Read input (single key press with optional timeout)
-		input, err := e.readKeyPress(noKeyCommands, noKeyTimeout)
+		// Read input in the menu's input mode (with optional timeout)
+		input, matchingCommands, err := e.readMenuCommand(menu, commands, ctx, parsedPrompt, noKeyCommands, noKeyTimeout)
 		if err != nil {
+			if errors.Is(err, errRedisplayMenu) {
+				e.currentRow = 1
+				e.displayGenericMenu(menu, commands, ctx)
+				continue
+			}
 			// Timeout reached - execute NOKEY commands
 			if errors.Is(err, errNoKeyTimeout) {
-				exitMenu, execErr := e.runCommands(noKeyCommands, ctx)
-				if execErr != nil {
-					return execErr
-				}
-				if exitMenu {
-					return nil
-				}
+				matchingCommands = noKeyCommands
+			} else {
+				return ResultContinue, err
+			}
+		} else {
+			if input == "" {
+				continue
+			}
+			if len(anyKeyCommands) > 0 {
+				matchingCommands = append(matchingCommands, anyKeyCommands...)
+			}
+			if len(matchingCommands) == 0 {
+				// Suppress invalid command messages
 				continue
 			}
-			return err
-		}
-
-		if input == "" {
-			continue
-		}
-
-		// Find matching commands (supports linked commands with same key)
-		matchingCommands := e.findCommands(commands, input)
-		if len(anyKeyCommands) > 0 {
-			matchingCommands = append(matchingCommands, anyKeyCommands...)
-		}
-
-		if len(matchingCommands) == 0 {
-			// Suppress invalid command messages
-			continue
 		}
 
-		exitMenu, execErr := e.runCommands(matchingCommands, ctx)
+		result, execErr := e.runCommands(matchingCommands, ctx)
 		if execErr != nil {
-			return execErr
+			return ResultContinue, execErr
 		}
-		if exitMenu {
-			break
+		if leavesMenu(result) {
+			return result, nil
+		}
+		if result == ResultRedisplay {
+			e.currentRow = 1
+			e.displayGenericMenu(menu, commands, ctx)
 		}
 	}
+}
 
-	return nil
+// leavesMenu reports whether a command result ends the current menu
+func leavesMenu(result CmdResult) bool {
+	return result == ResultExitMenu || result == ResultHangup
 }
 
 // displayGenericMenu displays the generic menu if applicable
 func (e *MenuExecutor) displayGenericMenu(menu *database.Menu, commands []database.MenuCommand, ctx *ExecutionContext) {
+	e.lightbar = nil
+
 	// Check ACS for menu access
 	if !e.checkACS(menu.ACSRequired, ctx) {
 		e.io.Print("Access denied.\r\n")
@@ -177,8 +271,8 @@ func (e *MenuExecutor) displayGenericMenu(menu *database.Menu, commands []databa
 
 	switch displayMode {
 	case database.DisplayModeThemeOnly:
-		if art := e.findThemeFile(menu.Name); art != "" {
-			if lines, err := e.serveThemeFile(art); err == nil && lines > 0 {
+		if art := e.findThemeFile(menu.Name, ctx); art != "" {
+			if lines, err := e.serveThemeFile(art, ctx); err == nil && lines > 0 {
 				headerDisplayed = true
 			}
 		}
@@ -186,8 +280,8 @@ func (e *MenuExecutor) displayGenericMenu(menu *database.Menu, commands []databa
 			displayMode = database.DisplayModeTitlesGenerated
 		}
 	case database.DisplayModeHeaderGenerated:
-		if art := e.findThemeFile(menu.Name + ".hdr"); art != "" {
-			if lines, err := e.serveThemeFile(art); err == nil && lines > 0 {
+		if art := e.findThemeFile(menu.Name+".hdr", ctx); art != "" {
+			if lines, err := e.serveThemeFile(art, ctx); err == nil && lines > 0 {
 				headerDisplayed = true
 			}
 		}
@@ -231,7 +325,7 @@ func (e *MenuExecutor) displayCenteredTitles(menu *database.Menu, ctx *Execution
 		return false
 	}
 	for _, title := range menu.Titles {
-		parsedTitle := ui.ParsePipeColorCodes(title)
+		parsedTitle := expandMCI(ctx, title)
 		centeredTitle := e.centerTitle(parsedTitle, ctx)
 		e.io.Printf("\r\n%s\r\n", centeredTitle)
 		e.currentRow += 2
@@ -239,40 +333,10 @@ func (e *MenuExecutor) displayCenteredTitles(menu *database.Menu, ctx *Execution
 	return true
 }
 
-func (e *MenuExecutor) findThemeFile(base string) string {
-	themeDir := e.getThemeBaseDir()
-
-	candidates := []string{}
-	for _, ext := range []string{".ans", ".asc"} {
-		name := base + ext
-		candidates = append(candidates,
-			filepath.Join(themeDir, name),
-			filepath.Join(themeDir, strings.ToLower(name)),
-			filepath.Join(themeDir, strings.ToUpper(name)))
-	}
-
-	for _, path := range candidates {
-		if _, err := os.Stat(path); err == nil {
-			return path
-		}
-	}
-
-	entries, err := os.ReadDir(themeDir)
-	if err != nil {
-		return ""
-	}
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		for _, ext := range []string{".ans", ".asc"} {
-			if strings.EqualFold(name, base+ext) {
-				return filepath.Join(themeDir, name)
-			}
-		}
-	}
-	return ""
+// findThemeFile resolves a menu's theme file, honouring emulation, security
+// level and random variants
+func (e *MenuExecutor) findThemeFile(base string, ctx *ExecutionContext) string {
+	return resolveDisplayFile(ctx, base, e.getThemeBaseDir())
 }
 
 // findCommands returns all active commands matching the input (supports linked commands)
@@ -324,7 +388,7 @@ func matchesMenuKey(keyDef, input string) bool {
 		}
 
 		tokenRunes := []rune(token)
-		if len(tokenRunes) > 1 && inputLen == 1 {
+		if len(tokenRunes) > 1 && inputLen == 1 && tokenRunes[0] != '/' {
 			for _, r := range tokenRunes {
 				if strings.EqualFold(string(r), input) {
 					return true
@@ -335,20 +399,34 @@ func matchesMenuKey(keyDef, input string) bool {
 	return false
 }
 
+// splitMenuKeyVariants splits a key definition into its alternatives. A '/'
+// separates keys, except at the start of a key where it begins a Renegade
+// multi-character key such as /G.
 func splitMenuKeyVariants(keyDef string) []string {
-	return strings.FieldsFunc(keyDef, func(r rune) bool {
-		switch r {
-		case ',', ';', '|', '/', '+':
-			return true
+	var tokens []string
+	var current []rune
+	for _, r := range keyDef {
+		switch {
+		case r == '/' && len(current) == 0:
+			current = append(current, r)
+		case r == ',' || r == ';' || r == '|' || r == '/' || r == '+' || unicode.IsSpace(r):
+			if len(current) > 0 {
+				tokens = append(tokens, string(current))
+				current = nil
+			}
 		default:
-			return unicode.IsSpace(r)
+			current = append(current, r)
 		}
-	})
+	}
+	if len(current) > 0 {
+		tokens = append(tokens, string(current))
+	}
+	return tokens
 }
 
-// runCommands executes the provided commands sequentially.
-// Returns true if menu execution should exit.
-func (e *MenuExecutor) runCommands(commands []database.MenuCommand, ctx *ExecutionContext) (bool, error) {
+// runCommands executes linked commands in order. It returns the result that
+// ended the chain early, or ResultContinue once every command has run.
+func (e *MenuExecutor) runCommands(commands []database.MenuCommand, ctx *ExecutionContext) (CmdResult, error) {
 	for _, cmd := range commands {
 		// Check ACS before executing each command
 		if !e.checkACS(cmd.ACSRequired, ctx) {
@@ -356,38 +434,69 @@ func (e *MenuExecutor) runCommands(commands []database.MenuCommand, ctx *Executi
 			continue
 		}
 
-		if err := e.executeCommand(cmd, ctx); err != nil {
-			if err.Error() == "user_logout" {
-				return true, err
-			}
-			return false, err
+		result, err := e.executeCommand(cmd, ctx)
+		if err != nil {
+			return ResultContinue, err
 		}
-
-		if strings.EqualFold(cmd.CmdKeys, "G") {
-			return true, nil
+		if result != ResultContinue {
+			return result, nil
 		}
 	}
 
-	return false, nil
+	return ResultContinue, nil
 }
 
-func (e *MenuExecutor) readKeyPress(noKeyCommands []database.MenuCommand, timeout time.Duration) (string, error) {
-	var seq string
-	var err error
-	if len(noKeyCommands) == 0 || timeout <= 0 {
-		seq, err = e.io.ReadKeySequence(0)
-	} else {
-		seq, err = e.io.ReadKeySequence(timeout)
-	}
+// readKeyPress waits for a key, returning errNoKeyTimeout once the NOKEY
+// timeout elapses. While idle it delivers node messages and redraws prompt.
+func (e *MenuExecutor) readKeyPress(ctx *ExecutionContext, prompt string, noKeyCommands []database.MenuCommand, timeout time.Duration) (string, error) {
+	seq, err := e.readRawKey(ctx, prompt, noKeyCommands, timeout)
 	if err != nil {
-		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-			return "", errNoKeyTimeout
-		}
 		return "", err
 	}
 	return normalizeInputKey(seq), nil
 }
 
+// readRawKey is readKeyPress without normalizing, so callers can tell arrow
+// keys apart from ESC
+func (e *MenuExecutor) readRawKey(ctx *ExecutionContext, prompt string, noKeyCommands []database.MenuCommand, timeout time.Duration) (string, error) {
+	var deadline time.Time
+	if len(noKeyCommands) > 0 && timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for {
+		wait := nodeMessagePollInterval
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return "", errNoKeyTimeout
+			}
+			if remaining < wait {
+				wait = remaining
+			}
+		}
+
+		seq, err := e.io.ReadKeySequence(wait)
+		if err == nil {
+			return seq, nil
+		}
+		if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+			return "", err
+		}
+
+		if chatted, err := e.checkSysOpBreakIn(ctx); chatted {
+			if err != nil {
+				return "", err
+			}
+			return "", errRedisplayMenu
+		}
+
+		if deliverNodeMessages(ctx) {
+			e.io.Print(prompt)
+		}
+	}
+}
+
 func (e *MenuExecutor) resolveNoKeyTimeout(commands []database.MenuCommand) time.Duration {
 	if len(commands) == 0 {
 		return 0
@@ -491,18 +600,27 @@ func (e *MenuExecutor) checkACS(acs string, ctx *ExecutionContext) bool {
 }
 
 // executeCommand executes a menu command
-func (e *MenuExecutor) executeCommand(cmd database.MenuCommand, ctx *ExecutionContext) error {
+func (e *MenuExecutor) executeCommand(cmd database.MenuCommand, ctx *ExecutionContext) (CmdResult, error) {
+	activity := strings.TrimSpace(cmd.NodeActivity)
+	if activity == "" {
+		if def := e.registry.GetDefinition(cmd.CmdKeys); def != nil {
+			activity = def.NodeActivity
+		}
+	}
+	updateNodeActivity(ctx, activity)
+
 	return e.registry.Execute(cmd.CmdKeys, ctx, cmd.Options)
 }
 
 // serveThemeFile serves the content of a theme file
-func (e *MenuExecutor) serveThemeFile(themePath string) (int, error) {
+func (e *MenuExecutor) serveThemeFile(themePath string, ctx *ExecutionContext) (int, error) {
 	content, err := os.ReadFile(themePath)
 	if err != nil {
 		return 0, fmt.Errorf("failed to read theme file %s: %w", themePath, err)
 	}
-	data := ui.StripSauce(string(content))
-	e.io.Print(data)
+	sauce, body := ui.ParseSauce(content)
+	data := expandMCI(ctx, string(body))
+	e.io.Print(ui.WithICEColors(data, sauce, sessionICEColors(ctx)))
 
 	lineCount := strings.Count(data, "\n")
 	if len(data) > 0 && !strings.HasSuffix(data, "\n") {
@@ -519,6 +637,22 @@ func (e *MenuExecutor) serveThemeFile(themePath string) (int, error) {
 	return lineCount, nil
 }
 
+// expandMCI expands MCI codes in text for the session in ctx
+func expandMCI(ctx *ExecutionContext, text string) string {
+	return mci.Expand(text, newMCIContext(ctx))
+}
+
+func newMCIContext(ctx *ExecutionContext) *mci.Context {
+	if ctx == nil {
+		return nil
+	}
+	mctx := &mci.Context{Session: ctx.Session, UserID: ctx.UserID}
+	if ctx.Executor != nil {
+		mctx.DB = ctx.Executor.db
+	}
+	return mctx
+}
+
 func detectANSIAbsoluteRow(data string) int {
 	maxRow := 0
 	for i := 0; i < len(data); i++ {
@@ -666,6 +800,7 @@ func (e *MenuExecutor) displayCommandsInColumns(commands []database.MenuCommand,
 		padding = 0
 	}
 
+	e.lightbar = make([]lightbarItem, len(displayCommands))
 	for row := 0; row < itemsPerColumn; row++ {
 		line := ""
 		for col := 0; col < columns; col++ {
@@ -679,6 +814,17 @@ func (e *MenuExecutor) displayCommandsInColumns(commands []database.MenuCommand,
 					bracketColor, rightBracket, resetColor,
 					descColor, cmd.ShortDescription, resetColor)
 
+				lead := interColumnPadding
+				if col == 0 {
+					lead = margin
+				}
+				e.lightbar[idx] = lightbarItem{
+					cmd:       cmd,
+					x:         padding + len(ui.StripANSI(line)) + lead + 1,
+					y:         e.currentRow,
+					formatted: formatted,
+				}
+
 				visibleLen := len(ui.StripANSI(formatted))
 				if visibleLen < colWidths[col] {
 					formatted += strings.Repeat(" ", colWidths[col]-visibleLen)
@@ -0,0 +1,323 @@
+package menu
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/telnet"
+)
+
+// fakeMenuDB serves menus and commands from memory. Other Database methods
+// are left to the nil embedded interface and panic if called.
+type fakeMenuDB struct {
+	database.Database
+	menus    []database.Menu
+	commands map[int][]database.MenuCommand
+
+	mu       sync.Mutex
+	details  map[string]string // The test caller's user details
+	upserts  int
+	lastRead map[string]int
+	stats    map[string]int // The test caller's counters
+	steps    map[string][]database.SequenceStep
+
+	validation *database.ValidationRecord // The test caller's validation, if queued
+}
+
+func (db *fakeMenuDB) GetMenuByName(name string) (*database.Menu, error) {
+	for _, m := range db.menus {
+		if m.Name == name {
+			menu := m
+			return &menu, nil
+		}
+	}
+	return nil, fmt.Errorf("menu %s not found", name)
+}
+
+func (db *fakeMenuDB) GetAllMenus() ([]database.Menu, error) {
+	return db.menus, nil
+}
+
+func (db *fakeMenuDB) GetMenuCommands(menuID int) ([]database.MenuCommand, error) {
+	return db.commands[menuID], nil
+}
+
+func (db *fakeMenuDB) GetAllConfigValues() ([]database.ConfigValue, error) {
+	return nil, nil
+}
+
+func (db *fakeMenuDB) GetUserDetails(userID int64) (map[string]string, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	details := make(map[string]string)
+	for k, v := range db.details {
+		details[k] = v
+	}
+	return details, nil
+}
+
+func (db *fakeMenuDB) UpsertUserDetail(userID int64, attrib, value string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.details == nil {
+		db.details = make(map[string]string)
+	}
+	db.details[attrib] = value
+	db.upserts++
+	return nil
+}
+
+func (db *fakeMenuDB) IncrementUserStat(userID int64, stat string, n int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.stats == nil {
+		db.stats = make(map[string]int)
+	}
+	db.stats[stat] += n
+	return nil
+}
+
+func (db *fakeMenuDB) IncrementDailyStat(date, stat string, n int) error {
+	return nil
+}
+
+func (db *fakeMenuDB) SetUserLastRead(userID int64, msgbase string, messageID int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.lastRead == nil {
+		db.lastRead = make(map[string]int)
+	}
+	db.lastRead[msgbase] = messageID
+	return nil
+}
+
+func (db *fakeMenuDB) GetSequenceSteps(sequence string) ([]database.SequenceStep, error) {
+	return db.steps[sequence], nil
+}
+
+// addMenu adds a menu with commands given as "KEYS CMDKEY [OPTIONS]"
+func (db *fakeMenuDB) addMenu(name string, commands ...string) {
+	id := len(db.menus) + 1
+	db.menus = append(db.menus, database.Menu{ID: id, Name: name, Prompt: name + "> ", GenericColumns: 1})
+	if db.commands == nil {
+		db.commands = make(map[int][]database.MenuCommand)
+	}
+	for i, spec := range commands {
+		fields := strings.SplitN(spec, " ", 3)
+		cmd := database.MenuCommand{ID: id*100 + i, MenuID: id, Keys: fields[0], CmdKeys: fields[1], Active: true}
+		if len(fields) > 2 {
+			cmd.Options = fields[2]
+		}
+		db.commands[id] = append(db.commands[id], cmd)
+	}
+}
+
+// fakeTerminal is the caller's end of a piped telnet connection. It keeps
+// everything the BBS prints so writes never block.
+type fakeTerminal struct {
+	conn net.Conn
+	mu   sync.Mutex
+	out  strings.Builder
+}
+
+func newFakeTerminal(t *testing.T) (*telnet.TelnetIO, *fakeTerminal) {
+	t.Helper()
+	server, client := net.Pipe()
+	term := &fakeTerminal{conn: client}
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := client.Read(buf)
+			term.mu.Lock()
+			term.out.Write(buf[:n])
+			term.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		server.Close()
+		client.Close()
+	})
+
+	session := &config.TelnetSession{Conn: server, Width: 80, Height: 24, Connected: true}
+	return &telnet.TelnetIO{
+		Reader:  bufio.NewReader(server),
+		Writer:  bufio.NewWriter(server),
+		Session: session,
+	}, term
+}
+
+// Type sends keys as the caller
+func (f *fakeTerminal) Type(keys string) {
+	go f.conn.Write([]byte(keys))
+}
+
+func (f *fakeTerminal) Output() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.out.String()
+}
+
+// menuRun wires an executor to a fake database and terminal, with test
+// command keys that record what ran
+type menuRun struct {
+	db    *fakeMenuDB
+	exec  *MenuExecutor
+	ctx   *ExecutionContext
+	term  *fakeTerminal
+	mu    sync.Mutex
+	calls []string
+}
+
+func newMenuRun(t *testing.T) *menuRun {
+	t.Helper()
+	r := &menuRun{db: &fakeMenuDB{}}
+	var tio *telnet.TelnetIO
+	tio, r.term = newFakeTerminal(t)
+	r.exec = NewMenuExecutor(r.db, tio)
+	r.ctx = &ExecutionContext{UserID: 7, Username: "tester", Session: tio.Session}
+	t.Cleanup(r.exec.Close)
+
+	record := func(result CmdResult) CmdKeyResultHandler {
+		return func(ctx *ExecutionContext, options string) (CmdResult, error) {
+			r.mu.Lock()
+			r.calls = append(r.calls, options)
+			r.mu.Unlock()
+			return result, nil
+		}
+	}
+	r.exec.registry.Register(&CmdKeyDefinition{CmdKey: "T1", Result: record(ResultContinue)})
+	r.exec.registry.Register(&CmdKeyDefinition{CmdKey: "T2", Result: record(ResultStopChain)})
+	r.exec.registry.Register(&CmdKeyDefinition{CmdKey: "T3", Result: record(ResultHangup)})
+	r.exec.registry.Register(&CmdKeyDefinition{CmdKey: "T4", Handler: func(ctx *ExecutionContext, options string) error {
+		return errors.New("user_logout")
+	}})
+	return r
+}
+
+// run executes MAIN and fails the test if it doesn't end within a few seconds
+func (r *menuRun) run(t *testing.T, keys string) []string {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- r.exec.ExecuteMenu("MAIN", r.ctx)
+	}()
+	if keys != "" {
+		r.term.Type(keys)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil && !errors.Is(err, io.EOF) {
+			t.Fatalf("ExecuteMenu: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("menu didn't finish; output so far: %q", r.term.Output())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func TestLinkedCommandsRunInOrder(t *testing.T) {
+	r := newMenuRun(t)
+	r.db.addMenu("MAIN",
+		"A T1 a1",
+		"A T1 a2",
+		"B T2 gate",
+		"B T1 skipped",
+		"Q T3 bye",
+		"Q T1 after-hangup",
+	)
+
+	got := r.run(t, "abQ")
+	want := []string{"a1", "a2", "gate", "bye"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+}
+
+func TestFirstCmdRunsOnReturn(t *testing.T) {
+	r := newMenuRun(t)
+	r.db.addMenu("MAIN",
+		"FIRSTCMD T1 main-first",
+		"S -/ SUB",
+		"Q T3 bye",
+	)
+	r.db.addMenu("SUB",
+		"FIRSTCMD T1 sub-first",
+		"R -\\",
+	)
+
+	got := r.run(t, "SRQ")
+	want := []string{"main-first", "sub-first", "main-first", "bye"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+}
+
+func TestAnyKeyRunsAfterEveryKey(t *testing.T) {
+	r := newMenuRun(t)
+	r.db.addMenu("MAIN",
+		"A T1 a",
+		"ANYKEY T1 any",
+		"Q T4",
+	)
+
+	got := r.run(t, "AZQ")
+	want := []string{"a", "any", "any"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+}
+
+func TestNoKeyRunsAfterTimeout(t *testing.T) {
+	r := newMenuRun(t)
+	r.db.addMenu("MAIN",
+		"NOKEY T1 1",
+		"NOKEY T3 idle-logoff",
+	)
+
+	start := time.Now()
+	got := r.run(t, "")
+	want := []string{"1", "idle-logoff"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("NOKEY ran after %v, before its 1 second timeout", elapsed)
+	}
+}
+
+func TestAdaptHandler(t *testing.T) {
+	tests := []struct {
+		err     error
+		want    CmdResult
+		wantErr bool
+	}{
+		{err: nil, want: ResultContinue},
+		{err: errRedisplayMenu, want: ResultRedisplay},
+		{err: errors.New("user_logout"), want: ResultHangup},
+		{err: errors.New("boom"), want: ResultContinue, wantErr: true},
+	}
+	for _, tt := range tests {
+		handler := AdaptHandler(func(ctx *ExecutionContext, options string) error { return tt.err })
+		got, err := handler(nil, "")
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("AdaptHandler(%v) = %v, %v; want %v", tt.err, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package menu
+
+import (
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// maxLineInput is the longest command accepted at a line-input prompt
+const maxLineInput = 20
+
+// lightbarItem is a generated command item and where it was drawn
+type lightbarItem struct {
+	cmd       database.MenuCommand
+	x, y      int
+	formatted string // The item as drawn without highlight
+}
+
+// readMenuCommand reads a command in the menu's input mode and returns what
+// was typed together with the commands it selects
+func (e *MenuExecutor) readMenuCommand(menu *database.Menu, commands []database.MenuCommand, ctx *ExecutionContext, prompt string, noKeyCommands []database.MenuCommand, timeout time.Duration) (string, []database.MenuCommand, error) {
+	ctx.Input = ""
+
+	mode := menu.InputMode
+	if ctx.Session != nil && ctx.Session.HotkeysOff && mode != database.InputModeLightbar {
+		mode = database.InputModeLine
+	}
+	switch mode {
+	case database.InputModeLine:
+		input, err := e.readLineInput(ctx, prompt, "", noKeyCommands, timeout)
+		if err != nil || input == "" {
+			return "", nil, err
+		}
+		return input, e.findLineCommands(commands, input, ctx), nil
+	case database.InputModeLightbar:
+		if len(e.lightbar) > 0 {
+			input, err := e.readLightbar(menu, ctx, prompt, noKeyCommands, timeout)
+			if err != nil || input == "" {
+				return "", nil, err
+			}
+			return e.completeHotkey(commands, ctx, prompt, input)
+		}
+	}
+
+	input, err := e.readKeyPress(ctx, prompt, noKeyCommands, timeout)
+	if err != nil || input == "" {
+		return "", nil, err
+	}
+	return e.completeHotkey(commands, ctx, prompt, input)
+}
+
+// completeHotkey finishes a hotkey that starts a longer command: '/' reads a
+// second key for keys like /G, and a digit reads a number for # commands
+func (e *MenuExecutor) completeHotkey(commands []database.MenuCommand, ctx *ExecutionContext, prompt, input string) (string, []database.MenuCommand, error) {
+	if matches := e.findCommands(commands, input); len(matches) > 0 {
+		return input, matches, nil
+	}
+
+	switch {
+	case input == "/" && hasSlashKeys(commands):
+		e.io.Print("/")
+		key, err := e.readKeyPress(ctx, prompt+"/", nil, 0)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(key) != 1 {
+			// ESC, ENTER and the like cancel the command
+			e.io.Print("\b \b")
+			return "", nil, nil
+		}
+		e.io.Print(key)
+		input = "/" + key
+		return input, e.findCommands(commands, input), nil
+	case isNumericInput(input) && len(e.findCommands(commands, "#")) > 0:
+		line, err := e.readLineInput(ctx, prompt, input, nil, 0)
+		if err != nil || line == "" {
+			return "", nil, err
+		}
+		return line, e.findLineCommands(commands, line, ctx), nil
+	}
+	return input, nil, nil
+}
+
+// findLineCommands matches a typed command: an exact key first, then a
+// number for # commands, then the one key that starts with the input
+func (e *MenuExecutor) findLineCommands(commands []database.MenuCommand, input string, ctx *ExecutionContext) []database.MenuCommand {
+	if matches := e.findCommands(commands, input); len(matches) > 0 {
+		return matches
+	}
+	if isNumericInput(input) {
+		ctx.Input = input
+		return e.findCommands(commands, "#")
+	}
+
+	var found string
+	for _, cmd := range commands {
+		if !cmd.Active {
+			continue
+		}
+		for _, token := range splitMenuKeyVariants(cmd.Keys) {
+			if _, special := specialKeyLiterals[strings.ToUpper(token)]; special {
+				continue
+			}
+			if len(token) <= len(input) || !strings.HasPrefix(strings.ToUpper(token), input) {
+				continue
+			}
+			if found != "" && !strings.EqualFold(found, token) {
+				return nil // Ambiguous prefix
+			}
+			found = token
+		}
+	}
+	if found == "" {
+		return nil
+	}
+	return e.findCommands(commands, found)
+}
+
+// readLineInput reads a command terminated by Enter, echoing what is typed.
+// Enter on an empty line returns "ENTER" and ESC returns "ESC", so those
+// special keys still work. NOKEY only fires while the line is empty.
+func (e *MenuExecutor) readLineInput(ctx *ExecutionContext, prompt, initial string, noKeyCommands []database.MenuCommand, timeout time.Duration) (string, error) {
+	buf := []byte(strings.ToUpper(initial))
+	e.io.Print(string(buf))
+
+	for {
+		var noKey []database.MenuCommand
+		if len(buf) == 0 {
+			noKey = noKeyCommands
+		}
+		seq, err := e.readRawKey(ctx, prompt+string(buf), noKey, timeout)
+		if err != nil {
+			return "", err
+		}
+
+		switch key := normalizeInputKey(seq); {
+		case key == "ENTER":
+			if len(buf) == 0 {
+				return key, nil
+			}
+			return string(buf), nil
+		case seq == "\x1b":
+			if len(buf) == 0 {
+				return "ESC", nil
+			}
+			e.io.Print(strings.Repeat("\b \b", len(buf)))
+			buf = buf[:0]
+		case seq == "\b" || seq == "\x7f":
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+				e.io.Print("\b \b")
+			}
+		case len(key) == 1 && key[0] >= ' ' && len(buf) < maxLineInput:
+			buf = append(buf, key[0])
+			e.io.Print(key)
+		}
+	}
+}
+
+// readLightbar highlights the selected command item and moves the highlight
+// with the arrow keys. Enter returns the selected item's key; any other key
+// is returned as typed so hotkeys keep working.
+func (e *MenuExecutor) readLightbar(menu *database.Menu, ctx *ExecutionContext, prompt string, noKeyCommands []database.MenuCommand, timeout time.Duration) (string, error) {
+	if e.selected < 0 || e.selected >= len(e.lightbar) {
+		e.selected = 0
+	}
+	rows := lightbarRows(e.lightbar)
+	e.drawLightbarItem(menu, e.selected, true)
+
+	for {
+		seq, err := e.readRawKey(ctx, prompt, noKeyCommands, timeout)
+		if err != nil {
+			return "", err
+		}
+
+		step := 0
+		switch seq {
+		case "\x1b[A", "\x1bOA":
+			step = -1
+		case "\x1b[B", "\x1bOB":
+			step = 1
+		case "\x1b[D", "\x1bOD":
+			step = -rows
+		case "\x1b[C", "\x1bOC":
+			step = rows
+		}
+		if step != 0 {
+			next := e.selected + step
+			if next < 0 || next >= len(e.lightbar) {
+				next = (next%len(e.lightbar) + len(e.lightbar)) % len(e.lightbar)
+			}
+			e.drawLightbarItem(menu, e.selected, false)
+			e.selected = next
+			e.drawLightbarItem(menu, e.selected, true)
+			continue
+		}
+
+		key := normalizeInputKey(seq)
+		if key != "ENTER" {
+			return key, nil
+		}
+		item := e.lightbar[e.selected]
+		if tokens := splitMenuKeyVariants(item.cmd.Keys); len(tokens) > 0 {
+			return strings.ToUpper(tokens[0]), nil
+		}
+		return strings.ToUpper(strings.TrimSpace(item.cmd.Keys)), nil
+	}
+}
+
+// drawLightbarItem redraws a command item in place, highlighted in the
+// menu's lightbar colors or as it was first drawn
+func (e *MenuExecutor) drawLightbarItem(menu *database.Menu, index int, highlight bool) {
+	item := e.lightbar[index]
+	text := item.formatted
+	if highlight {
+		text = ui.ColorFromNumber(menu.LightbarFgColor) + ui.BackgroundFromNumber(menu.LightbarBgColor) +
+			ui.StripANSI(item.formatted) + ui.Ansi.Reset
+	}
+	e.io.Print(ui.Esc + "s" + ui.MoveCursorSequence(item.x, item.y) + text + ui.Esc + "u")
+}
+
+// lightbarRows counts the items in the first column, which is how far Left
+// and Right move the highlight
+func lightbarRows(items []lightbarItem) int {
+	rows := 0
+	for _, item := range items {
+		if item.x != items[0].x {
+			break
+		}
+		rows++
+	}
+	return max(rows, 1)
+}
+
+func hasSlashKeys(commands []database.MenuCommand) bool {
+	for _, cmd := range commands {
+		for _, token := range splitMenuKeyVariants(cmd.Keys) {
+			if cmd.Active && len(token) > 1 && token[0] == '/' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isNumericInput(input string) bool {
+	if input == "" {
+		return false
+	}
+	for i := 0; i < len(input); i++ {
+		if input[i] < '0' || input[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package menu
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robbiew/retrograde/internal/database"
+)
+
+func TestSplitMenuKeyVariants(t *testing.T) {
+	tests := map[string][]string{
+		"Q/G":    {"Q", "G"},
+		"/G":     {"/G"},
+		"Q /G":   {"Q", "/G"},
+		"A,B;C":  {"A", "B", "C"},
+		"/":      {"/"},
+		"ANYKEY": {"ANYKEY"},
+	}
+	for keyDef, want := range tests {
+		if got := splitMenuKeyVariants(keyDef); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitMenuKeyVariants(%q) = %q, want %q", keyDef, got, want)
+		}
+	}
+}
+
+func TestFindLineCommands(t *testing.T) {
+	commands := []database.MenuCommand{
+		{ID: 1, Keys: "/G", Active: true},
+		{ID: 2, Keys: "READ", Active: true},
+		{ID: 3, Keys: "REPLY", Active: true},
+		{ID: 4, Keys: "#", Active: true},
+		{ID: 5, Keys: "G", Active: true},
+	}
+	e := &MenuExecutor{}
+
+	tests := []struct {
+		input string
+		want  []int
+		num   string
+	}{
+		{input: "/G", want: []int{1}},
+		{input: "G", want: []int{5}},
+		{input: "REA", want: []int{2}},
+		{input: "RE"}, // Ambiguous
+		{input: "42", want: []int{4}, num: "42"},
+		{input: "X"},
+	}
+	for _, tt := range tests {
+		ctx := &ExecutionContext{}
+		var got []int
+		for _, cmd := range e.findLineCommands(commands, tt.input, ctx) {
+			got = append(got, cmd.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("findLineCommands(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if ctx.Input != tt.num {
+			t.Errorf("findLineCommands(%q) set Input %q, want %q", tt.input, ctx.Input, tt.num)
+		}
+	}
+}
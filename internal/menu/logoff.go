@@ -0,0 +1,155 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+)
+
+// LogoffStyle picks what the caller sees when their session ends
+type LogoffStyle int
+
+const (
+	LogoffGoodbye     LogoffStyle = iota // Show the logoff display file (or a message) before hanging up
+	LogoffImmediate                      // Hang up without a goodbye
+	LogoffCarrierLost                    // The caller is already gone; only record the call
+)
+
+// Logoff ends the caller's session. It runs at most once per executor, so
+// the server can call it after the menus return without repeating a logoff
+// a command already made. A goodbye runs the logoff sequence first; the
+// configured logoff events run whatever the style. message, when set, is
+// shown instead of the logoff display file.
+func (e *MenuExecutor) Logoff(ctx *ExecutionContext, style LogoffStyle, message string) {
+	if e.loggingOff {
+		// A logoff sequence step asked to log off; the logoff under way
+		// carries on once the sequence returns
+		return
+	}
+	e.logoff.Do(func() {
+		if ctx == nil || ctx.Session == nil {
+			return
+		}
+		cfg := config.GetDefaultConfig()
+		if e.db != nil {
+			if loaded, err := config.LoadConfigFromDB(e.db); err == nil {
+				cfg = loaded
+			}
+		}
+		logoffCfg := cfg.Configuration.Logoff
+		session := ctx.Session
+
+		if style == LogoffGoodbye && ctx.IO != nil {
+			e.loggingOff = true
+			if e.RunSequence(ctx, database.SequenceLogoff) != ResultHangup {
+				showGoodbye(ctx, logoffCfg, message)
+			}
+		}
+
+		online := int(time.Since(session.StartTime).Minutes())
+		if nm := logging.GetNodeManager(); nm != nil && session.NodeNumber > 0 {
+			nm.LogOff(session.NodeNumber, max(session.TimeLeft-online, 0))
+		}
+		if ctx.UserID > 0 && e.db != nil {
+			if logoffCfg.UpdateStats {
+				e.updateCallerStats(ctx.UserID, online)
+			}
+			if logoffCfg.FlushLastReads {
+				e.flushLastReads(ctx.UserID, session.LastRead)
+			}
+			e.recordLogoff(online)
+		}
+		logging.LogLogout(session.NodeNumber, ctx.Username, session.IPAddress)
+
+		session.Connected = false
+		if session.Conn != nil {
+			session.Conn.Close()
+		}
+	})
+}
+
+// showGoodbye shows the logoff display file, or message if one is given,
+// and leaves it on screen for the configured delay
+func showGoodbye(ctx *ExecutionContext, cfg config.LogoffConfig, message string) {
+	ctx.IO.Print("\r\n")
+	switch {
+	case message != "":
+		ctx.IO.Print(expandMCI(ctx, strings.ReplaceAll(message, "~", "\r\n")) + "\r\n")
+	case cfg.DisplayFile != "" && findDisplayFile(ctx, cfg.DisplayFile) != "":
+		displayFile(ctx, findDisplayFile(ctx, cfg.DisplayFile), true, displayFileFlags{noAbort: true})
+	default:
+		ctx.IO.Printf("Goodbye, %s!\r\n", ctx.Username)
+	}
+	if cfg.HangupDelay > 0 {
+		time.Sleep(time.Duration(cfg.HangupDelay) * time.Second)
+	}
+}
+
+// updateCallerStats adds this call's minutes to the caller's and today's
+// time online and records when they logged off
+func (e *MenuExecutor) updateCallerStats(userID int64, online int) {
+	countStat(e.db, userID, database.StatMinutes, online)
+	if err := e.db.UpsertUserDetail(userID, config.LastLogoffStat, time.Now().Format(time.RFC3339)); err != nil {
+		fmt.Printf("Warning: could not save logoff time: %v\n", err)
+	}
+}
+
+// flushLastReads saves the message pointers advanced this call
+func (e *MenuExecutor) flushLastReads(userID int64, lastRead map[string]int) {
+	for area, msgNum := range lastRead {
+		if err := e.db.SetUserLastRead(userID, area, msgNum); err != nil {
+			fmt.Printf("Warning: could not save last read for %s: %v\n", area, err)
+		}
+	}
+}
+
+// handleGoodbye handles the G command: show the logoff screen and hang up
+func handleGoodbye(ctx *ExecutionContext, options string) (CmdResult, error) {
+	return logoffCommand(ctx, LogoffGoodbye, "")
+}
+
+// handleCarefulLogoff handles HC [question]: log off only if the caller
+// confirms. No (the default) returns to the menu.
+func handleCarefulLogoff(ctx *ExecutionContext, options string) (CmdResult, error) {
+	if ctx == nil || ctx.IO == nil {
+		return ResultContinue, fmt.Errorf("careful logoff requires an execution context with IO")
+	}
+	question := strings.TrimSpace(options)
+	if question == "" {
+		question = " Are you sure you want to log off?"
+	}
+	yes, err := askYesNo(ctx, question, false)
+	if err != nil {
+		return ResultContinue, err
+	}
+	if !yes {
+		return ResultStopChain, nil
+	}
+	return logoffCommand(ctx, LogoffGoodbye, "")
+}
+
+// handleImmediateLogoff handles HI: hang up without a goodbye
+func handleImmediateLogoff(ctx *ExecutionContext, options string) (CmdResult, error) {
+	return logoffCommand(ctx, LogoffImmediate, "")
+}
+
+// handleMessageLogoff handles HM [string]: show the string (MCI codes
+// expanded, ~ for a new line) and hang up
+func handleMessageLogoff(ctx *ExecutionContext, options string) (CmdResult, error) {
+	if strings.TrimSpace(options) == "" {
+		return logoffCommand(ctx, LogoffImmediate, "")
+	}
+	return logoffCommand(ctx, LogoffGoodbye, options)
+}
+
+func logoffCommand(ctx *ExecutionContext, style LogoffStyle, message string) (CmdResult, error) {
+	if ctx == nil || ctx.Executor == nil || ctx.Session == nil {
+		return ResultContinue, fmt.Errorf("logoff requires an active session")
+	}
+	ctx.Executor.Logoff(ctx, style, message)
+	return ResultHangup, nil
+}
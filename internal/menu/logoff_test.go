@@ -0,0 +1,49 @@
+package menu
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+)
+
+func TestLogoffRunsOnce(t *testing.T) {
+	logging.SetLogDirectory(t.TempDir())
+	r := newMenuRun(t)
+	r.db.addMenu("MAIN",
+		"Q HC Really leave?",
+		"Q T1 kept",
+		"X HI",
+	)
+	session := r.ctx.Session
+	session.StartTime = time.Now()
+	session.MarkRead("general", 5)
+	session.MarkRead("general", 3)
+
+	got := r.run(t, "QnX")
+	if len(got) != 0 {
+		t.Errorf("declined HC ran linked commands %q", got)
+	}
+	if !strings.Contains(r.term.Output(), "Really leave?") {
+		t.Errorf("HC didn't ask its question; output %q", r.term.Output())
+	}
+	if session.Connected {
+		t.Errorf("HI left the session connected")
+	}
+	if r.db.lastRead["general"] != 5 {
+		t.Errorf("last read for general %d, want 5", r.db.lastRead["general"])
+	}
+	if _, ok := r.db.stats[database.StatMinutes]; !ok || r.db.details[config.LastLogoffStat] == "" {
+		t.Errorf("caller stats not recorded: %v %v", r.db.stats, r.db.details)
+	}
+
+	// The server logs off again when the menus return; that must do nothing
+	upserts := r.db.upserts
+	r.exec.Logoff(r.ctx, LogoffCarrierLost, "")
+	if r.db.upserts != upserts {
+		t.Errorf("second logoff updated caller stats again")
+	}
+}
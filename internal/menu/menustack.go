@@ -0,0 +1,101 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxMenuStackDepth limits nested gosubs. Deeper calls drop the oldest
+// return point, as Renegade does.
+const maxMenuStackDepth = 8
+
+// menuJump is a menu change requested by a navigation command. The menu loop
+// leaves the current menu and ExecuteMenu resolves the jump.
+type menuJump struct {
+	target string
+	gosub  bool // Push the current menu before jumping
+	ret    bool // Return to the menu on top of the stack
+}
+
+// resolveJump applies a jump to the menu stack and returns the next menu
+func (e *MenuExecutor) resolveJump(current string, jump *menuJump) string {
+	switch {
+	case jump.ret:
+		if len(e.menuStack) == 0 {
+			// Nothing to return to: reload the current menu
+			return current
+		}
+		top := e.menuStack[len(e.menuStack)-1]
+		e.menuStack = e.menuStack[:len(e.menuStack)-1]
+		return top
+	case jump.gosub:
+		if len(e.menuStack) >= maxMenuStackDepth {
+			e.menuStack = e.menuStack[1:]
+		}
+		e.menuStack = append(e.menuStack, current)
+	}
+	return jump.target
+}
+
+// requestJump validates the target menu and asks the menu loop to leave
+func (e *MenuExecutor) requestJump(target string, gosub bool) error {
+	if _, err := e.lookupMenuByName(target); err != nil {
+		return err
+	}
+	e.jump = &menuJump{target: target, gosub: gosub}
+	return nil
+}
+
+func handleGoToMenu(ctx *ExecutionContext, options string) error {
+	if ctx == nil {
+		return fmt.Errorf("go to menu command requires an execution context")
+	}
+
+	target := strings.TrimSpace(options)
+	if target == "" {
+		return fmt.Errorf("go to menu command requires a target menu name in options")
+	}
+
+	if ctx.Executor == nil {
+		return fmt.Errorf("go to menu command cannot run without a menu executor")
+	}
+
+	return ctx.Executor.requestJump(target, false)
+}
+
+// handleGosubMenu handles -/: jump to a menu, returning here on -\ or -"
+func handleGosubMenu(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.Executor == nil {
+		return fmt.Errorf("gosub menu command cannot run without a menu executor")
+	}
+
+	target := strings.TrimSpace(options)
+	if target == "" {
+		return fmt.Errorf("gosub menu command requires a target menu name in options")
+	}
+
+	return ctx.Executor.requestJump(target, true)
+}
+
+// handleReturnFromMenu handles -\ and -": go back to the menu that made the
+// last gosub
+func handleReturnFromMenu(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.Executor == nil {
+		return fmt.Errorf("return from menu command cannot run without a menu executor")
+	}
+	ctx.Executor.jump = &menuJump{ret: true}
+	return nil
+}
+
+// handleMacro handles -;: the options are queued as typed keys, with ';'
+// standing for Enter
+func handleMacro(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.IO == nil {
+		return fmt.Errorf("macro command requires an execution context with IO")
+	}
+	if options == "" {
+		return nil
+	}
+	ctx.IO.QueueInput(strings.ReplaceAll(options, ";", "\r"))
+	return nil
+}
@@ -0,0 +1,42 @@
+package menu
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResolveJump(t *testing.T) {
+	e := &MenuExecutor{}
+
+	current := e.resolveJump("MAIN", &menuJump{target: "MSG", gosub: true})
+	current = e.resolveJump(current, &menuJump{target: "READ", gosub: true})
+	if current != "READ" || len(e.menuStack) != 2 {
+		t.Fatalf("after two gosubs at %s with stack %v", current, e.menuStack)
+	}
+
+	current = e.resolveJump(current, &menuJump{target: "FILES"})
+	if current != "FILES" || len(e.menuStack) != 2 {
+		t.Fatalf("goto changed the stack: at %s with stack %v", current, e.menuStack)
+	}
+
+	for _, want := range []string{"MSG", "MAIN", "MAIN"} {
+		current = e.resolveJump(current, &menuJump{ret: true})
+		if current != want {
+			t.Fatalf("return went to %s, want %s", current, want)
+		}
+	}
+}
+
+func TestResolveJumpDepthLimit(t *testing.T) {
+	e := &MenuExecutor{}
+	current := "M0"
+	for i := 1; i <= maxMenuStackDepth+2; i++ {
+		current = e.resolveJump(current, &menuJump{target: fmt.Sprintf("M%d", i), gosub: true})
+	}
+	if len(e.menuStack) != maxMenuStackDepth {
+		t.Fatalf("stack depth %d, want %d", len(e.menuStack), maxMenuStackDepth)
+	}
+	if e.menuStack[0] != "M2" {
+		t.Errorf("oldest return point %s, want M2", e.menuStack[0])
+	}
+}
@@ -0,0 +1,141 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// handleConfirmYes handles -Y [question;quote]: a Yes answer (the default)
+// continues with the linked commands, a No answer shows the quote and stops
+func handleConfirmYes(ctx *ExecutionContext, options string) (CmdResult, error) {
+	return confirmGate(ctx, options, true)
+}
+
+// handleConfirmNo handles -N [question;quote]: a No answer (the default)
+// continues with the linked commands, a Yes answer shows the quote and stops
+func handleConfirmNo(ctx *ExecutionContext, options string) (CmdResult, error) {
+	return confirmGate(ctx, options, false)
+}
+
+func confirmGate(ctx *ExecutionContext, options string, continueOnYes bool) (CmdResult, error) {
+	if ctx == nil || ctx.IO == nil {
+		return ResultContinue, fmt.Errorf("yes/no prompt requires an execution context with IO")
+	}
+
+	question, quote, _ := strings.Cut(options, ";")
+	yes, err := askYesNo(ctx, question, continueOnYes)
+	if err != nil {
+		return ResultContinue, err
+	}
+	if yes == continueOnYes {
+		return ResultContinue, nil
+	}
+	if quote != "" {
+		printPromptLine(ctx, expandMCI(ctx, quote))
+	}
+	return ResultStopChain, nil
+}
+
+// askYesNo shows question and waits for Y or N. Enter picks defaultYes.
+func askYesNo(ctx *ExecutionContext, question string, defaultYes bool) (bool, error) {
+	io := ctx.IO
+	hint := "(y/N)"
+	if defaultYes {
+		hint = "(Y/n)"
+	}
+	io.Print("\r\n" + expandMCI(ctx, question) + " " + hint + ": ")
+
+	answer := defaultYes
+	for answered := false; !answered; {
+		key, err := io.GetKeyPressUpper()
+		if err != nil {
+			return false, err
+		}
+		switch key {
+		case 'Y', 'N':
+			answer = key == 'Y'
+			answered = true
+		case '\r', '\n':
+			answered = true
+		}
+	}
+
+	if answer {
+		io.Print("Yes\r\n")
+	} else {
+		io.Print("No\r\n")
+	}
+	if ctx.AdvanceRows != nil {
+		ctx.AdvanceRows(2)
+	}
+	return answer, nil
+}
+
+// handlePasswordGate handles -$ [password];[prompt];[bad-message]: the linked
+// commands only run if the caller types the password. Case is ignored.
+func handlePasswordGate(ctx *ExecutionContext, options string) (CmdResult, error) {
+	if ctx == nil || ctx.IO == nil {
+		return ResultContinue, fmt.Errorf("password command requires an execution context with IO")
+	}
+
+	parts := strings.SplitN(options, ";", 3)
+	password := strings.TrimSpace(parts[0])
+	if password == "" {
+		return ResultContinue, fmt.Errorf("password command requires a password in options")
+	}
+	prompt := " Password: "
+	if len(parts) > 1 && parts[1] != "" {
+		prompt = expandMCI(ctx, parts[1])
+	}
+	badMessage := ui.Ansi.RedHi + " Wrong password." + ui.Ansi.Reset
+	if len(parts) > 2 && parts[2] != "" {
+		badMessage = expandMCI(ctx, parts[2])
+	}
+
+	ctx.IO.Print("\r\n")
+	if ctx.AdvanceRows != nil {
+		ctx.AdvanceRows(2)
+	}
+	entered, err := ui.PromptPasswordSimple(ctx.IO, prompt, 20, "", ui.Ansi.WhiteHi, ui.Ansi.BgBlue)
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			ctx.IO.Print("\r\n")
+			return ResultStopChain, nil
+		}
+		return ResultContinue, err
+	}
+	if strings.EqualFold(entered, password) {
+		return ResultContinue, nil
+	}
+
+	if ctx.Session != nil {
+		logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "PASSWORD_GATE", "Wrong menu password")
+	}
+	printPromptLine(ctx, badMessage)
+	return ResultStopChain, nil
+}
+
+// handleSysOpLog handles -S [string]: append a line with MCI codes expanded to
+// the SysOp log
+func handleSysOpLog(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.Session == nil {
+		return fmt.Errorf("sysop log command requires an execution context with a session")
+	}
+	line := strings.TrimSpace(ui.StripANSI(expandMCI(ctx, options)))
+	if line == "" {
+		return nil
+	}
+	logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "SYSOP_LOG", line)
+	return nil
+}
+
+// printPromptLine prints a line below the prompt and counts the rows used
+func printPromptLine(ctx *ExecutionContext, text string) {
+	ctx.IO.Print(text + "\r\n")
+	if ctx.AdvanceRows != nil {
+		ctx.AdvanceRows(1 + strings.Count(text, "\n"))
+	}
+}
@@ -0,0 +1,249 @@
+package menu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/jam"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// sequenceStepDetail is the user_details key recording the day a
+// once-per-day step last ran for the caller
+const sequenceStepDetail = "sequence_step_%d"
+
+// sequenceStepRunner runs one step of the logon or logoff sequence
+type sequenceStepRunner func(ctx *ExecutionContext, options string) (CmdResult, error)
+
+// sequenceStepRunners maps step types to what they do. Steps backed by a
+// command key are skipped until that key is implemented; types without a
+// runner are skipped as well.
+var sequenceStepRunners = map[string]sequenceStepRunner{
+	database.StepDisplayFile: runDisplayStep,
+	database.StepLastCallers: commandKeyStep("OL"),
+	database.StepOneLiners:   runOneLinersStep,
+	database.StepMailCheck:   runMailCheckStep,
+	database.StepNewscan:     runNewscanStep,
+	database.StepAutomessage: commandKeyStep("UR"),
+	database.StepBulletins:   runBulletinsStep,
+	database.StepRumor:       runRumorStep,
+	database.StepCommand:     runCommandStep,
+}
+
+// RunSequence runs the sysop's logon or logoff steps in order. Steps the
+// caller fails the ACS for, or already saw today when once per day, are
+// skipped, and a failing step does not stop the others. It returns
+// ResultHangup if a step ended the call.
+func (e *MenuExecutor) RunSequence(ctx *ExecutionContext, sequence string) CmdResult {
+	if e.db == nil {
+		return ResultContinue
+	}
+	ctx = e.prepareContext(ctx)
+	steps, err := e.db.GetSequenceSteps(sequence)
+	if err != nil {
+		fmt.Printf("Warning: could not load %s sequence: %v\n", sequence, err)
+		return ResultContinue
+	}
+
+	var details map[string]string
+	today := time.Now().Format("2006-01-02")
+	for _, step := range steps {
+		if !step.Enabled || !e.checkACS(step.ACS, ctx) {
+			continue
+		}
+		run, ok := sequenceStepRunners[step.StepType]
+		if !ok {
+			continue
+		}
+
+		detailKey := fmt.Sprintf(sequenceStepDetail, step.ID)
+		if step.OncePerDay && ctx.UserID > 0 {
+			if details == nil {
+				if details, err = e.db.GetUserDetails(ctx.UserID); err != nil || details == nil {
+					details = map[string]string{}
+				}
+			}
+			if details[detailKey] == today {
+				continue
+			}
+		}
+
+		result, err := run(ctx, step.Options)
+		if err != nil {
+			fmt.Printf("Warning: %s step %d (%s) failed: %v\n", sequence, step.ID, step.StepType, err)
+		}
+		if step.OncePerDay && ctx.UserID > 0 {
+			details[detailKey] = today
+			if err := e.db.UpsertUserDetail(ctx.UserID, detailKey, today); err != nil {
+				fmt.Printf("Warning: could not save %s step %d: %v\n", sequence, step.ID, err)
+			}
+		}
+		if result == ResultHangup || (ctx.Session != nil && !ctx.Session.Connected) {
+			return ResultHangup
+		}
+	}
+	return ResultContinue
+}
+
+// runDisplayStep shows a display file (with -F flags) and pauses. A missing
+// file is skipped quietly so callers never see the error.
+func runDisplayStep(ctx *ExecutionContext, options string) (CmdResult, error) {
+	name, _, _ := strings.Cut(options, ";")
+	if strings.TrimSpace(name) == "" || findDisplayFile(ctx, strings.TrimSpace(name)) == "" {
+		return ResultContinue, nil
+	}
+	if err := displayFileCommand(ctx, options, true); err != nil {
+		return ResultContinue, err
+	}
+	ui.Pause(ctx.IO)
+	return ResultContinue, nil
+}
+
+// commandKeyStep runs a fixed command key for a step, once it is implemented
+func commandKeyStep(key string) sequenceStepRunner {
+	return func(ctx *ExecutionContext, options string) (CmdResult, error) {
+		return runCommandKey(ctx, key, options)
+	}
+}
+
+// runCommandStep runs the "KEY options" command key in options
+func runCommandStep(ctx *ExecutionContext, options string) (CmdResult, error) {
+	key, args, _ := strings.Cut(strings.TrimSpace(options), " ")
+	if key == "" {
+		return ResultContinue, nil
+	}
+	return runCommandKey(ctx, key, strings.TrimSpace(args))
+}
+
+func runCommandKey(ctx *ExecutionContext, key, options string) (CmdResult, error) {
+	registry := ctx.Executor.registry
+	def := registry.GetDefinition(key)
+	if def == nil || !def.Implemented {
+		return ResultContinue, nil
+	}
+	updateNodeActivity(ctx, def.NodeActivity)
+	return registry.Execute(key, ctx, options)
+}
+
+// runMailCheckStep tells the caller how much unread private mail is waiting
+func runMailCheckStep(ctx *ExecutionContext, options string) (CmdResult, error) {
+	count, err := countNewMail(ctx.Executor.db, ctx.Username)
+	if err != nil {
+		return ResultContinue, err
+	}
+	switch count {
+	case 0:
+		ctx.IO.Print(ui.Ansi.Cyan + "\r\n You have no new mail.\r\n" + ui.Ansi.Reset)
+	case 1:
+		ctx.IO.Print(ui.Ansi.YellowHi + "\r\n You have 1 new private message.\r\n" + ui.Ansi.Reset)
+	default:
+		ctx.IO.Print(ui.Ansi.YellowHi + fmt.Sprintf("\r\n You have %d new private messages.\r\n", count) + ui.Ansi.Reset)
+	}
+	return ResultContinue, nil
+}
+
+// countNewMail counts private messages to username, past their lastread
+// pointer, across every message area
+func countNewMail(db database.Database, username string) (int, error) {
+	areas, err := db.GetAllMessageAreas()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load message areas: %w", err)
+	}
+	toCRC := jam.CRC32String(username)
+
+	total := 0
+	for _, area := range areas {
+		base, err := openExistingBase(area)
+		if err != nil || base == nil {
+			continue
+		}
+		lastRead := 0
+		if lr, err := base.GetLastRead(username); err == nil {
+			lastRead = int(lr.LastReadMsg)
+		}
+		count, _ := base.GetMessageCount()
+		for msgNum := lastRead + 1; msgNum <= count; msgNum++ {
+			idx, err := base.ReadIndexRecord(msgNum)
+			if err != nil || idx.ToCRC != toCRC {
+				continue
+			}
+			hdr, err := base.ReadMessageHeader(msgNum)
+			if err != nil {
+				continue
+			}
+			if hdr.Attribute&jam.MSG_PRIVATE != 0 && hdr.Attribute&(jam.MSG_DELETED|jam.MSG_READ) == 0 {
+				total++
+			}
+		}
+		base.Close()
+	}
+	return total, nil
+}
+
+// openExistingBase opens an area's JAM base, or returns nil if it has never
+// been written to
+func openExistingBase(area database.MessageArea) (*jam.JAMBase, error) {
+	path := filepath.Join(area.Path, area.File)
+	if _, err := os.Stat(path + ".jhr"); err != nil {
+		return nil, nil
+	}
+	return jam.Open(path)
+}
+
+// runNewscanStep lists the areas with new messages and offers to read them.
+// Options override the question.
+func runNewscanStep(ctx *ExecutionContext, options string) (CmdResult, error) {
+	areas, err := ctx.Executor.db.GetAllMessageAreas()
+	if err != nil {
+		return ResultContinue, fmt.Errorf("failed to load message areas: %w", err)
+	}
+
+	io := ctx.IO
+	var found []database.MessageArea
+	for _, area := range areas {
+		base, err := openExistingBase(area)
+		if err != nil || base == nil {
+			continue
+		}
+		unread, err := base.GetUnreadCount(ctx.Username)
+		base.Close()
+		if err != nil || unread == 0 {
+			continue
+		}
+		if len(found) == 0 {
+			io.Print(ui.Ansi.WhiteHi + "\r\n New messages:\r\n" + ui.Ansi.Reset)
+		}
+		io.Printf(ui.Ansi.Cyan+"  %-30s "+ui.Ansi.YellowHi+"%d\r\n"+ui.Ansi.Reset, area.Name, unread)
+		found = append(found, area)
+	}
+	if len(found) == 0 {
+		io.Print(ui.Ansi.Cyan + "\r\n No new messages.\r\n" + ui.Ansi.Reset)
+		return ResultContinue, nil
+	}
+
+	question := strings.TrimSpace(options)
+	if question == "" {
+		question = " Read new messages now?"
+	}
+	yes, err := askYesNo(ctx, question, true)
+	if err != nil || !yes {
+		return ResultContinue, err
+	}
+
+	saved := ctx.Session.CurrentMessageArea
+	defer func() { ctx.Session.CurrentMessageArea = saved }()
+	for i := range found {
+		ctx.Session.CurrentMessageArea = &found[i]
+		if err := handleReadMessages(ctx, ""); err != nil {
+			return ResultContinue, err
+		}
+		if !ctx.Session.Connected {
+			return ResultHangup, nil
+		}
+	}
+	return ResultContinue, nil
+}
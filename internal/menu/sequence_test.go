@@ -0,0 +1,69 @@
+package menu
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/robbiew/retrograde/internal/database"
+)
+
+func TestRunSequenceHonoursACSAndOncePerDay(t *testing.T) {
+	r := newMenuRun(t)
+	var ran []string
+	record := func(result CmdResult) CmdKeyResultHandler {
+		return func(ctx *ExecutionContext, options string) (CmdResult, error) {
+			ran = append(ran, options)
+			return result, nil
+		}
+	}
+	r.exec.registry.Register(&CmdKeyDefinition{CmdKey: "S1", Implemented: true, Result: record(ResultContinue)})
+	r.exec.registry.Register(&CmdKeyDefinition{CmdKey: "S2", Implemented: true, Result: record(ResultHangup)})
+
+	command := func(options string) database.SequenceStep {
+		return database.SequenceStep{StepType: database.StepCommand, Options: options, Enabled: true}
+	}
+	sysopOnly := command("S1 sysop")
+	sysopOnly.ACS = "255"
+	daily := command("S1 daily")
+	daily.OncePerDay = true
+	disabled := command("S1 disabled")
+	disabled.Enabled = false
+	r.db.steps = map[string][]database.SequenceStep{
+		database.SequenceLogon: {
+			command("S1 first"),
+			sysopOnly,
+			daily,
+			command("T1 not-implemented"),
+			{StepType: "retired", Enabled: true},
+			disabled,
+		},
+		database.SequenceLogoff: {
+			command("S2 hangup"),
+			command("S1 after-hangup"),
+		},
+	}
+	for i := range r.db.steps[database.SequenceLogon] {
+		r.db.steps[database.SequenceLogon][i].ID = i + 1
+	}
+
+	if result := r.exec.RunSequence(r.ctx, database.SequenceLogon); result != ResultContinue {
+		t.Fatalf("logon sequence returned %v", result)
+	}
+	if want := []string{"first", "daily"}; !reflect.DeepEqual(ran, want) {
+		t.Fatalf("first logon ran %q, want %q", ran, want)
+	}
+
+	ran = nil
+	r.exec.RunSequence(r.ctx, database.SequenceLogon)
+	if want := []string{"first"}; !reflect.DeepEqual(ran, want) {
+		t.Fatalf("second logon ran %q, want %q", ran, want)
+	}
+
+	ran = nil
+	if result := r.exec.RunSequence(r.ctx, database.SequenceLogoff); result != ResultHangup {
+		t.Fatalf("logoff sequence returned %v, want a hangup", result)
+	}
+	if want := []string{"hangup"}; !reflect.DeepEqual(ran, want) {
+		t.Fatalf("logoff ran %q, want %q", ran, want)
+	}
+}
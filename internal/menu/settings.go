@@ -0,0 +1,535 @@
+package menu
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/robbiew/retrograde/internal/auth"
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/telnet"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// Auth audit event types written by OP
+const (
+	auditPasswordChange = "password_change"
+	auditSettingsChange = "settings_change"
+)
+
+// Limits on what a caller can put in their profile
+const (
+	signatureLines = 4
+	signatureWidth = 72
+	taglineWidth   = 60
+)
+
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// messageEditors are the editors a caller can pick as their default, by the
+// value saved in their preferences
+var messageEditors = []struct {
+	Value string
+	Name  string
+}{
+	{"line", "Line editor"},
+}
+
+// userSetting is one line of the OP settings screen
+type userSetting struct {
+	Key   string
+	Label string
+	Value func(s *settingsState) string
+	Edit  func(ctx *ExecutionContext, s *settingsState) error
+}
+
+// settingsState is what the settings screen knows about the caller
+type settingsState struct {
+	user    *database.UserRecord
+	details map[string]string
+	prefs   map[string]database.UserPreferenceRecord
+}
+
+func (s *settingsState) pref(key string) string {
+	return s.prefs[key].PreferenceValue
+}
+
+var userSettings = []userSetting{
+	{"P", "Password", func(*settingsState) string { return "********" }, editPassword},
+	{"E", "Email", func(s *settingsState) string { return s.user.Email.String }, editEmail},
+	{"L", "Location", func(s *settingsState) string { return s.user.Locations.String }, editLocation},
+	{"S", "Screen size", screenSizeValue, editScreenSize},
+	{"C", "Character set", func(s *settingsState) string { return autoValue(s.details[config.EncodingPreference]) }, editEncoding},
+	{"T", "Terminal emulation", func(s *settingsState) string { return autoValue(s.details[config.EmulationPreference]) }, editEmulation},
+	{"H", "Hotkeys", func(s *settingsState) string { return onOffValue(s.pref(config.HotkeysPreference) != "off") }, toggleHotkeys},
+	{"X", "Expert mode", func(s *settingsState) string { return onOffValue(s.pref(config.ExpertPreference) == "on") }, toggleExpert},
+	{"M", "Message editor", editorValue, editEditor},
+	{"G", "Signature", func(s *settingsState) string { return firstLine(s.details[config.SignatureDetail]) }, editSignature},
+	{"A", "Tagline", func(s *settingsState) string { return s.details[config.TaglineDetail] }, editTagline},
+}
+
+func autoValue(value string) string {
+	if value == "" || value == "auto" {
+		return "Auto"
+	}
+	return value
+}
+
+func onOffValue(on bool) string {
+	if on {
+		return "On"
+	}
+	return "Off"
+}
+
+func firstLine(text string) string {
+	line, _, more := strings.Cut(text, "\n")
+	if more {
+		line += " ..."
+	}
+	return line
+}
+
+func screenSizeValue(s *settingsState) string {
+	width, height := autoValue(s.details[config.WidthPreference]), autoValue(s.details[config.HeightPreference])
+	if width == "Auto" && height == "Auto" {
+		return "Auto"
+	}
+	return width + " x " + height
+}
+
+func editorValue(s *settingsState) string {
+	value := s.pref(config.EditorPreference)
+	for _, editor := range messageEditors {
+		if editor.Value == value {
+			return editor.Name
+		}
+	}
+	return messageEditors[0].Name
+}
+
+// handleUserSettings handles OP: let the caller change their password,
+// contact details, terminal overrides and profile.
+// Options: [info type] - the letter of one setting to change, skipping the
+// settings screen
+func handleUserSettings(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.IO == nil || ctx.Session == nil || ctx.Executor == nil || ctx.Executor.db == nil {
+		return fmt.Errorf("user settings require an execution context with IO and a database")
+	}
+	if ctx.UserID <= 0 {
+		return fmt.Errorf("user settings require a logged in user")
+	}
+	io := ctx.IO
+
+	if key := strings.ToUpper(strings.TrimSpace(options)); key != "" {
+		state, err := loadSettingsState(ctx)
+		if err != nil {
+			return err
+		}
+		setting := findUserSetting(key)
+		if setting == nil {
+			return fmt.Errorf("unknown user setting %q", options)
+		}
+		return setting.Edit(ctx, state)
+	}
+
+	for {
+		state, err := loadSettingsState(ctx)
+		if err != nil {
+			return err
+		}
+		printUserSettings(ctx, state)
+
+		io.Print("\r\n")
+		answer, err := ui.PromptSimple(io, " Change which setting? (Q)uit: ", 1, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil
+			}
+			return err
+		}
+		answer = strings.ToUpper(strings.TrimSpace(answer))
+		if answer == "" || answer == "Q" {
+			io.Print("\r\n")
+			return nil
+		}
+		if setting := findUserSetting(answer); setting != nil {
+			io.Print("\r\n")
+			if err := setting.Edit(ctx, state); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func findUserSetting(key string) *userSetting {
+	for i := range userSettings {
+		if userSettings[i].Key == key {
+			return &userSettings[i]
+		}
+	}
+	return nil
+}
+
+func loadSettingsState(ctx *ExecutionContext) (*settingsState, error) {
+	db := ctx.Executor.db
+	user, err := db.GetUserByID(ctx.UserID)
+	if err != nil {
+		return nil, err
+	}
+	details, err := db.GetUserDetails(ctx.UserID)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := db.GetUserPreferences(ctx.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &settingsState{user: user, details: details, prefs: prefs}, nil
+}
+
+func printUserSettings(ctx *ExecutionContext, state *settingsState) {
+	io := ctx.IO
+	io.ClearScreen()
+	io.Print(ui.Ansi.WhiteHi + "\r\n Your Settings\r\n" + ui.Ansi.Cyan + " " + strings.Repeat("\xc4", 60) + "\r\n" + ui.Ansi.Reset)
+	for _, setting := range userSettings {
+		io.Printf(ui.Ansi.Cyan+" ("+ui.Ansi.YellowHi+"%s"+ui.Ansi.Cyan+") %-20s"+ui.Ansi.WhiteHi+"%.36s\r\n"+ui.Ansi.Reset,
+			setting.Key, setting.Label, setting.Value(state))
+	}
+}
+
+// auditSetting records a settings change in the auth audit trail and the
+// event log
+func auditSetting(ctx *ExecutionContext, eventType, field string) {
+	session := ctx.Session
+	entry := &database.AuthAuditEntry{
+		UserID:    sql.NullInt64{Int64: ctx.UserID, Valid: true},
+		Username:  database.NullString(ctx.Username),
+		EventType: eventType,
+		IPAddress: database.NullString(session.IPAddress),
+		Metadata:  database.NullString("field=" + field),
+		Context:   database.NullString("OP"),
+	}
+	if err := ctx.Executor.db.InsertAuthAudit(entry); err != nil {
+		fmt.Printf("Warning: could not audit settings change: %v\n", err)
+	}
+	logging.LogEvent(session.NodeNumber, ctx.Username, session.IPAddress, "SETTINGS", field)
+}
+
+// settingSaved confirms a change to the caller
+func settingSaved(ctx *ExecutionContext, label string) {
+	settingNotice(ctx, label+" saved.")
+}
+
+func settingNotice(ctx *ExecutionContext, message string) {
+	ctx.IO.Print(ui.Ansi.GreenHi + "\r\n\r\n " + message + "\r\n" + ui.Ansi.Reset)
+	ui.Pause(ctx.IO)
+}
+
+// promptSetting asks for a new value, reporting ok false on Esc
+func promptSetting(ctx *ExecutionContext, label string, width int, current string) (string, bool, error) {
+	value, err := ui.PromptSimple(ctx.IO, label, width, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, current)
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return strings.TrimSpace(value), true, nil
+}
+
+func settingError(ctx *ExecutionContext, message string) error {
+	ctx.IO.Print(ui.Ansi.RedHi + "\r\n\r\n " + message + "\r\n" + ui.Ansi.Reset)
+	ui.Pause(ctx.IO)
+	return nil
+}
+
+func editPassword(ctx *ExecutionContext, s *settingsState) error {
+	io := ctx.IO
+	current, err := ui.PromptPasswordSimple(io, " Current password: ", 20, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue)
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			return nil
+		}
+		return err
+	}
+	stored := auth.PasswordDigest{Hash: s.user.PasswordHash, Algorithm: s.user.PasswordAlgo.String, Salt: s.user.PasswordSalt.String}
+	if !auth.CheckPassword(current, stored) {
+		logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "SETTINGS_FAILED", "wrong current password")
+		return settingError(ctx, "Wrong password.")
+	}
+
+	io.Print("\r\n")
+	password, err := ui.PromptPasswordSimple(io, " New password: ", 20, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue)
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			return nil
+		}
+		return err
+	}
+	if len(password) < 4 {
+		return settingError(ctx, "Password must be at least 4 characters.")
+	}
+	io.Print("\r\n")
+	confirm, err := ui.PromptPasswordSimple(io, " Confirm password: ", 20, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue)
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			return nil
+		}
+		return err
+	}
+	if confirm != password {
+		return settingError(ctx, "Passwords do not match.")
+	}
+
+	cfg, err := config.LoadConfigFromDB(ctx.Executor.db)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	digest, err := auth.NewPasswordDigest(password, cfg.Configuration.Auth.PasswordAlgorithm)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Executor.db.UpdatePassword(ctx.UserID, digest.Hash, digest.Algorithm, digest.Salt, digest.UpdatedAt); err != nil {
+		return err
+	}
+	auditSetting(ctx, auditPasswordChange, "password")
+	settingSaved(ctx, "Password")
+	return nil
+}
+
+func editEmail(ctx *ExecutionContext, s *settingsState) error {
+	email, ok, err := promptSetting(ctx, " Email address: ", 40, s.user.Email.String)
+	if err != nil || !ok || email == s.user.Email.String {
+		return err
+	}
+	if !emailPattern.MatchString(email) {
+		return settingError(ctx, "That is not an email address.")
+	}
+	if other, err := ctx.Executor.db.GetUserByEmail(email); err == nil && other != nil && other.ID != ctx.UserID {
+		return settingError(ctx, "That email address is already in use.")
+	}
+
+	s.user.Email = database.NullString(email)
+	if err := ctx.Executor.db.UpdateUser(s.user); err != nil {
+		return err
+	}
+	auditSetting(ctx, auditSettingsChange, "email")
+	settingSaved(ctx, "Email address")
+	return nil
+}
+
+func editLocation(ctx *ExecutionContext, s *settingsState) error {
+	location, ok, err := promptSetting(ctx, " Location: ", 30, s.user.Locations.String)
+	if err != nil || !ok || location == s.user.Locations.String {
+		return err
+	}
+
+	s.user.Locations = database.NullString(location)
+	if err := ctx.Executor.db.UpdateUser(s.user); err != nil {
+		return err
+	}
+	if err := ctx.Executor.db.UpsertUserDetail(ctx.UserID, "locations", location); err != nil {
+		return err
+	}
+	auditSetting(ctx, auditSettingsChange, "location")
+	settingSaved(ctx, "Location")
+	return nil
+}
+
+func editScreenSize(ctx *ExecutionContext, s *settingsState) error {
+	ctx.IO.Print(ui.Ansi.Yellow + " Leave blank to use what your terminal reports.\r\n\r\n" + ui.Ansi.Reset)
+	width, ok, err := promptSize(ctx, " Width (20-255): ", s.details[config.WidthPreference], 20)
+	if err != nil || !ok {
+		return err
+	}
+	ctx.IO.Print("\r\n")
+	height, ok, err := promptSize(ctx, " Height (10-255): ", s.details[config.HeightPreference], 10)
+	if err != nil || !ok {
+		return err
+	}
+
+	db := ctx.Executor.db
+	if err := db.UpsertUserDetail(ctx.UserID, config.WidthPreference, width); err != nil {
+		return err
+	}
+	if err := db.UpsertUserDetail(ctx.UserID, config.HeightPreference, height); err != nil {
+		return err
+	}
+	telnet.ApplyPreferences(ctx.Session, map[string]string{config.WidthPreference: width, config.HeightPreference: height})
+	auditSetting(ctx, auditSettingsChange, "screen_size")
+	settingSaved(ctx, "Screen size")
+	return nil
+}
+
+// promptSize asks for a screen dimension between min and 255, returning
+// "auto" for a blank answer
+func promptSize(ctx *ExecutionContext, label, current string, min int) (string, bool, error) {
+	if current == "auto" {
+		current = ""
+	}
+	for {
+		value, ok, err := promptSetting(ctx, label, 3, current)
+		if err != nil || !ok {
+			return "", ok, err
+		}
+		if value == "" {
+			return "auto", true, nil
+		}
+		if n, err := strconv.Atoi(value); err == nil && n >= min && n <= 255 {
+			return value, true, nil
+		}
+		ctx.IO.Print(ui.Ansi.RedHi + fmt.Sprintf("\r\n Enter a number from %d to 255.\r\n", min) + ui.Ansi.Reset)
+	}
+}
+
+// chooseSetting offers a list of values and saves the one picked to the
+// user detail key
+func chooseSetting(ctx *ExecutionContext, label, key string, choices []string, apply func(value string)) error {
+	io := ctx.IO
+	for i, choice := range choices {
+		io.Printf(ui.Ansi.Cyan+" ("+ui.Ansi.YellowHi+"%d"+ui.Ansi.Cyan+") "+ui.Ansi.WhiteHi+"%s\r\n"+ui.Ansi.Reset, i+1, choice)
+	}
+	io.Print("\r\n")
+	answer, ok, err := promptSetting(ctx, " "+label+": ", 1, "")
+	if err != nil || !ok {
+		return err
+	}
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(choices) {
+		return nil
+	}
+	value := choices[n-1]
+	if err := ctx.Executor.db.UpsertUserDetail(ctx.UserID, key, value); err != nil {
+		return err
+	}
+	if value != "auto" {
+		apply(value)
+	}
+	auditSetting(ctx, auditSettingsChange, key)
+	settingSaved(ctx, label)
+	return nil
+}
+
+func editEncoding(ctx *ExecutionContext, s *settingsState) error {
+	return chooseSetting(ctx, "Character set", config.EncodingPreference,
+		[]string{"auto", config.EncodingCP437, config.EncodingUTF8},
+		func(value string) { ctx.Session.Encoding = value })
+}
+
+func editEmulation(ctx *ExecutionContext, s *settingsState) error {
+	return chooseSetting(ctx, "Terminal emulation", config.EmulationPreference,
+		[]string{"auto", ui.EmulationANSI, ui.EmulationRIP, ui.EmulationASCII},
+		func(value string) { ctx.Session.Emulation = value })
+}
+
+// setPreference saves a preference and audits the change
+func setPreference(ctx *ExecutionContext, key, value, category string) error {
+	pref := &database.UserPreferenceRecord{
+		UserID:          ctx.UserID,
+		PreferenceKey:   key,
+		PreferenceValue: value,
+		Category:        database.NullString(category),
+	}
+	if err := ctx.Executor.db.SetUserPreference(pref); err != nil {
+		return err
+	}
+	auditSetting(ctx, auditSettingsChange, key)
+	return nil
+}
+
+func toggleHotkeys(ctx *ExecutionContext, s *settingsState) error {
+	off := s.pref(config.HotkeysPreference) != "off"
+	if err := setPreference(ctx, config.HotkeysPreference, strings.ToLower(onOffValue(!off)), "menu"); err != nil {
+		return err
+	}
+	ctx.Session.HotkeysOff = off
+	settingNotice(ctx, "Hotkeys are now "+onOffValue(!off)+".")
+	return nil
+}
+
+func toggleExpert(ctx *ExecutionContext, s *settingsState) error {
+	expert := s.pref(config.ExpertPreference) != "on"
+	if err := setPreference(ctx, config.ExpertPreference, strings.ToLower(onOffValue(expert)), "menu"); err != nil {
+		return err
+	}
+	ctx.Session.Expert = expert
+	settingNotice(ctx, "Expert mode is now "+onOffValue(expert)+".")
+	return nil
+}
+
+func editEditor(ctx *ExecutionContext, s *settingsState) error {
+	io := ctx.IO
+	for i, editor := range messageEditors {
+		io.Printf(ui.Ansi.Cyan+" ("+ui.Ansi.YellowHi+"%d"+ui.Ansi.Cyan+") "+ui.Ansi.WhiteHi+"%s\r\n"+ui.Ansi.Reset, i+1, editor.Name)
+	}
+	io.Print("\r\n")
+	answer, ok, err := promptSetting(ctx, " Message editor: ", 1, "")
+	if err != nil || !ok {
+		return err
+	}
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(messageEditors) {
+		return nil
+	}
+	if err := setPreference(ctx, config.EditorPreference, messageEditors[n-1].Value, "editor"); err != nil {
+		return err
+	}
+	settingSaved(ctx, "Message editor")
+	return nil
+}
+
+func editSignature(ctx *ExecutionContext, s *settingsState) error {
+	io := ctx.IO
+	if current := s.details[config.SignatureDetail]; current != "" {
+		io.Print(ui.Ansi.Cyan + " Your signature now:\r\n" + ui.Ansi.Reset)
+		for _, line := range strings.Split(current, "\n") {
+			io.Print(ui.Ansi.WhiteHi + " " + line + "\r\n" + ui.Ansi.Reset)
+		}
+	}
+	io.Print(ui.Ansi.Yellow + fmt.Sprintf("\r\n Enter your signature (%d lines, empty line to end; none clears it):\r\n\r\n", signatureLines) + ui.Ansi.Reset)
+	lines, escaped, err := readTextLines(io, signatureLines, signatureWidth)
+	if err != nil || escaped {
+		return err
+	}
+
+	if err := ctx.Executor.db.UpsertUserDetail(ctx.UserID, config.SignatureDetail, strings.Join(lines, "\n")); err != nil {
+		return err
+	}
+	auditSetting(ctx, auditSettingsChange, config.SignatureDetail)
+	settingSaved(ctx, "Signature")
+	return nil
+}
+
+func editTagline(ctx *ExecutionContext, s *settingsState) error {
+	tagline, ok, err := promptSetting(ctx, " Tagline: ", taglineWidth, s.details[config.TaglineDetail])
+	if err != nil || !ok || tagline == s.details[config.TaglineDetail] {
+		return err
+	}
+
+	if err := ctx.Executor.db.UpsertUserDetail(ctx.UserID, config.TaglineDetail, tagline); err != nil {
+		return err
+	}
+	auditSetting(ctx, auditSettingsChange, config.TaglineDetail)
+	settingSaved(ctx, "Tagline")
+	return nil
+}
+
+// ApplySessionPreferences applies a caller's saved menu preferences to their
+// session at logon
+func ApplySessionPreferences(db database.Database, userID int64, session *config.TelnetSession) {
+	if db == nil || session == nil || userID <= 0 {
+		return
+	}
+	prefs, err := db.GetUserPreferences(userID)
+	if err != nil {
+		fmt.Printf("Warning: could not load user preferences: %v\n", err)
+		return
+	}
+	session.HotkeysOff = prefs[config.HotkeysPreference].PreferenceValue == "off"
+	session.Expert = prefs[config.ExpertPreference].PreferenceValue == "on"
+}
@@ -0,0 +1,342 @@
+package menu
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/bus"
+	"github.com/robbiew/retrograde/internal/chat"
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/jam"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/notify"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// errRedisplayMenu asks the menu loop to redraw the menu, e.g. after the
+// sysop broke in for chat
+var errRedisplayMenu = errors.New("menu_redisplay")
+
+// handleSysOpWindow handles the -C command (display a message on the SysOp window).
+// Options: <string>
+func handleSysOpWindow(ctx *ExecutionContext, options string) error {
+	text := strings.TrimSpace(options)
+	if text == "" {
+		return nil
+	}
+	node := 0
+	if ctx.Session != nil {
+		node = ctx.Session.NodeNumber
+	}
+	chat.Desk().WindowMessage(node, ctx.Username, fmt.Sprintf("Node %d (%s): %s", node, ctx.Username, text))
+	return nil
+}
+
+// handlePageSysOp handles the OC command. Within the configured hours the
+// sysop console is paged; if nobody answers, or outside those hours, the
+// caller may leave feedback instead.
+// Options: <user #> <;string> - user to receive feedback, custom reason prompt
+func handlePageSysOp(ctx *ExecutionContext, options string) error {
+	if ctx.Session == nil || ctx.IO == nil || ctx.Executor == nil {
+		return fmt.Errorf("page sysop requires an active session")
+	}
+	io := ctx.IO
+
+	cfg, err := config.LoadConfigFromDB(ctx.Executor.db)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	chatCfg := cfg.Configuration.SysOpChat
+
+	userPart, prompt, _ := strings.Cut(options, ";")
+	recipient := cfg.Configuration.General.SysOpName
+	if id, err := strconv.ParseInt(strings.TrimSpace(userPart), 10, 64); err == nil && id > 0 {
+		if user, err := ctx.Executor.db.GetUserByID(id); err == nil && user != nil {
+			recipient = user.Username
+		}
+	}
+	if recipient == "" {
+		recipient = "SysOp"
+	}
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		prompt = " Reason for chat: "
+	}
+
+	if !chat.WithinHours(chatCfg.PageStart, chatCfg.PageEnd, time.Now()) {
+		io.Print("\r\n" + ui.Ansi.Yellow + fmt.Sprintf(" %s is not available for chat right now.", recipient) + ui.Ansi.Reset + "\r\n")
+		return offerFeedback(ctx, cfg, recipient)
+	}
+
+	io.Print("\r\n")
+	reason, err := ui.PromptSimple(io, expandMCI(ctx, prompt), 50, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			io.Print("\r\n")
+			return nil
+		}
+		return err
+	}
+
+	node := ctx.Session.NodeNumber
+	desk := chat.Desk()
+	desk.Page(node, ctx.Username, strings.TrimSpace(reason))
+	updateNodeActivity(ctx, "Paging the SysOp.")
+	logging.LogEvent(node, ctx.Username, ctx.Session.IPAddress, "SYSOP_PAGE", strings.TrimSpace(reason))
+	notify.SysOpPage(cfg, node, ctx.Username, strings.TrimSpace(reason))
+
+	seconds := chatCfg.PageSeconds
+	if seconds <= 0 {
+		seconds = 30
+	}
+	io.Print("\r\n" + ui.Ansi.CyanHi + fmt.Sprintf(" Paging %s, ESC to stop ", recipient) + ui.Ansi.Reset)
+
+	deadline := time.Now().Add(time.Duration(seconds) * time.Second)
+	nextRing := time.Now()
+	for time.Now().Before(deadline) {
+		if time.Now().After(nextRing) {
+			io.Print("\a" + ui.Ansi.WhiteHi + "." + ui.Ansi.Reset)
+			nextRing = time.Now().Add(3 * time.Second)
+		}
+
+		if ctx.Executor.takeSysOpChatRequest() {
+			return ctx.Executor.runSysOpChat(ctx, cfg)
+		}
+
+		seq, err := io.ReadKeySequence(250 * time.Millisecond)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			desk.CancelPage(node)
+			return err
+		}
+		if normalizeInputKey(seq) == "ESC" {
+			desk.CancelPage(node)
+			io.Print("\r\n")
+			return nil
+		}
+	}
+
+	desk.CancelPage(node)
+	io.Print("\r\n\r\n" + ui.Ansi.Yellow + fmt.Sprintf(" %s didn't answer.", recipient) + ui.Ansi.Reset + "\r\n")
+	return offerFeedback(ctx, cfg, recipient)
+}
+
+// offerFeedback asks whether to leave a private message for recipient in the
+// feedback area
+func offerFeedback(ctx *ExecutionContext, cfg *config.Config, recipient string) error {
+	io := ctx.IO
+	io.Print(ui.Ansi.Yellow + " Leave feedback instead? (Y/N): " + ui.Ansi.Reset)
+	key, err := io.GetKeyPressUpper()
+	if err != nil {
+		return err
+	}
+	io.Printf("%c\r\n", key)
+	if key != 'Y' {
+		return nil
+	}
+	return leaveFeedback(ctx, cfg, recipient)
+}
+
+func leaveFeedback(ctx *ExecutionContext, cfg *config.Config, recipient string) error {
+	return leavePrivateMessage(ctx, cfg, recipient, "Feedback", "Feedback", "FEEDBACK")
+}
+
+// leavePrivateMessage writes a private message to recipient in the feedback
+// area. label names the message in what the caller sees, and action is the
+// log action recorded once it is saved.
+func leavePrivateMessage(ctx *ExecutionContext, cfg *config.Config, recipient, subject, label, action string) error {
+	io := ctx.IO
+
+	area, err := feedbackArea(ctx.Executor.db, cfg.Configuration.SysOpChat.FeedbackArea)
+	if err != nil {
+		io.Print(ui.Ansi.RedHi + "\r\n " + err.Error() + "\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+
+	io.Print("\r\n")
+	defaultSubject := subject
+	subject, err = ui.PromptSimple(io, " Subject: ", 60, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, defaultSubject)
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			io.Print("\r\n")
+			return nil
+		}
+		return err
+	}
+	if strings.TrimSpace(subject) == "" {
+		subject = defaultSubject
+	}
+
+	lines, escaped, err := readMessageLines(io)
+	if err != nil {
+		return err
+	}
+	if escaped || len(lines) == 0 {
+		io.Print(ui.Ansi.Yellow + fmt.Sprintf("\r\n %s not sent.\r\n", label) + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+
+	if err := writePrivateMessage(area, ctx.Username, recipient, subject, strings.Join(lines, "\n")); err != nil {
+		return fmt.Errorf("failed to save %s: %w", strings.ToLower(label), err)
+	}
+
+	logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, action, fmt.Sprintf("to=%s area=%s", recipient, area.Name))
+	io.Print(ui.Ansi.GreenHi + fmt.Sprintf("\r\n %s sent to %s.\r\n", label, recipient) + ui.Ansi.Reset)
+	ui.Pause(io)
+	return nil
+}
+
+// SendPrivateMail leaves a private message in the feedback area, which is
+// where callers read mail addressed to them
+func SendPrivateMail(db database.Database, cfg *config.Config, from, to, subject, text string) error {
+	area, err := feedbackArea(db, cfg.Configuration.SysOpChat.FeedbackArea)
+	if err != nil {
+		return err
+	}
+	return writePrivateMessage(area, from, to, subject, text)
+}
+
+func writePrivateMessage(area *database.MessageArea, from, to, subject, text string) error {
+	if err := os.MkdirAll(area.Path, 0755); err != nil {
+		return fmt.Errorf("failed to create message directory: %w", err)
+	}
+	base, err := jam.Open(filepath.Join(area.Path, area.File))
+	if err != nil {
+		return fmt.Errorf("failed to open feedback area: %w", err)
+	}
+	defer base.Close()
+
+	msg := jam.NewMessage()
+	msg.Header = &jam.MessageHeader{Attribute: jam.MSG_LOCAL | jam.MSG_TYPELOCAL | jam.MSG_PRIVATE}
+	msg.From = from
+	msg.To = to
+	msg.Subject = subject
+	msg.Text = text
+	_, err = base.WriteMessage(msg)
+	return err
+}
+
+// feedbackArea finds the configured feedback area by file name, or the first
+// local area when none is configured. Private mail must never land in a
+// network area, where it would be exported with the public posts.
+func feedbackArea(db database.Database, file string) (*database.MessageArea, error) {
+	areas, err := db.GetAllMessageAreas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load message areas: %w", err)
+	}
+	file = strings.TrimSpace(file)
+	for i := range areas {
+		area := &areas[i]
+		if file != "" && !strings.EqualFold(area.File, file) {
+			continue
+		}
+		if !isLocalArea(area) {
+			if file != "" {
+				return nil, fmt.Errorf("feedback area %s is a network area", file)
+			}
+			continue
+		}
+		return area, nil
+	}
+	if file != "" {
+		return nil, fmt.Errorf("feedback area %s not found", file)
+	}
+	return nil, fmt.Errorf("no local message area is configured for feedback")
+}
+
+// isLocalArea reports whether area stays on this system
+func isLocalArea(area *database.MessageArea) bool {
+	areaType := strings.TrimSpace(area.AreaType)
+	return areaType == "" || strings.EqualFold(areaType, "local")
+}
+
+// watchSysOpChat subscribes the session to sysop chat requests for its node
+func (e *MenuExecutor) watchSysOpChat(ctx *ExecutionContext) {
+	if e.sysopChat != nil || ctx == nil || ctx.Session == nil || ctx.Session.NodeNumber <= 0 {
+		return
+	}
+	e.sysopChat = bus.Default().Subscribe(chat.SysOpChatTopic(ctx.Session.NodeNumber, false), 256)
+}
+
+// takeSysOpChatRequest reports whether the sysop has opened a chat with this
+// node, discarding leftovers from earlier chats
+func (e *MenuExecutor) takeSysOpChatRequest() bool {
+	if e.sysopChat == nil {
+		return false
+	}
+	for {
+		select {
+		case ev, ok := <-e.sysopChat.C:
+			if !ok {
+				return false
+			}
+			if ev.Kind == chat.KindChatOpen {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+// runSysOpChat runs the caller's side of a split-screen sysop chat and saves
+// the transcript to the logs directory
+func (e *MenuExecutor) runSysOpChat(ctx *ExecutionContext, cfg *config.Config) error {
+	node := ctx.Session.NodeNumber
+	sysopName := "SysOp"
+	if cfg != nil && cfg.Configuration.General.SysOpName != "" {
+		sysopName = cfg.Configuration.General.SysOpName
+	}
+
+	updateNodeActivity(ctx, "Chatting with the SysOp.")
+	logging.LogEvent(node, ctx.Username, ctx.Session.IPAddress, "SYSOP_CHAT", "Chat started")
+
+	transcript, err := chat.OpenTranscript(logging.LogDirectory(), node, ctx.Username)
+	if err != nil {
+		logging.LogEvent(node, ctx.Username, ctx.Session.IPAddress, "SYSOP_CHAT", err.Error())
+	}
+
+	c := &chat.SplitChat{
+		IO:         ctx.IO,
+		Bus:        bus.Default(),
+		In:         e.sysopChat,
+		Node:       node,
+		SysOpName:  sysopName,
+		CallerName: ctx.Username,
+		Transcript: transcript,
+	}
+	runErr := c.Run()
+	chat.Desk().CloseChat(node)
+
+	details := "Chat ended"
+	if transcript != nil {
+		transcript.Close()
+		details += ", transcript " + transcript.Path
+	}
+	logging.LogEvent(node, ctx.Username, ctx.Session.IPAddress, "SYSOP_CHAT", details)
+	return runErr
+}
+
+// checkSysOpBreakIn runs a sysop chat if the console opened one while the
+// caller sat at a prompt. Returns true if a chat took place.
+func (e *MenuExecutor) checkSysOpBreakIn(ctx *ExecutionContext) (bool, error) {
+	if !e.takeSysOpChatRequest() {
+		return false, nil
+	}
+	cfg, err := config.LoadConfigFromDB(e.db)
+	if err != nil {
+		cfg = nil
+	}
+	return true, e.runSysOpChat(ctx, cfg)
+}
@@ -0,0 +1,44 @@
+package menu
+
+import (
+	"testing"
+
+	"github.com/robbiew/retrograde/internal/database"
+)
+
+// areasDB serves a fixed list of message areas
+type areasDB struct {
+	database.Database
+	areas []database.MessageArea
+}
+
+func (db *areasDB) GetAllMessageAreas() ([]database.MessageArea, error) {
+	return db.areas, nil
+}
+
+func TestFeedbackAreaIsLocal(t *testing.T) {
+	db := &areasDB{areas: []database.MessageArea{
+		{File: "dovenet", AreaType: "qwknet"},
+		{File: "fidonet", AreaType: "echomail"},
+		{File: "general", AreaType: "local"},
+		{File: "sysop", AreaType: "local"},
+	}}
+
+	if area, err := feedbackArea(db, ""); err != nil || area.File != "general" {
+		t.Errorf("unconfigured feedback area = %+v, %v; want general", area, err)
+	}
+	if area, err := feedbackArea(db, "SYSOP"); err != nil || area.File != "sysop" {
+		t.Errorf("configured feedback area = %+v, %v; want sysop", area, err)
+	}
+	if _, err := feedbackArea(db, "dovenet"); err == nil {
+		t.Errorf("a QWKnet area was accepted as the feedback area")
+	}
+	if _, err := feedbackArea(db, "missing"); err == nil {
+		t.Errorf("a missing feedback area fell back to another area")
+	}
+
+	db.areas = db.areas[:2]
+	if _, err := feedbackArea(db, ""); err == nil {
+		t.Errorf("feedback went to a network area when no local area exists")
+	}
+}
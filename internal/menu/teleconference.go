@@ -0,0 +1,372 @@
+package menu
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/bus"
+	"github.com/robbiew/retrograde/internal/chat"
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// teleconferencePollInterval controls how often the chat screen checks the
+// bus for new lines while waiting for keystrokes
+const teleconferencePollInterval = 200 * time.Millisecond
+
+// teleconferenceScreen draws the chat screen. Incoming lines scroll inside an
+// ANSI scroll region above a status bar, and the caller's input line on the
+// bottom row is redrawn after every incoming line so typing is never lost.
+type teleconferenceScreen struct {
+	ctx     *ExecutionContext
+	width   int
+	height  int
+	channel string
+	input   []rune
+}
+
+// handleGroupChat handles the NG command (multi-node teleconference).
+// Options: [ channel ]
+func handleGroupChat(ctx *ExecutionContext, options string) error {
+	if ctx.Session == nil || ctx.IO == nil {
+		return fmt.Errorf("teleconference requires an active session")
+	}
+
+	tc := chat.Default()
+	node := ctx.Session.NodeNumber
+	user := ctx.Username
+	if user == "" {
+		user = ctx.Session.Alias
+	}
+	isSysOp := ctx.Session.SecurityLevel >= config.SecurityLevelSysOp
+
+	sub, history, err := tc.Join(node, user, options)
+	if err != nil {
+		return showNodeNotice(ctx, ui.Ansi.RedHi+" "+err.Error()+".")
+	}
+	defer func() {
+		sub.Close()
+		tc.Part(node)
+	}()
+
+	direct := bus.Default().Subscribe(bus.NodeTopic(node), 16)
+	defer direct.Close()
+
+	updateNodeActivity(ctx, "In group chat.")
+
+	member, _ := tc.Member(node)
+	screen := newTeleconferenceScreen(ctx, member.Channel)
+	screen.open()
+	defer screen.close()
+
+	for _, ev := range history {
+		screen.printEvent(ev, node)
+	}
+	screen.printLine(ui.Ansi.BlackHi, "Type /? for help, /q to leave.")
+
+	for {
+		// Drain everything waiting on the bus before blocking on input
+	drain:
+		for {
+			select {
+			case ev, ok := <-sub.C:
+				if ok {
+					screen.printEvent(ev, node)
+				}
+			case ev, ok := <-direct.C:
+				if !ok {
+					continue
+				}
+				if ev.Kind == chat.KindKick {
+					screen.printLine(ui.Ansi.RedHi, ev.Text)
+					time.Sleep(2 * time.Second)
+					return nil
+				}
+				screen.printEvent(ev, node)
+			default:
+				break drain
+			}
+		}
+		for _, line := range pendingNodeLines(ctx) {
+			screen.printLine("", strings.TrimSpace(line))
+		}
+
+		seq, err := ctx.IO.ReadKeySequence(teleconferencePollInterval)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		switch key := normalizeInputKey(seq); {
+		case key == "ESC":
+			return nil
+		case key == "ENTER":
+			line := strings.TrimSpace(string(screen.input))
+			screen.input = screen.input[:0]
+			screen.drawInput()
+			if line == "" {
+				continue
+			}
+			if !strings.HasPrefix(line, "/") {
+				if err := tc.Say(node, line); err != nil {
+					screen.printLine(ui.Ansi.RedHi, chatErrorText(err))
+				}
+				continue
+			}
+			leave, newSub := runTeleconferenceCommand(ctx, tc, screen, sub, line, isSysOp)
+			sub = newSub
+			if leave {
+				return nil
+			}
+		case seq == "\b" || seq == "\x7f":
+			if len(screen.input) > 0 {
+				screen.input = screen.input[:len(screen.input)-1]
+				screen.drawInput()
+			}
+		case len(seq) == 1 && seq[0] >= 32 && seq[0] < 127:
+			if len(screen.input) < screen.width-4 {
+				screen.input = append(screen.input, rune(seq[0]))
+				ctx.IO.Print(seq)
+			}
+		}
+	}
+}
+
+// runTeleconferenceCommand executes a slash command. Returns true when the
+// caller should leave, along with the (possibly new) channel subscription.
+func runTeleconferenceCommand(ctx *ExecutionContext, tc *chat.Teleconference, screen *teleconferenceScreen, sub *bus.Subscription, line string, isSysOp bool) (bool, *bus.Subscription) {
+	node := ctx.Session.NodeNumber
+	cmd, arg, _ := strings.Cut(line[1:], " ")
+	arg = strings.TrimSpace(arg)
+
+	requireSysOp := func() bool {
+		if !isSysOp {
+			screen.printLine(ui.Ansi.RedHi, "That command is for sysops only.")
+		}
+		return isSysOp
+	}
+
+	switch strings.ToLower(cmd) {
+	case "q", "quit", "x", "exit":
+		return true, sub
+
+	case "?", "h", "help":
+		help := []string{
+			"/me <action>         Perform an action",
+			"/who                 List users in this channel",
+			"/channels            List active channels",
+			"/join <channel>      Switch channels",
+			"/msg <user> <text>   Whisper to a user (or node #)",
+			"/q                   Leave the teleconference",
+		}
+		if isSysOp {
+			help = append(help,
+				"/kick <user>         Remove a user",
+				"/mute <user>         Silence a user (/unmute to undo)",
+				"/broadcast <text>    Announce to every node")
+		}
+		for _, h := range help {
+			screen.printLine(ui.Ansi.Cyan, h)
+		}
+
+	case "me":
+		if arg == "" {
+			screen.printLine(ui.Ansi.Yellow, "Usage: /me <action>")
+			break
+		}
+		if err := tc.Action(node, arg); err != nil {
+			screen.printLine(ui.Ansi.RedHi, chatErrorText(err))
+		}
+
+	case "who":
+		members := tc.Who(screen.channel)
+		screen.printLine(ui.Ansi.CyanHi, fmt.Sprintf("Users in #%s:", screen.channel))
+		for _, m := range members {
+			note := ""
+			if m.Muted {
+				note = " (muted)"
+			}
+			screen.printLine(ui.Ansi.White, fmt.Sprintf("  Node %-3d %s%s", m.Node, m.User, note))
+		}
+
+	case "channels", "list":
+		counts := tc.Channels()
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		screen.printLine(ui.Ansi.CyanHi, "Active channels:")
+		for _, name := range names {
+			screen.printLine(ui.Ansi.White, fmt.Sprintf("  #%-20s %d user(s)", name, counts[name]))
+		}
+
+	case "join", "j":
+		sub.Close()
+		newSub, history, err := tc.Join(node, ctx.Username, arg)
+		if err != nil {
+			screen.printLine(ui.Ansi.RedHi, chatErrorText(err))
+			// Stay in the current channel
+			newSub, _, _ = tc.Join(node, ctx.Username, screen.channel)
+			return false, newSub
+		}
+		member, _ := tc.Member(node)
+		screen.channel = member.Channel
+		screen.drawStatus()
+		for _, ev := range history {
+			screen.printEvent(ev, node)
+		}
+		return false, newSub
+
+	case "msg", "w", "whisper":
+		target, text, _ := strings.Cut(arg, " ")
+		text = strings.TrimSpace(text)
+		if target == "" || text == "" {
+			screen.printLine(ui.Ansi.Yellow, "Usage: /msg <user|node> <text>")
+			break
+		}
+		to, err := tc.Whisper(node, target, text)
+		if err != nil {
+			screen.printLine(ui.Ansi.RedHi, chatErrorText(err))
+			break
+		}
+		screen.printLine(ui.Ansi.Magenta, fmt.Sprintf("-> %s: %s", to.User, text))
+
+	case "kick":
+		if !requireSysOp() {
+			break
+		}
+		if _, err := tc.Kick(ctx.Username, arg); err != nil {
+			screen.printLine(ui.Ansi.RedHi, chatErrorText(err))
+		}
+
+	case "mute", "unmute":
+		if !requireSysOp() {
+			break
+		}
+		if _, err := tc.SetMuted(ctx.Username, arg, strings.EqualFold(cmd, "mute")); err != nil {
+			screen.printLine(ui.Ansi.RedHi, chatErrorText(err))
+		}
+
+	case "broadcast":
+		if !requireSysOp() {
+			break
+		}
+		if arg == "" {
+			screen.printLine(ui.Ansi.Yellow, "Usage: /broadcast <text>")
+			break
+		}
+		bus.Default().Publish(bus.TopicBroadcast, bus.Event{FromNode: node, FromUser: ctx.Username, Text: arg})
+
+	default:
+		screen.printLine(ui.Ansi.Yellow, fmt.Sprintf("Unknown command /%s. Type /? for help.", cmd))
+	}
+
+	return false, sub
+}
+
+func newTeleconferenceScreen(ctx *ExecutionContext, channel string) *teleconferenceScreen {
+	width, height := 80, 24
+	if ctx.Session.Width > 0 {
+		width = ctx.Session.Width
+	}
+	if ctx.Session.Height > 0 {
+		height = ctx.Session.Height
+	}
+	return &teleconferenceScreen{ctx: ctx, width: width, height: height, channel: channel}
+}
+
+// open clears the screen and sets the scroll region above the status bar
+func (s *teleconferenceScreen) open() {
+	s.ctx.IO.Print(ui.Ansi.Reset + ui.Ansi.EraseScreen + fmt.Sprintf(ui.Esc+"1;%dr", s.height-2))
+	s.drawStatus()
+	s.drawInput()
+}
+
+// close restores the full-screen scroll region
+func (s *teleconferenceScreen) close() {
+	s.ctx.IO.Print(ui.Esc + "r" + ui.Ansi.Reset + ui.Ansi.EraseScreen + ui.MoveCursorSequence(1, 1))
+}
+
+func (s *teleconferenceScreen) drawStatus() {
+	title := fmt.Sprintf(" Teleconference  #%s ", s.channel)
+	bar := title + strings.Repeat(" ", max(0, s.width-len(title)))
+	s.ctx.IO.Print(ui.MoveCursorSequence(1, s.height-1) + ui.Ansi.BgBlue + ui.Ansi.WhiteHi + truncateText(bar, s.width) + ui.Ansi.Reset)
+	s.drawInput()
+}
+
+func (s *teleconferenceScreen) drawInput() {
+	s.ctx.IO.Print(ui.MoveCursorSequence(1, s.height) + ui.Ansi.EraseLine +
+		ui.Ansi.CyanHi + "> " + ui.Ansi.WhiteHi + string(s.input))
+}
+
+// printLine scrolls text into the chat region, wrapping long lines, then
+// puts the cursor back at the end of the caller's input
+func (s *teleconferenceScreen) printLine(color, text string) {
+	var out strings.Builder
+	for _, chunk := range wrapChatText(text, s.width-1) {
+		out.WriteString(ui.MoveCursorSequence(1, s.height-2) + "\r\n" + color + chunk + ui.Ansi.Reset)
+	}
+	s.ctx.IO.Print(out.String())
+	s.drawInput()
+}
+
+func (s *teleconferenceScreen) printEvent(ev bus.Event, ownNode int) {
+	text := ui.StripANSI(ui.StripPipeCodes(ev.Text))
+	stamp := ev.Time.Format("15:04") + " "
+
+	switch ev.Kind {
+	case chat.KindSay:
+		color := ui.Ansi.White
+		if ev.FromNode == ownNode {
+			color = ui.Ansi.GreenHi
+		}
+		s.printLine(color, fmt.Sprintf("%s<%s> %s", stamp, ev.FromUser, text))
+	case chat.KindAction:
+		s.printLine(ui.Ansi.YellowHi, fmt.Sprintf("%s* %s %s", stamp, ev.FromUser, text))
+	case chat.KindWhisper:
+		s.printLine(ui.Ansi.MagentaHi, fmt.Sprintf("%s*%s whispers* %s", stamp, ev.FromUser, text))
+	case chat.KindJoin, chat.KindPart, chat.KindSystem:
+		s.printLine(ui.Ansi.BlackHi, stamp+"-- "+text)
+	default:
+		s.printLine(ui.Ansi.Cyan, stamp+text)
+	}
+}
+
+func wrapChatText(text string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+	runes := []rune(text)
+	var lines []string
+	for len(runes) > width {
+		cut := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, string(runes[:cut]))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	return append(lines, string(runes))
+}
+
+func chatErrorText(err error) string {
+	switch {
+	case errors.Is(err, chat.ErrMuted):
+		return "You have been muted and cannot speak."
+	case errors.Is(err, chat.ErrUnknownTarget):
+		return "No such user in the teleconference."
+	case errors.Is(err, chat.ErrBadChannel):
+		return "Channel names are letters, digits, - and _ (20 max)."
+	}
+	return err.Error()
+}
@@ -0,0 +1,101 @@
+package menu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/robbiew/retrograde/internal/events"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// handleEditEvents handles *E: list the timed events and let the sysop turn
+// them on or off or run one now. Full editing lives in the config editor.
+func handleEditEvents(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.IO == nil || ctx.Executor == nil || ctx.Executor.db == nil {
+		return fmt.Errorf("event editor requires an execution context with IO and a database")
+	}
+	io := ctx.IO
+	db := ctx.Executor.db
+	scheduler := events.Running()
+
+	for {
+		list, err := db.GetAllTimedEvents()
+		if err != nil {
+			return fmt.Errorf("failed to load timed events: %w", err)
+		}
+
+		io.ClearScreen()
+		io.Print(ui.Ansi.WhiteHi + "\r\n Timed Events\r\n\r\n" + ui.Ansi.Reset)
+		if len(list) == 0 {
+			io.Print(ui.Ansi.Yellow + " No timed events are defined. Add them in the config editor.\r\n" + ui.Ansi.Reset)
+			ui.Pause(io)
+			return nil
+		}
+		io.Print(ui.Ansi.Cyan + fmt.Sprintf(" %-3s %-24s %-20s %-16s %s\r\n", "#", "Name", "Schedule", "Next run", "On") + ui.Ansi.Reset)
+		for i, event := range list {
+			next := "-"
+			if scheduler != nil && event.Enabled {
+				if at, err := scheduler.NextRun(event); err != nil {
+					next = "bad schedule"
+				} else if !at.IsZero() {
+					next = at.Format("Mon 01/02 15:04")
+				}
+			}
+			on := "No"
+			if event.Enabled {
+				on = "Yes"
+			}
+			io.Printf(" %-3d %-24.24s %-20.20s %-16s %s\r\n", i+1, event.Name, event.Schedule, next, on)
+		}
+		if scheduler == nil {
+			io.Print(ui.Ansi.Yellow + "\r\n The scheduler is not running; events will not run on their own.\r\n" + ui.Ansi.Reset)
+		}
+
+		io.Print("\r\n" + ui.Ansi.WhiteHi + " (T)oggle, (R)un now, (Q)uit: " + ui.Ansi.Reset)
+		key, err := io.GetKeyPressUpper()
+		if err != nil {
+			return err
+		}
+		io.Printf("%c\r\n", key)
+		if key != 'T' && key != 'R' {
+			return nil
+		}
+
+		answer, err := ui.PromptSimple(io, " Event #: ", 3, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				continue
+			}
+			return err
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(answer))
+		if err != nil || n < 1 || n > len(list) {
+			continue
+		}
+		event := list[n-1]
+
+		switch key {
+		case 'T':
+			event.Enabled = !event.Enabled
+			if err := db.UpdateTimedEvent(&event); err != nil {
+				return fmt.Errorf("failed to update timed event: %w", err)
+			}
+		case 'R':
+			if scheduler == nil {
+				io.Print(ui.Ansi.RedHi + "\r\n The scheduler is not running.\r\n" + ui.Ansi.Reset)
+				ui.Pause(io)
+				continue
+			}
+			io.Print(ui.Ansi.Yellow + fmt.Sprintf("\r\n Running %s...\r\n", event.Name) + ui.Ansi.Reset)
+			logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "EVENT", "run now: "+event.Name)
+			result, err := scheduler.RunNow(event.ID)
+			if err != nil {
+				result = err.Error()
+			}
+			io.Print(ui.Ansi.GreenHi + " " + result + "\r\n" + ui.Ansi.Reset)
+			ui.Pause(io)
+		}
+	}
+}
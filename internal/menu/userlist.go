@@ -0,0 +1,310 @@
+package menu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// userListEntry is one row of the caller-facing user list
+type userListEntry struct {
+	ID        int64
+	Name      string
+	Location  string
+	LastOn    string
+	LevelName string
+}
+
+// userListView holds what decides how other users are shown to the caller
+type userListView struct {
+	ViewerID  int64
+	SysOp     bool   // Sysops also see users at invisible security levels
+	RealNames bool   // Show real names instead of aliases
+	ACS       string // Only users whose security level passes this are listed
+}
+
+// newUserListView builds the view for the caller. Real names are shown while
+// the caller is in a message area that uses them.
+func newUserListView(ctx *ExecutionContext) userListView {
+	view := userListView{ViewerID: ctx.UserID}
+	if ctx.Session != nil {
+		view.SysOp = ctx.Session.SecurityLevel >= config.SecurityLevelSysOp
+		view.RealNames = ctx.Session.CurrentMessageArea != nil && ctx.Session.CurrentMessageArea.RealNames
+	}
+	return view
+}
+
+// levelFor returns the highest security level at or below level
+func levelFor(levels []database.SecurityLevelRecord, level int) *database.SecurityLevelRecord {
+	var best *database.SecurityLevelRecord
+	for i := range levels {
+		if levels[i].SecLevel <= level && (best == nil || levels[i].SecLevel > best.SecLevel) {
+			best = &levels[i]
+		}
+	}
+	return best
+}
+
+// visible reports whether the viewer may see user. Users at an invisible
+// security level only show to themselves and to sysops.
+func (v userListView) visible(user database.UserRecord, levels []database.SecurityLevelRecord) bool {
+	if v.ACS != "" {
+		userCtx := &ExecutionContext{Session: &config.TelnetSession{SecurityLevel: user.SecurityLevel}}
+		if !(&MenuExecutor{}).checkACS(v.ACS, userCtx) {
+			return false
+		}
+	}
+	if v.SysOp || user.ID == v.ViewerID {
+		return true
+	}
+	level := levelFor(levels, user.SecurityLevel)
+	return level == nil || !level.Invisible
+}
+
+// displayName is the name a user is listed under
+func (v userListView) displayName(user database.UserRecord) string {
+	if v.RealNames {
+		if name := strings.TrimSpace(user.FirstName.String + " " + user.LastName.String); name != "" {
+			return name
+		}
+	}
+	return user.Username
+}
+
+// buildUserList returns the users the viewer may see whose name or location
+// contains search, in name order
+func buildUserList(users []database.UserRecord, levels []database.SecurityLevelRecord, view userListView, search string) []userListEntry {
+	search = strings.ToLower(strings.TrimSpace(search))
+	var entries []userListEntry
+	for _, user := range users {
+		if !view.visible(user, levels) {
+			continue
+		}
+		entry := userListEntry{
+			ID:       user.ID,
+			Name:     view.displayName(user),
+			Location: user.Locations.String,
+			LastOn:   userDate(user.LastLogin.String),
+		}
+		if level := levelFor(levels, user.SecurityLevel); level != nil {
+			entry.LevelName = level.Name
+		}
+		if search != "" && !strings.Contains(strings.ToLower(entry.Name), search) &&
+			!strings.Contains(strings.ToLower(entry.Location), search) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+	return entries
+}
+
+// userDate formats a stored user time as a date, or "Never"
+func userDate(value string) string {
+	if value == "" {
+		return "Never"
+	}
+	for _, layout := range []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05"} {
+		if at, err := time.Parse(layout, value); err == nil {
+			return at.Local().Format("01/02/06")
+		}
+	}
+	return value
+}
+
+// handleUserListing handles OU: a paged list of users the caller can search
+// and pick profiles from.
+// Options: <ACS;filename> - list only users whose security level passes the
+// ACS, and show the display file above the list instead of the heading
+func handleUserListing(ctx *ExecutionContext, options string) error {
+	if ctx == nil || ctx.IO == nil || ctx.Executor == nil || ctx.Executor.db == nil {
+		return fmt.Errorf("the user listing requires an execution context with IO and a database")
+	}
+	db := ctx.Executor.db
+	users, err := db.GetAllUsers()
+	if err != nil {
+		return err
+	}
+	levels, err := db.GetAllSecurityLevels()
+	if err != nil {
+		return err
+	}
+
+	io := ctx.IO
+	view := newUserListView(ctx)
+	acs, header, _ := strings.Cut(options, ";")
+	view.ACS = strings.TrimSpace(acs)
+	if header = strings.TrimSpace(header); header != "" {
+		header = findDisplayFile(ctx, header)
+	}
+	search := ""
+	entries := buildUserList(users, levels, view, search)
+	pageSize := 18
+	if ctx.Session != nil && ctx.Session.Height > 8 {
+		pageSize = ctx.Session.Height - 7
+	}
+	page := 0
+
+	for {
+		pages := max((len(entries)+pageSize-1)/pageSize, 1)
+		page = min(max(page, 0), pages-1)
+		printUserListPage(ctx, header, entries, page, pages, pageSize, search, view)
+
+		io.Print("\r\n")
+		answer, err := ui.PromptSimple(io, " (N)ext (P)rev (S)earch (V)iew profile (Q)uit: ", 1, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil
+			}
+			return err
+		}
+		switch strings.ToUpper(strings.TrimSpace(answer)) {
+		case "Q":
+			io.Print("\r\n")
+			return nil
+		case "":
+			if page == pages-1 {
+				io.Print("\r\n")
+				return nil
+			}
+			page++
+		case "N":
+			page++
+		case "P":
+			page--
+		case "S":
+			io.Print("\r\n")
+			search, err = ui.PromptSimple(io, " Search for (blank for everyone): ", 30, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+			if err != nil && err.Error() != "ESC_PRESSED" {
+				return err
+			}
+			search = strings.TrimSpace(search)
+			entries = buildUserList(users, levels, view, search)
+			page = 0
+		case "V":
+			io.Print("\r\n")
+			name, err := ui.PromptSimple(io, " View whose profile? ", 30, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+			if err != nil {
+				if err.Error() == "ESC_PRESSED" {
+					continue
+				}
+				return err
+			}
+			if entry, ok := findUserListEntry(entries, buildUserList(users, levels, view, ""), name); ok {
+				if err := showUserProfile(ctx, entry, users, view); err != nil {
+					return err
+				}
+			} else if strings.TrimSpace(name) != "" {
+				io.Print(ui.Ansi.RedHi + "\r\n\r\n No such user.\r\n" + ui.Ansi.Reset)
+				ui.Pause(io)
+			}
+		}
+	}
+}
+
+// findUserListEntry finds a user by exact name, first in the current list
+// and then among everyone the caller may see
+func findUserListEntry(current, everyone []userListEntry, name string) (userListEntry, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return userListEntry{}, false
+	}
+	for _, list := range [][]userListEntry{current, everyone} {
+		for _, entry := range list {
+			if strings.EqualFold(entry.Name, name) {
+				return entry, true
+			}
+		}
+	}
+	return userListEntry{}, false
+}
+
+func printUserListPage(ctx *ExecutionContext, header string, entries []userListEntry, page, pages, pageSize int, search string, view userListView) {
+	io := ctx.IO
+	io.ClearScreen()
+	title := " User Listing"
+	if header != "" {
+		if _, err := displayFile(ctx, header, true, displayFileFlags{}); err == nil {
+			title = ""
+		}
+	}
+	if search != "" {
+		title += fmt.Sprintf(" matching \"%s\"", search)
+	}
+	io.Printf(ui.Ansi.WhiteHi+"\r\n%s"+ui.Ansi.Cyan+" (page %d of %d)\r\n\r\n"+ui.Ansi.Reset, title, page+1, pages)
+
+	nameHeading := "User"
+	if view.RealNames {
+		nameHeading = "Name"
+	}
+	io.Print(ui.Ansi.Cyan + fmt.Sprintf(" %-24s %-26s %-8s %s\r\n", nameHeading, "Location", "Last On", "Level") + ui.Ansi.Reset)
+	io.Print(ui.Ansi.Cyan + " " + strings.Repeat("\xc4", 73) + "\r\n" + ui.Ansi.Reset)
+	if len(entries) == 0 {
+		io.Print(ui.Ansi.Yellow + " No users found.\r\n" + ui.Ansi.Reset)
+		return
+	}
+	end := min((page+1)*pageSize, len(entries))
+	for _, entry := range entries[page*pageSize : end] {
+		io.Printf(ui.Ansi.WhiteHi+" %-24.24s "+ui.Ansi.Cyan+"%-26.26s "+ui.Ansi.White+"%-8s "+ui.Ansi.YellowHi+"%.12s\r\n"+ui.Ansi.Reset,
+			entry.Name, entry.Location, entry.LastOn, entry.LevelName)
+	}
+}
+
+// showUserProfile shows a user's profile: what the user list shows, when
+// they joined, their call counts and the tagline and signature they set
+func showUserProfile(ctx *ExecutionContext, entry userListEntry, users []database.UserRecord, view userListView) error {
+	db := ctx.Executor.db
+	var user database.UserRecord
+	for _, u := range users {
+		if u.ID == entry.ID {
+			user = u
+			break
+		}
+	}
+	details, err := db.GetUserDetails(entry.ID)
+	if err != nil {
+		return err
+	}
+	stats, err := db.GetUserStats(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	io := ctx.IO
+	io.ClearScreen()
+	io.Print(ui.Ansi.WhiteHi + "\r\n Profile of " + entry.Name + "\r\n" + ui.Ansi.Cyan + " " + strings.Repeat("\xc4", 73) + "\r\n" + ui.Ansi.Reset)
+	field := func(label, value string) {
+		if value != "" {
+			io.Printf(ui.Ansi.Cyan+" %-14s"+ui.Ansi.WhiteHi+"%s\r\n"+ui.Ansi.Reset, label+":", value)
+		}
+	}
+	if view.RealNames && entry.Name != user.Username {
+		field("Alias", user.Username)
+	}
+	field("Location", entry.Location)
+	field("Level", entry.LevelName)
+	field("Member since", userDate(user.CreatedDate))
+	field("Last on", entry.LastOn)
+	field("Calls", fmt.Sprintf("%d", stats.Calls))
+	field("Posts", fmt.Sprintf("%d", stats.Posts))
+	field("Time online", fmt.Sprintf("%d min", stats.Minutes))
+
+	if tagline := strings.TrimSpace(details[config.TaglineDetail]); tagline != "" {
+		io.Print("\r\n" + ui.Ansi.YellowHi + " \"" + tagline + "\"\r\n" + ui.Ansi.Reset)
+	}
+	if signature := strings.TrimRight(details[config.SignatureDetail], "\n"); strings.TrimSpace(signature) != "" {
+		io.Print("\r\n")
+		for _, line := range strings.Split(signature, "\n") {
+			io.Print(ui.Ansi.White + " " + line + "\r\n" + ui.Ansi.Reset)
+		}
+	}
+	ui.Pause(io)
+	return nil
+}
@@ -0,0 +1,39 @@
+package menu
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/robbiew/retrograde/internal/database"
+)
+
+func TestBuildUserListHonoursInvisibilityAndRealNames(t *testing.T) {
+	levels := []database.SecurityLevelRecord{
+		{Name: "User", SecLevel: 10},
+		{Name: "Co-SysOp", SecLevel: 90, Invisible: true},
+	}
+	users := []database.UserRecord{
+		{ID: 1, Username: "zed", SecurityLevel: 20, Locations: sql.NullString{String: "Dallas, TX", Valid: true}},
+		{ID: 2, Username: "ghost", SecurityLevel: 95},
+		{ID: 3, Username: "amy", SecurityLevel: 10, FirstName: sql.NullString{String: "Amy", Valid: true}, LastName: sql.NullString{String: "Pond", Valid: true}},
+	}
+
+	entries := buildUserList(users, levels, userListView{ViewerID: 1}, "")
+	if len(entries) != 2 || entries[0].Name != "amy" || entries[1].Name != "zed" || entries[1].LevelName != "User" || entries[1].LastOn != "Never" {
+		t.Fatalf("expected amy and zed without the invisible user, got %+v", entries)
+	}
+	if entries := buildUserList(users, levels, userListView{ViewerID: 2}, ""); len(entries) != 3 {
+		t.Fatalf("expected invisible users to see themselves, got %+v", entries)
+	}
+	if entries := buildUserList(users, levels, userListView{SysOp: true}, "dallas"); len(entries) != 1 || entries[0].ID != 1 {
+		t.Fatalf("expected a search by location, got %+v", entries)
+	}
+	if entries := buildUserList(users, levels, userListView{SysOp: true, ACS: ">=20"}, ""); len(entries) != 2 || entries[0].Name != "ghost" {
+		t.Fatalf("expected the ACS to leave out amy, got %+v", entries)
+	}
+
+	entries = buildUserList(users, levels, userListView{RealNames: true}, "pond")
+	if len(entries) != 1 || entries[0].Name != "Amy Pond" {
+		t.Fatalf("expected real names to be listed and searched, got %+v", entries)
+	}
+}
@@ -0,0 +1,135 @@
+package menu
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// maxValidationAttempts is how many wrong OA passwords a caller may enter
+// before being hung up on, as at login
+const maxValidationAttempts = 3
+
+// SendValidationMail tells a user by private mail how their validation was
+// decided. level is the security level they now have.
+func SendValidationMail(db database.Database, cfg *config.Config, username, status string, level int) error {
+	from := strings.TrimSpace(cfg.Configuration.General.SysOpName)
+	if from == "" {
+		from = "SysOp"
+	}
+
+	levelName := fmt.Sprintf("%d", level)
+	if levels, err := db.GetAllSecurityLevels(); err == nil {
+		if record := levelFor(levels, level); record != nil {
+			levelName = fmt.Sprintf("%d (%s)", level, record.Name)
+		}
+	}
+
+	var subject, text string
+	switch status {
+	case database.ValidationApproved:
+		subject = "Your account has been validated"
+		text = fmt.Sprintf("Hello %s,\n\nYour account has been validated. Your security level is now %s.\n\nEnjoy your stay!", username, levelName)
+	case database.ValidationRejected:
+		subject = "Your account was not validated"
+		text = fmt.Sprintf("Hello %s,\n\nYour account was not validated, and stays at security level %s.\nPage the sysop if you think this is a mistake.", username, levelName)
+	default:
+		return fmt.Errorf("invalid validation status %q", status)
+	}
+	return SendPrivateMail(db, cfg, from, username, subject, text)
+}
+
+// handleAutoValidate handles OA: callers who know the password validate
+// their own account.
+// Options: [password]<;Level> - the password to ask for, and the security
+// level to raise the caller to (default: the Validated Level of New Users).
+// Callers who enter a wrong password maxValidationAttempts times are hung up on.
+func handleAutoValidate(ctx *ExecutionContext, options string) (CmdResult, error) {
+	if ctx == nil || ctx.Session == nil || ctx.IO == nil || ctx.Executor == nil || ctx.Executor.db == nil {
+		return ResultContinue, fmt.Errorf("auto-validation requires an active session and a database")
+	}
+	passwordOpt, levelOpt, _ := strings.Cut(options, ";")
+	passwordOpt = strings.TrimSpace(passwordOpt)
+	if passwordOpt == "" {
+		return ResultContinue, fmt.Errorf("OA requires a password option")
+	}
+
+	db := ctx.Executor.db
+	cfg, err := config.LoadConfigFromDB(db)
+	if err != nil {
+		return ResultContinue, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	target := cfg.Configuration.NewUsers.ValidatedLevel
+	if level, err := strconv.Atoi(strings.TrimSpace(levelOpt)); err == nil && level > 0 {
+		target = level
+	}
+	if target <= 0 {
+		target = config.SecurityLevelRegular
+	}
+
+	io := ctx.IO
+	session := ctx.Session
+	if session.SecurityLevel >= target {
+		io.Print(ui.Ansi.Yellow + "\r\n Your account is already validated.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return ResultContinue, nil
+	}
+
+	// A sysop's decision stands: only pending or unqueued accounts may
+	// validate themselves
+	record, err := db.GetValidation(ctx.UserID)
+	if err != nil {
+		return ResultContinue, err
+	}
+	if record != nil && record.Status != database.ValidationPending {
+		logging.LogEvent(session.NodeNumber, ctx.Username, session.IPAddress, "AUTO_VALIDATE_REFUSED",
+			fmt.Sprintf("validation already %s by %s", record.Status, record.DecidedBy))
+		io.Print(ui.Ansi.Yellow + "\r\n The sysop has already decided on your account. Page the sysop if you\r\n think this is a mistake.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return ResultContinue, nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		io.Print("\r\n")
+		password, err := ui.PromptPasswordSimple(io, " Validation password: ", 20, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue)
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				io.Print("\r\n")
+				return ResultContinue, nil
+			}
+			return ResultContinue, err
+		}
+		if subtle.ConstantTimeCompare([]byte(password), []byte(passwordOpt)) == 1 {
+			break
+		}
+
+		logging.LogEvent(session.NodeNumber, ctx.Username, session.IPAddress, "AUTO_VALIDATE_FAILED",
+			fmt.Sprintf("wrong password (attempt %d of %d)", attempt, maxValidationAttempts))
+		io.Print(ui.Ansi.RedHi + "\r\n\r\n Wrong password.\r\n" + ui.Ansi.Reset)
+		if attempt >= maxValidationAttempts {
+			logging.LogEvent(session.NodeNumber, ctx.Username, session.IPAddress, "AUTO_VALIDATE_FAILED",
+				fmt.Sprintf("disconnected after %d failed attempts", maxValidationAttempts))
+			io.Print(ui.Ansi.RedHi + " Too many failed attempts. Goodbye.\r\n" + ui.Ansi.Reset)
+			return logoffCommand(ctx, LogoffImmediate, "")
+		}
+	}
+
+	if err := db.ResolveValidation(ctx.UserID, database.ValidationApproved, "OA", target); err != nil {
+		return ResultContinue, err
+	}
+	session.SecurityLevel = target
+	logging.LogApplicationApproval(session.NodeNumber, ctx.Username, session.IPAddress, "new user", "OA")
+	if err := SendValidationMail(db, cfg, ctx.Username, database.ValidationApproved, target); err != nil {
+		logging.LogEvent(session.NodeNumber, ctx.Username, session.IPAddress, "MAIL_FAILED", err.Error())
+	}
+
+	io.Printf(ui.Ansi.GreenHi+"\r\n\r\n Your account has been validated at security level %d.\r\n"+ui.Ansi.Reset, target)
+	ui.Pause(io)
+	return ResultContinue, nil
+}
@@ -0,0 +1,55 @@
+package menu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+)
+
+func (db *fakeMenuDB) GetValidation(userID int64) (*database.ValidationRecord, error) {
+	return db.validation, nil
+}
+
+func TestAutoValidateHangsUpAfterThreeFailures(t *testing.T) {
+	logging.SetLogDirectory(t.TempDir())
+	r := newMenuRun(t)
+	r.db.addMenu("MAIN",
+		"A OA Secret;20",
+		"X HI",
+	)
+	r.ctx.Session.SecurityLevel = 10
+
+	// Case differs on the first try: the password must match exactly
+	r.run(t, "Asecret\rwrong\rSecret1\r")
+	if r.ctx.Session.Connected {
+		t.Errorf("three wrong passwords left the session connected")
+	}
+	if r.ctx.Session.SecurityLevel != 10 {
+		t.Errorf("security level %d after failed validation, want 10", r.ctx.Session.SecurityLevel)
+	}
+	if got := strings.Count(r.term.Output(), "Wrong password."); got != maxValidationAttempts {
+		t.Errorf("%d wrong-password messages, want %d", got, maxValidationAttempts)
+	}
+}
+
+func TestAutoValidateRefusesRejectedCaller(t *testing.T) {
+	logging.SetLogDirectory(t.TempDir())
+	r := newMenuRun(t)
+	r.db.addMenu("MAIN",
+		"A OA Secret;20",
+		"X HI",
+	)
+	r.db.validation = &database.ValidationRecord{UserID: 7, Status: database.ValidationRejected, DecidedBy: "SysOp"}
+	r.ctx.Session.SecurityLevel = 10
+
+	// The password is never asked for; a nil ResolveValidation would panic
+	r.run(t, "A\rX")
+	if r.ctx.Session.SecurityLevel != 10 {
+		t.Errorf("rejected caller validated themselves at level %d", r.ctx.Session.SecurityLevel)
+	}
+	if strings.Contains(r.term.Output(), "Validation password") {
+		t.Errorf("rejected caller was asked for the password")
+	}
+}
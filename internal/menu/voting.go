@@ -0,0 +1,554 @@
+package menu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// Width of the results bar graph, in characters
+const voteBarWidth = 30
+
+// maxVotingChoices caps how many answers a topic can have
+const maxVotingChoices = 20
+
+func votingDB(ctx *ExecutionContext) (database.Database, error) {
+	if ctx == nil || ctx.IO == nil || ctx.Executor == nil || ctx.Executor.db == nil {
+		return nil, fmt.Errorf("voting requires an execution context with IO and a database")
+	}
+	return ctx.Executor.db, nil
+}
+
+// handleListTopics handles VL: list the topics, marking the ones the caller
+// has voted on
+func handleListTopics(ctx *ExecutionContext, options string) error {
+	db, err := votingDB(ctx)
+	if err != nil {
+		return err
+	}
+	topics, err := db.GetAllVotingTopics()
+	if err != nil {
+		return fmt.Errorf("failed to load voting topics: %w", err)
+	}
+	votes, err := db.GetUserVotes(ctx.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load votes: %w", err)
+	}
+
+	io := ctx.IO
+	io.ClearScreen()
+	io.Print(ui.Ansi.WhiteHi + "\r\n Voting Booth\r\n\r\n" + ui.Ansi.Reset)
+	if len(topics) == 0 {
+		io.Print(ui.Ansi.Yellow + " There are no voting topics.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+	printTopicList(ctx, topics, votes)
+	io.Print(ui.Ansi.Cyan + "\r\n * you have voted   - closed\r\n" + ui.Ansi.Reset)
+	ui.Pause(io)
+	return nil
+}
+
+func printTopicList(ctx *ExecutionContext, topics []database.VotingTopic, votes map[int]int) {
+	io := ctx.IO
+	io.Print(ui.Ansi.Cyan + fmt.Sprintf(" %-4s %s\r\n", "#", "Topic") + ui.Ansi.Reset)
+	for i, topic := range topics {
+		mark := " "
+		if _, voted := votes[topic.ID]; voted {
+			mark = "*"
+		} else if !topic.Active {
+			mark = "-"
+		}
+		io.Printf(ui.Ansi.YellowHi+" %-3d"+ui.Ansi.WhiteHi+"%s "+ui.Ansi.Cyan+"%.70s\r\n"+ui.Ansi.Reset, i+1, mark, topic.Title)
+	}
+}
+
+// handleVoteOnTopic handles V#: vote on the topic numbered in options, or
+// ask which one
+func handleVoteOnTopic(ctx *ExecutionContext, options string) error {
+	db, err := votingDB(ctx)
+	if err != nil {
+		return err
+	}
+	topic, err := chooseTopic(ctx, db, options)
+	if err != nil || topic == nil {
+		return err
+	}
+	_, err = voteOnTopic(ctx, db, *topic, true)
+	return err
+}
+
+// handleVoteOnAll handles VV: offer every open topic the caller has not
+// voted on yet
+func handleVoteOnAll(ctx *ExecutionContext, options string) error {
+	db, err := votingDB(ctx)
+	if err != nil {
+		return err
+	}
+	topics, err := db.GetAllVotingTopics()
+	if err != nil {
+		return fmt.Errorf("failed to load voting topics: %w", err)
+	}
+	votes, err := db.GetUserVotes(ctx.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load votes: %w", err)
+	}
+
+	offered := 0
+	for _, topic := range topics {
+		if _, voted := votes[topic.ID]; voted || !topic.Active || !ctx.Executor.checkACS(topic.VoteACS, ctx) {
+			continue
+		}
+		offered++
+		stop, err := voteOnTopic(ctx, db, topic, false)
+		if err != nil || stop {
+			return err
+		}
+	}
+	if offered == 0 {
+		ctx.IO.Print(ui.Ansi.Cyan + "\r\n You have voted on every topic.\r\n" + ui.Ansi.Reset)
+		ui.Pause(ctx.IO)
+	}
+	return nil
+}
+
+// voteOnTopic shows a topic's choices and records the caller's pick. Enter
+// skips the topic; Q (or Esc) reports stop so VV can end early.
+func voteOnTopic(ctx *ExecutionContext, db database.Database, topic database.VotingTopic, showResults bool) (bool, error) {
+	io := ctx.IO
+	if !topic.Active {
+		io.Print(ui.Ansi.Yellow + "\r\n Voting on this topic is closed.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return false, nil
+	}
+	if !ctx.Executor.checkACS(topic.VoteACS, ctx) {
+		io.Print(ui.Ansi.RedHi + "\r\n You may not vote on this topic.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return false, nil
+	}
+
+	for {
+		choices, err := db.GetVotingChoices(topic.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to load voting choices: %w", err)
+		}
+		votes, err := db.GetUserVotes(ctx.UserID)
+		if err != nil {
+			return false, fmt.Errorf("failed to load votes: %w", err)
+		}
+		canAdd := topic.AddACS != "" && ctx.Executor.checkACS(topic.AddACS, ctx) && len(choices) < maxVotingChoices
+
+		io.ClearScreen()
+		io.Print(ui.Ansi.WhiteHi + "\r\n " + topic.Title + "\r\n" + ui.Ansi.Reset)
+		if topic.Description != "" {
+			io.Print(ui.Ansi.Cyan + " " + topic.Description + "\r\n" + ui.Ansi.Reset)
+		}
+		io.Print("\r\n")
+		for i, choice := range choices {
+			mark := " "
+			if votes[topic.ID] == choice.ID {
+				mark = "*"
+			}
+			io.Printf(ui.Ansi.YellowHi+" %2d"+ui.Ansi.WhiteHi+"%s "+ui.Ansi.Cyan+"%.70s\r\n"+ui.Ansi.Reset, i+1, mark, choice.Text)
+		}
+
+		label := " Your vote (#, Enter to skip, Q to quit"
+		if canAdd {
+			label += ", A to add a choice"
+		}
+		answer, err := ui.PromptSimple(io, label+"): ", 3, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return true, nil
+			}
+			return false, err
+		}
+		answer = strings.ToUpper(strings.TrimSpace(answer))
+
+		switch {
+		case answer == "":
+			return false, nil
+		case answer == "Q":
+			return true, nil
+		case answer == "A" && canAdd:
+			text, err := ui.PromptSimple(io, "\r\n Your choice: ", 60, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+			if err != nil && err.Error() != "ESC_PRESSED" {
+				return false, err
+			}
+			if text = strings.TrimSpace(text); err == nil && text != "" {
+				choice := database.VotingChoice{TopicID: topic.ID, Position: len(choices) + 1, Text: text, AddedBy: ctx.Username}
+				if _, err := db.CreateVotingChoice(&choice); err != nil {
+					return false, err
+				}
+				logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "VOTE_CHOICE", fmt.Sprintf("%s: %s", topic.Title, text))
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(answer)
+		if err != nil || n < 1 || n > len(choices) {
+			continue
+		}
+		if err := db.CastVote(topic.ID, choices[n-1].ID, ctx.UserID); err != nil {
+			return false, err
+		}
+		logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "VOTE", fmt.Sprintf("%s: %s", topic.Title, choices[n-1].Text))
+		if showResults {
+			return false, showTopicResults(ctx, db, topic)
+		}
+		return false, nil
+	}
+}
+
+// handleViewResults handles VR: show a topic's results as bar graphs
+func handleViewResults(ctx *ExecutionContext, options string) error {
+	db, err := votingDB(ctx)
+	if err != nil {
+		return err
+	}
+	topic, err := chooseTopic(ctx, db, options)
+	if err != nil || topic == nil {
+		return err
+	}
+	return showTopicResults(ctx, db, *topic)
+}
+
+func showTopicResults(ctx *ExecutionContext, db database.Database, topic database.VotingTopic) error {
+	choices, err := db.GetVotingChoices(topic.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load voting choices: %w", err)
+	}
+	total := 0
+	for _, choice := range choices {
+		total += choice.Votes
+	}
+
+	io := ctx.IO
+	io.ClearScreen()
+	io.Print(ui.Ansi.WhiteHi + "\r\n " + topic.Title + "\r\n" + ui.Ansi.Reset)
+	io.Printf(ui.Ansi.Cyan+" %d vote(s)\r\n\r\n"+ui.Ansi.Reset, total)
+	for i, choice := range choices {
+		percent := 0
+		if total > 0 {
+			percent = choice.Votes * 100 / total
+		}
+		io.Printf(ui.Ansi.YellowHi+" %2d "+ui.Ansi.WhiteHi+"%-40.40s\r\n"+ui.Ansi.Reset, i+1, choice.Text)
+		io.Print("    " + voteBar(choice.Votes, total) + ui.Ansi.WhiteHi + fmt.Sprintf(" %3d%% (%d)\r\n", percent, choice.Votes) + ui.Ansi.Reset)
+	}
+	ui.Pause(io)
+	return nil
+}
+
+// voteBar draws a share of total as a CP437 block bar
+func voteBar(votes, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = (votes*voteBarWidth + total/2) / total
+	}
+	return ui.Ansi.GreenHi + strings.Repeat("\xdb", filled) + ui.Ansi.Green + strings.Repeat("\xb0", voteBarWidth-filled)
+}
+
+// chooseTopic returns the topic numbered in options, or lists the topics and
+// asks for one. It returns nil if the caller backs out.
+func chooseTopic(ctx *ExecutionContext, db database.Database, options string) (*database.VotingTopic, error) {
+	topics, err := db.GetAllVotingTopics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voting topics: %w", err)
+	}
+	io := ctx.IO
+	if len(topics) == 0 {
+		io.Print(ui.Ansi.Yellow + "\r\n There are no voting topics.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil, nil
+	}
+
+	answer := strings.TrimSpace(options)
+	if answer == "" {
+		votes, err := db.GetUserVotes(ctx.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load votes: %w", err)
+		}
+		io.ClearScreen()
+		io.Print(ui.Ansi.WhiteHi + "\r\n Voting Booth\r\n\r\n" + ui.Ansi.Reset)
+		printTopicList(ctx, topics, votes)
+		answer, err = ui.PromptSimple(io, "\r\n Topic #: ", 3, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil, nil
+			}
+			return nil, err
+		}
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || n < 1 || n > len(topics) {
+		return nil, nil
+	}
+	return &topics[n-1], nil
+}
+
+// handleAddTopic handles VA: the caller writes a question and its answers
+func handleAddTopic(ctx *ExecutionContext, options string) error {
+	db, err := votingDB(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = addTopic(ctx, db)
+	return err
+}
+
+// addTopic prompts for a new topic and at least two choices, and saves it
+func addTopic(ctx *ExecutionContext, db database.Database) (*database.VotingTopic, error) {
+	io := ctx.IO
+	io.ClearScreen()
+	io.Print(ui.Ansi.WhiteHi + "\r\n Add a Voting Topic\r\n\r\n" + ui.Ansi.Reset)
+
+	prompt := func(label string, width int) (string, bool, error) {
+		answer, err := ui.PromptSimple(io, label, width, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		io.Print("\r\n")
+		return strings.TrimSpace(answer), true, nil
+	}
+
+	title, ok, err := prompt(" Question: ", 60)
+	if err != nil || !ok || title == "" {
+		return nil, err
+	}
+	description, ok, err := prompt(" Details (optional): ", 60)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	io.Print(ui.Ansi.Cyan + "\r\n Enter the choices, a blank line to finish.\r\n" + ui.Ansi.Reset)
+	var choices []string
+	for len(choices) < maxVotingChoices {
+		text, ok, err := prompt(fmt.Sprintf(" %2d: ", len(choices)+1), 60)
+		if err != nil || !ok {
+			return nil, err
+		}
+		if text == "" {
+			break
+		}
+		choices = append(choices, text)
+	}
+	if len(choices) < 2 {
+		io.Print(ui.Ansi.Yellow + "\r\n A topic needs at least two choices.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil, nil
+	}
+
+	if yes, err := askYesNo(ctx, " Save this topic?", true); err != nil || !yes {
+		return nil, err
+	}
+	topic := database.VotingTopic{Title: title, Description: description, CreatedBy: ctx.Username, Active: true}
+	topicChoices := make([]database.VotingChoice, len(choices))
+	for i, text := range choices {
+		topicChoices[i] = database.VotingChoice{Position: i + 1, Text: text, AddedBy: ctx.Username}
+	}
+	if _, err := db.CreateVotingTopicWithChoices(&topic, topicChoices); err != nil {
+		return nil, err
+	}
+	logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "VOTE_TOPIC", title)
+	io.Print(ui.Ansi.GreenHi + "\r\n\r\n Topic added.\r\n" + ui.Ansi.Reset)
+	ui.Pause(io)
+	return &topic, nil
+}
+
+// handleTopicVoters handles VU: who voted for what on a topic
+func handleTopicVoters(ctx *ExecutionContext, options string) error {
+	db, err := votingDB(ctx)
+	if err != nil {
+		return err
+	}
+	topic, err := chooseTopic(ctx, db, options)
+	if err != nil || topic == nil {
+		return err
+	}
+	return showTopicVoters(ctx, db, *topic)
+}
+
+func showTopicVoters(ctx *ExecutionContext, db database.Database, topic database.VotingTopic) error {
+	votes, err := db.GetTopicVotes(topic.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load votes: %w", err)
+	}
+
+	io := ctx.IO
+	io.ClearScreen()
+	io.Print(ui.Ansi.WhiteHi + "\r\n Voters: " + topic.Title + "\r\n\r\n" + ui.Ansi.Reset)
+	if len(votes) == 0 {
+		io.Print(ui.Ansi.Yellow + " Nobody has voted yet.\r\n" + ui.Ansi.Reset)
+	}
+	for _, vote := range votes {
+		io.Printf(ui.Ansi.WhiteHi+" %-25.25s "+ui.Ansi.Cyan+"%.50s\r\n"+ui.Ansi.Reset, vote.Username, vote.Choice)
+	}
+	ui.Pause(io)
+	return nil
+}
+
+// handleTrackUserVote handles VT: how one user (by number or name in
+// options, or asked for) voted on every topic
+func handleTrackUserVote(ctx *ExecutionContext, options string) error {
+	db, err := votingDB(ctx)
+	if err != nil {
+		return err
+	}
+	io := ctx.IO
+
+	name := strings.TrimSpace(options)
+	if name == "" {
+		name, err = ui.PromptSimple(io, "\r\n User name: ", 30, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+		if err != nil {
+			if err.Error() == "ESC_PRESSED" {
+				return nil
+			}
+			return err
+		}
+		if name = strings.TrimSpace(name); name == "" {
+			return nil
+		}
+	}
+	var user *database.UserRecord
+	if id, convErr := strconv.ParseInt(name, 10, 64); convErr == nil {
+		user, err = db.GetUserByID(id)
+	} else {
+		user, err = db.GetUserByUsername(name)
+	}
+	if err != nil || user == nil {
+		io.Print(ui.Ansi.Yellow + "\r\n\r\n No such user.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+
+	topics, err := db.GetAllVotingTopics()
+	if err != nil {
+		return fmt.Errorf("failed to load voting topics: %w", err)
+	}
+	votes, err := db.GetUserVotes(user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load votes: %w", err)
+	}
+
+	io.ClearScreen()
+	io.Print(ui.Ansi.WhiteHi + "\r\n Votes cast by " + user.Username + "\r\n\r\n" + ui.Ansi.Reset)
+	for i, topic := range topics {
+		answer := ui.Ansi.Yellow + "(not voted)"
+		if choiceID, voted := votes[topic.ID]; voted {
+			choices, err := db.GetVotingChoices(topic.ID)
+			if err != nil {
+				return fmt.Errorf("failed to load voting choices: %w", err)
+			}
+			for _, choice := range choices {
+				if choice.ID == choiceID {
+					answer = ui.Ansi.GreenHi + choice.Text
+				}
+			}
+		}
+		io.Printf(ui.Ansi.YellowHi+" %-3d"+ui.Ansi.Cyan+"%-36.36s %.36s\r\n"+ui.Ansi.Reset, i+1, topic.Title, answer)
+	}
+	ui.Pause(io)
+	return nil
+}
+
+// handleVotingEditor handles *V: add, close, delete and inspect topics.
+// Choices and ACS are edited in the config editor.
+func handleVotingEditor(ctx *ExecutionContext, options string) error {
+	db, err := votingDB(ctx)
+	if err != nil {
+		return err
+	}
+	io := ctx.IO
+
+	for {
+		topics, err := db.GetAllVotingTopics()
+		if err != nil {
+			return fmt.Errorf("failed to load voting topics: %w", err)
+		}
+
+		io.ClearScreen()
+		io.Print(ui.Ansi.WhiteHi + "\r\n Voting Editor\r\n\r\n" + ui.Ansi.Reset)
+		io.Print(ui.Ansi.Cyan + fmt.Sprintf(" %-3s %-40s %-6s %s\r\n", "#", "Topic", "Votes", "Open") + ui.Ansi.Reset)
+		for i, topic := range topics {
+			votes, err := db.GetTopicVotes(topic.ID)
+			if err != nil {
+				return fmt.Errorf("failed to load votes: %w", err)
+			}
+			open := "No"
+			if topic.Active {
+				open = "Yes"
+			}
+			io.Printf(" %-3d %-40.40s %-6d %s\r\n", i+1, topic.Title, len(votes), open)
+		}
+
+		io.Print("\r\n" + ui.Ansi.WhiteHi + " (A)dd, (T)oggle open, (D)elete, (V)oters, (R)esults, (Q)uit: " + ui.Ansi.Reset)
+		key, err := io.GetKeyPressUpper()
+		if err != nil {
+			return err
+		}
+		io.Printf("%c\r\n", key)
+
+		switch key {
+		case 'A':
+			if _, err := addTopic(ctx, db); err != nil {
+				return err
+			}
+			continue
+		case 'T', 'D', 'V', 'R':
+		default:
+			return nil
+		}
+
+		number := promptTopicNumber(ctx)
+		if number == "" {
+			continue
+		}
+		topic, err := chooseTopic(ctx, db, number)
+		if err != nil {
+			return err
+		}
+		if topic == nil {
+			continue
+		}
+		switch key {
+		case 'T':
+			topic.Active = !topic.Active
+			if err := db.UpdateVotingTopic(topic); err != nil {
+				return fmt.Errorf("failed to update voting topic: %w", err)
+			}
+		case 'D':
+			if yes, err := askYesNo(ctx, fmt.Sprintf(" Delete \"%s\" and its votes?", topic.Title), false); err != nil {
+				return err
+			} else if yes {
+				if err := db.DeleteVotingTopic(int64(topic.ID)); err != nil {
+					return fmt.Errorf("failed to delete voting topic: %w", err)
+				}
+				logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "VOTE_TOPIC", "deleted: "+topic.Title)
+			}
+		case 'V':
+			if err := showTopicVoters(ctx, db, *topic); err != nil {
+				return err
+			}
+		case 'R':
+			if err := showTopicResults(ctx, db, *topic); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// promptTopicNumber asks for a topic number, returning "" on Esc
+func promptTopicNumber(ctx *ExecutionContext) string {
+	answer, err := ui.PromptSimple(ctx.IO, " Topic #: ", 3, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(answer)
+}
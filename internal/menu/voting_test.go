@@ -0,0 +1,27 @@
+package menu
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVoteBarFillsShareOfTotal(t *testing.T) {
+	cases := []struct {
+		votes, total, filled int
+	}{
+		{0, 0, 0},
+		{0, 4, 0},
+		{1, 4, 8}, // 7.5 rounds up
+		{2, 4, 15},
+		{4, 4, voteBarWidth},
+	}
+	for _, c := range cases {
+		bar := voteBar(c.votes, c.total)
+		if got := strings.Count(bar, "\xdb"); got != c.filled {
+			t.Errorf("voteBar(%d, %d) filled %d, want %d", c.votes, c.total, got, c.filled)
+		}
+		if got := strings.Count(bar, "\xdb") + strings.Count(bar, "\xb0"); got != voteBarWidth {
+			t.Errorf("voteBar(%d, %d) is %d wide, want %d", c.votes, c.total, got, voteBarWidth)
+		}
+	}
+}
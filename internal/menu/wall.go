@@ -0,0 +1,294 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// Width of an automessage line
+const automessageWidth = 72
+
+func wallConfig(ctx *ExecutionContext) (database.Database, *config.Config, error) {
+	if ctx == nil || ctx.IO == nil || ctx.Executor == nil || ctx.Executor.db == nil {
+		return nil, nil, fmt.Errorf("the automessage and wall require an execution context with IO and a database")
+	}
+	cfg, err := config.LoadConfigFromDB(ctx.Executor.db)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return ctx.Executor.db, cfg, nil
+}
+
+// handleReadAutomessage handles UR: show the current automessage
+func handleReadAutomessage(ctx *ExecutionContext, options string) error {
+	db, _, err := wallConfig(ctx)
+	if err != nil {
+		return err
+	}
+	msg, err := db.GetAutomessage()
+	if err != nil {
+		return err
+	}
+
+	io := ctx.IO
+	io.Print(ui.Ansi.WhiteHi + "\r\n Automessage\r\n" + ui.Ansi.Cyan + " " + strings.Repeat("\xc4", automessageWidth) + "\r\n" + ui.Ansi.Reset)
+	if msg == nil {
+		io.Print(ui.Ansi.Yellow + " Nobody has written an automessage yet.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+	for _, line := range strings.Split(msg.Text, "\n") {
+		io.Print(ui.Ansi.WhiteHi + " " + line + "\r\n" + ui.Ansi.Reset)
+	}
+	author := msg.Author
+	if msg.Anonymous {
+		author = "Anonymous"
+	}
+	written := msg.WrittenAt
+	if at, err := time.Parse(time.RFC3339, msg.WrittenAt); err == nil {
+		written = at.Format("01/02/06 15:04")
+	}
+	io.Print(ui.Ansi.Cyan + " " + strings.Repeat("\xc4", automessageWidth) + "\r\n" + ui.Ansi.Reset)
+	io.Printf(ui.Ansi.Cyan+" Posted by "+ui.Ansi.YellowHi+"%s"+ui.Ansi.Cyan+" on %s\r\n"+ui.Ansi.Reset, author, written)
+	ui.Pause(io)
+	return nil
+}
+
+// handleWriteAutomessage handles UW: replace the automessage, optionally
+// without the author's name
+func handleWriteAutomessage(ctx *ExecutionContext, options string) error {
+	db, cfg, err := wallConfig(ctx)
+	if err != nil {
+		return err
+	}
+	io := ctx.IO
+	social := cfg.Configuration.Social
+	if !ctx.Executor.checkACS(social.AutomessageACS, ctx) {
+		io.Print(ui.Ansi.RedHi + "\r\n You can't write the automessage.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+
+	maxLines := social.AutomessageLines
+	if maxLines <= 0 {
+		maxLines = 5
+	}
+	io.Print(ui.Ansi.Yellow + fmt.Sprintf("\r\n Enter the new automessage (%d lines, empty line to end):\r\n\r\n", maxLines) + ui.Ansi.Reset)
+	lines, escaped, err := readTextLines(io, maxLines, automessageWidth)
+	if err != nil {
+		return err
+	}
+	if escaped || len(lines) == 0 {
+		io.Print(ui.Ansi.Yellow + "\r\n Automessage not changed.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+
+	anonymous, err := askYesNo(ctx, " Post anonymously?", false)
+	if err != nil {
+		return err
+	}
+	msg := &database.Automessage{
+		UserID:    ctx.UserID,
+		Author:    ctx.Username,
+		Anonymous: anonymous,
+		Text:      strings.Join(lines, "\n"),
+	}
+	if err := db.SetAutomessage(msg); err != nil {
+		return err
+	}
+
+	logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "AUTOMSG", fmt.Sprintf("anonymous=%t", anonymous))
+	io.Print(ui.Ansi.GreenHi + "\r\n Automessage saved.\r\n" + ui.Ansi.Reset)
+	ui.Pause(io)
+	return nil
+}
+
+// handleReplyAutomessage handles UA: send private mail to the author of the
+// automessage. Anonymous automessages cannot be replied to.
+func handleReplyAutomessage(ctx *ExecutionContext, options string) error {
+	db, cfg, err := wallConfig(ctx)
+	if err != nil {
+		return err
+	}
+	msg, err := db.GetAutomessage()
+	if err != nil {
+		return err
+	}
+
+	io := ctx.IO
+	switch {
+	case msg == nil:
+		io.Print(ui.Ansi.Yellow + "\r\n There is no automessage to reply to.\r\n" + ui.Ansi.Reset)
+	case msg.Anonymous:
+		io.Print(ui.Ansi.Yellow + "\r\n The automessage was posted anonymously.\r\n" + ui.Ansi.Reset)
+	case strings.EqualFold(msg.Author, ctx.Username):
+		io.Print(ui.Ansi.Yellow + "\r\n You wrote the automessage.\r\n" + ui.Ansi.Reset)
+	default:
+		io.Printf(ui.Ansi.Cyan+"\r\n Reply to "+ui.Ansi.YellowHi+"%s"+ui.Ansi.Cyan+" in private mail.\r\n"+ui.Ansi.Reset, msg.Author)
+		return leavePrivateMessage(ctx, cfg, msg.Author, "Re: Automessage", "Reply", "AUTOMSG_REPLY")
+	}
+	ui.Pause(io)
+	return nil
+}
+
+// handleOneLiners handles UO: show the one-liner wall and offer to add a line
+func handleOneLiners(ctx *ExecutionContext, options string) error {
+	db, cfg, err := wallConfig(ctx)
+	if err != nil {
+		return err
+	}
+	return showOneLiners(ctx, db, cfg.Configuration.Social)
+}
+
+func showOneLiners(ctx *ExecutionContext, db database.Database, social config.SocialConfig) error {
+	count := social.OneLinerCount
+	if count <= 0 {
+		count = 10
+	}
+	width := social.OneLinerLength
+	if width <= 0 {
+		width = 60
+	}
+
+	if err := printOneLiners(ctx, db, count); err != nil {
+		return err
+	}
+	io := ctx.IO
+	if !ctx.Executor.checkACS(social.OneLinerACS, ctx) {
+		ui.Pause(io)
+		return nil
+	}
+
+	add, err := askYesNo(ctx, " Add a one-liner?", false)
+	if err != nil || !add {
+		return err
+	}
+	io.Print("\r\n")
+	text, err := ui.PromptSimple(io, " > ", width, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			return nil
+		}
+		return err
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return nil
+	}
+	if len(text) > width {
+		text = text[:width]
+	}
+	if err := db.AddOneLiner(&database.OneLiner{UserID: ctx.UserID, Username: ctx.Username, Text: text}); err != nil {
+		return err
+	}
+	logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "ONELINER", text)
+
+	if err := printOneLiners(ctx, db, count); err != nil {
+		return err
+	}
+	ui.Pause(io)
+	return nil
+}
+
+func printOneLiners(ctx *ExecutionContext, db database.Database, count int) error {
+	lines, err := db.GetOneLiners(count)
+	if err != nil {
+		return err
+	}
+
+	io := ctx.IO
+	io.ClearScreen()
+	io.Print(ui.Ansi.WhiteHi + "\r\n One-Liners\r\n\r\n" + ui.Ansi.Reset)
+	if len(lines) == 0 {
+		io.Print(ui.Ansi.Yellow + " The wall is empty. Be the first!\r\n" + ui.Ansi.Reset)
+	}
+	for _, line := range lines {
+		io.Printf(ui.Ansi.Cyan+" %-15.15s "+ui.Ansi.WhiteHi+"%s\r\n"+ui.Ansi.Reset, line.Username, line.Text)
+	}
+	return nil
+}
+
+// handleRumor handles UM: show a random rumor. With the option A the caller
+// adds a rumor instead.
+func handleRumor(ctx *ExecutionContext, options string) error {
+	db, cfg, err := wallConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(strings.TrimSpace(options), "A") {
+		return addRumor(ctx, db, cfg.Configuration.Social)
+	}
+	shown, err := showRumor(ctx, db)
+	if err != nil {
+		return err
+	}
+	if !shown {
+		ctx.IO.Print(ui.Ansi.Yellow + "\r\n No rumors are going around.\r\n" + ui.Ansi.Reset)
+	}
+	ui.Pause(ctx.IO)
+	return nil
+}
+
+// showRumor prints a random rumor, reporting false if there are none
+func showRumor(ctx *ExecutionContext, db database.Database) (bool, error) {
+	rumor, err := db.GetRandomRumor()
+	if err != nil || rumor == nil {
+		return false, err
+	}
+	ctx.IO.Print(ui.Ansi.Cyan + "\r\n Rumor has it: " + ui.Ansi.WhiteHi + rumor.Text + "\r\n" + ui.Ansi.Reset)
+	return true, nil
+}
+
+func addRumor(ctx *ExecutionContext, db database.Database, social config.SocialConfig) error {
+	io := ctx.IO
+	if !ctx.Executor.checkACS(social.RumorACS, ctx) {
+		io.Print(ui.Ansi.RedHi + "\r\n You can't add rumors.\r\n" + ui.Ansi.Reset)
+		ui.Pause(io)
+		return nil
+	}
+
+	io.Print(ui.Ansi.Yellow + "\r\n Rumors are shown without your name.\r\n\r\n" + ui.Ansi.Reset)
+	text, err := ui.PromptSimple(io, " Rumor: ", 70, ui.Ansi.Cyan, ui.Ansi.WhiteHi, ui.Ansi.BgBlue, "")
+	if err != nil {
+		if err.Error() == "ESC_PRESSED" {
+			return nil
+		}
+		return err
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return nil
+	}
+	if err := db.AddRumor(&database.Rumor{Text: text, AddedBy: ctx.Username}); err != nil {
+		return err
+	}
+
+	logging.LogEvent(ctx.Session.NodeNumber, ctx.Username, ctx.Session.IPAddress, "RUMOR", text)
+	io.Print(ui.Ansi.GreenHi + "\r\n\r\n Rumor added.\r\n" + ui.Ansi.Reset)
+	ui.Pause(io)
+	return nil
+}
+
+// runOneLinersStep shows the one-liner wall during a sequence
+func runOneLinersStep(ctx *ExecutionContext, options string) (CmdResult, error) {
+	return ResultContinue, handleOneLiners(ctx, options)
+}
+
+// runRumorStep shows a random rumor during a sequence; with no rumors it
+// shows nothing
+func runRumorStep(ctx *ExecutionContext, options string) (CmdResult, error) {
+	db, _, err := wallConfig(ctx)
+	if err != nil {
+		return ResultContinue, err
+	}
+	if shown, err := showRumor(ctx, db); err != nil || !shown {
+		return ResultContinue, err
+	}
+	ui.Pause(ctx.IO)
+	return ResultContinue, nil
+}
@@ -0,0 +1,179 @@
+// Package notify sends BBS events (new users, sysop pages, new posts,
+// security blocks) to Discord and generic JSON webhooks. Deliveries are
+// queued and posted in the background so a slow or unreachable endpoint
+// never holds up a caller.
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Dispatcher defaults
+const (
+	defaultQueueSize   = 100
+	defaultMaxAttempts = 5
+	defaultBackoff     = 2 * time.Second
+	defaultMaxBackoff  = time.Minute
+	requestTimeout     = 10 * time.Second
+)
+
+// Sink turns a notification into a request for one endpoint
+type Sink interface {
+	Name() string
+	Endpoint() string
+	Payload(n Notification) ([]byte, error)
+}
+
+// delivery is one notification bound for one sink
+type delivery struct {
+	sink Sink
+	n    Notification
+}
+
+// Dispatcher posts queued notifications, retrying failed deliveries with
+// exponential backoff. The queue is bounded; when it is full new
+// notifications are dropped rather than blocking the caller.
+type Dispatcher struct {
+	client      *http.Client
+	queue       chan delivery
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDispatcher creates a dispatcher holding up to queueSize pending
+// deliveries. Call Start to begin sending.
+func NewDispatcher(queueSize int) *Dispatcher {
+	if queueSize < 1 {
+		queueSize = defaultQueueSize
+	}
+	return &Dispatcher{
+		client:      &http.Client{Timeout: requestTimeout},
+		queue:       make(chan delivery, queueSize),
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start sends queued deliveries in the background until Stop is called
+func (d *Dispatcher) Start() {
+	go func() {
+		defer close(d.done)
+		for {
+			select {
+			case <-d.stop:
+				return
+			case item := <-d.queue:
+				d.deliver(item)
+			}
+		}
+	}()
+}
+
+// Stop ends the background sender. Deliveries still queued are dropped.
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
+	<-d.done
+}
+
+// Enqueue queues a notification for a sink. It reports false if the queue
+// is full and the notification was dropped.
+func (d *Dispatcher) Enqueue(sink Sink, n Notification) bool {
+	select {
+	case d.queue <- delivery{sink: sink, n: n}:
+		return true
+	default:
+		fmt.Printf("Warning: notification queue full, dropping %s for %s\n", n.Kind, sink.Name())
+		return false
+	}
+}
+
+// deliver posts one delivery, retrying until it succeeds, fails for good or
+// the dispatcher is stopped
+func (d *Dispatcher) deliver(item delivery) {
+	body, err := item.sink.Payload(item.n)
+	if err != nil {
+		fmt.Printf("Warning: could not build %s notification: %v\n", item.sink.Name(), err)
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		wait, retry, err := d.post(item.sink.Endpoint(), body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= d.maxAttempts {
+			fmt.Printf("Warning: %s notification failed after %d attempt(s): %v\n", item.sink.Name(), attempt, err)
+			return
+		}
+		if wait <= 0 {
+			wait = d.backoffFor(attempt)
+		} else if wait > d.maxBackoff {
+			wait = d.maxBackoff
+		}
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// post sends body to url. On failure it reports whether the request is worth
+// retrying and how long the endpoint asked us to wait, if it said.
+func (d *Dispatcher) post(url string, body []byte) (time.Duration, bool, error) {
+	resp, err := d.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, false, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return retryAfter(resp.Header.Get("Retry-After")), true, fmt.Errorf("rate limited (%s)", resp.Status)
+	case resp.StatusCode >= 500:
+		return 0, true, fmt.Errorf("server error (%s)", resp.Status)
+	default:
+		// Other client errors (bad URL, deleted webhook, bad payload) will not
+		// succeed on a retry
+		return 0, false, fmt.Errorf("rejected (%s)", resp.Status)
+	}
+}
+
+// backoffFor returns the wait after the given failed attempt: the base delay
+// doubled each time, capped at maxBackoff
+func (d *Dispatcher) backoffFor(attempt int) time.Duration {
+	wait := d.backoff
+	for i := 1; i < attempt && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.maxBackoff {
+		wait = d.maxBackoff
+	}
+	return wait
+}
+
+// retryAfter parses a Retry-After header in (possibly fractional) seconds
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testDispatcher returns a started dispatcher that retries quickly
+func testDispatcher(t *testing.T) *Dispatcher {
+	t.Helper()
+	d := NewDispatcher(4)
+	d.backoff = time.Millisecond
+	d.maxBackoff = 10 * time.Millisecond
+	d.Start()
+	t.Cleanup(d.Stop)
+	return d
+}
+
+func TestDiscordEmbedIsPostedAfterRetries(t *testing.T) {
+	var attempts atomic.Int32
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch attempts.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusInternalServerError)
+		case 2:
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			body, _ := io.ReadAll(r.Body)
+			bodies <- body
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	d := testDispatcher(t)
+	sink := DiscordSink{URL: server.URL, Username: "Retrograde Bot", Footer: "Test BBS"}
+	d.Enqueue(sink, Notification{
+		Kind:        KindNewUser,
+		Title:       "New User Application:",
+		Description: "tester",
+		Fields:      []Field{{Name: "Location", Value: "Anytown", Inline: true}, {Name: "Empty"}},
+		Time:        time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	var body []byte
+	select {
+	case body = <-bodies:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("notification was not delivered (%d attempts)", attempts.Load())
+	}
+
+	var msg discordMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatalf("bad payload %s: %v", body, err)
+	}
+	if msg.Username != "Retrograde Bot" || len(msg.Embeds) != 1 {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	embed := msg.Embeds[0]
+	if embed.Title != "New User Application:" || embed.Description != "tester" || embed.Color != kindColors[KindNewUser] {
+		t.Fatalf("unexpected embed: %+v", embed)
+	}
+	if len(embed.Fields) != 1 || embed.Fields[0].Value != "Anytown" || !embed.Fields[0].Inline {
+		t.Fatalf("expected only the non-empty field, got %+v", embed.Fields)
+	}
+	if embed.Footer == nil || embed.Footer.Text != "Test BBS" || embed.Timestamp != "2024-05-01T12:00:00Z" {
+		t.Fatalf("unexpected footer or timestamp: %+v", embed)
+	}
+}
+
+func TestClientErrorsAreNotRetried(t *testing.T) {
+	var attempts atomic.Int32
+	done := make(chan struct{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		done <- struct{}{}
+	}))
+	defer server.Close()
+
+	d := testDispatcher(t)
+	d.Enqueue(JSONSink{URL: server.URL + "/missing"}, Notification{Kind: KindSysOpPage, Title: "page"})
+	// Deliveries are sent in order, so once the second arrives the first
+	// has finished for good
+	d.Enqueue(JSONSink{URL: server.URL + "/ok"}, Notification{Kind: KindSysOpPage, Title: "page"})
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("deliveries did not finish")
+		}
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Fatalf("expected the rejected delivery to be tried once, got %d requests", n)
+	}
+}
+
+func TestEnqueueDropsWhenQueueIsFull(t *testing.T) {
+	d := NewDispatcher(1) // Not started, so nothing drains the queue
+	sink := JSONSink{URL: "http://127.0.0.1:0"}
+	if !d.Enqueue(sink, Notification{Kind: KindNewPost}) {
+		t.Fatalf("expected the first notification to be queued")
+	}
+	if d.Enqueue(sink, Notification{Kind: KindNewPost}) {
+		t.Fatalf("expected the second notification to be dropped")
+	}
+}
+
+func TestJSONSinkPayload(t *testing.T) {
+	sink := JSONSink{URL: "http://example.invalid", Source: "Test BBS"}
+	body, err := sink.Payload(Notification{
+		Kind:   KindSecurityBlock,
+		Title:  "Connection blocked",
+		Fields: []Field{{Name: "Address", Value: "192.0.2.1", Inline: true}},
+		Time:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"event":"security_block","source":"Test BBS","title":"Connection blocked","fields":[{"name":"Address","value":"192.0.2.1"}],"time":"2024-05-01T12:00:00Z"}`
+	if string(body) != want {
+		t.Fatalf("unexpected payload:\n got %s\nwant %s", body, want)
+	}
+}
+
+func TestBackoffDoublesUpToTheCap(t *testing.T) {
+	d := NewDispatcher(1)
+	d.backoff = time.Second
+	d.maxBackoff = 5 * time.Second
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := d.backoffFor(attempt + 1); got != want {
+			t.Fatalf("attempt %d: expected %v, got %v", attempt+1, want, got)
+		}
+	}
+}
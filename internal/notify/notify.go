@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+)
+
+// Kind identifies what happened
+type Kind string
+
+// Kinds of notification
+const (
+	KindNewUser       Kind = "new_user"
+	KindSysOpPage     Kind = "sysop_page"
+	KindNewPost       Kind = "new_post"
+	KindSecurityBlock Kind = "security_block"
+)
+
+// securityCooldown limits security notifications to one per address in this
+// window, so a flood of refused connections does not flood the channel
+const securityCooldown = time.Hour
+
+// Notification is one event to announce
+type Notification struct {
+	Kind        Kind
+	Title       string
+	Description string
+	Fields      []Field
+	Time        time.Time
+}
+
+// Field is a labelled value shown with a notification
+type Field struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"-"`
+}
+
+var (
+	runningMu sync.Mutex
+	running   *Dispatcher
+
+	blockedMu sync.Mutex
+	blocked   = make(map[string]time.Time)
+)
+
+// Start starts the process-wide dispatcher the event helpers publish to
+func Start() {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	if running != nil {
+		return
+	}
+	running = NewDispatcher(defaultQueueSize)
+	running.Start()
+}
+
+// Stop stops the process-wide dispatcher
+func Stop() {
+	runningMu.Lock()
+	d := running
+	running = nil
+	runningMu.Unlock()
+	if d != nil {
+		d.Stop()
+	}
+}
+
+// Sinks returns the endpoints the configuration sends notifications to
+func Sinks(cfg *config.Config) []Sink {
+	var sinks []Sink
+	bbsName := cfg.Configuration.General.BBSName
+	if discord := cfg.Other.Discord; discord.Enabled && strings.TrimSpace(discord.WebhookURL) != "" {
+		sinks = append(sinks, DiscordSink{URL: strings.TrimSpace(discord.WebhookURL), Username: discord.Username, Footer: bbsName})
+	}
+	if hook := cfg.Other.Webhook; hook.Enabled && strings.TrimSpace(hook.URL) != "" {
+		sinks = append(sinks, JSONSink{URL: strings.TrimSpace(hook.URL), Source: bbsName})
+	}
+	return sinks
+}
+
+// Publish queues a notification for every configured endpoint. It does
+// nothing if the dispatcher is not running.
+func Publish(cfg *config.Config, n Notification) {
+	runningMu.Lock()
+	d := running
+	runningMu.Unlock()
+	if d == nil || cfg == nil {
+		return
+	}
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+	for _, sink := range Sinks(cfg) {
+		d.Enqueue(sink, n)
+	}
+}
+
+// NewUser announces a new user registration
+func NewUser(cfg *config.Config, node int, username, location string) {
+	if cfg == nil || !cfg.Other.Notify.NewUsers {
+		return
+	}
+	title := strings.TrimSpace(cfg.Other.Discord.Title)
+	if title == "" {
+		title = "New User Application:"
+	}
+	Publish(cfg, Notification{
+		Kind:        KindNewUser,
+		Title:       title,
+		Description: username,
+		Fields: []Field{
+			{Name: "Location", Value: location, Inline: true},
+			{Name: "Node", Value: fmt.Sprint(node), Inline: true},
+		},
+	})
+}
+
+// SysOpPage announces a caller paging the sysop
+func SysOpPage(cfg *config.Config, node int, username, reason string) {
+	if cfg == nil || !cfg.Other.Notify.SysOpPages {
+		return
+	}
+	Publish(cfg, Notification{
+		Kind:        KindSysOpPage,
+		Title:       fmt.Sprintf("%s is paging the sysop", username),
+		Description: reason,
+		Fields:      []Field{{Name: "Node", Value: fmt.Sprint(node), Inline: true}},
+	})
+}
+
+// NewPost announces a message posted to an area listed in PostAreas.
+// Private messages are never announced.
+func NewPost(cfg *config.Config, area database.MessageArea, msgNum int, from, to, subject string, private bool) {
+	if cfg == nil || private || !postArea(cfg, area.File) {
+		return
+	}
+	Publish(cfg, Notification{
+		Kind:        KindNewPost,
+		Title:       fmt.Sprintf("New post in %s", area.Name),
+		Description: subject,
+		Fields: []Field{
+			{Name: "From", Value: from, Inline: true},
+			{Name: "To", Value: to, Inline: true},
+			{Name: "Message", Value: fmt.Sprintf("#%d", msgNum), Inline: true},
+		},
+	})
+}
+
+// SecurityBlock announces a refused connection, at most once an hour for
+// each address
+func SecurityBlock(cfg *config.Config, ip, reason string) {
+	if cfg == nil || !cfg.Other.Notify.SecurityBlocks {
+		return
+	}
+	now := time.Now()
+	blockedMu.Lock()
+	if last, ok := blocked[ip]; ok && now.Sub(last) < securityCooldown {
+		blockedMu.Unlock()
+		return
+	}
+	blocked[ip] = now
+	for addr, last := range blocked {
+		if now.Sub(last) >= securityCooldown {
+			delete(blocked, addr)
+		}
+	}
+	blockedMu.Unlock()
+
+	Publish(cfg, Notification{
+		Kind:        KindSecurityBlock,
+		Title:       "Connection blocked",
+		Description: reason,
+		Fields:      []Field{{Name: "Address", Value: ip, Inline: true}},
+		Time:        now,
+	})
+}
+
+func postArea(cfg *config.Config, file string) bool {
+	for _, f := range cfg.Other.Notify.PostAreas {
+		if strings.EqualFold(strings.TrimSpace(f), file) {
+			return true
+		}
+	}
+	return false
+}
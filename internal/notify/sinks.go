@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Discord limits on embed text
+const (
+	discordTitleLimit       = 256
+	discordDescriptionLimit = 4096
+	discordFieldNameLimit   = 256
+	discordFieldValueLimit  = 1024
+	discordFieldLimit       = 25
+)
+
+// Embed colours by kind of notification
+var kindColors = map[Kind]int{
+	KindNewUser:       0x2ecc71,
+	KindSysOpPage:     0xf1c40f,
+	KindNewPost:       0x3498db,
+	KindSecurityBlock: 0xe74c3c,
+}
+
+// DiscordSink posts notifications as embeds to a Discord webhook
+type DiscordSink struct {
+	URL      string
+	Username string // Name the webhook posts as; blank uses the webhook's own
+	Footer   string // Shown under each embed, normally the BBS name
+}
+
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+func (s DiscordSink) Name() string     { return "Discord" }
+func (s DiscordSink) Endpoint() string { return s.URL }
+
+// Payload builds the webhook body, trimming text to Discord's limits
+func (s DiscordSink) Payload(n Notification) ([]byte, error) {
+	embed := discordEmbed{
+		Title:       truncate(n.Title, discordTitleLimit),
+		Description: truncate(n.Description, discordDescriptionLimit),
+		Color:       kindColors[n.Kind],
+		Timestamp:   n.Time.UTC().Format(time.RFC3339),
+	}
+	for _, f := range n.Fields {
+		if len(embed.Fields) == discordFieldLimit {
+			break
+		}
+		if f.Value == "" {
+			continue
+		}
+		embed.Fields = append(embed.Fields, discordField{
+			Name:   truncate(f.Name, discordFieldNameLimit),
+			Value:  truncate(f.Value, discordFieldValueLimit),
+			Inline: f.Inline,
+		})
+	}
+	if s.Footer != "" {
+		embed.Footer = &discordFooter{Text: s.Footer}
+	}
+	return json.Marshal(discordMessage{Username: s.Username, Embeds: []discordEmbed{embed}})
+}
+
+// JSONSink posts notifications as plain JSON for other services to consume
+type JSONSink struct {
+	URL    string
+	Source string // Identifies the BBS in each payload
+}
+
+type jsonMessage struct {
+	Event       Kind    `json:"event"`
+	Source      string  `json:"source,omitempty"`
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Fields      []Field `json:"fields,omitempty"`
+	Time        string  `json:"time"`
+}
+
+func (s JSONSink) Name() string     { return "webhook" }
+func (s JSONSink) Endpoint() string { return s.URL }
+
+// Payload builds the webhook body
+func (s JSONSink) Payload(n Notification) ([]byte, error) {
+	return json.Marshal(jsonMessage{
+		Event:       n.Kind,
+		Source:      s.Source,
+		Title:       n.Title,
+		Description: n.Description,
+		Fields:      n.Fields,
+		Time:        n.Time.UTC().Format(time.RFC3339),
+	})
+}
+
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,453 @@
+package qwk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/jam"
+)
+
+// AreaTypeQWKNet is the MessageArea.AreaType value for QWK network areas
+const AreaTypeQWKNet = "qwknet"
+
+// lastReadPrefix namespaces hub export pointers in the JAM lastread file so
+// they never collide with a caller's own pointers
+const lastReadPrefix = "qwknet:"
+
+// Result summarizes a single exchange run
+type Result struct {
+	Imported int      // Messages tossed into local areas
+	Exported int      // Messages packed for the hub or downstream nodes
+	Skipped  int      // Messages for conferences with no mapped area
+	Packets  []string // Packets written or consumed
+}
+
+// Exchange tosses and scans QWKnet packets for the configured mode
+type Exchange struct {
+	cfg     config.QWKNetConfig
+	general config.GeneralConfig
+	db      database.Database
+	now     func() time.Time
+}
+
+// NewExchange creates an exchange for the current configuration
+func NewExchange(cfg *config.Config, db database.Database) *Exchange {
+	return &Exchange{
+		cfg:     cfg.Networking.QWKNet,
+		general: cfg.Configuration.General,
+		db:      db,
+		now:     time.Now,
+	}
+}
+
+// Run performs one exchange cycle in the configured mode
+func (x *Exchange) Run() (*Result, error) {
+	if !x.cfg.Enabled {
+		return nil, fmt.Errorf("QWKnet is not enabled")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(x.cfg.Mode)) {
+	case config.QWKNetModeHub:
+		return x.RunHub()
+	case config.QWKNetModeNode, "":
+		return x.RunNode()
+	default:
+		return nil, fmt.Errorf("unknown QWKnet mode %q", x.cfg.Mode)
+	}
+}
+
+// RunNode imports the hub's QWK packet and builds an outbound REP packet
+// from messages posted locally in QWKnet areas.
+func (x *Exchange) RunNode() (*Result, error) {
+	hubID, err := NormalizePacketID(x.cfg.HubPacketID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hub packet ID: %w", err)
+	}
+
+	areas, err := x.networkAreas()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+
+	// Toss inbound hub packet
+	if path, ok := findPacket(x.cfg.InboundPath, hubID+".QWK"); ok {
+		_, msgs, err := ReadQWKPacket(path)
+		if err != nil {
+			return result, err
+		}
+		if err := x.toss(msgs, areas, hubID, result); err != nil {
+			return result, err
+		}
+		if err := os.Remove(path); err != nil {
+			return result, fmt.Errorf("failed to remove tossed packet: %w", err)
+		}
+		result.Packets = append(result.Packets, path)
+	}
+
+	// Scan local posts into the outbound REP
+	repPath := filepath.Join(x.cfg.OutboundPath, hubID+".REP")
+	var pending []Message
+	if existing, ok := findPacket(x.cfg.OutboundPath, hubID+".REP"); ok {
+		// The hub has not collected the previous REP yet; append to it
+		pending, err = ReadREPPacket(existing, hubID)
+		if err != nil {
+			return result, err
+		}
+		repPath = existing
+	}
+
+	var marks []sentMark
+	for conf, area := range areas {
+		base, err := openAreaBase(area)
+		if err != nil {
+			return result, err
+		}
+
+		count, err := base.GetMessageCount()
+		if err != nil {
+			base.Close()
+			return result, err
+		}
+		for msgNum := 1; msgNum <= count; msgNum++ {
+			msg, err := base.ReadMessage(msgNum)
+			if err != nil || msg.IsDeleted() {
+				continue
+			}
+			// Private mail (feedback, validation notices) stays on this system
+			attr := msg.Header.Attribute
+			if attr&jam.MSG_LOCAL == 0 || attr&jam.MSG_SENT != 0 || attr&jam.MSG_PRIVATE != 0 {
+				continue
+			}
+			pending = append(pending, fromJAM(msg, conf, 0))
+			marks = append(marks, sentMark{base: areaBasePath(area), msgNum: msgNum})
+		}
+		base.Close()
+	}
+
+	if len(marks) == 0 {
+		return result, nil
+	}
+
+	// Flag the messages before writing the packet: if the packet is not
+	// written they are unflagged again, and if we stop in between they are
+	// not sent at all rather than sent twice
+	if err := setSent(marks, true); err != nil {
+		setSent(marks, false)
+		return result, err
+	}
+	if err := WriteREPPacket(repPath, hubID, pending); err != nil {
+		if clearErr := setSent(marks, false); clearErr != nil {
+			return result, fmt.Errorf("%w; failed to unflag exported messages: %v", err, clearErr)
+		}
+		return result, err
+	}
+	result.Packets = append(result.Packets, repPath)
+	result.Exported += len(marks)
+
+	return result, nil
+}
+
+// sentMark is a local message exported to the hub
+type sentMark struct {
+	base   string
+	msgNum int
+}
+
+// setSent sets or clears MSG_SENT on exported messages
+func setSent(marks []sentMark, sent bool) error {
+	for _, mark := range marks {
+		base, err := jam.Open(mark.base)
+		if err != nil {
+			return fmt.Errorf("failed to open message base: %w", err)
+		}
+		hdr, err := base.ReadMessageHeader(mark.msgNum)
+		if err == nil {
+			if sent {
+				hdr.Attribute |= jam.MSG_SENT
+			} else {
+				hdr.Attribute &^= jam.MSG_SENT
+			}
+			err = base.UpdateMessageHeader(mark.msgNum, hdr)
+		}
+		base.Close()
+		if err != nil {
+			return fmt.Errorf("failed to flag exported message: %w", err)
+		}
+	}
+	return nil
+}
+
+// RunHub imports REP packets uploaded by downstream nodes and builds a QWK
+// packet for each node containing everything it has not yet received.
+// Node uploads are expected in <inbound>/<NODEID>/<HUBID>.REP and packets
+// are served from <outbound>/<NODEID>/<HUBID>.QWK.
+func (x *Exchange) RunHub() (*Result, error) {
+	hubID, err := NormalizePacketID(x.cfg.PacketID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid packet ID: %w", err)
+	}
+
+	areas, err := x.networkAreas()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+
+	for _, node := range x.cfg.HubNodes {
+		nodeID, err := NormalizePacketID(node)
+		if err != nil {
+			return result, fmt.Errorf("invalid hub node: %w", err)
+		}
+
+		if path, ok := findPacket(filepath.Join(x.cfg.InboundPath, nodeID), hubID+".REP"); ok {
+			msgs, err := ReadREPPacket(path, hubID)
+			if err != nil {
+				return result, err
+			}
+			if err := x.toss(msgs, areas, nodeID, result); err != nil {
+				return result, err
+			}
+			if err := os.Remove(path); err != nil {
+				return result, fmt.Errorf("failed to remove tossed packet: %w", err)
+			}
+			result.Packets = append(result.Packets, path)
+		}
+	}
+
+	for _, node := range x.cfg.HubNodes {
+		nodeID, _ := NormalizePacketID(node)
+		if err := x.packNode(hubID, nodeID, areas, result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// packNode builds (or extends) the QWK packet waiting for a downstream node
+func (x *Exchange) packNode(hubID, nodeID string, areas map[int]database.MessageArea, result *Result) error {
+	qwkPath := filepath.Join(x.cfg.OutboundPath, nodeID, hubID+".QWK")
+	var pending []Message
+	if existing, ok := findPacket(filepath.Join(x.cfg.OutboundPath, nodeID), hubID+".QWK"); ok {
+		// The node has not collected the previous packet yet; append to it
+		_, msgs, err := ReadQWKPacket(existing)
+		if err != nil {
+			return err
+		}
+		pending = msgs
+		qwkPath = existing
+	}
+
+	pointer := lastReadPrefix + nodeID
+	type highMark struct {
+		base string
+		high uint32
+	}
+	var highs []highMark
+	added := 0
+	for conf, area := range areas {
+		base, err := openAreaBase(area)
+		if err != nil {
+			return err
+		}
+
+		var start uint32
+		if lr, err := base.GetLastRead(pointer); err == nil {
+			start = lr.HighReadMsg
+		}
+
+		count, err := base.GetMessageCount()
+		if err != nil {
+			base.Close()
+			return err
+		}
+		high := start
+		for msgNum := int(start) + 1; msgNum <= count; msgNum++ {
+			high = uint32(msgNum)
+			msg, err := base.ReadMessage(msgNum)
+			if err != nil || msg.IsDeleted() {
+				continue
+			}
+			// Never echo a node's own posts back to it, and keep private
+			// mail written here on this system
+			if strings.EqualFold(msg.OrigAddr, nodeID) {
+				continue
+			}
+			if attr := msg.Header.Attribute; attr&jam.MSG_LOCAL != 0 && attr&jam.MSG_PRIVATE != 0 {
+				continue
+			}
+			pending = append(pending, fromJAM(msg, conf, msgNum))
+			added++
+		}
+		base.Close()
+
+		if high != start {
+			highs = append(highs, highMark{base: areaBasePath(area), high: high})
+		}
+	}
+
+	if added > 0 {
+		control := &Control{
+			BBSName:     x.general.BBSName,
+			Location:    x.general.BBSLocation,
+			Phone:       "000-000-0000",
+			SysOp:       x.general.SysOpName,
+			PacketID:    hubID,
+			Created:     x.now(),
+			User:        nodeID,
+			Conferences: conferenceList(areas),
+		}
+		if err := WriteQWKPacket(qwkPath, control, pending); err != nil {
+			return err
+		}
+		result.Exported += added
+		result.Packets = append(result.Packets, qwkPath)
+	}
+
+	for _, mark := range highs {
+		base, err := jam.Open(mark.base)
+		if err != nil {
+			return fmt.Errorf("failed to open message base: %w", err)
+		}
+		err = base.SetLastRead(pointer, mark.high, mark.high)
+		base.Close()
+		if err != nil {
+			return fmt.Errorf("failed to update export pointer for %s: %w", nodeID, err)
+		}
+	}
+
+	return nil
+}
+
+// toss writes inbound messages into their mapped areas. source is the
+// packet ID the messages arrived from and is recorded as the origin address.
+func (x *Exchange) toss(msgs []Message, areas map[int]database.MessageArea, source string, result *Result) error {
+	bases := make(map[int]*jam.JAMBase)
+	defer func() {
+		for _, base := range bases {
+			base.Close()
+		}
+	}()
+
+	for _, m := range msgs {
+		area, ok := areas[m.Conference]
+		if !ok {
+			result.Skipped++
+			continue
+		}
+
+		base, ok := bases[m.Conference]
+		if !ok {
+			var err error
+			base, err = openAreaBase(area)
+			if err != nil {
+				return err
+			}
+			bases[m.Conference] = base
+		}
+
+		attr := uint32(jam.MSG_TYPEECHO)
+		if m.Private {
+			attr |= jam.MSG_PRIVATE
+		}
+
+		msg := jam.NewMessage()
+		msg.Header = &jam.MessageHeader{Attribute: attr}
+		msg.From = m.From
+		msg.To = m.To
+		msg.Subject = m.Subject
+		msg.DateTime = m.DateTime
+		msg.Text = m.Text
+		msg.OrigAddr = source
+		if _, err := base.WriteMessage(msg); err != nil {
+			return fmt.Errorf("failed to toss message into %s: %w", area.Name, err)
+		}
+		result.Imported++
+	}
+
+	return nil
+}
+
+// networkAreas returns QWKnet areas keyed by their QWK conference number
+func (x *Exchange) networkAreas() (map[int]database.MessageArea, error) {
+	all, err := x.db.GetAllMessageAreas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load message areas: %w", err)
+	}
+
+	areas := make(map[int]database.MessageArea)
+	for _, area := range all {
+		if !strings.EqualFold(area.AreaType, AreaTypeQWKNet) || area.QWKConference <= 0 {
+			continue
+		}
+		if existing, dup := areas[area.QWKConference]; dup {
+			return nil, fmt.Errorf("QWK conference %d is mapped to both %s and %s", area.QWKConference, existing.Name, area.Name)
+		}
+		areas[area.QWKConference] = area
+	}
+	return areas, nil
+}
+
+// conferenceList returns the CONTROL.DAT conference list for the areas
+func conferenceList(areas map[int]database.MessageArea) []Conference {
+	list := make([]Conference, 0, len(areas))
+	for conf, area := range areas {
+		list = append(list, Conference{Number: conf, Name: area.Name})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Number < list[j].Number })
+	return list
+}
+
+// fromJAM converts a stored JAM message to a QWK message
+func fromJAM(msg *jam.Message, conference, number int) Message {
+	return Message{
+		Conference: conference,
+		Number:     number,
+		To:         msg.To,
+		From:       msg.From,
+		Subject:    msg.Subject,
+		DateTime:   msg.DateTime,
+		Private:    msg.IsPrivate(),
+		Text:       msg.Text,
+	}
+}
+
+// areaBasePath returns the JAM base path (without extension) for an area
+func areaBasePath(area database.MessageArea) string {
+	return filepath.Join(area.Path, area.File)
+}
+
+// openAreaBase opens (or creates) the JAM base for an area
+func openAreaBase(area database.MessageArea) (*jam.JAMBase, error) {
+	if strings.TrimSpace(area.Path) == "" || strings.TrimSpace(area.File) == "" {
+		return nil, fmt.Errorf("message area %s has no path configured", area.Name)
+	}
+	base, err := jam.Open(areaBasePath(area))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open message base for %s: %w", area.Name, err)
+	}
+	return base, nil
+}
+
+// findPacket locates a packet in dir, matching its name case-insensitively
+func findPacket(dir, name string) (string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(entry.Name(), name) {
+			return filepath.Join(dir, entry.Name()), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,243 @@
+package qwk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/jam"
+)
+
+func setupExchange(t *testing.T, mode string) (*config.Config, *database.SQLiteDB, database.MessageArea) {
+	t.Helper()
+
+	dir := t.TempDir()
+	db, err := database.OpenSQLite(database.ConnectionConfig{Path: filepath.Join(dir, "test.db")})
+	if err != nil {
+		t.Fatalf("OpenSQLite error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitializeSchema(); err != nil {
+		t.Fatalf("InitializeSchema error: %v", err)
+	}
+
+	confs, err := db.GetAllConferences()
+	if err != nil || len(confs) == 0 {
+		t.Fatalf("expected seeded conference, got %v (%v)", confs, err)
+	}
+	area := database.MessageArea{
+		Name:          "DOVE General",
+		File:          "dovegen",
+		Path:          filepath.Join(dir, "msgs"),
+		ReadSecLevel:  "public",
+		WriteSecLevel: "public",
+		AreaType:      AreaTypeQWKNet,
+		ConferenceID:  confs[0].ID,
+		QWKConference: 2001,
+	}
+	if _, err := db.CreateMessageArea(&area); err != nil {
+		t.Fatalf("CreateMessageArea error: %v", err)
+	}
+
+	cfg := config.GetDefaultConfig()
+	cfg.Networking.QWKNet.Enabled = true
+	cfg.Networking.QWKNet.Mode = mode
+	cfg.Networking.QWKNet.PacketID = "NODE1"
+	cfg.Networking.QWKNet.HubPacketID = "HUB"
+	cfg.Networking.QWKNet.InboundPath = filepath.Join(dir, "in")
+	cfg.Networking.QWKNet.OutboundPath = filepath.Join(dir, "out")
+	if mode == config.QWKNetModeHub {
+		cfg.Networking.QWKNet.PacketID = "HUB"
+		cfg.Networking.QWKNet.HubNodes = []string{"node1"}
+	}
+
+	return cfg, db, area
+}
+
+func postLocal(t *testing.T, area database.MessageArea, subject string) {
+	t.Helper()
+	base, err := jam.Open(areaBasePath(area))
+	if err != nil {
+		t.Fatalf("jam.Open error: %v", err)
+	}
+	defer base.Close()
+
+	msg := jam.NewMessage()
+	msg.From = "Caller"
+	msg.To = "All"
+	msg.Subject = subject
+	msg.Text = "posted locally"
+	if _, err := base.WriteMessage(msg); err != nil {
+		t.Fatalf("WriteMessage error: %v", err)
+	}
+}
+
+func TestNodeExportsLocalPostsOnce(t *testing.T) {
+	cfg, db, area := setupExchange(t, config.QWKNetModeNode)
+	postLocal(t, area, "first")
+
+	x := NewExchange(cfg, db)
+	result, err := x.Run()
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if result.Exported != 1 {
+		t.Fatalf("expected 1 exported message, got %d", result.Exported)
+	}
+
+	repPath := filepath.Join(cfg.Networking.QWKNet.OutboundPath, "HUB.REP")
+	msgs, err := ReadREPPacket(repPath, "HUB")
+	if err != nil {
+		t.Fatalf("ReadREPPacket error: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].Conference != 2001 || msgs[0].Subject != "first" {
+		t.Fatalf("unexpected REP contents: %+v", msgs)
+	}
+
+	// A second run must not re-export the same message
+	result, err = x.Run()
+	if err != nil {
+		t.Fatalf("second Run error: %v", err)
+	}
+	if result.Exported != 0 {
+		t.Fatalf("expected no exports on second run, got %d", result.Exported)
+	}
+}
+
+func TestNodeKeepsPrivateMailLocal(t *testing.T) {
+	cfg, db, area := setupExchange(t, config.QWKNetModeNode)
+	postLocal(t, area, "public")
+
+	base, err := jam.Open(areaBasePath(area))
+	if err != nil {
+		t.Fatalf("jam.Open error: %v", err)
+	}
+	msg := jam.NewMessage()
+	msg.Header = &jam.MessageHeader{Attribute: jam.MSG_LOCAL | jam.MSG_TYPELOCAL | jam.MSG_PRIVATE}
+	msg.From = "Caller"
+	msg.To = "SysOp"
+	msg.Subject = "feedback"
+	msg.Text = "for the sysop only"
+	if _, err := base.WriteMessage(msg); err != nil {
+		t.Fatalf("WriteMessage error: %v", err)
+	}
+	base.Close()
+
+	result, err := NewExchange(cfg, db).Run()
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if result.Exported != 1 {
+		t.Fatalf("expected 1 exported message, got %d", result.Exported)
+	}
+	msgs, err := ReadREPPacket(filepath.Join(cfg.Networking.QWKNet.OutboundPath, "HUB.REP"), "HUB")
+	if err != nil {
+		t.Fatalf("ReadREPPacket error: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].Subject != "public" {
+		t.Fatalf("private mail exported: %+v", msgs)
+	}
+}
+
+func TestNodeKeepsPostsWhenPacketFails(t *testing.T) {
+	cfg, db, area := setupExchange(t, config.QWKNetModeNode)
+	postLocal(t, area, "first")
+
+	// A file where the outbound directory should be makes the REP unwritable
+	outbound := cfg.Networking.QWKNet.OutboundPath
+	if err := os.WriteFile(outbound, nil, 0644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	x := NewExchange(cfg, db)
+	if _, err := x.Run(); err == nil {
+		t.Fatalf("expected the run to fail")
+	}
+
+	if err := os.Remove(outbound); err != nil {
+		t.Fatalf("Remove error: %v", err)
+	}
+	result, err := x.Run()
+	if err != nil {
+		t.Fatalf("second Run error: %v", err)
+	}
+	if result.Exported != 1 {
+		t.Fatalf("expected the post exported after the failed run, got %d", result.Exported)
+	}
+}
+
+func TestNodeTossesHubPacket(t *testing.T) {
+	cfg, db, area := setupExchange(t, config.QWKNetModeNode)
+
+	control := &Control{PacketID: "HUB", Created: time.Now(), User: "NODE1", Conferences: []Conference{{Number: 2001, Name: "General"}}}
+	msgs := []Message{
+		{Conference: 2001, Number: 1, To: "All", From: "Remote", Subject: "hello", DateTime: time.Now(), Text: "from the hub"},
+		{Conference: 9999, Number: 2, To: "All", From: "Remote", Subject: "unmapped", DateTime: time.Now(), Text: "nowhere"},
+	}
+	qwkPath := filepath.Join(cfg.Networking.QWKNet.InboundPath, "hub.qwk")
+	if err := WriteQWKPacket(qwkPath, control, msgs); err != nil {
+		t.Fatalf("WriteQWKPacket error: %v", err)
+	}
+
+	result, err := NewExchange(cfg, db).Run()
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if result.Imported != 1 || result.Skipped != 1 || result.Exported != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if _, err := os.Stat(qwkPath); !os.IsNotExist(err) {
+		t.Fatalf("expected tossed packet to be removed")
+	}
+
+	base, err := jam.Open(areaBasePath(area))
+	if err != nil {
+		t.Fatalf("jam.Open error: %v", err)
+	}
+	defer base.Close()
+	msg, err := base.ReadMessage(1)
+	if err != nil {
+		t.Fatalf("ReadMessage error: %v", err)
+	}
+	if msg.Subject != "hello" || msg.OrigAddr != "HUB" {
+		t.Fatalf("unexpected tossed message: %+v", msg)
+	}
+}
+
+func TestHubServesNodesWithoutEchoingTheirPosts(t *testing.T) {
+	cfg, db, area := setupExchange(t, config.QWKNetModeHub)
+	postLocal(t, area, "hub post")
+
+	rep := []Message{{Conference: 2001, To: "All", From: "NodeUser", Subject: "node post", DateTime: time.Now(), Text: "from node1"}}
+	repPath := filepath.Join(cfg.Networking.QWKNet.InboundPath, "NODE1", "HUB.REP")
+	if err := WriteREPPacket(repPath, "HUB", rep); err != nil {
+		t.Fatalf("WriteREPPacket error: %v", err)
+	}
+
+	result, err := NewExchange(cfg, db).Run()
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if result.Imported != 1 || result.Exported != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	_, msgs, err := ReadQWKPacket(filepath.Join(cfg.Networking.QWKNet.OutboundPath, "NODE1", "HUB.QWK"))
+	if err != nil {
+		t.Fatalf("ReadQWKPacket error: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].Subject != "hub post" {
+		t.Fatalf("expected only the hub's own post, got %+v", msgs)
+	}
+
+	// Nothing new: the packet must not grow
+	result, err = NewExchange(cfg, db).Run()
+	if err != nil {
+		t.Fatalf("second Run error: %v", err)
+	}
+	if result.Exported != 0 {
+		t.Fatalf("expected no exports on second run, got %d", result.Exported)
+	}
+}
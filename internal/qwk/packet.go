@@ -0,0 +1,176 @@
+package qwk
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	controlFile  = "CONTROL.DAT"
+	messagesFile = "MESSAGES.DAT"
+)
+
+// WriteQWKPacket writes a zipped QWK packet containing CONTROL.DAT and
+// MESSAGES.DAT. The packet is written to a temporary file and renamed so
+// a downstream system never picks up a partial packet.
+func WriteQWKPacket(path string, control *Control, msgs []Message) error {
+	control.Messages = len(msgs)
+
+	var ctl, dat bytes.Buffer
+	if err := WriteControl(&ctl, control); err != nil {
+		return err
+	}
+	if err := WriteMessages(&dat, "Produced by Retrograde BBS", msgs, false); err != nil {
+		return err
+	}
+
+	return writeZip(path, map[string][]byte{
+		controlFile:  ctl.Bytes(),
+		messagesFile: dat.Bytes(),
+	})
+}
+
+// ReadQWKPacket reads a zipped QWK packet
+func ReadQWKPacket(path string) (*Control, []Message, error) {
+	files, err := readZip(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctlData, ok := files[controlFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s missing from %s", ErrInvalidPacket, controlFile, filepath.Base(path))
+	}
+	control, err := ReadControl(bytes.NewReader(ctlData))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	datData, ok := files[messagesFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s missing from %s", ErrInvalidPacket, messagesFile, filepath.Base(path))
+	}
+	msgs, err := ReadMessages(bytes.NewReader(datData), false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return control, msgs, nil
+}
+
+// WriteREPPacket writes a zipped REP packet containing <packetID>.MSG
+func WriteREPPacket(path, packetID string, msgs []Message) error {
+	var dat bytes.Buffer
+	if err := WriteMessages(&dat, packetID, msgs, true); err != nil {
+		return err
+	}
+
+	return writeZip(path, map[string][]byte{
+		packetID + ".MSG": dat.Bytes(),
+	})
+}
+
+// ReadREPPacket reads a zipped REP packet addressed to packetID
+func ReadREPPacket(path, packetID string) ([]Message, error) {
+	files, err := readZip(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToUpper(packetID) + ".MSG"
+	data, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s missing from %s", ErrInvalidPacket, name, filepath.Base(path))
+	}
+
+	return ReadMessages(bytes.NewReader(data), true)
+}
+
+// writeZip atomically writes a zip archive with the given members
+func writeZip(path string, members map[string][]byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create packet directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create packet: %w", err)
+	}
+
+	zw := zip.NewWriter(f)
+	for _, name := range []string{controlFile, messagesFile} {
+		if data, ok := members[name]; ok {
+			if err := addZipMember(zw, name, data); err != nil {
+				f.Close()
+				os.Remove(tmpPath)
+				return err
+			}
+			delete(members, name)
+		}
+	}
+	for name, data := range members {
+		if err := addZipMember(zw, name, data); err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to finalize packet: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close packet: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to move packet into place: %w", err)
+	}
+	return nil
+}
+
+func addZipMember(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to packet: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to packet: %w", name, err)
+	}
+	return nil
+}
+
+// readZip returns the members of a zip archive keyed by upper-cased name
+func readZip(path string) (map[string][]byte, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open packet %s: %w", filepath.Base(path), err)
+	}
+	defer zr.Close()
+
+	files := make(map[string][]byte)
+	for _, zf := range zr.File {
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in packet: %w", zf.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in packet: %w", zf.Name, err)
+		}
+		files[strings.ToUpper(filepath.Base(zf.Name))] = data
+	}
+
+	return files, nil
+}
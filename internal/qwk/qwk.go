@@ -0,0 +1,364 @@
+// Package qwk implements the QWK/REP offline mail packet format and the
+// QWKnet hub/node exchange used to carry echomail between systems.
+package qwk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	BlockSize      = 128  // QWK packets are made of fixed 128-byte blocks
+	LineTerminator = 0xE3 // QWK end-of-line marker (pi character in CP437)
+	ActiveFlag     = 0xE1 // Message is active
+	InactiveFlag   = 0xE2 // Message is killed
+
+	MaxPacketIDLength = 8
+	fieldWidth        = 25 // To/From/Subject width in the message header
+)
+
+var (
+	ErrShortBlock     = errors.New("qwk: truncated message block")
+	ErrInvalidPacket  = errors.New("qwk: invalid packet")
+	ErrInvalidControl = errors.New("qwk: invalid CONTROL.DAT")
+)
+
+// Message is a single QWK/REP message
+type Message struct {
+	Conference int
+	Number     int
+	To         string
+	From       string
+	Subject    string
+	DateTime   time.Time
+	ReplyTo    int
+	Private    bool
+	Text       string
+}
+
+// Conference maps a QWK conference number to its name
+type Conference struct {
+	Number int
+	Name   string
+}
+
+// Control holds the fields of a CONTROL.DAT file
+type Control struct {
+	BBSName     string
+	Location    string
+	Phone       string
+	SysOp       string
+	PacketID    string
+	Created     time.Time
+	User        string
+	Messages    int
+	Conferences []Conference
+}
+
+// NormalizePacketID upper-cases and validates a QWK packet ID (BBSID)
+func NormalizePacketID(id string) (string, error) {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	if id == "" {
+		return "", fmt.Errorf("packet ID cannot be empty")
+	}
+	if len(id) > MaxPacketIDLength {
+		return "", fmt.Errorf("packet ID %q is longer than %d characters", id, MaxPacketIDLength)
+	}
+	for _, r := range id {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' && r != '-' {
+			return "", fmt.Errorf("packet ID %q contains invalid character %q", id, r)
+		}
+	}
+	return id, nil
+}
+
+// WriteMessages writes a MESSAGES.DAT (QWK) or <ID>.MSG (REP) stream.
+// The first block carries the producer string (packet ID for REP packets).
+// For REP packets the message number field holds the conference number.
+func WriteMessages(w io.Writer, producer string, msgs []Message, reply bool) error {
+	first := padBlock([]byte(producer))
+	if _, err := w.Write(first); err != nil {
+		return fmt.Errorf("failed to write packet header: %w", err)
+	}
+
+	for i, msg := range msgs {
+		text := encodeText(msg.Text)
+		blocks := 1 + len(text)/BlockSize
+
+		hdr := bytes.Repeat([]byte{' '}, BlockSize)
+		hdr[0] = statusFlag(msg.Private)
+		number := msg.Number
+		if reply {
+			number = msg.Conference
+		}
+		putField(hdr[1:8], strconv.Itoa(number))
+		putField(hdr[8:16], msg.DateTime.Format("01-02-06"))
+		putField(hdr[16:21], msg.DateTime.Format("15:04"))
+		putField(hdr[21:46], msg.To)
+		putField(hdr[46:71], msg.From)
+		putField(hdr[71:96], msg.Subject)
+		if msg.ReplyTo > 0 {
+			putField(hdr[108:116], strconv.Itoa(msg.ReplyTo))
+		}
+		putField(hdr[116:122], strconv.Itoa(blocks))
+		hdr[122] = ActiveFlag
+		binary.LittleEndian.PutUint16(hdr[123:125], uint16(msg.Conference))
+		binary.LittleEndian.PutUint16(hdr[125:127], uint16(i+1))
+		hdr[127] = ' '
+
+		if _, err := w.Write(hdr); err != nil {
+			return fmt.Errorf("failed to write message header: %w", err)
+		}
+		if _, err := w.Write(text); err != nil {
+			return fmt.Errorf("failed to write message text: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReadMessages parses a MESSAGES.DAT (QWK) or <ID>.MSG (REP) stream and
+// returns the active messages. The producer block is skipped.
+func ReadMessages(r io.Reader, reply bool) ([]Message, error) {
+	br := bufio.NewReader(r)
+	block := make([]byte, BlockSize)
+
+	if _, err := io.ReadFull(br, block); err != nil {
+		if err == io.EOF {
+			return nil, ErrInvalidPacket
+		}
+		return nil, ErrShortBlock
+	}
+
+	var msgs []Message
+	for {
+		if _, err := io.ReadFull(br, block); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, ErrShortBlock
+		}
+
+		blocks, _ := strconv.Atoi(getField(block[116:122]))
+		if blocks < 1 {
+			return nil, fmt.Errorf("%w: bad block count %q", ErrInvalidPacket, getField(block[116:122]))
+		}
+
+		text := make([]byte, (blocks-1)*BlockSize)
+		if _, err := io.ReadFull(br, text); err != nil {
+			return nil, ErrShortBlock
+		}
+
+		if block[122] == InactiveFlag {
+			continue
+		}
+
+		number, _ := strconv.Atoi(getField(block[1:8]))
+		conference := int(binary.LittleEndian.Uint16(block[123:125]))
+		msg := Message{
+			Number:   number,
+			To:       getField(block[21:46]),
+			From:     getField(block[46:71]),
+			Subject:  getField(block[71:96]),
+			DateTime: parseDateTime(getField(block[8:16]), getField(block[16:21])),
+			Private:  block[0] == '*' || block[0] == '+',
+			Text:     decodeText(text),
+		}
+		msg.ReplyTo, _ = strconv.Atoi(getField(block[108:116]))
+		if reply {
+			// REP packets carry the conference in the number field; the
+			// binary field is optional for many readers.
+			msg.Conference = number
+			msg.Number = 0
+		} else {
+			msg.Conference = conference
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// WriteControl writes a CONTROL.DAT file
+func WriteControl(w io.Writer, c *Control) error {
+	lines := []string{
+		c.BBSName,
+		c.Location,
+		c.Phone,
+		c.SysOp,
+		"00000," + c.PacketID,
+		c.Created.Format("01-02-2006,15:04:05"),
+		strings.ToUpper(c.User),
+		"",
+		"0",
+		strconv.Itoa(c.Messages),
+		strconv.Itoa(len(c.Conferences) - 1),
+	}
+	for _, conf := range c.Conferences {
+		lines = append(lines, strconv.Itoa(conf.Number), conf.Name)
+	}
+	lines = append(lines, "", "", "")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\r\n"); err != nil {
+			return fmt.Errorf("failed to write CONTROL.DAT: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadControl parses a CONTROL.DAT file
+func ReadControl(r io.Reader) (*Control, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CONTROL.DAT: %w", err)
+	}
+	if len(lines) < 11 {
+		return nil, ErrInvalidControl
+	}
+
+	c := &Control{
+		BBSName:  lines[0],
+		Location: lines[1],
+		Phone:    lines[2],
+		SysOp:    lines[3],
+		User:     lines[6],
+	}
+	if parts := strings.SplitN(lines[4], ",", 2); len(parts) == 2 {
+		c.PacketID = strings.TrimSpace(parts[1])
+	}
+	if created, err := time.ParseInLocation("01-02-2006,15:04:05", strings.TrimSpace(lines[5]), time.Local); err == nil {
+		c.Created = created
+	}
+	c.Messages, _ = strconv.Atoi(strings.TrimSpace(lines[9]))
+
+	count, err := strconv.Atoi(strings.TrimSpace(lines[10]))
+	if err != nil {
+		return nil, ErrInvalidControl
+	}
+	for i := 0; i <= count; i++ {
+		pos := 11 + i*2
+		if pos+1 >= len(lines) {
+			return nil, ErrInvalidControl
+		}
+		number, err := strconv.Atoi(strings.TrimSpace(lines[pos]))
+		if err != nil {
+			return nil, ErrInvalidControl
+		}
+		c.Conferences = append(c.Conferences, Conference{Number: number, Name: lines[pos+1]})
+	}
+
+	return c, nil
+}
+
+// statusFlag returns the header status byte for an unread message
+func statusFlag(private bool) byte {
+	if private {
+		return '+'
+	}
+	return ' '
+}
+
+// putField copies value into a space-padded, left-justified header field
+func putField(dst []byte, value string) {
+	for i := range dst {
+		dst[i] = ' '
+	}
+	copy(dst, value)
+}
+
+// getField returns a header field with its padding removed
+func getField(src []byte) string {
+	return strings.TrimRight(string(bytes.TrimRight(src, "\x00")), " ")
+}
+
+// padBlock pads data with spaces to a multiple of BlockSize
+func padBlock(data []byte) []byte {
+	size := ((len(data) + BlockSize - 1) / BlockSize) * BlockSize
+	if size == 0 {
+		size = BlockSize
+	}
+	out := bytes.Repeat([]byte{' '}, size)
+	copy(out, data)
+	return out
+}
+
+// encodeText converts message text to QWK line terminators and pads it to
+// whole blocks
+func encodeText(text string) []byte {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.TrimRight(text, "\n")
+
+	data := []byte(text)
+	for i, b := range data {
+		if b == '\n' {
+			data[i] = LineTerminator
+		}
+	}
+	data = append(data, LineTerminator)
+	return padBlock(data)
+}
+
+// decodeText converts QWK message text back to newline separated text.
+// Only trailing terminators and padding are trimmed, so text ending in a
+// high CP437 byte survives. In UTF-8 text, 0xE3 also leads many runes, so
+// those are kept when the message decodes as UTF-8 that way.
+func decodeText(data []byte) string {
+	end := len(data)
+	for end > 0 && (data[end-1] == LineTerminator || data[end-1] == ' ' || data[end-1] == 0) {
+		end--
+	}
+	data = data[:end]
+
+	text := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		if data[i] == LineTerminator {
+			if r, size := utf8.DecodeRune(data[i:]); r != utf8.RuneError && size > 1 {
+				text = append(text, data[i:i+size]...)
+				i += size
+				continue
+			}
+			text = append(text, '\n')
+			i++
+			continue
+		}
+		text = append(text, data[i])
+		i++
+	}
+	if utf8.Valid(text) {
+		return string(text)
+	}
+
+	// Not UTF-8 (CP437): every 0xE3 is a line break
+	text = append(text[:0], data...)
+	for i, b := range text {
+		if b == LineTerminator {
+			text[i] = '\n'
+		}
+	}
+	return string(text)
+}
+
+// parseDateTime parses the MM-DD-YY / HH:MM header fields
+func parseDateTime(date, clock string) time.Time {
+	date = strings.ReplaceAll(date, "/", "-")
+	t, err := time.ParseInLocation("01-02-06 15:04", date+" "+clock, time.Local)
+	if err != nil {
+		return time.Now()
+	}
+	return t
+}
@@ -0,0 +1,130 @@
+package qwk
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMessagesRoundTrip(t *testing.T) {
+	when := time.Date(2025, 3, 14, 21, 5, 0, 0, time.Local)
+	msgs := []Message{
+		{Conference: 2001, Number: 12, To: "All", From: "SysOp", Subject: "Hello DOVE", DateTime: when, Text: "Line one\nLine two"},
+		{Conference: 2002, Number: 13, To: "Bob", From: "Alice", Subject: "Private", DateTime: when, Private: true, Text: "A message long enough to need a second block. " +
+			"A message long enough to need a second block. A message long enough to need a second block."},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMessages(&buf, "Produced by test", msgs, false); err != nil {
+		t.Fatalf("WriteMessages error: %v", err)
+	}
+	if buf.Len()%BlockSize != 0 {
+		t.Fatalf("expected packet length to be a multiple of %d, got %d", BlockSize, buf.Len())
+	}
+
+	got, err := ReadMessages(&buf, false)
+	if err != nil {
+		t.Fatalf("ReadMessages error: %v", err)
+	}
+	if len(got) != len(msgs) {
+		t.Fatalf("expected %d messages, got %d", len(msgs), len(got))
+	}
+	for i := range msgs {
+		if got[i].Conference != msgs[i].Conference || got[i].Number != msgs[i].Number {
+			t.Fatalf("message %d: expected conf %d num %d, got conf %d num %d", i, msgs[i].Conference, msgs[i].Number, got[i].Conference, got[i].Number)
+		}
+		if got[i].From != msgs[i].From || got[i].To != msgs[i].To || got[i].Subject != msgs[i].Subject {
+			t.Fatalf("message %d: header fields mismatch: %+v", i, got[i])
+		}
+		if got[i].Text != msgs[i].Text {
+			t.Fatalf("message %d: expected text %q, got %q", i, msgs[i].Text, got[i].Text)
+		}
+		if got[i].Private != msgs[i].Private {
+			t.Fatalf("message %d: expected private %v", i, msgs[i].Private)
+		}
+		if !got[i].DateTime.Equal(when) {
+			t.Fatalf("message %d: expected date %v, got %v", i, when, got[i].DateTime)
+		}
+	}
+}
+
+func TestMessageTextKeepsHighBytes(t *testing.T) {
+	for _, text := range []string{
+		"Shaded\n\xB0\xB1\xB2",                         // CP437 ending in a high byte
+		"Box \xC9\xCD\xBB\n\xC8\xCD\xBC",               // CP437 box drawing on both lines
+		"\u3053\u3093\u306b\u3061\u306f\n\u4e16\u754c", // UTF-8 with 0xE3 lead bytes
+	} {
+		if got := decodeText(encodeText(text)); got != text {
+			t.Errorf("round trip of %q gave %q", text, got)
+		}
+	}
+	if got := decodeText([]byte{'a', 0xB0, 0xB1, LineTerminator, LineTerminator, ' '}); got != "a\xB0\xB1" {
+		t.Errorf("trailing terminators trimmed to %q", got)
+	}
+}
+
+func TestREPPacketCarriesConferenceNumber(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "HUB.REP")
+	msgs := []Message{{Conference: 7, To: "All", From: "Caller", Subject: "Reply", DateTime: time.Now(), Text: "hi"}}
+
+	if err := WriteREPPacket(path, "HUB", msgs); err != nil {
+		t.Fatalf("WriteREPPacket error: %v", err)
+	}
+
+	got, err := ReadREPPacket(path, "hub")
+	if err != nil {
+		t.Fatalf("ReadREPPacket error: %v", err)
+	}
+	if len(got) != 1 || got[0].Conference != 7 {
+		t.Fatalf("expected one message in conference 7, got %+v", got)
+	}
+}
+
+func TestQWKPacketRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "HUB.QWK")
+	control := &Control{
+		BBSName:  "Retrograde",
+		Location: "Somewhere",
+		Phone:    "000-000-0000",
+		SysOp:    "SysOp",
+		PacketID: "HUB",
+		Created:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local),
+		User:     "NODE1",
+		Conferences: []Conference{
+			{Number: 2001, Name: "General"},
+			{Number: 2002, Name: "Programming"},
+		},
+	}
+	msgs := []Message{{Conference: 2002, Number: 1, To: "All", From: "Someone", Subject: "Go", DateTime: time.Now(), Text: "gophers"}}
+
+	if err := WriteQWKPacket(path, control, msgs); err != nil {
+		t.Fatalf("WriteQWKPacket error: %v", err)
+	}
+
+	gotControl, gotMsgs, err := ReadQWKPacket(path)
+	if err != nil {
+		t.Fatalf("ReadQWKPacket error: %v", err)
+	}
+	if gotControl.PacketID != "HUB" || gotControl.Messages != 1 {
+		t.Fatalf("unexpected control: %+v", gotControl)
+	}
+	if len(gotControl.Conferences) != 2 || gotControl.Conferences[1].Number != 2002 || gotControl.Conferences[1].Name != "Programming" {
+		t.Fatalf("unexpected conferences: %+v", gotControl.Conferences)
+	}
+	if len(gotMsgs) != 1 || gotMsgs[0].Text != "gophers" {
+		t.Fatalf("unexpected messages: %+v", gotMsgs)
+	}
+}
+
+func TestNormalizePacketID(t *testing.T) {
+	if id, err := NormalizePacketID(" dove "); err != nil || id != "DOVE" {
+		t.Fatalf("expected DOVE, got %q (%v)", id, err)
+	}
+	if _, err := NormalizePacketID("TOOLONGID"); err == nil {
+		t.Fatalf("expected error for long packet ID")
+	}
+	if _, err := NormalizePacketID("BAD ID"); err == nil {
+		t.Fatalf("expected error for packet ID with spaces")
+	}
+}
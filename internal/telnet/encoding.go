@@ -0,0 +1,150 @@
+package telnet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/ui"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// encodingProbe is a box-drawing line in UTF-8. A UTF-8 terminal draws it in
+// one column; a CP437 terminal draws its three bytes as three characters.
+const encodingProbe = "\xe2\x94\x80"
+
+// isUTF8 reports whether the session's terminal expects UTF-8
+func (t *TelnetIO) isUTF8() bool {
+	return t.Session != nil && t.Session.Encoding == config.EncodingUTF8
+}
+
+// encodeOutput converts CP437 text to the session's character set, or to
+// plain ASCII for terminals without ANSI support
+func (t *TelnetIO) encodeOutput(text string) string {
+	if t.Session != nil && t.Session.Emulation == ui.EmulationASCII {
+		return EncodeASCII(text)
+	}
+	if !t.isUTF8() {
+		return text
+	}
+	return EncodeUTF8(text)
+}
+
+// decodeInput converts a byte read from a UTF-8 terminal back to CP437,
+// reading the rest of a multi-byte character when needed. Characters with no
+// CP437 equivalent become '?'.
+func (t *TelnetIO) decodeInput(b byte) byte {
+	if !t.isUTF8() || b < utf8.RuneSelf {
+		return b
+	}
+
+	size := 0
+	switch {
+	case b&0xE0 == 0xC0:
+		size = 2
+	case b&0xF0 == 0xE0:
+		size = 3
+	case b&0xF8 == 0xF0:
+		size = 4
+	default:
+		return '?'
+	}
+
+	buf := []byte{b}
+	for len(buf) < size {
+		next, err := t.Reader.ReadByte()
+		if err != nil {
+			return '?'
+		}
+		buf = append(buf, next)
+	}
+	r, _ := utf8.DecodeRune(buf)
+	if enc, ok := charmap.CodePage437.EncodeRune(r); ok {
+		return enc
+	}
+	return '?'
+}
+
+// EncodeUTF8 converts CP437 text to UTF-8. ASCII, including escape
+// sequences, passes through unchanged.
+func EncodeUTF8(text string) string {
+	ascii := true
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return text
+	}
+
+	var out strings.Builder
+	out.Grow(len(text) + len(text)/2)
+	for i := 0; i < len(text); i++ {
+		if b := text[i]; b < utf8.RuneSelf {
+			out.WriteByte(b)
+		} else {
+			out.WriteRune(charmap.CodePage437.DecodeByte(b))
+		}
+	}
+	return out.String()
+}
+
+func parseCursorPosition(reply string) (row, col int, ok bool) {
+	body, found := strings.CutPrefix(reply, "\x1b[")
+	if !found {
+		return 0, 0, false
+	}
+	rowText, colText, found := strings.Cut(strings.TrimSuffix(body, "R"), ";")
+	if !found {
+		return 0, 0, false
+	}
+	row, err := strconv.Atoi(rowText)
+	if err != nil {
+		return 0, 0, false
+	}
+	col, err = strconv.Atoi(colText)
+	if err != nil {
+		return 0, 0, false
+	}
+	return row, col, true
+}
+
+// ChooseEncoding shows the same box in both character sets and asks which one
+// the caller can read
+func (t *TelnetIO) ChooseEncoding() (string, error) {
+	prompt := "\r\n" + ui.Ansi.Cyan + " Which of these looks like a box?" + ui.Ansi.Reset + "\r\n\r\n" +
+		ui.Ansi.WhiteHi + "  1) " + ui.Ansi.Reset + "\xda\xc4\xc4\xbf  " + ui.Ansi.WhiteHi + "2) " + ui.Ansi.Reset + "┌──┐" + "\r\n\r\n" +
+		ui.Ansi.Cyan + " Choice: " + ui.Ansi.Reset
+	if err := t.writeRaw(prompt); err != nil {
+		return "", err
+	}
+	for {
+		key, err := t.GetKeyPress()
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case '1':
+			t.writeRaw("1\r\n")
+			return config.EncodingCP437, nil
+		case '2':
+			t.writeRaw("2\r\n")
+			return config.EncodingUTF8, nil
+		}
+	}
+}
+
+// writeRaw sends bytes to the terminal without transcoding them
+func (t *TelnetIO) writeRaw(text string) error {
+	if t.Writer == nil {
+		return fmt.Errorf("telnet writer is not initialized")
+	}
+	if _, err := t.Writer.WriteString(text); err != nil {
+		return err
+	}
+	return t.Writer.Flush()
+}
@@ -0,0 +1,44 @@
+package telnet
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/robbiew/retrograde/internal/config"
+)
+
+func TestUTF8Transcoding(t *testing.T) {
+	var out bytes.Buffer
+	session := &config.TelnetSession{Encoding: config.EncodingUTF8}
+	tio := &TelnetIO{
+		Reader:  bufio.NewReader(strings.NewReader("┌é€\x1b[A")),
+		Writer:  bufio.NewWriter(&out),
+		Session: session,
+	}
+
+	if err := tio.Print("\x1b[1m\xda\xc4\xbf\x82"); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	if got, want := out.String(), "\x1b[1m┌─┐é"; got != want {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+
+	for _, want := range []string{"\xda", "\x82", "?", "\x1b[A"} {
+		seq, err := tio.ReadKeySequence(0)
+		if err != nil {
+			t.Fatalf("ReadKeySequence: %v", err)
+		}
+		if seq != want {
+			t.Fatalf("input = %q, want %q", seq, want)
+		}
+	}
+
+	out.Reset()
+	session.Encoding = config.EncodingCP437
+	tio.Print("\xda\xc4\xbf")
+	if out.String() != "\xda\xc4\xbf" {
+		t.Fatalf("CP437 output was transcoded: %q", out.String())
+	}
+}
@@ -15,6 +15,24 @@ type TelnetIO struct {
 	Reader  *bufio.Reader
 	Writer  *bufio.Writer
 	Session *config.TelnetSession // Reference to session for activity tracking
+
+	ttypeRefused bool   // Client answered WONT TTYPE
+	typeahead    []byte // Keys queued by QueueInput, read before the connection
+}
+
+// QueueInput queues keys to be read as if the caller had typed them
+func (t *TelnetIO) QueueInput(keys string) {
+	t.typeahead = append(t.typeahead, keys...)
+}
+
+// nextQueued pops the next queued key
+func (t *TelnetIO) nextQueued() (byte, bool) {
+	if len(t.typeahead) == 0 {
+		return 0, false
+	}
+	b := t.typeahead[0]
+	t.typeahead = t.typeahead[1:]
+	return b, true
 }
 
 // FlushInput clears any buffered input from the reader
@@ -42,6 +60,10 @@ func (t *TelnetIO) FlushInput() {
 
 // GetKeyPress reads a single key press from telnet connection
 func (t *TelnetIO) GetKeyPress() (byte, error) {
+	if b, ok := t.nextQueued(); ok {
+		return b, nil
+	}
+
 	b, err := t.Reader.ReadByte()
 	if err != nil {
 		return 0, err
@@ -59,7 +81,7 @@ func (t *TelnetIO) GetKeyPress() (byte, error) {
 		t.Session.LastActivity = time.Now()
 	}
 
-	return b, nil
+	return t.decodeInput(b), nil
 }
 
 // GetKeyPressUpper reads a key and converts to uppercase
@@ -94,13 +116,9 @@ func (t *TelnetIO) GetKeyPressUpperWithTimeout(timeout time.Duration) (byte, err
 	return key, nil
 }
 
-// Print sends text to the telnet client
+// Print sends text to the telnet client, transcoded for its character set
 func (t *TelnetIO) Print(text string) error {
-	_, err := t.Writer.WriteString(text)
-	if err != nil {
-		return err
-	}
-	return t.Writer.Flush()
+	return t.writeRaw(t.encodeOutput(text))
 }
 
 // Printf sends formatted text to the telnet client
@@ -111,7 +129,7 @@ func (t *TelnetIO) Printf(format string, args ...interface{}) error {
 
 // PrintAt sends text at a specific cursor position
 func (t *TelnetIO) PrintAt(text string, x, y int) error {
-	if _, err := ui.WriteAt(t.Writer, text, x, y); err != nil {
+	if _, err := ui.WriteAt(t.Writer, t.encodeOutput(text), x, y); err != nil {
 		return err
 	}
 	return t.Writer.Flush()
@@ -138,6 +156,9 @@ func (t *TelnetIO) Pause() error {
 
 // ReadKeySequence reads a key or escape sequence, honoring an optional timeout.
 func (t *TelnetIO) ReadKeySequence(timeout time.Duration) (string, error) {
+	if b, ok := t.nextQueued(); ok {
+		return string(b), nil
+	}
 	if t.Reader == nil {
 		return "", fmt.Errorf("telnet reader is not initialized")
 	}
@@ -170,7 +191,7 @@ func (t *TelnetIO) ReadKeySequence(timeout time.Duration) (string, error) {
 		t.Session.LastActivity = time.Now()
 	}
 
-	seq := []byte{b}
+	seq := []byte{t.decodeInput(b)}
 
 	switch b {
 	case 27: // ESC
@@ -240,50 +261,36 @@ func (t *TelnetIO) handleTelnetCommand() {
 
 	// Handle different telnet commands
 	switch cmd {
+	case sb:
+		t.handleSubnegotiation()
 	case 251, 252, 253, 254: // WILL, WONT, DO, DONT
 		// Read the option byte
 		option, err := t.Reader.ReadByte()
 		if err != nil {
 			return
 		}
-		// Handle NAWS subnegotiation
-		if option == 31 { // NAWS option
-			t.handleNAWSSubnegotiation()
+		if option == optTTYPE {
+			switch cmd {
+			case will:
+				t.requestTerminalType()
+			case wont:
+				t.ttypeRefused = true
+			}
 		}
-		// For other options, just consume the option - could respond appropriately later
+		// For other options, just consume the option - NAWS sizes arrive as a subnegotiation
 	}
 }
 
-func (t *TelnetIO) handleNAWSSubnegotiation() {
-	// Read the subnegotiation data: IAC SB NAWS <width-high> <width-low> <height-high> <height-low> IAC SE
-
-	// Read width high byte
-	widthHigh, err := t.Reader.ReadByte()
-	if err != nil {
-		return
-	}
-
-	// Read width low byte
-	widthLow, err := t.Reader.ReadByte()
-	if err != nil {
-		return
-	}
-
-	// Read height high byte
-	heightHigh, err := t.Reader.ReadByte()
-	if err != nil {
-		return
-	}
-
-	// Read height low byte
-	heightLow, err := t.Reader.ReadByte()
-	if err != nil {
+// handleNAWSSubnegotiation applies the data of an IAC SB NAWS <width-high>
+// <width-low> <height-high> <height-low> IAC SE block
+func (t *TelnetIO) handleNAWSSubnegotiation(data []byte) {
+	if len(data) < 4 {
 		return
 	}
 
 	// Calculate width and height (big-endian)
-	width := int(widthHigh)<<8 | int(widthLow)
-	height := int(heightHigh)<<8 | int(heightLow)
+	width := int(data[0])<<8 | int(data[1])
+	height := int(data[2])<<8 | int(data[3])
 
 	fmt.Printf("DEBUG: NAWS received - Width: %d, Height: %d\n", width, height)
 
@@ -301,10 +308,14 @@ func (t *TelnetIO) handleNAWSSubnegotiation() {
 		height = 24
 	}
 
-	// Store dimensions in session
+	// Store dimensions in session, except those the caller set themselves
 	if t.Session != nil {
-		t.Session.Width = width
-		t.Session.Height = height
+		if t.Session.WidthOverride == 0 {
+			t.Session.Width = width
+		}
+		if t.Session.HeightOverride == 0 {
+			t.Session.Height = height
+		}
 	}
 }
 
@@ -0,0 +1,296 @@
+package telnet
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// Telnet protocol bytes used by the terminal handshake
+const (
+	iac        = 255
+	sb         = 250
+	se         = 240
+	will       = 251
+	wont       = 252
+	optTTYPE   = 24
+	optNAWS    = 31
+	ttypeIS    = 0
+	ttypeSEND  = 1
+	ripQuery   = "\x1b[!"
+	ripReplyID = "RIPSCRIP"
+)
+
+// ttypeWait is how long the handshake waits for a terminal type after the
+// cursor position reply
+const ttypeWait = 250 * time.Millisecond
+
+// TerminalInfo is what the connect handshake learned about a caller's terminal
+type TerminalInfo struct {
+	Emulation    string // ui.EmulationANSI, ui.EmulationRIP or ui.EmulationASCII
+	Encoding     string // config.EncodingUTF8 or config.EncodingCP437; empty when unknown
+	TerminalType string // Name reported through telnet TTYPE
+	ICEColors    bool   // The client is known to support CSI ?33h
+	Answered     bool   // The terminal replied to the cursor position request
+}
+
+// iceClients are terminal types known to show bright backgrounds on request
+var iceClients = []string{"syncterm", "netrunner", "icyterm", "icy term", "magiterm"}
+
+// asciiTerminals are terminal types that can't interpret ANSI sequences
+var asciiTerminals = []string{"dumb", "unknown", "tty33", "glasstty"}
+
+// DetectTerminal probes the terminal at connect. It prints a UTF-8 character,
+// asks for RIP support and the cursor position, and waits up to timeout for
+// the reply. Terminals that don't answer and don't report an ANSI-capable
+// TTYPE fall back to plain ASCII.
+func (t *TelnetIO) DetectTerminal(timeout time.Duration) TerminalInfo {
+	info := TerminalInfo{Emulation: ui.EmulationASCII}
+	if t.Session == nil || t.Session.Conn == nil {
+		return info
+	}
+
+	if err := t.writeRaw("\r" + encodingProbe + ripQuery + ui.Esc + "6n"); err != nil {
+		return info
+	}
+	_, col, seen, ok := t.awaitCursorReport(timeout)
+	if ok {
+		info.Answered = true
+		info.Emulation = ui.EmulationANSI
+		switch col {
+		case 2:
+			info.Encoding = config.EncodingUTF8
+		case 4:
+			info.Encoding = config.EncodingCP437
+		}
+		t.writeRaw("\r" + ui.Ansi.EraseLine)
+	} else {
+		t.writeRaw("\r\n")
+	}
+
+	if t.Session.TerminalType == "" && !t.ttypeRefused {
+		t.awaitTerminalType(ttypeWait)
+	}
+	info.TerminalType = t.Session.TerminalType
+
+	name := strings.ToLower(info.TerminalType)
+	switch {
+	case bytes.Contains(seen, []byte(ripReplyID)) || strings.Contains(name, "rip"):
+		info.Emulation = ui.EmulationRIP
+	case !info.Answered && name != "" && !containsAny(name, asciiTerminals):
+		// Some clients never answer ESC[6n but say what they are
+		info.Emulation = ui.EmulationANSI
+	case info.Answered && containsAny(name, asciiTerminals):
+		info.Emulation = ui.EmulationASCII
+	}
+	info.ICEColors = info.Emulation != ui.EmulationASCII && containsAny(name, iceClients)
+	return info
+}
+
+// Apply stores the handshake results in the session
+func (info TerminalInfo) Apply(session *config.TelnetSession) {
+	session.Emulation = info.Emulation
+	session.TerminalType = info.TerminalType
+	session.ICEColors = info.ICEColors
+	if info.Encoding != "" {
+		session.Encoding = info.Encoding
+	}
+}
+
+// ApplyPreferences applies a caller's saved terminal overrides, read from
+// their user details. Missing values and "auto" keep what was detected. A
+// saved width or height also stops NAWS from changing it later in the call.
+func ApplyPreferences(session *config.TelnetSession, details map[string]string) {
+	switch pref := details[config.EncodingPreference]; pref {
+	case config.EncodingCP437, config.EncodingUTF8:
+		session.Encoding = pref
+	}
+	switch pref := strings.ToLower(details[config.EmulationPreference]); pref {
+	case ui.EmulationANSI, ui.EmulationRIP, ui.EmulationASCII:
+		session.Emulation = pref
+	}
+	switch strings.ToLower(details[config.ICEColorsPreference]) {
+	case "on", "yes", "true":
+		session.ICEColors = true
+	case "off", "no", "false":
+		session.ICEColors = false
+	}
+	session.WidthOverride, session.HeightOverride = 0, 0
+	if width, err := strconv.Atoi(details[config.WidthPreference]); err == nil && width >= 20 && width <= 255 {
+		session.Width = width
+		session.WidthOverride = width
+	}
+	if height, err := strconv.Atoi(details[config.HeightPreference]); err == nil && height >= 10 && height <= 255 {
+		session.Height = height
+		session.HeightOverride = height
+	}
+}
+
+// CursorPosition asks the terminal where its cursor is (ESC[6n) and waits up
+// to timeout for the reply. It needs a connection to set a read deadline on.
+func (t *TelnetIO) CursorPosition(timeout time.Duration) (row, col int, ok bool) {
+	if t.Session == nil || t.Session.Conn == nil {
+		return 0, 0, false
+	}
+	if err := t.writeRaw(ui.Esc + "6n"); err != nil {
+		return 0, 0, false
+	}
+	row, col, _, ok = t.awaitCursorReport(timeout)
+	return row, col, ok
+}
+
+// awaitCursorReport reads until an ESC[row;colR reply arrives or timeout
+// passes. Telnet commands are handled along the way and any other bytes are
+// returned in seen.
+func (t *TelnetIO) awaitCursorReport(timeout time.Duration) (row, col int, seen []byte, ok bool) {
+	conn := t.Session.Conn
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, 0, nil, false
+	}
+	defer conn.SetReadDeadline(time.Time{})
+
+	var reply []byte
+	for {
+		b, err := t.Reader.ReadByte()
+		if err != nil {
+			return 0, 0, seen, false
+		}
+		switch {
+		case b == iac:
+			t.handleTelnetCommand()
+		case b == 27:
+			seen = append(seen, reply...)
+			reply = []byte{b}
+		case len(reply) > 0:
+			reply = append(reply, b)
+			if b == 'R' {
+				if row, col, ok := parseCursorPosition(string(reply)); ok {
+					return row, col, seen, true
+				}
+				seen = append(seen, reply...)
+				reply = nil
+			} else if len(reply) > 16 {
+				seen = append(seen, reply...)
+				reply = nil
+			}
+		default:
+			seen = append(seen, b)
+		}
+	}
+}
+
+// awaitTerminalType processes telnet negotiation until the client reports its
+// terminal type, refuses to, or timeout passes. Other input is discarded.
+func (t *TelnetIO) awaitTerminalType(timeout time.Duration) {
+	conn := t.Session.Conn
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return
+	}
+	defer conn.SetReadDeadline(time.Time{})
+
+	for t.Session.TerminalType == "" && !t.ttypeRefused {
+		b, err := t.Reader.ReadByte()
+		if err != nil {
+			return
+		}
+		if b == iac {
+			t.handleTelnetCommand()
+		}
+	}
+}
+
+// handleSubnegotiation reads an IAC SB ... IAC SE block after the SB byte
+func (t *TelnetIO) handleSubnegotiation() {
+	option, err := t.Reader.ReadByte()
+	if err != nil {
+		return
+	}
+
+	var data []byte
+	for {
+		b, err := t.Reader.ReadByte()
+		if err != nil {
+			return
+		}
+		if b == iac {
+			next, err := t.Reader.ReadByte()
+			if err != nil || next == se {
+				break
+			}
+			// IAC IAC is an escaped 255 data byte
+		}
+		data = append(data, b)
+	}
+
+	switch option {
+	case optNAWS:
+		t.handleNAWSSubnegotiation(data)
+	case optTTYPE:
+		if len(data) > 1 && data[0] == ttypeIS && t.Session != nil && t.Session.TerminalType == "" {
+			t.Session.TerminalType = strings.TrimSpace(string(data[1:]))
+		}
+	}
+}
+
+// requestTerminalType answers a client's WILL TTYPE by asking for its name
+func (t *TelnetIO) requestTerminalType() {
+	t.writeRaw(string([]byte{iac, sb, optTTYPE, ttypeSEND, iac, se}))
+}
+
+var (
+	clearScreenPattern = regexp.MustCompile(`\x1b\[[0-9]*J`)
+	escapePattern      = regexp.MustCompile(`\x1b(\[[0-9;?!]*[ -/]*[@-~]|[^\[])`)
+)
+
+// cp437ASCII folds CP437 0x80-0xAF to their closest ASCII letters
+const cp437ASCII = "CueaaaaceeeiiiAAEaAooouuyOUcLYPfaiounNao?--??!<>"
+
+// EncodeASCII reduces CP437 text with ANSI sequences to plain ASCII for
+// terminals without ANSI support: escape sequences are dropped, a clear
+// screen becomes a blank line, and line drawing becomes +, - and |.
+func EncodeASCII(text string) string {
+	text = clearScreenPattern.ReplaceAllString(text, "\r\n")
+	text = escapePattern.ReplaceAllString(text, "")
+
+	out := []byte(text)
+	for i, b := range out {
+		if b < 0x80 {
+			continue
+		}
+		switch {
+		case b < 0xB0:
+			out[i] = cp437ASCII[b-0x80]
+		case b <= 0xB2, b >= 0xDB && b <= 0xDF:
+			out[i] = '#'
+		case b == 0xB3 || b == 0xBA:
+			out[i] = '|'
+		case b == 0xC4 || b == 0xCD:
+			out[i] = '-'
+		case b <= 0xDA:
+			out[i] = '+'
+		case b == 0xF9 || b == 0xFA:
+			out[i] = '.'
+		case b == 0xFE:
+			out[i] = '*'
+		case b == 0xFF:
+			out[i] = ' '
+		default:
+			out[i] = '?'
+		}
+	}
+	return string(out)
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
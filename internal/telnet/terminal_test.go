@@ -0,0 +1,154 @@
+package telnet
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/ui"
+)
+
+// fakeClient describes how a simulated terminal answers the handshake
+type fakeClient struct {
+	answerCPR  bool   // Reply to ESC[6n
+	probeWidth int    // Columns the UTF-8 probe advances the cursor
+	ttype      string // Name sent for TTYPE; empty refuses TTYPE
+	rip        bool   // Reply to ESC[! with a RIPscrip version
+}
+
+func (c fakeClient) connect(t *testing.T) (*TelnetIO, func()) {
+	t.Helper()
+	server, client := net.Pipe()
+	session := &config.TelnetSession{Conn: server}
+	tio := &TelnetIO{Reader: bufio.NewReader(server), Writer: bufio.NewWriter(server), Session: session}
+
+	// net.Pipe writes block until read, so replies are sent from their own goroutines
+	send := func(data []byte) { go client.Write(data) }
+	go func() {
+		if c.ttype != "" {
+			send([]byte{iac, will, optTTYPE})
+		} else {
+			send([]byte{iac, wont, optTTYPE})
+		}
+		buf := make([]byte, 256)
+		var pending []byte
+		for {
+			n, err := client.Read(buf)
+			if err != nil {
+				return
+			}
+			pending = append(pending, buf[:n]...)
+			// Terminals answer queries in order, so both replies go in one write
+			var reply []byte
+			if bytes.Contains(pending, []byte(ripQuery)) && c.rip {
+				reply = append(reply, "RIPSCRIP015400"...)
+			}
+			if bytes.Contains(pending, []byte("\x1b[6n")) && c.answerCPR {
+				reply = append(reply, "\x1b[5;"+strconv.Itoa(1+c.probeWidth)+"R"...)
+			}
+			if len(reply) > 0 {
+				send(reply)
+			}
+			if bytes.Contains(pending, []byte{iac, sb, optTTYPE, ttypeSEND, iac, se}) {
+				send(append(append([]byte{iac, sb, optTTYPE, ttypeIS}, c.ttype...), iac, se))
+			}
+			if bytes.Contains(pending, []byte("\x1b[6n")) {
+				pending = nil
+			}
+		}
+	}()
+	return tio, func() { server.Close(); client.Close() }
+}
+
+func TestDetectTerminal(t *testing.T) {
+	cases := []struct {
+		name   string
+		client fakeClient
+		want   TerminalInfo
+	}{
+		{"utf8 ansi", fakeClient{answerCPR: true, probeWidth: 1, ttype: "xterm-256color"},
+			TerminalInfo{Emulation: ui.EmulationANSI, Encoding: config.EncodingUTF8, TerminalType: "xterm-256color", Answered: true}},
+		{"syncterm", fakeClient{answerCPR: true, probeWidth: 3, ttype: "syncterm"},
+			TerminalInfo{Emulation: ui.EmulationANSI, Encoding: config.EncodingCP437, TerminalType: "syncterm", ICEColors: true, Answered: true}},
+		{"rip", fakeClient{answerCPR: true, probeWidth: 3, rip: true},
+			TerminalInfo{Emulation: ui.EmulationRIP, Encoding: config.EncodingCP437, Answered: true}},
+		{"ttype only", fakeClient{ttype: "ANSI"},
+			TerminalInfo{Emulation: ui.EmulationANSI, TerminalType: "ANSI"}},
+		{"dumb", fakeClient{ttype: "dumb"},
+			TerminalInfo{Emulation: ui.EmulationASCII, TerminalType: "dumb"}},
+		{"silent", fakeClient{},
+			TerminalInfo{Emulation: ui.EmulationASCII}},
+	}
+	for _, c := range cases {
+		tio, done := c.client.connect(t)
+		got := tio.DetectTerminal(100 * time.Millisecond)
+		done()
+		if got != c.want {
+			t.Fatalf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNAWSSubnegotiation(t *testing.T) {
+	session := &config.TelnetSession{}
+	data := []byte{iac, sb, optNAWS, 0, 60, 0, 20, iac, se, 'x'}
+	tio := &TelnetIO{Reader: bufio.NewReader(bytes.NewReader(data)), Writer: bufio.NewWriter(&bytes.Buffer{}), Session: session}
+	key, err := tio.GetKeyPress()
+	if err != nil || key != 'x' {
+		t.Fatalf("GetKeyPress = %q, %v", key, err)
+	}
+	if session.Width != 60 || session.Height != 20 {
+		t.Fatalf("size = %dx%d, want 60x20", session.Width, session.Height)
+	}
+}
+
+func TestEncodeASCII(t *testing.T) {
+	got := EncodeASCII("\x1b[2J\x1b[1;37m\xda\xc4\xbf\x1b[0m \x82t\x82 \xdb\x1b[10;5H!")
+	if want := "\r\n+-+ ete #!"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	var out bytes.Buffer
+	tio := &TelnetIO{Writer: bufio.NewWriter(&out), Session: &config.TelnetSession{Emulation: ui.EmulationASCII}}
+	tio.Print(ui.Ansi.Cyan + "\xb3 Menu")
+	if out.String() != "| Menu" {
+		t.Fatalf("ASCII session got %q", out.String())
+	}
+}
+
+func TestApplyPreferences(t *testing.T) {
+	session := &config.TelnetSession{Emulation: ui.EmulationANSI, Encoding: config.EncodingCP437, Width: 80, Height: 24}
+	ApplyPreferences(session, map[string]string{
+		config.EncodingPreference:  config.EncodingUTF8,
+		config.EmulationPreference: "ASCII",
+		config.ICEColorsPreference: "on",
+		config.WidthPreference:     "132",
+		config.HeightPreference:    "5",
+	})
+	if session.Encoding != config.EncodingUTF8 || session.Emulation != ui.EmulationASCII || !session.ICEColors || session.Width != 132 || session.Height != 24 {
+		t.Fatalf("unexpected session %+v", session)
+	}
+
+	ApplyPreferences(session, map[string]string{config.EmulationPreference: "auto"})
+	if session.Emulation != ui.EmulationASCII {
+		t.Fatalf("auto should keep the current emulation")
+	}
+}
+
+func TestNAWSKeepsSizeOverride(t *testing.T) {
+	session := &config.TelnetSession{Width: 80, Height: 24}
+	ApplyPreferences(session, map[string]string{config.WidthPreference: "40"})
+
+	data := []byte{iac, sb, optNAWS, 0, 60, 0, 20, iac, se, 'x'}
+	tio := &TelnetIO{Reader: bufio.NewReader(bytes.NewReader(data)), Writer: bufio.NewWriter(&bytes.Buffer{}), Session: session}
+	if _, err := tio.GetKeyPress(); err != nil {
+		t.Fatalf("GetKeyPress error: %v", err)
+	}
+	if session.Width != 40 || session.Height != 20 {
+		t.Fatalf("size = %dx%d, want the 40 column override and the reported 20 rows", session.Width, session.Height)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/mattn/go-isatty"
 	"github.com/robbiew/retrograde/internal/config"
 	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/logging"
 	"github.com/robbiew/retrograde/internal/ui"
 )
 
@@ -85,11 +86,17 @@ const (
 	SecurityLevelsMode                             // Security levels management interface
 	ConferenceManagementMode                       // Conference management interface
 	AreaManagementMode                             // Message area management interface
+	EventManagementMode                            // Timed event management interface
+	SequenceManagementMode                         // Logon/logoff sequence management interface
+	VotingManagementMode                           // Voting topic management interface
+	VotingChoicesMode                              // Choices of the selected voting topic
+	ValidationQueueMode                            // New users waiting for validation
 	MenuManagementMode                             // Menu management interface
 	MenuModifyMode                                 // Menu modification interface (command list)
 	MenuCommandReorderMode                         // Selecting new position for a menu command
 	MenuEditDataMode                               // Edit menu data modal
 	MenuEditCommandMode                            // Edit command modal
+	ThemeArtMode                                   // Theme art browser
 	SavePrompt                                     // Confirming save on exit
 	SaveChangesPrompt                              // Prompt to save changes when exiting edit modal
 	DeleteConfirmPrompt                            // Confirm deletion
@@ -123,9 +130,25 @@ type Model struct {
 	// Message area management list
 	areaListUI list.Model
 
+	// Timed event management list
+	eventListUI list.Model
+
+	// Logon/logoff sequence step list
+	sequenceListUI list.Model
+
+	// Voting topic and choice lists
+	votingListUI list.Model
+	choiceListUI list.Model
+
+	// New user validation queue
+	validationListUI list.Model
+
 	// Menu management list
 	menuListUI list.Model
 
+	// Theme art browser list
+	themeArtUI list.Model
+
 	// Modal form state
 	modalFields      []SubmenuItem // All fields in the current section
 	modalFieldIndex  int           // Currently selected field in modal
@@ -158,6 +181,31 @@ type Model struct {
 	editingArea *database.MessageArea  // Currently editing message area
 	areaIsNew   bool                   // Track if editing area is new
 
+	// Timed event management state
+	eventList    []database.TimedEvent // List of timed events for management
+	editingEvent *database.TimedEvent  // Currently editing timed event
+	eventIsNew   bool                  // Track if editing event is new
+
+	// Logon/logoff sequence management state
+	sequenceName  string                  // database.SequenceLogon or SequenceLogoff
+	sequenceSteps []database.SequenceStep // Steps of the sequence being managed
+	editingStep   *database.SequenceStep  // Currently editing step
+	stepIsNew     bool                    // Track if editing step is new
+
+	// Voting booth management state
+	votingTopics  []database.VotingTopic  // List of voting topics for management
+	votingTopic   database.VotingTopic    // Topic whose choices are being managed
+	votingChoices []database.VotingChoice // Choices of votingTopic
+	editingTopic  *database.VotingTopic   // Currently editing topic
+	topicIsNew    bool                    // Track if editing topic is new
+	editingChoice *database.VotingChoice  // Currently editing choice
+	choiceIsNew   bool                    // Track if editing choice is new
+
+	// New user validation state
+	validations       []database.ValidationRecord // Users waiting for validation
+	upgradeLevels     []SelectOption              // Levels offered while choosing an upgrade; nil otherwise
+	upgradeLevelIndex int                         // Selected entry of upgradeLevels
+
 	// Menu management state
 	menuList         []database.Menu        // List of menus for management
 	menuCommandsList []database.MenuCommand // List of commands for current menu
@@ -421,6 +469,18 @@ func (i securityLevelListItem) FilterValue() string {
 	return i.securityLevel.Name
 }
 
+// themeArtListItem implements list.Item interface for theme art files
+type themeArtListItem struct {
+	name     string
+	sauce    *ui.Sauce
+	icyDraw  bool // Icy Draw document rather than ANSI text
+	readFail bool
+}
+
+func (i themeArtListItem) FilterValue() string {
+	return i.name + " " + i.sauce.Credits()
+}
+
 // menuListItem implements list.Item interface for menu items
 type menuListItem struct {
 	menu         database.Menu
@@ -548,6 +608,304 @@ func (d conferenceDelegate) Render(w io.Writer, m list.Model, index int, listIte
 	fmt.Fprint(w, str)
 }
 
+// timedEventListItem implements list.Item for timed events
+type timedEventListItem struct {
+	event database.TimedEvent
+}
+
+func (i timedEventListItem) FilterValue() string {
+	return i.event.Name
+}
+
+// timedEventDelegate controls timed event list presentation
+type timedEventDelegate struct {
+	maxWidth int
+}
+
+func (d timedEventDelegate) Height() int                             { return 1 }
+func (d timedEventDelegate) Spacing() int                            { return 0 }
+func (d timedEventDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d timedEventDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(timedEventListItem)
+	if !ok {
+		return
+	}
+
+	var str string
+	isSelected := index == m.Index()
+
+	enabled := "No"
+	if item.event.Enabled {
+		enabled = "Yes"
+	}
+
+	itemText := fmt.Sprintf(" %-20.20s %-18.18s %-8s %-3s", item.event.Name, item.event.Schedule, item.event.Action, enabled)
+
+	if len(ui.StripANSI(itemText)) > d.maxWidth {
+		itemText = ui.TruncateWithPipeCodes(itemText, d.maxWidth-3)
+	}
+
+	padding := ""
+	if len(itemText) < d.maxWidth {
+		padding = strings.Repeat(" ", d.maxWidth-len(itemText))
+	}
+
+	if isSelected {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextBright)).
+			Background(lipgloss.Color(ColorAccent)).
+			Bold(true).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	} else {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextNormal)).
+			Background(lipgloss.Color(ColorBgMedium)).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	}
+
+	fmt.Fprint(w, str)
+}
+
+// sequenceStepListItem implements list.Item for logon/logoff sequence steps
+type sequenceStepListItem struct {
+	step database.SequenceStep
+}
+
+func (i sequenceStepListItem) FilterValue() string {
+	return i.step.StepType + " " + i.step.Options
+}
+
+// sequenceStepDelegate controls sequence step list presentation
+type sequenceStepDelegate struct {
+	maxWidth int
+}
+
+func (d sequenceStepDelegate) Height() int                             { return 1 }
+func (d sequenceStepDelegate) Spacing() int                            { return 0 }
+func (d sequenceStepDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d sequenceStepDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(sequenceStepListItem)
+	if !ok {
+		return
+	}
+
+	var str string
+	isSelected := index == m.Index()
+
+	daily := ""
+	if item.step.OncePerDay {
+		daily = "Yes"
+	}
+	enabled := "No"
+	if item.step.Enabled {
+		enabled = "Yes"
+	}
+
+	itemText := fmt.Sprintf(" %2d %-12s %-18.18s %-5.5s %-5s %-3s", index+1, sequenceStepLabel(item.step.StepType), item.step.Options, item.step.ACS, daily, enabled)
+
+	if len(ui.StripANSI(itemText)) > d.maxWidth {
+		itemText = ui.TruncateWithPipeCodes(itemText, d.maxWidth-3)
+	}
+
+	padding := ""
+	if len(itemText) < d.maxWidth {
+		padding = strings.Repeat(" ", d.maxWidth-len(itemText))
+	}
+
+	if isSelected {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextBright)).
+			Background(lipgloss.Color(ColorAccent)).
+			Bold(true).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	} else {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextNormal)).
+			Background(lipgloss.Color(ColorBgMedium)).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	}
+
+	fmt.Fprint(w, str)
+}
+
+// votingTopicListItem implements list.Item for voting topics
+type votingTopicListItem struct {
+	topic database.VotingTopic
+}
+
+func (i votingTopicListItem) FilterValue() string {
+	return i.topic.Title
+}
+
+// votingTopicDelegate controls voting topic list presentation
+type votingTopicDelegate struct {
+	maxWidth int
+}
+
+func (d votingTopicDelegate) Height() int                             { return 1 }
+func (d votingTopicDelegate) Spacing() int                            { return 0 }
+func (d votingTopicDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d votingTopicDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(votingTopicListItem)
+	if !ok {
+		return
+	}
+
+	var str string
+	isSelected := index == m.Index()
+
+	open := "No"
+	if item.topic.Active {
+		open = "Yes"
+	}
+
+	itemText := fmt.Sprintf(" %2d %-34.34s %-5.5s %-5.5s %-3s", index+1, item.topic.Title, item.topic.VoteACS, item.topic.AddACS, open)
+
+	if len(ui.StripANSI(itemText)) > d.maxWidth {
+		itemText = ui.TruncateWithPipeCodes(itemText, d.maxWidth-3)
+	}
+
+	padding := ""
+	if len(itemText) < d.maxWidth {
+		padding = strings.Repeat(" ", d.maxWidth-len(itemText))
+	}
+
+	if isSelected {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextBright)).
+			Background(lipgloss.Color(ColorAccent)).
+			Bold(true).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	} else {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextNormal)).
+			Background(lipgloss.Color(ColorBgMedium)).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	}
+
+	fmt.Fprint(w, str)
+}
+
+// votingChoiceListItem implements list.Item for the choices of a topic
+type votingChoiceListItem struct {
+	choice database.VotingChoice
+}
+
+func (i votingChoiceListItem) FilterValue() string {
+	return i.choice.Text
+}
+
+// votingChoiceDelegate controls voting choice list presentation
+type votingChoiceDelegate struct {
+	maxWidth int
+}
+
+func (d votingChoiceDelegate) Height() int                             { return 1 }
+func (d votingChoiceDelegate) Spacing() int                            { return 0 }
+func (d votingChoiceDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d votingChoiceDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(votingChoiceListItem)
+	if !ok {
+		return
+	}
+
+	var str string
+	isSelected := index == m.Index()
+
+	itemText := fmt.Sprintf(" %2d %-36.36s %-8.8s %5d", index+1, item.choice.Text, item.choice.AddedBy, item.choice.Votes)
+
+	if len(ui.StripANSI(itemText)) > d.maxWidth {
+		itemText = ui.TruncateWithPipeCodes(itemText, d.maxWidth-3)
+	}
+
+	padding := ""
+	if len(itemText) < d.maxWidth {
+		padding = strings.Repeat(" ", d.maxWidth-len(itemText))
+	}
+
+	if isSelected {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextBright)).
+			Background(lipgloss.Color(ColorAccent)).
+			Bold(true).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	} else {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextNormal)).
+			Background(lipgloss.Color(ColorBgMedium)).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	}
+
+	fmt.Fprint(w, str)
+}
+
+// validationListItem implements list.Item for users waiting for validation
+type validationListItem struct {
+	validation database.ValidationRecord
+}
+
+func (i validationListItem) FilterValue() string {
+	return i.validation.Username
+}
+
+// validationDelegate controls validation queue presentation
+type validationDelegate struct {
+	maxWidth int
+}
+
+func (d validationDelegate) Height() int                             { return 1 }
+func (d validationDelegate) Spacing() int                            { return 0 }
+func (d validationDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d validationDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(validationListItem)
+	if !ok {
+		return
+	}
+
+	var str string
+	isSelected := index == m.Index()
+
+	requested := item.validation.RequestedAt
+	if at, err := time.Parse(time.RFC3339, requested); err == nil {
+		requested = at.Local().Format("01/02/06")
+	}
+	itemText := fmt.Sprintf(" %-15.15s %-22.22s %5d %-8s", item.validation.Username, item.validation.Location, item.validation.Level, requested)
+
+	if len(itemText) > d.maxWidth {
+		itemText = itemText[:d.maxWidth-3] + "..."
+	}
+
+	padding := ""
+	if len(itemText) < d.maxWidth {
+		padding = strings.Repeat(" ", d.maxWidth-len(itemText))
+	}
+
+	if isSelected {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextBright)).
+			Background(lipgloss.Color(ColorAccent)).
+			Bold(true).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	} else {
+		style := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextNormal)).
+			Background(lipgloss.Color(ColorBgMedium)).
+			Width(d.maxWidth)
+		str = style.Render(itemText + padding)
+	}
+
+	fmt.Fprint(w, str)
+}
+
 // messageAreaListItem implements list.Item for message areas
 type messageAreaListItem struct {
 	area database.MessageArea
@@ -619,6 +977,71 @@ func (d messageAreaDelegate) Render(w io.Writer, m list.Model, index int, listIt
 	fmt.Fprint(w, str)
 }
 
+// themeArtDelegate implements list.ItemDelegate for theme art rendering
+type themeArtDelegate struct {
+	maxWidth int
+}
+
+func (d themeArtDelegate) Height() int                             { return 1 }
+func (d themeArtDelegate) Spacing() int                            { return 0 }
+func (d themeArtDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d themeArtDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(themeArtListItem)
+	if !ok {
+		return
+	}
+
+	title, author, size, ice := "", "", "", ""
+	switch {
+	case item.readFail:
+		title = "(unreadable)"
+	case item.icyDraw:
+		title = "(Icy Draw document)"
+	case item.sauce == nil:
+		title = "(no SAUCE)"
+	default:
+		title = item.sauce.Title
+		author = item.sauce.Author
+		if item.sauce.Group != "" {
+			author = strings.TrimPrefix(author+"/"+item.sauce.Group, "/")
+		}
+		if item.sauce.Width() > 0 {
+			size = fmt.Sprintf("%dx%d", item.sauce.Width(), item.sauce.Height())
+		}
+		if item.sauce.ICEColors() {
+			ice = "Yes"
+		}
+	}
+
+	// Format with fixed-width columns to match headers
+	// Columns are padded by rune count since SAUCE text may be non-ASCII
+	itemText := fmt.Sprintf(" %-20s %-22s %-18s %-7s %-3s",
+		truncateText(item.name, 20), truncateText(title, 22), truncateText(author, 18), size, ice)
+
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorTextNormal)).
+		Background(lipgloss.Color(ColorBgMedium)).
+		Width(d.maxWidth)
+	if index == m.Index() {
+		style = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextBright)).
+			Background(lipgloss.Color(ColorAccent)).
+			Bold(true).
+			Width(d.maxWidth)
+	}
+
+	fmt.Fprint(w, style.Render(itemText))
+}
+
+// truncateText shortens s to at most n runes, marking the cut with "..."
+func truncateText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
+
 // securityLevelDelegate implements list.ItemDelegate for custom security level rendering
 type securityLevelDelegate struct {
 	maxWidth int
@@ -888,6 +1311,9 @@ func (m Model) renderPersistentHeader() string {
 func RunConfigEditorTUI(cfg *config.Config) error {
 	m := InitialModelV2(cfg)
 
+	// Validation decisions are written to the BBS logs
+	logging.SetLogDirectory(cfg.Configuration.Paths.Logs)
+
 	// Use alt screen only when running on an interactive TTY to avoid
 	// Windows "making raw" errors when stdin/stdout are not consoles.
 	var p *tea.Program
@@ -2,10 +2,15 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/ui"
 )
 
 // loadUsers loads all users from the database
@@ -196,6 +201,176 @@ func (m *Model) loadConferences() error {
 	return nil
 }
 
+// loadTimedEvents loads all timed events from the database
+func (m *Model) loadTimedEvents() error {
+	if m.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	events, err := m.db.GetAllTimedEvents()
+	if err != nil {
+		return fmt.Errorf("failed to get timed events: %w", err)
+	}
+
+	m.eventList = events
+
+	var items []list.Item
+	for _, event := range events {
+		items = append(items, timedEventListItem{event: event})
+	}
+
+	maxWidth := 55
+	eventList := list.New(items, timedEventDelegate{maxWidth: maxWidth}, maxWidth, 15)
+	eventList.Title = ""
+	eventList.SetShowStatusBar(false)
+	eventList.SetFilteringEnabled(true)
+	eventList.SetShowHelp(false)
+	eventList.SetShowPagination(true)
+
+	eventList.Styles.Title = lipgloss.NewStyle()
+	eventList.Styles.PaginationStyle = lipgloss.NewStyle()
+	eventList.Styles.HelpStyle = lipgloss.NewStyle()
+
+	m.eventListUI = eventList
+	return nil
+}
+
+// loadSequenceSteps loads the steps of the current logon or logoff sequence
+func (m *Model) loadSequenceSteps() error {
+	if m.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	steps, err := m.db.GetSequenceSteps(m.sequenceName)
+	if err != nil {
+		return fmt.Errorf("failed to get sequence steps: %w", err)
+	}
+
+	m.sequenceSteps = steps
+
+	var items []list.Item
+	for _, step := range steps {
+		items = append(items, sequenceStepListItem{step: step})
+	}
+
+	maxWidth := 55
+	stepList := list.New(items, sequenceStepDelegate{maxWidth: maxWidth}, maxWidth, 15)
+	stepList.Title = ""
+	stepList.SetShowStatusBar(false)
+	stepList.SetFilteringEnabled(false)
+	stepList.SetShowHelp(false)
+	stepList.SetShowPagination(true)
+
+	stepList.Styles.Title = lipgloss.NewStyle()
+	stepList.Styles.PaginationStyle = lipgloss.NewStyle()
+	stepList.Styles.HelpStyle = lipgloss.NewStyle()
+
+	m.sequenceListUI = stepList
+	return nil
+}
+
+// loadVotingTopics loads all voting topics from the database
+func (m *Model) loadVotingTopics() error {
+	if m.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	topics, err := m.db.GetAllVotingTopics()
+	if err != nil {
+		return fmt.Errorf("failed to get voting topics: %w", err)
+	}
+
+	m.votingTopics = topics
+
+	var items []list.Item
+	for _, topic := range topics {
+		items = append(items, votingTopicListItem{topic: topic})
+	}
+
+	maxWidth := 55
+	topicList := list.New(items, votingTopicDelegate{maxWidth: maxWidth}, maxWidth, 15)
+	topicList.Title = ""
+	topicList.SetShowStatusBar(false)
+	topicList.SetFilteringEnabled(true)
+	topicList.SetShowHelp(false)
+	topicList.SetShowPagination(true)
+
+	topicList.Styles.Title = lipgloss.NewStyle()
+	topicList.Styles.PaginationStyle = lipgloss.NewStyle()
+	topicList.Styles.HelpStyle = lipgloss.NewStyle()
+
+	m.votingListUI = topicList
+	return nil
+}
+
+// loadVotingChoices loads the choices of the current voting topic
+func (m *Model) loadVotingChoices() error {
+	if m.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	choices, err := m.db.GetVotingChoices(m.votingTopic.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get voting choices: %w", err)
+	}
+
+	m.votingChoices = choices
+
+	var items []list.Item
+	for _, choice := range choices {
+		items = append(items, votingChoiceListItem{choice: choice})
+	}
+
+	maxWidth := 55
+	choiceList := list.New(items, votingChoiceDelegate{maxWidth: maxWidth}, maxWidth, 15)
+	choiceList.Title = ""
+	choiceList.SetShowStatusBar(false)
+	choiceList.SetFilteringEnabled(false)
+	choiceList.SetShowHelp(false)
+	choiceList.SetShowPagination(true)
+
+	choiceList.Styles.Title = lipgloss.NewStyle()
+	choiceList.Styles.PaginationStyle = lipgloss.NewStyle()
+	choiceList.Styles.HelpStyle = lipgloss.NewStyle()
+
+	m.choiceListUI = choiceList
+	return nil
+}
+
+// loadValidations loads the users waiting for validation
+func (m *Model) loadValidations() error {
+	if m.db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	validations, err := m.db.GetPendingValidations()
+	if err != nil {
+		return fmt.Errorf("failed to get validations: %w", err)
+	}
+
+	m.validations = validations
+
+	var items []list.Item
+	for _, validation := range validations {
+		items = append(items, validationListItem{validation: validation})
+	}
+
+	maxWidth := 55
+	validationList := list.New(items, validationDelegate{maxWidth: maxWidth}, maxWidth, 15)
+	validationList.Title = ""
+	validationList.SetShowStatusBar(false)
+	validationList.SetFilteringEnabled(false)
+	validationList.SetShowHelp(false)
+	validationList.SetShowPagination(true)
+
+	validationList.Styles.Title = lipgloss.NewStyle()
+	validationList.Styles.PaginationStyle = lipgloss.NewStyle()
+	validationList.Styles.HelpStyle = lipgloss.NewStyle()
+
+	m.validationListUI = validationList
+	return nil
+}
+
 // loadMessageAreas loads all message areas from the database
 func (m *Model) loadMessageAreas() error {
 	if m.db == nil {
@@ -238,3 +413,57 @@ func (m *Model) loadMessageAreas() error {
 	m.areaListUI = areasList
 	return nil
 }
+
+// loadThemeArt reads the SAUCE records of the art in the theme directory and
+// its text subdirectory
+func (m *Model) loadThemeArt() error {
+	themeDir := strings.TrimSpace(m.config.Configuration.Paths.Themes)
+	if themeDir == "" {
+		return fmt.Errorf("theme path not configured")
+	}
+
+	var items []list.Item
+	for _, dir := range []string{themeDir, filepath.Join(themeDir, "text")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if dir == themeDir {
+				return fmt.Errorf("failed to read theme directory: %w", err)
+			}
+			continue
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return strings.ToLower(entries[i].Name()) < strings.ToLower(entries[j].Name())
+		})
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			name, _ := filepath.Rel(themeDir, filepath.Join(dir, entry.Name()))
+			item := themeArtListItem{name: name}
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				item.readFail = true
+			} else if ui.IsIcyDrawDocument(data) {
+				item.icyDraw = true
+			} else {
+				item.sauce, _ = ui.ParseSauce(data)
+			}
+			items = append(items, item)
+		}
+	}
+
+	maxWidth := 76
+	themeArtList := list.New(items, themeArtDelegate{maxWidth: maxWidth}, maxWidth, 15)
+	themeArtList.Title = ""
+	themeArtList.SetShowStatusBar(false)
+	themeArtList.SetFilteringEnabled(true)
+	themeArtList.SetShowHelp(false)
+	themeArtList.SetShowPagination(true)
+
+	themeArtList.Styles.Title = lipgloss.NewStyle()
+	themeArtList.Styles.PaginationStyle = lipgloss.NewStyle()
+	themeArtList.Styles.HelpStyle = lipgloss.NewStyle()
+
+	m.themeArtUI = themeArtList
+	return nil
+}
@@ -3,7 +3,9 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/robbiew/retrograde/internal/auth"
 	"github.com/robbiew/retrograde/internal/config"
 )
 
@@ -250,6 +252,402 @@ func configurationMenu(cfg *config.Config) MenuCategory {
 					},
 				},
 			},
+			{
+				ID:       "sysop-chat",
+				Label:    "SysOp Chat",
+				ItemType: SectionHeader,
+				SubItems: []SubmenuItem{
+					{
+						ID:       "page-start",
+						Label:    "Page Start",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.sysop_chat.page_start",
+							Label:     "Page Start",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.SysOpChat.PageStart },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.SysOpChat.PageStart = v.(string)
+									return nil
+								},
+							},
+							HelpText:   "Time (HH:MM) you start taking pages; same as Page End means always",
+							Validation: validateClockTime,
+						},
+					},
+					{
+						ID:       "page-end",
+						Label:    "Page End",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.sysop_chat.page_end",
+							Label:     "Page End",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.SysOpChat.PageEnd },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.SysOpChat.PageEnd = v.(string)
+									return nil
+								},
+							},
+							HelpText:   "Time (HH:MM) you stop taking pages; may wrap past midnight",
+							Validation: validateClockTime,
+						},
+					},
+					{
+						ID:       "page-seconds",
+						Label:    "Page Seconds",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.sysop_chat.page_seconds",
+							Label:     "Page Seconds",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.SysOpChat.PageSeconds },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.SysOpChat.PageSeconds = v.(int)
+									return nil
+								},
+							},
+							HelpText: "How long a page rings before offering feedback",
+							Validation: func(v interface{}) error {
+								if v.(int) <= 0 {
+									return fmt.Errorf("page time must be positive")
+								}
+								return nil
+							},
+						},
+					},
+					{
+						ID:       "console-port",
+						Label:    "Console Port",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.sysop_chat.console_port",
+							Label:     "Console Port",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.SysOpChat.ConsolePort },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.SysOpChat.ConsolePort = v.(int)
+									return nil
+								},
+							},
+							HelpText: "Loopback port for the sysop console (0 disables)",
+							Validation: func(v interface{}) error {
+								if port := v.(int); port < 0 || port > 65535 {
+									return fmt.Errorf("port must be between 0 and 65535")
+								}
+								return nil
+							},
+						},
+					},
+					{
+						ID:       "feedback-area",
+						Label:    "Feedback Area",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.sysop_chat.feedback_area",
+							Label:     "Feedback Area",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.SysOpChat.FeedbackArea },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.SysOpChat.FeedbackArea = v.(string)
+									return nil
+								},
+							},
+							HelpText: "Local message area file that receives feedback (default: first local area)",
+						},
+					},
+				},
+			},
+			{
+				ID:       "logoff",
+				Label:    "Logoff",
+				ItemType: SectionHeader,
+				SubItems: []SubmenuItem{
+					{
+						ID:       "logoff-display-file",
+						Label:    "Display File",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.logoff.display_file",
+							Label:     "Display File",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Logoff.DisplayFile },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Logoff.DisplayFile = v.(string)
+									return nil
+								},
+							},
+							HelpText: "Display file shown by G and HC (blank for a plain goodbye)",
+						},
+					},
+					{
+						ID:       "logoff-hangup-delay",
+						Label:    "Hangup Delay",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.logoff.hangup_delay",
+							Label:     "Hangup Delay",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Logoff.HangupDelay },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Logoff.HangupDelay = v.(int)
+									return nil
+								},
+							},
+							HelpText: "Seconds the goodbye stays on screen before hanging up",
+							Validation: func(v interface{}) error {
+								if delay := v.(int); delay < 0 || delay > 30 {
+									return fmt.Errorf("delay must be between 0 and 30 seconds")
+								}
+								return nil
+							},
+						},
+					},
+					{
+						ID:       "logoff-update-stats",
+						Label:    "Update Stats",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.logoff.update_stats",
+							Label:     "Update Stats",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Logoff.UpdateStats },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Logoff.UpdateStats = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Count time online (Top 10, caller and daily stats) and record the last logoff",
+						},
+					},
+					{
+						ID:       "logoff-flush-last-reads",
+						Label:    "Save Last Read",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.logoff.flush_last_reads",
+							Label:     "Save Last Read",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Logoff.FlushLastReads },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Logoff.FlushLastReads = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Save message pointers advanced during the call",
+						},
+					},
+				},
+			},
+			{
+				ID:       "social",
+				Label:    "Automessage & Wall",
+				ItemType: SectionHeader,
+				SubItems: []SubmenuItem{
+					{
+						ID:       "social-automessage-acs",
+						Label:    "Automessage ACS",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.social.automessage_acs",
+							Label:     "Automessage ACS",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Social.AutomessageACS },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Social.AutomessageACS = strings.TrimSpace(v.(string))
+									return nil
+								},
+							},
+							HelpText: "ACS needed to write the automessage with UW (blank for everyone)",
+						},
+					},
+					{
+						ID:       "social-automessage-lines",
+						Label:    "Automessage Lines",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.social.automessage_lines",
+							Label:     "Automessage Lines",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Social.AutomessageLines },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Social.AutomessageLines = v.(int)
+									return nil
+								},
+							},
+							HelpText: "Longest automessage, in lines",
+							Validation: func(v interface{}) error {
+								if n := v.(int); n < 1 || n > 20 {
+									return fmt.Errorf("lines must be between 1 and 20")
+								}
+								return nil
+							},
+						},
+					},
+					{
+						ID:       "social-oneliner-count",
+						Label:    "One-Liners Shown",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.social.oneliner_count",
+							Label:     "One-Liners Shown",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Social.OneLinerCount },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Social.OneLinerCount = v.(int)
+									return nil
+								},
+							},
+							HelpText: "How many one-liners the wall shows",
+							Validation: func(v interface{}) error {
+								if n := v.(int); n < 1 || n > 50 {
+									return fmt.Errorf("count must be between 1 and 50")
+								}
+								return nil
+							},
+						},
+					},
+					{
+						ID:       "social-oneliner-length",
+						Label:    "One-Liner Length",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.social.oneliner_length",
+							Label:     "One-Liner Length",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Social.OneLinerLength },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Social.OneLinerLength = v.(int)
+									return nil
+								},
+							},
+							HelpText: "Longest one-liner a caller can write",
+							Validation: func(v interface{}) error {
+								if n := v.(int); n < 10 || n > 75 {
+									return fmt.Errorf("length must be between 10 and 75")
+								}
+								return nil
+							},
+						},
+					},
+					{
+						ID:       "social-oneliner-acs",
+						Label:    "One-Liner ACS",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.social.oneliner_acs",
+							Label:     "One-Liner ACS",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Social.OneLinerACS },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Social.OneLinerACS = strings.TrimSpace(v.(string))
+									return nil
+								},
+							},
+							HelpText: "ACS needed to add to the one-liner wall (blank for everyone)",
+						},
+					},
+					{
+						ID:       "social-rumor-acs",
+						Label:    "Rumor ACS",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.social.rumor_acs",
+							Label:     "Rumor ACS",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.Social.RumorACS },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.Social.RumorACS = strings.TrimSpace(v.(string))
+									return nil
+								},
+							},
+							HelpText: "ACS needed to add a rumor (blank for everyone)",
+						},
+					},
+				},
+			},
+			{
+				ID:       "timed-events",
+				Label:    "Timed Events",
+				ItemType: SectionHeader,
+				SubItems: []SubmenuItem{
+					{
+						ID:       "events-enabled",
+						Label:    "Enabled",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.events.enabled",
+							Label:     "Enabled",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Events.Enabled },
+								SetValue: func(v interface{}) error {
+									cfg.Events.Enabled = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Run timed events inside the server (Editors > Timed Events)",
+						},
+					},
+					{
+						ID:       "events-backup-path",
+						Label:    "Backup Path",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.events.backup_path",
+							Label:     "Backup Path",
+							ValueType: PathValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Events.BackupPath },
+								SetValue: func(v interface{}) error {
+									cfg.Events.BackupPath = v.(string)
+									return nil
+								},
+							},
+							HelpText: "Directory backup events write to when they name none",
+						},
+					},
+					{
+						ID:       "events-backup-keep",
+						Label:    "Backups Kept",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.events.backup_keep",
+							Label:     "Backups Kept",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Events.BackupKeep },
+								SetValue: func(v interface{}) error {
+									cfg.Events.BackupKeep = v.(int)
+									return nil
+								},
+							},
+							HelpText: "Number of database backups to keep (0 keeps them all)",
+							Validation: func(v interface{}) error {
+								if keep := v.(int); keep < 0 || keep > 365 {
+									return fmt.Errorf("backups kept must be between 0 and 365")
+								}
+								return nil
+							},
+						},
+					},
+				},
+			},
 			{
 				ID:       "new-users",
 				Label:    "New Users",
@@ -344,6 +742,72 @@ func configurationMenu(cfg *config.Config) MenuCategory {
 							HelpText: "Ask for location during registration",
 						},
 					},
+					{
+						ID:       "new-user-level",
+						Label:    "New User Level",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.new_users.security_level",
+							Label:     "New User Level",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.NewUsers.SecurityLevel },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.NewUsers.SecurityLevel = v.(int)
+									return nil
+								},
+							},
+							HelpText: "Security level new accounts are created at",
+							Validation: func(v interface{}) error {
+								if v.(int) < 1 {
+									return fmt.Errorf("security level must be at least 1")
+								}
+								return nil
+							},
+						},
+					},
+					{
+						ID:       "require-validation",
+						Label:    "Validate Users",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.new_users.require_validation",
+							Label:     "Validate Users",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.NewUsers.RequireValidation },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.NewUsers.RequireValidation = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Queue new accounts for validation in User Management",
+						},
+					},
+					{
+						ID:       "validated-level",
+						Label:    "Validated Level",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "config.new_users.validated_level",
+							Label:     "Validated Level",
+							ValueType: IntValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Configuration.NewUsers.ValidatedLevel },
+								SetValue: func(v interface{}) error {
+									cfg.Configuration.NewUsers.ValidatedLevel = v.(int)
+									return nil
+								},
+							},
+							HelpText: "Security level a user is raised to when validated",
+							Validation: func(v interface{}) error {
+								if v.(int) < 1 {
+									return fmt.Errorf("security level must be at least 1")
+								}
+								return nil
+							},
+						},
+					},
 				},
 			},
 			{
@@ -399,14 +863,14 @@ func configurationMenu(cfg *config.Config) MenuCategory {
 								GetValue: func() interface{} { return cfg.Configuration.Auth.PasswordAlgorithm },
 								SetValue: func(v interface{}) error {
 									algo := strings.TrimSpace(v.(string))
-									if algo == "" {
-										return fmt.Errorf("password algorithm cannot be empty")
+									if !auth.SupportedPasswordAlgorithm(algo) {
+										return fmt.Errorf("password algorithm must be %s or %s", auth.PasswordAlgoSHA256, auth.PasswordAlgoPBKDF2)
 									}
 									cfg.Configuration.Auth.PasswordAlgorithm = algo
 									return nil
 								},
 							},
-							HelpText: "Default hashing algorithm for persisted passwords",
+							HelpText: "Hashing algorithm for changed passwords: sha256 or pbkdf2-sha256",
 						},
 					},
 				},
@@ -414,3 +878,11 @@ func configurationMenu(cfg *config.Config) MenuCategory {
 		},
 	}
 }
+
+// validateClockTime checks an HH:MM (24 hour) time of day
+func validateClockTime(v interface{}) error {
+	if _, err := time.Parse("15:04", strings.TrimSpace(v.(string))); err != nil {
+		return fmt.Errorf("use 24 hour HH:MM, e.g. 08:00")
+	}
+	return nil
+}
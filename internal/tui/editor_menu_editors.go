@@ -26,6 +26,26 @@ func editorsMenu() MenuCategory {
 				Label:    "Message Areas",
 				ItemType: ActionItem,
 			},
+			{
+				ID:       "logon-sequence-editor",
+				Label:    "Logon Sequence",
+				ItemType: ActionItem,
+			},
+			{
+				ID:       "logoff-sequence-editor",
+				Label:    "Logoff Sequence",
+				ItemType: ActionItem,
+			},
+			{
+				ID:       "timed-events-editor",
+				Label:    "Timed Events",
+				ItemType: ActionItem,
+			},
+			{
+				ID:       "voting-editor",
+				Label:    "Voting",
+				ItemType: ActionItem,
+			},
 			{
 				ID:       "menu-editor",
 				Label:    "Menus",
@@ -112,9 +112,67 @@ func getAreaTypeOptions() []SelectOption {
 		{Value: "local", Label: "Local", Description: "Local-only message area", Implemented: true},
 		{Value: "echomail", Label: "Echomail", Description: "Network echoed message area", Implemented: true},
 		{Value: "netmail", Label: "Netmail", Description: "Direct network mail", Implemented: true},
+		{Value: "qwknet", Label: "QWKnet", Description: "QWK network conference (QWK/REP exchange)", Implemented: true},
 	}
 }
 
+func getEventActionOptions() []SelectOption {
+	return []SelectOption{
+		{Value: database.EventActionCommand, Label: "Command", Description: "Run an external command (options: command line)", Implemented: true},
+		{Value: database.EventActionPack, Label: "Pack Bases", Description: "Pack every message base", Implemented: true},
+		{Value: database.EventActionNetwork, Label: "Network", Description: "Toss and scan QWKnet mail", Implemented: true},
+		{Value: database.EventActionBackup, Label: "Backup", Description: "Back up the database (options: directory)", Implemented: true},
+		{Value: database.EventActionMessage, Label: "Message", Description: "Post a system message (options: area;subject;text)", Implemented: true},
+	}
+}
+
+// getSequenceStepOptions returns the logon/logoff step types. Steps that run
+// a command key are only implemented once that key is.
+func getSequenceStepOptions() []SelectOption {
+	registry := menu.NewCmdKeyRegistry()
+	keyImplemented := func(key string) bool {
+		def := registry.GetDefinition(key)
+		return def != nil && def.Implemented
+	}
+
+	return []SelectOption{
+		{Value: database.StepDisplayFile, Label: "Display File", Description: "Show a display file and pause (options: file name, -F flags)", Implemented: true},
+		{Value: database.StepLastCallers, Label: "Last Callers", Description: "Show the last callers (runs OL)", Implemented: keyImplemented("OL")},
+		{Value: database.StepOneLiners, Label: "One-Liners", Description: "Show the one-liner wall and offer to add a line", Implemented: true},
+		{Value: database.StepMailCheck, Label: "Mail Check", Description: "Report new private mail", Implemented: true},
+		{Value: database.StepNewscan, Label: "Newscan Prompt", Description: "List areas with new messages and offer to read them (options: question)", Implemented: true},
+		{Value: database.StepAutomessage, Label: "Automessage", Description: "Show the automessage (runs UR)", Implemented: keyImplemented("UR")},
+		{Value: database.StepBulletins, Label: "New Bulletins", Description: "Offer bulletins changed since the last call (options: as OS)", Implemented: true},
+		{Value: database.StepRumor, Label: "Rumor", Description: "Show a random rumor", Implemented: true},
+		{Value: database.StepCommand, Label: "Command", Description: "Run a command key (options: KEY options)", Implemented: true},
+	}
+}
+
+// sequenceStepLabel returns the short name of a step type for lists
+func sequenceStepLabel(stepType string) string {
+	switch stepType {
+	case database.StepDisplayFile:
+		return "Display"
+	case database.StepLastCallers:
+		return "Last Callers"
+	case database.StepOneLiners:
+		return "One-Liners"
+	case database.StepMailCheck:
+		return "Mail Check"
+	case database.StepNewscan:
+		return "Newscan"
+	case database.StepAutomessage:
+		return "Automessage"
+	case database.StepBulletins:
+		return "Bulletins"
+	case database.StepRumor:
+		return "Rumor"
+	case database.StepCommand:
+		return "Command"
+	}
+	return stepType
+}
+
 func (m *Model) getMenuSelectOptions() []SelectOption {
 	menus, err := m.db.GetAllMenus()
 	if err != nil {
@@ -160,3 +218,33 @@ func getMenuDisplayModeOptions() []SelectOption {
 		},
 	}
 }
+
+// getMenuInputModeOptions returns SelectOptions for menu input modes
+func getMenuInputModeOptions() []SelectOption {
+	return []SelectOption{
+		{
+			Value:       database.InputModeHotkey,
+			Label:       "Hotkey",
+			Description: "Run a command on a single key press",
+			Implemented: true,
+		},
+		{
+			Value:       database.InputModeLine,
+			Label:       "Line Input",
+			Description: "Type a command and press Enter",
+			Implemented: true,
+		},
+		{
+			Value:       database.InputModeLightbar,
+			Label:       "Lightbar",
+			Description: "Arrow between commands, Enter to run",
+			Implemented: true,
+		},
+	}
+}
+
+// isMenuTargetCmdKey reports whether a command key takes a menu name as its
+// options (goto and gosub)
+func isMenuTargetCmdKey(cmdKey string) bool {
+	return cmdKey == "-^" || cmdKey == "-/"
+}
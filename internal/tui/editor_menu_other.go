@@ -1,15 +1,59 @@
 package tui
 
 import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/robbiew/retrograde/internal/chat"
 	"github.com/robbiew/retrograde/internal/config"
 )
 
+// consoleExec runs the sysop console in the terminal while the TUI is
+// suspended (see tea.Exec)
+type consoleExec struct {
+	addr   string
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// consoleClosedMsg is sent when the sysop console hands the terminal back
+type consoleClosedMsg struct {
+	err error
+}
+
+func (c *consoleExec) SetStdin(r io.Reader)  { c.stdin = r }
+func (c *consoleExec) SetStdout(w io.Writer) { c.stdout = w }
+func (c *consoleExec) SetStderr(io.Writer)   {}
+
+func (c *consoleExec) Run() error {
+	stdin, ok := c.stdin.(*os.File)
+	if !ok {
+		stdin = os.Stdin
+	}
+	stdout := c.stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	return chat.ConnectConsole(c.addr, stdin, stdout)
+}
+
 func otherMenu(cfg *config.Config) MenuCategory {
 	return MenuCategory{
 		ID:     "other",
 		Label:  "Other",
 		HotKey: 'O',
 		SubItems: []SubmenuItem{
+			{
+				ID:       "sysop-console",
+				Label:    "SysOp Console",
+				ItemType: ActionItem,
+			},
+			{
+				ID:       "theme-art",
+				Label:    "Theme Art",
+				ItemType: ActionItem,
+			},
 			{
 				ID:       "discord-integration",
 				Label:    "Discord Integration",
@@ -107,6 +151,133 @@ func otherMenu(cfg *config.Config) MenuCategory {
 					},
 				},
 			},
+			{
+				ID:       "webhook-integration",
+				Label:    "JSON Webhook",
+				ItemType: SectionHeader,
+				SubItems: []SubmenuItem{
+					{
+						ID:       "webhook-enabled",
+						Label:    "Webhook Enabled",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "other.webhook.enabled",
+							Label:     "Webhook Enabled",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Other.Webhook.Enabled },
+								SetValue: func(v interface{}) error {
+									cfg.Other.Webhook.Enabled = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Post notifications as JSON to the webhook URL",
+						},
+					},
+					{
+						ID:       "webhook-url",
+						Label:    "Webhook URL",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "other.webhook.url",
+							Label:     "Webhook URL",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Other.Webhook.URL },
+								SetValue: func(v interface{}) error {
+									cfg.Other.Webhook.URL = strings.TrimSpace(v.(string))
+									return nil
+								},
+							},
+							HelpText: "URL that receives a JSON POST for each notification",
+						},
+					},
+				},
+			},
+			{
+				ID:       "notifications",
+				Label:    "Notifications",
+				ItemType: SectionHeader,
+				SubItems: []SubmenuItem{
+					{
+						ID:       "notify-new-users",
+						Label:    "New Users",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "other.notify.new_users",
+							Label:     "New Users",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Other.Notify.NewUsers },
+								SetValue: func(v interface{}) error {
+									cfg.Other.Notify.NewUsers = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Announce new user registrations",
+						},
+					},
+					{
+						ID:       "notify-sysop-pages",
+						Label:    "SysOp Pages",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "other.notify.sysop_pages",
+							Label:     "SysOp Pages",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Other.Notify.SysOpPages },
+								SetValue: func(v interface{}) error {
+									cfg.Other.Notify.SysOpPages = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Announce callers paging the sysop",
+						},
+					},
+					{
+						ID:       "notify-security-blocks",
+						Label:    "Security Blocks",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "other.notify.security_blocks",
+							Label:     "Security Blocks",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Other.Notify.SecurityBlocks },
+								SetValue: func(v interface{}) error {
+									cfg.Other.Notify.SecurityBlocks = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Announce refused connections (once an hour per address)",
+						},
+					},
+					{
+						ID:       "notify-post-areas",
+						Label:    "Announce Areas",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "other.notify.post_areas",
+							Label:     "Announce Areas",
+							ValueType: ListValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return strings.Join(cfg.Other.Notify.PostAreas, ", ") },
+								SetValue: func(v interface{}) error {
+									cfg.Other.Notify.PostAreas = nil
+									for _, file := range strings.Split(v.(string), ",") {
+										if file = strings.TrimSpace(file); file != "" {
+											cfg.Other.Notify.PostAreas = append(cfg.Other.Notify.PostAreas, file)
+										}
+									}
+									return nil
+								},
+							},
+							HelpText: "Message area files whose new public posts are announced",
+						},
+					},
+				},
+			},
 		},
 	}
 }
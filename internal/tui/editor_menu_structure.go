@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/robbiew/retrograde/internal/config"
+	"github.com/robbiew/retrograde/internal/qwk"
 )
 
 func buildMenuStructure(cfg *config.Config) MenuBar {
@@ -10,24 +13,180 @@ func buildMenuStructure(cfg *config.Config) MenuBar {
 		Items: []MenuCategory{
 			configurationMenu(cfg),
 			serversMenu(cfg),
-			networkingMenu(),
+			networkingMenu(cfg),
 			editorsMenu(),
 			otherMenu(cfg),
 		},
 	}
 }
 
-func networkingMenu() MenuCategory {
+func networkingMenu(cfg *config.Config) MenuCategory {
 	return MenuCategory{
-		ID:       "networking",
-		Label:    "Networking",
-		HotKey:   'N',
+		ID:     "networking",
+		Label:  "Networking",
+		HotKey: 'N',
 		SubItems: []SubmenuItem{
-			// TODO: Add network configuration when needed
+			{
+				ID:       "qwknet",
+				Label:    "QWKnet",
+				ItemType: SectionHeader,
+				SubItems: []SubmenuItem{
+					{
+						ID:       "qwknet-enabled",
+						Label:    "QWKnet Enabled",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "networking.qwknet.enabled",
+							Label:     "QWKnet Enabled",
+							ValueType: BoolValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Networking.QWKNet.Enabled },
+								SetValue: func(v interface{}) error {
+									cfg.Networking.QWKNet.Enabled = v.(bool)
+									return nil
+								},
+							},
+							HelpText: "Exchange QWKnet areas with other systems",
+						},
+					},
+					{
+						ID:       "qwknet-mode",
+						Label:    "Mode",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "networking.qwknet.mode",
+							Label:     "Mode",
+							ValueType: SelectValue,
+							Field: ConfigField{
+								GetValue: func() interface{} {
+									if cfg.Networking.QWKNet.Mode == "" {
+										return config.QWKNetModeNode
+									}
+									return cfg.Networking.QWKNet.Mode
+								},
+								SetValue: func(v interface{}) error {
+									cfg.Networking.QWKNet.Mode = v.(string)
+									return nil
+								},
+							},
+							SelectOptions: []SelectOption{
+								{Value: config.QWKNetModeNode, Label: "Node", Description: "Send REP packets to and receive QWK packets from a hub", Implemented: true},
+								{Value: config.QWKNetModeHub, Label: "Hub", Description: "Serve QWK packets to downstream systems", Implemented: true},
+							},
+							HelpText: "Node exchanges with a hub; hub serves downstream systems",
+						},
+					},
+					{
+						ID:       "qwknet-packet-id",
+						Label:    "Packet ID",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "networking.qwknet.packet_id",
+							Label:     "Packet ID",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Networking.QWKNet.PacketID },
+								SetValue: func(v interface{}) error {
+									cfg.Networking.QWKNet.PacketID = strings.ToUpper(strings.TrimSpace(v.(string)))
+									return nil
+								},
+							},
+							Validation: validateQWKPacketID,
+							HelpText:   "This system's QWK packet ID (BBSID, max 8 characters)",
+						},
+					},
+					{
+						ID:       "qwknet-hub-packet-id",
+						Label:    "Hub Packet ID",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "networking.qwknet.hub_packet_id",
+							Label:     "Hub Packet ID",
+							ValueType: StringValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Networking.QWKNet.HubPacketID },
+								SetValue: func(v interface{}) error {
+									cfg.Networking.QWKNet.HubPacketID = strings.ToUpper(strings.TrimSpace(v.(string)))
+									return nil
+								},
+							},
+							Validation: validateQWKPacketID,
+							HelpText:   "Packet ID of the upstream hub (node mode)",
+						},
+					},
+					{
+						ID:       "qwknet-inbound",
+						Label:    "Inbound",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "networking.qwknet.inbound",
+							Label:     "Inbound",
+							ValueType: PathValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Networking.QWKNet.InboundPath },
+								SetValue: func(v interface{}) error {
+									cfg.Networking.QWKNet.InboundPath = v.(string)
+									return nil
+								},
+							},
+							HelpText: "Directory where incoming QWK/REP packets arrive",
+						},
+					},
+					{
+						ID:       "qwknet-outbound",
+						Label:    "Outbound",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "networking.qwknet.outbound",
+							Label:     "Outbound",
+							ValueType: PathValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return cfg.Networking.QWKNet.OutboundPath },
+								SetValue: func(v interface{}) error {
+									cfg.Networking.QWKNet.OutboundPath = v.(string)
+									return nil
+								},
+							},
+							HelpText: "Directory where outgoing QWK/REP packets are written",
+						},
+					},
+					{
+						ID:       "qwknet-hub-nodes",
+						Label:    "Hub Nodes",
+						ItemType: EditableField,
+						EditableItem: &MenuItem{
+							ID:        "networking.qwknet.hub_nodes",
+							Label:     "Hub Nodes",
+							ValueType: ListValue,
+							Field: ConfigField{
+								GetValue: func() interface{} { return strings.Join(cfg.Networking.QWKNet.HubNodes, ", ") },
+								SetValue: func(v interface{}) error {
+									s := v.(string)
+									cfg.Networking.QWKNet.HubNodes = nil
+									for _, node := range strings.Split(s, ",") {
+										node = strings.ToUpper(strings.TrimSpace(node))
+										if node != "" {
+											cfg.Networking.QWKNet.HubNodes = append(cfg.Networking.QWKNet.HubNodes, node)
+										}
+									}
+									return nil
+								},
+							},
+							HelpText: "Downstream system packet IDs served in hub mode",
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// validateQWKPacketID checks the QWK packet ID (BBSID) format
+func validateQWKPacketID(v interface{}) error {
+	_, err := qwk.NormalizePacketID(v.(string))
+	return err
+}
+
 // ============================================================================
 // Initialization
 // ============================================================================
@@ -7,10 +7,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/robbiew/retrograde/internal/config"
 	"github.com/robbiew/retrograde/internal/database"
+	"github.com/robbiew/retrograde/internal/events"
+	"github.com/robbiew/retrograde/internal/logging"
+	"github.com/robbiew/retrograde/internal/menu"
 )
 
 // ============================================================================
@@ -86,6 +90,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.screenHeight = msg.Height
 		return m, nil
 
+	case consoleClosedMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("SysOp console: %v", msg.err)
+		} else {
+			m.message = "SysOp console closed"
+		}
+		m.messageTime = time.Now()
+		return m, nil
+
 	case tea.KeyMsg:
 		// Global quit handling
 		if msg.String() == "ctrl+c" {
@@ -135,6 +148,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleConferenceManagement(msg)
 		case AreaManagementMode:
 			return m.handleAreaManagement(msg)
+		case EventManagementMode:
+			return m.handleEventManagement(msg)
+		case SequenceManagementMode:
+			return m.handleSequenceManagement(msg)
+		case VotingManagementMode:
+			return m.handleVotingManagement(msg)
+		case VotingChoicesMode:
+			return m.handleVotingChoices(msg)
+		case ValidationQueueMode:
+			return m.handleValidationQueue(msg)
 		case MenuManagementMode:
 			return m.handleMenuManagement(msg)
 		case MenuModifyMode:
@@ -145,6 +168,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleMenuEditData(msg)
 		case MenuEditCommandMode:
 			return m.handleMenuEditCommand(msg)
+		case ThemeArtMode:
+			return m.handleThemeArt(msg)
 		case SavePrompt:
 			return m.handleSavePrompt(msg)
 		case SaveChangesPrompt:
@@ -255,6 +280,70 @@ func (m Model) handleDeleteConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						m.messageType = ErrorMessage
 					}
 				}
+			case "delete_event":
+				if err := m.db.DeleteTimedEvent(m.confirmMenuID); err != nil {
+					m.message = fmt.Sprintf("Error deleting timed event: %v", err)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+				} else {
+					if err := m.loadTimedEvents(); err != nil {
+						m.message = fmt.Sprintf("Error reloading timed events: %v", err)
+						m.messageTime = time.Now()
+						m.messageType = ErrorMessage
+					} else {
+						m.message = "Timed event deleted"
+						m.messageTime = time.Now()
+						m.messageType = SuccessMessage
+					}
+				}
+			case "delete_step":
+				if err := m.db.DeleteSequenceStep(m.confirmMenuID); err != nil {
+					m.message = fmt.Sprintf("Error deleting sequence step: %v", err)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+				} else {
+					if err := m.loadSequenceSteps(); err != nil {
+						m.message = fmt.Sprintf("Error reloading sequence steps: %v", err)
+						m.messageTime = time.Now()
+						m.messageType = ErrorMessage
+					} else {
+						m.message = "Sequence step deleted"
+						m.messageTime = time.Now()
+						m.messageType = SuccessMessage
+					}
+				}
+			case "delete_topic":
+				if err := m.db.DeleteVotingTopic(m.confirmMenuID); err != nil {
+					m.message = fmt.Sprintf("Error deleting voting topic: %v", err)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+				} else {
+					if err := m.loadVotingTopics(); err != nil {
+						m.message = fmt.Sprintf("Error reloading voting topics: %v", err)
+						m.messageTime = time.Now()
+						m.messageType = ErrorMessage
+					} else {
+						m.message = "Voting topic deleted"
+						m.messageTime = time.Now()
+						m.messageType = SuccessMessage
+					}
+				}
+			case "delete_choice":
+				if err := m.db.DeleteVotingChoice(m.confirmMenuID); err != nil {
+					m.message = fmt.Sprintf("Error deleting choice: %v", err)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+				} else {
+					if err := m.loadVotingChoices(); err != nil {
+						m.message = fmt.Sprintf("Error reloading choices: %v", err)
+						m.messageTime = time.Now()
+						m.messageType = ErrorMessage
+					} else {
+						m.message = "Choice deleted"
+						m.messageTime = time.Now()
+						m.messageType = SuccessMessage
+					}
+				}
 			case "delete_area":
 				if err := m.db.DeleteMessageArea(m.confirmMenuID); err != nil {
 					m.message = fmt.Sprintf("Error deleting message area: %v", err)
@@ -412,6 +501,14 @@ func (m Model) handleLevel4ModalNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 				m.returnToMode = ConferenceManagementMode
 			} else if m.editingArea != nil {
 				m.returnToMode = AreaManagementMode
+			} else if m.editingEvent != nil {
+				m.returnToMode = EventManagementMode
+			} else if m.editingStep != nil {
+				m.returnToMode = SequenceManagementMode
+			} else if m.editingTopic != nil {
+				m.returnToMode = VotingManagementMode
+			} else if m.editingChoice != nil {
+				m.returnToMode = VotingChoicesMode
 			} else {
 				hasSubSections := false
 				for _, field := range m.modalFields {
@@ -460,6 +557,34 @@ func (m Model) handleLevel4ModalNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.modalSectionName = ""
 			m.editingArea = nil
 			m.areaIsNew = false
+		} else if m.editingEvent != nil {
+			m.navMode = EventManagementMode
+			m.modalFields = nil
+			m.modalFieldIndex = 0
+			m.modalSectionName = ""
+			m.editingEvent = nil
+			m.eventIsNew = false
+		} else if m.editingStep != nil {
+			m.navMode = SequenceManagementMode
+			m.modalFields = nil
+			m.modalFieldIndex = 0
+			m.modalSectionName = ""
+			m.editingStep = nil
+			m.stepIsNew = false
+		} else if m.editingTopic != nil {
+			m.navMode = VotingManagementMode
+			m.modalFields = nil
+			m.modalFieldIndex = 0
+			m.modalSectionName = ""
+			m.editingTopic = nil
+			m.topicIsNew = false
+		} else if m.editingChoice != nil {
+			m.navMode = VotingChoicesMode
+			m.modalFields = nil
+			m.modalFieldIndex = 0
+			m.modalSectionName = ""
+			m.editingChoice = nil
+			m.choiceIsNew = false
 		} else {
 			hasSubSections := false
 			for _, field := range m.modalFields {
@@ -900,6 +1025,140 @@ func (m Model) handleSaveChangesPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 				m.areaIsNew = false
 				m.editingArea = nil
+			} else if m.editingEvent != nil {
+				var saveErr error
+				if m.eventIsNew {
+					_, saveErr = m.db.CreateTimedEvent(m.editingEvent)
+				} else {
+					saveErr = m.db.UpdateTimedEvent(m.editingEvent)
+				}
+				if saveErr != nil {
+					m.message = fmt.Sprintf("Error saving timed event: %v", saveErr)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+					m.savePrompt = false
+					m.navMode = m.returnToMode
+					return m, nil
+				}
+
+				savedEventID := m.editingEvent.ID
+				if reloadErr := m.loadTimedEvents(); reloadErr != nil {
+					m.message = fmt.Sprintf("Error reloading timed events: %v", reloadErr)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+				} else {
+					items := m.eventListUI.Items()
+					for idx, item := range items {
+						if eventItem, ok := item.(timedEventListItem); ok && eventItem.event.ID == savedEventID {
+							m.eventListUI.Select(idx)
+							break
+						}
+					}
+					m.message = "Timed event saved"
+					m.messageTime = time.Now()
+					m.messageType = SuccessMessage
+				}
+
+				m.eventIsNew = false
+				m.editingEvent = nil
+			} else if m.editingStep != nil {
+				var saveErr error
+				if m.stepIsNew {
+					_, saveErr = m.db.CreateSequenceStep(m.editingStep)
+				} else {
+					saveErr = m.db.UpdateSequenceStep(m.editingStep)
+				}
+				if saveErr != nil {
+					m.message = fmt.Sprintf("Error saving sequence step: %v", saveErr)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+					m.savePrompt = false
+					m.navMode = m.returnToMode
+					return m, nil
+				}
+
+				savedStepID := m.editingStep.ID
+				if reloadErr := m.loadSequenceSteps(); reloadErr != nil {
+					m.message = fmt.Sprintf("Error reloading sequence steps: %v", reloadErr)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+				} else {
+					m.selectSequenceStep(savedStepID)
+					m.message = "Sequence step saved"
+					m.messageTime = time.Now()
+					m.messageType = SuccessMessage
+				}
+
+				m.stepIsNew = false
+				m.editingStep = nil
+			} else if m.editingTopic != nil {
+				var saveErr error
+				if m.topicIsNew {
+					_, saveErr = m.db.CreateVotingTopic(m.editingTopic)
+				} else {
+					saveErr = m.db.UpdateVotingTopic(m.editingTopic)
+				}
+				if saveErr != nil {
+					m.message = fmt.Sprintf("Error saving voting topic: %v", saveErr)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+					m.savePrompt = false
+					m.navMode = m.returnToMode
+					return m, nil
+				}
+
+				savedTopicID := m.editingTopic.ID
+				if reloadErr := m.loadVotingTopics(); reloadErr != nil {
+					m.message = fmt.Sprintf("Error reloading voting topics: %v", reloadErr)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+				} else {
+					for idx, item := range m.votingListUI.Items() {
+						if topicItem, ok := item.(votingTopicListItem); ok && topicItem.topic.ID == savedTopicID {
+							m.votingListUI.Select(idx)
+							break
+						}
+					}
+					m.message = "Voting topic saved"
+					if m.topicIsNew {
+						m.message = "Voting topic saved (C to add its choices)"
+					}
+					m.messageTime = time.Now()
+					m.messageType = SuccessMessage
+				}
+
+				m.topicIsNew = false
+				m.editingTopic = nil
+			} else if m.editingChoice != nil {
+				var saveErr error
+				if m.choiceIsNew {
+					_, saveErr = m.db.CreateVotingChoice(m.editingChoice)
+				} else {
+					saveErr = m.db.UpdateVotingChoice(m.editingChoice)
+				}
+				if saveErr != nil {
+					m.message = fmt.Sprintf("Error saving choice: %v", saveErr)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+					m.savePrompt = false
+					m.navMode = m.returnToMode
+					return m, nil
+				}
+
+				savedChoiceID := m.editingChoice.ID
+				if reloadErr := m.loadVotingChoices(); reloadErr != nil {
+					m.message = fmt.Sprintf("Error reloading choices: %v", reloadErr)
+					m.messageTime = time.Now()
+					m.messageType = ErrorMessage
+				} else {
+					m.selectVotingChoice(savedChoiceID)
+					m.message = "Choice saved"
+					m.messageTime = time.Now()
+					m.messageType = SuccessMessage
+				}
+
+				m.choiceIsNew = false
+				m.editingChoice = nil
 			} else if m.editingUser != nil {
 				// Save user changes
 				err = m.db.UpdateUser(m.editingUser)
@@ -959,6 +1218,18 @@ func (m Model) handleSaveChangesPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else if m.editingArea != nil {
 				m.editingArea = nil
 				m.areaIsNew = false
+			} else if m.editingEvent != nil {
+				m.editingEvent = nil
+				m.eventIsNew = false
+			} else if m.editingStep != nil {
+				m.editingStep = nil
+				m.stepIsNew = false
+			} else if m.editingTopic != nil {
+				m.editingTopic = nil
+				m.topicIsNew = false
+			} else if m.editingChoice != nil {
+				m.editingChoice = nil
+				m.choiceIsNew = false
 			}
 			// CRITICAL: Reset modifiedCount when discarding changes
 			m.modifiedCount = 0
@@ -971,8 +1242,16 @@ func (m Model) handleSaveChangesPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.editingUser = nil
 		m.editingConference = nil
 		m.editingArea = nil
+		m.editingEvent = nil
+		m.editingStep = nil
+		m.editingTopic = nil
+		m.editingChoice = nil
 		m.conferenceIsNew = false
 		m.areaIsNew = false
+		m.eventIsNew = false
+		m.stepIsNew = false
+		m.topicIsNew = false
+		m.choiceIsNew = false
 
 		// Clean up modal if returning to Level 2
 		if m.returnToMode == Level2MenuNavigation {
@@ -1264,6 +1543,138 @@ func (m Model) handleLevel2MenuNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						m.message = ""
 					}
 
+				case "logon-sequence-editor", "logoff-sequence-editor":
+					if m.config.Configuration.Paths.Database == "" {
+						m.message = "Database path not configured. Please set it under Configuration > Paths > Database first."
+						m.messageTime = time.Now()
+						return m, nil
+					}
+
+					if m.db == nil {
+						if existingDB := config.GetDatabase(); existingDB != nil {
+							if sqliteDB, ok := existingDB.(*database.SQLiteDB); ok {
+								m.db = sqliteDB
+								if err := m.db.InitializeSchema(); err != nil {
+									m.message = fmt.Sprintf("Failed to initialize database schema: %v", err)
+									m.messageTime = time.Now()
+									return m, nil
+								}
+							} else {
+								m.message = "Database connection type mismatch"
+								m.messageTime = time.Now()
+								return m, nil
+							}
+						} else {
+							m.message = "No database connection available"
+							m.messageTime = time.Now()
+							return m, nil
+						}
+					}
+
+					m.sequenceName = database.SequenceLogon
+					if item.submenuItem.ID == "logoff-sequence-editor" {
+						m.sequenceName = database.SequenceLogoff
+					}
+					if err := m.loadSequenceSteps(); err != nil {
+						m.message = fmt.Sprintf("Error loading %s sequence: %v", m.sequenceName, err)
+						m.messageTime = time.Now()
+					} else {
+						m.navMode = SequenceManagementMode
+						m.message = ""
+					}
+
+				case "timed-events-editor":
+					if m.config.Configuration.Paths.Database == "" {
+						m.message = "Database path not configured. Please set it under Configuration > Paths > Database first."
+						m.messageTime = time.Now()
+						return m, nil
+					}
+
+					if m.db == nil {
+						if existingDB := config.GetDatabase(); existingDB != nil {
+							if sqliteDB, ok := existingDB.(*database.SQLiteDB); ok {
+								m.db = sqliteDB
+								if err := m.db.InitializeSchema(); err != nil {
+									m.message = fmt.Sprintf("Failed to initialize database schema: %v", err)
+									m.messageTime = time.Now()
+									return m, nil
+								}
+							} else {
+								m.message = "Database connection type mismatch"
+								m.messageTime = time.Now()
+								return m, nil
+							}
+						} else {
+							m.message = "No database connection available"
+							m.messageTime = time.Now()
+							return m, nil
+						}
+					}
+
+					if err := m.loadTimedEvents(); err != nil {
+						m.message = fmt.Sprintf("Error loading timed events: %v", err)
+						m.messageTime = time.Now()
+					} else {
+						m.navMode = EventManagementMode
+						m.message = ""
+					}
+
+				case "voting-editor":
+					if m.config.Configuration.Paths.Database == "" {
+						m.message = "Database path not configured. Please set it under Configuration > Paths > Database first."
+						m.messageTime = time.Now()
+						return m, nil
+					}
+
+					if m.db == nil {
+						if existingDB := config.GetDatabase(); existingDB != nil {
+							if sqliteDB, ok := existingDB.(*database.SQLiteDB); ok {
+								m.db = sqliteDB
+								if err := m.db.InitializeSchema(); err != nil {
+									m.message = fmt.Sprintf("Failed to initialize database schema: %v", err)
+									m.messageTime = time.Now()
+									return m, nil
+								}
+							} else {
+								m.message = "Database connection type mismatch"
+								m.messageTime = time.Now()
+								return m, nil
+							}
+						} else {
+							m.message = "No database connection available"
+							m.messageTime = time.Now()
+							return m, nil
+						}
+					}
+
+					if err := m.loadVotingTopics(); err != nil {
+						m.message = fmt.Sprintf("Error loading voting topics: %v", err)
+						m.messageTime = time.Now()
+					} else {
+						m.navMode = VotingManagementMode
+						m.message = ""
+					}
+
+				case "sysop-console":
+					// Hand the terminal to the running server's sysop console
+					port := m.config.Configuration.SysOpChat.ConsolePort
+					if port <= 0 {
+						m.message = "SysOp console is disabled. Set Configuration > SysOp Chat > Console Port first."
+						m.messageTime = time.Now()
+						return m, nil
+					}
+					console := &consoleExec{addr: fmt.Sprintf("127.0.0.1:%d", port)}
+					return m, tea.Exec(console, func(err error) tea.Msg { return consoleClosedMsg{err: err} })
+
+				case "theme-art":
+					if err := m.loadThemeArt(); err != nil {
+						m.message = fmt.Sprintf("Error loading theme art: %v", err)
+						m.messageTime = time.Now()
+					} else {
+						m.navMode = ThemeArtMode
+						m.message = ""
+					}
+
 				case "menu-editor":
 					// Launch menu management interface
 					// Check if database path is configured
@@ -1803,6 +2214,21 @@ func (m Model) handleUserManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.message = ""
 		}
 		return m, nil
+	case "v", "V":
+		if m.userListUI.FilterState() == list.Filtering {
+			break
+		}
+		// Open the new user validation queue
+		if err := m.loadValidations(); err != nil {
+			m.message = fmt.Sprintf("Error loading validations: %v", err)
+			m.messageTime = time.Now()
+			m.messageType = ErrorMessage
+			return m, nil
+		}
+		m.upgradeLevels = nil
+		m.navMode = ValidationQueueMode
+		m.message = ""
+		return m, nil
 	case "esc":
 		// Return to Level 2 menu navigation (Editors menu)
 		m.navMode = Level2MenuNavigation
@@ -2444,7 +2870,7 @@ func (m Model) handleSelectingValue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 		shouldRefreshCommand := currentItemID == "command-cmdkeys"
-		shouldAutoPromptMenu := shouldRefreshCommand && strings.EqualFold(selectedOption.Value, "-^")
+		shouldAutoPromptMenu := shouldRefreshCommand && isMenuTargetCmdKey(selectedOption.Value)
 
 		if shouldRefreshCommand {
 			m.setupMenuEditCommandModal()
@@ -2702,12 +3128,27 @@ func (m Model) handleSecurityLevelsManagement(msg tea.KeyMsg) (tea.Model, tea.Cm
 			m.message = ""
 		}
 		return m, nil
-	case "esc":
-		// Return to Level 2 menu navigation (Editors menu)
-		m.navMode = Level2MenuNavigation
-		m.message = ""
-	}
-
+	case "v", "V":
+		if m.userListUI.FilterState() == list.Filtering {
+			break
+		}
+		// Open the new user validation queue
+		if err := m.loadValidations(); err != nil {
+			m.message = fmt.Sprintf("Error loading validations: %v", err)
+			m.messageTime = time.Now()
+			m.messageType = ErrorMessage
+			return m, nil
+		}
+		m.upgradeLevels = nil
+		m.navMode = ValidationQueueMode
+		m.message = ""
+		return m, nil
+	case "esc":
+		// Return to Level 2 menu navigation (Editors menu)
+		m.navMode = Level2MenuNavigation
+		m.message = ""
+	}
+
 	// Update the list for any other input (like filtering)
 	// Only update if not handled above and not ESC (to prevent quit command)
 	if msg.String() != "esc" {
@@ -2716,6 +3157,40 @@ func (m Model) handleSecurityLevelsManagement(msg tea.KeyMsg) (tea.Model, tea.Cm
 	return m, cmd
 }
 
+// handleThemeArt processes input in the theme art browser
+func (m Model) handleThemeArt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "up", "k":
+		if idx := m.themeArtUI.Index(); idx > 0 {
+			m.themeArtUI.Select(idx - 1)
+		}
+		return m, nil
+	case "down", "j":
+		if idx := m.themeArtUI.Index(); idx < len(m.themeArtUI.Items())-1 {
+			m.themeArtUI.Select(idx + 1)
+		}
+		return m, nil
+	case "home":
+		m.themeArtUI.Select(0)
+		return m, nil
+	case "end":
+		if items := m.themeArtUI.Items(); len(items) > 0 {
+			m.themeArtUI.Select(len(items) - 1)
+		}
+		return m, nil
+	case "esc":
+		// Return to the Other menu
+		m.navMode = Level2MenuNavigation
+		m.message = ""
+		return m, nil
+	}
+
+	m.themeArtUI, cmd = m.themeArtUI.Update(msg)
+	return m, cmd
+}
+
 // handleMenuManagement processes input in menu management mode
 func (m Model) handleMenuManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -2771,6 +3246,9 @@ func (m Model) handleMenuManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				RightBracket:        originalMenu.RightBracket,
 				DisplayMode:         originalMenu.DisplayMode,
 				NodeActivity:        originalMenu.NodeActivity,
+				InputMode:           originalMenu.InputMode,
+				LightbarFgColor:     originalMenu.LightbarFgColor,
+				LightbarBgColor:     originalMenu.LightbarBgColor,
 			}
 
 			// Make a working copy
@@ -2789,6 +3267,9 @@ func (m Model) handleMenuManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				RightBracket:        originalMenu.RightBracket,
 				DisplayMode:         originalMenu.DisplayMode,
 				NodeActivity:        originalMenu.NodeActivity,
+				InputMode:           originalMenu.InputMode,
+				LightbarFgColor:     originalMenu.LightbarFgColor,
+				LightbarBgColor:     originalMenu.LightbarBgColor,
 			}
 
 			if err := m.loadMenuCommandsForEditing(); err != nil {
@@ -2846,6 +3327,9 @@ func (m Model) handleMenuManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			RightBracket:        "]",
 			DisplayMode:         database.DisplayModeTitlesGenerated,
 			NodeActivity:        "",
+			InputMode:           database.InputModeHotkey,
+			LightbarFgColor:     15,
+			LightbarBgColor:     1,
 		}
 		// Initialize empty commands list for new menu
 		m.menuCommandsList = []database.MenuCommand{}
@@ -3255,68 +3739,720 @@ func (m Model) handleAreaManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-// Update this helper function
-func (m Model) returnToMenuModifyOrModal() NavigationMode {
-	// If we're editing a menu command, return to command edit mode
-	if m.editingMenuCommand != nil {
-		return MenuEditCommandMode
-	}
-	// If we have editingMenu set, we're in MenuModifyMode
-	if m.editingMenu != nil {
-		return MenuModifyMode
+// handleEventManagement processes input in timed event management mode
+func (m Model) handleEventManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "up", "k":
+		idx := m.eventListUI.Index()
+		if idx > 0 {
+			m.eventListUI.Select(idx - 1)
+		}
+		return m, nil
+	case "down", "j":
+		idx := m.eventListUI.Index()
+		items := m.eventListUI.Items()
+		if idx < len(items)-1 {
+			m.eventListUI.Select(idx + 1)
+		}
+		return m, nil
+	case "home":
+		m.eventListUI.Select(0)
+		return m, nil
+	case "end":
+		items := m.eventListUI.Items()
+		if len(items) > 0 {
+			m.eventListUI.Select(len(items) - 1)
+		}
+		return m, nil
+	case "enter":
+		selected := m.eventListUI.SelectedItem()
+		if selected == nil {
+			return m, nil
+		}
+
+		eventItem, ok := selected.(timedEventListItem)
+		if !ok {
+			return m, nil
+		}
+
+		eventCopy := eventItem.event
+		m.beginEventEdit(&eventCopy, false)
+		return m, nil
+	case "n", "N":
+		newEvent := database.TimedEvent{
+			Schedule: "daily 03:00",
+			Action:   database.EventActionBackup,
+			Enabled:  true,
+		}
+		m.beginEventEdit(&newEvent, true)
+		return m, nil
+	case "d", "D":
+		items := m.eventListUI.Items()
+		idx := m.eventListUI.Index()
+		if idx < 0 || idx >= len(items) {
+			return m, nil
+		}
+
+		eventItem, ok := items[idx].(timedEventListItem)
+		if !ok || eventItem.event.ID == 0 {
+			m.message = "Timed event must be saved before deletion"
+			m.messageTime = time.Now()
+			m.messageType = WarningMessage
+			return m, nil
+		}
+
+		m.confirmAction = "delete_event"
+		m.confirmMenuID = int64(eventItem.event.ID)
+		m.confirmPromptText = fmt.Sprintf("Delete event '%s'? This action cannot be undone.", eventItem.event.Name)
+		m.savePrompt = true
+		m.savePromptSelection = 0
+		m.navMode = DeleteConfirmPrompt
+		m.returnToMode = EventManagementMode
+		return m, nil
+	case "f1":
+		m.message = "Keys: N New   ENTER Edit   D Delete   ESC Back"
+		m.messageTime = time.Now()
+		m.messageType = InfoMessage
+		return m, nil
+	case "esc":
+		m.navMode = Level2MenuNavigation
+		m.message = ""
+		return m, nil
 	}
-	// Otherwise return to Level4ModalNavigation
-	return Level4ModalNavigation
+
+	m.eventListUI, cmd = m.eventListUI.Update(msg)
+	return m, cmd
 }
 
-func (m *Model) defaultMessageBasePath() string {
-	if m.config != nil {
-		if path := strings.TrimSpace(m.config.Configuration.Paths.MessageBase); path != "" {
-			return path
+// handleSequenceManagement processes input in logon/logoff sequence management mode
+func (m Model) handleSequenceManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "up", "k":
+		idx := m.sequenceListUI.Index()
+		if idx > 0 {
+			m.sequenceListUI.Select(idx - 1)
+		}
+		return m, nil
+	case "down", "j":
+		idx := m.sequenceListUI.Index()
+		items := m.sequenceListUI.Items()
+		if idx < len(items)-1 {
+			m.sequenceListUI.Select(idx + 1)
+		}
+		return m, nil
+	case "home":
+		m.sequenceListUI.Select(0)
+		return m, nil
+	case "end":
+		items := m.sequenceListUI.Items()
+		if len(items) > 0 {
+			m.sequenceListUI.Select(len(items) - 1)
+		}
+		return m, nil
+	case "enter":
+		selected := m.sequenceListUI.SelectedItem()
+		if selected == nil {
+			return m, nil
 		}
-	}
 
-	if m.db != nil {
-		if value, err := m.db.GetConfig("Configuration.Paths", "", "Message_Base"); err == nil {
-			if path := strings.TrimSpace(value); path != "" {
-				return path
-			}
+		stepItem, ok := selected.(sequenceStepListItem)
+		if !ok {
+			return m, nil
+		}
+
+		stepCopy := stepItem.step
+		m.beginStepEdit(&stepCopy, false)
+		return m, nil
+	case "n", "N":
+		position := 1
+		if count := len(m.sequenceSteps); count > 0 {
+			position = m.sequenceSteps[count-1].Position + 1
+		}
+		newStep := database.SequenceStep{
+			Sequence: m.sequenceName,
+			Position: position,
+			StepType: database.StepDisplayFile,
+			Enabled:  true,
+		}
+		m.beginStepEdit(&newStep, true)
+		return m, nil
+	case "+", "=", "-":
+		offset := 1
+		if msg.String() == "-" {
+			offset = -1
+		}
+		if err := m.moveSequenceStep(m.sequenceListUI.Index(), offset); err != nil {
+			m.message = fmt.Sprintf("Error moving step: %v", err)
+			m.messageTime = time.Now()
+			m.messageType = ErrorMessage
 		}
+		return m, nil
+	case "d", "D":
+		items := m.sequenceListUI.Items()
+		idx := m.sequenceListUI.Index()
+		if idx < 0 || idx >= len(items) {
+			return m, nil
+		}
+
+		stepItem, ok := items[idx].(sequenceStepListItem)
+		if !ok || stepItem.step.ID == 0 {
+			return m, nil
+		}
+
+		m.confirmAction = "delete_step"
+		m.confirmMenuID = int64(stepItem.step.ID)
+		m.confirmPromptText = fmt.Sprintf("Delete step %d (%s)? This action cannot be undone.", idx+1, sequenceStepLabel(stepItem.step.StepType))
+		m.savePrompt = true
+		m.savePromptSelection = 0
+		m.navMode = DeleteConfirmPrompt
+		m.returnToMode = SequenceManagementMode
+		return m, nil
+	case "f1":
+		m.message = "Keys: N New   ENTER Edit   +/- Move   D Delete   ESC Back"
+		m.messageTime = time.Now()
+		m.messageType = InfoMessage
+		return m, nil
+	case "esc":
+		m.navMode = Level2MenuNavigation
+		m.message = ""
+		return m, nil
 	}
 
-	return "messages"
+	m.sequenceListUI, cmd = m.sequenceListUI.Update(msg)
+	return m, cmd
 }
 
-func (m *Model) conferenceNameByID(id int) string {
-	if id <= 0 {
-		return ""
+// moveSequenceStep moves the step at idx up (-1) or down (+1) and renumbers
+// the sequence so positions stay 1..n
+func (m *Model) moveSequenceStep(idx, offset int) error {
+	target := idx + offset
+	if idx < 0 || target < 0 || idx >= len(m.sequenceSteps) || target >= len(m.sequenceSteps) {
+		return nil
 	}
 
-	for _, conf := range m.conferenceList {
-		if conf.ID == id {
-			return conf.Name
+	steps := m.sequenceSteps
+	movedID := steps[idx].ID
+	steps[idx], steps[target] = steps[target], steps[idx]
+	for i := range steps {
+		if steps[i].Position == i+1 {
+			continue
+		}
+		steps[i].Position = i + 1
+		if err := m.db.UpdateSequenceStep(&steps[i]); err != nil {
+			return err
 		}
 	}
 
-	if m.db != nil {
-		if conferences, err := m.db.GetAllConferences(); err == nil {
-			m.conferenceList = conferences
-			for _, conf := range conferences {
-				if conf.ID == id {
-					return conf.Name
-				}
-			}
+	if err := m.loadSequenceSteps(); err != nil {
+		return err
+	}
+	m.selectSequenceStep(movedID)
+	return nil
+}
+
+// selectSequenceStep highlights the step with the given ID
+func (m *Model) selectSequenceStep(id int) {
+	for idx, item := range m.sequenceListUI.Items() {
+		if stepItem, ok := item.(sequenceStepListItem); ok && stepItem.step.ID == id {
+			m.sequenceListUI.Select(idx)
+			return
 		}
 	}
+}
 
-	return ""
+// handleVotingManagement processes input in voting topic management mode
+func (m Model) handleVotingManagement(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "up", "k":
+		idx := m.votingListUI.Index()
+		if idx > 0 {
+			m.votingListUI.Select(idx - 1)
+		}
+		return m, nil
+	case "down", "j":
+		idx := m.votingListUI.Index()
+		items := m.votingListUI.Items()
+		if idx < len(items)-1 {
+			m.votingListUI.Select(idx + 1)
+		}
+		return m, nil
+	case "home":
+		m.votingListUI.Select(0)
+		return m, nil
+	case "end":
+		items := m.votingListUI.Items()
+		if len(items) > 0 {
+			m.votingListUI.Select(len(items) - 1)
+		}
+		return m, nil
+	case "enter":
+		selected := m.votingListUI.SelectedItem()
+		if selected == nil {
+			return m, nil
+		}
+
+		topicItem, ok := selected.(votingTopicListItem)
+		if !ok {
+			return m, nil
+		}
+
+		topicCopy := topicItem.topic
+		m.beginTopicEdit(&topicCopy, false)
+		return m, nil
+	case "n", "N":
+		newTopic := database.VotingTopic{
+			CreatedBy: m.config.Configuration.General.SysOpName,
+			Active:    true,
+		}
+		m.beginTopicEdit(&newTopic, true)
+		return m, nil
+	case "c", "C":
+		selected := m.votingListUI.SelectedItem()
+		topicItem, ok := selected.(votingTopicListItem)
+		if !ok {
+			return m, nil
+		}
+
+		m.votingTopic = topicItem.topic
+		if err := m.loadVotingChoices(); err != nil {
+			m.message = fmt.Sprintf("Error loading choices: %v", err)
+			m.messageTime = time.Now()
+			m.messageType = ErrorMessage
+			return m, nil
+		}
+		m.navMode = VotingChoicesMode
+		m.message = ""
+		return m, nil
+	case "d", "D":
+		items := m.votingListUI.Items()
+		idx := m.votingListUI.Index()
+		if idx < 0 || idx >= len(items) {
+			return m, nil
+		}
+
+		topicItem, ok := items[idx].(votingTopicListItem)
+		if !ok || topicItem.topic.ID == 0 {
+			return m, nil
+		}
+
+		m.confirmAction = "delete_topic"
+		m.confirmMenuID = int64(topicItem.topic.ID)
+		m.confirmPromptText = fmt.Sprintf("Delete topic '%s' with its choices and votes? This action cannot be undone.", topicItem.topic.Title)
+		m.savePrompt = true
+		m.savePromptSelection = 0
+		m.navMode = DeleteConfirmPrompt
+		m.returnToMode = VotingManagementMode
+		return m, nil
+	case "f1":
+		m.message = "Keys: N New   ENTER Edit   C Choices   D Delete   ESC Back"
+		m.messageTime = time.Now()
+		m.messageType = InfoMessage
+		return m, nil
+	case "esc":
+		m.navMode = Level2MenuNavigation
+		m.message = ""
+		return m, nil
+	}
+
+	m.votingListUI, cmd = m.votingListUI.Update(msg)
+	return m, cmd
 }
 
-func (m *Model) beginConferenceEdit(conf *database.Conference, isNew bool) {
-	m.editingConference = conf
-	m.conferenceIsNew = isNew
-	m.modalSectionName = "Conference"
-	m.modalFieldIndex = 0
+// handleVotingChoices processes input in the choice list of a voting topic
+func (m Model) handleVotingChoices(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "up", "k":
+		idx := m.choiceListUI.Index()
+		if idx > 0 {
+			m.choiceListUI.Select(idx - 1)
+		}
+		return m, nil
+	case "down", "j":
+		idx := m.choiceListUI.Index()
+		items := m.choiceListUI.Items()
+		if idx < len(items)-1 {
+			m.choiceListUI.Select(idx + 1)
+		}
+		return m, nil
+	case "home":
+		m.choiceListUI.Select(0)
+		return m, nil
+	case "end":
+		items := m.choiceListUI.Items()
+		if len(items) > 0 {
+			m.choiceListUI.Select(len(items) - 1)
+		}
+		return m, nil
+	case "enter":
+		selected := m.choiceListUI.SelectedItem()
+		if selected == nil {
+			return m, nil
+		}
+
+		choiceItem, ok := selected.(votingChoiceListItem)
+		if !ok {
+			return m, nil
+		}
+
+		choiceCopy := choiceItem.choice
+		m.beginChoiceEdit(&choiceCopy, false)
+		return m, nil
+	case "n", "N":
+		position := 1
+		if count := len(m.votingChoices); count > 0 {
+			position = m.votingChoices[count-1].Position + 1
+		}
+		newChoice := database.VotingChoice{
+			TopicID:  m.votingTopic.ID,
+			Position: position,
+			AddedBy:  m.config.Configuration.General.SysOpName,
+		}
+		m.beginChoiceEdit(&newChoice, true)
+		return m, nil
+	case "+", "=", "-":
+		offset := 1
+		if msg.String() == "-" {
+			offset = -1
+		}
+		if err := m.moveVotingChoice(m.choiceListUI.Index(), offset); err != nil {
+			m.message = fmt.Sprintf("Error moving choice: %v", err)
+			m.messageTime = time.Now()
+			m.messageType = ErrorMessage
+		}
+		return m, nil
+	case "d", "D":
+		items := m.choiceListUI.Items()
+		idx := m.choiceListUI.Index()
+		if idx < 0 || idx >= len(items) {
+			return m, nil
+		}
+
+		choiceItem, ok := items[idx].(votingChoiceListItem)
+		if !ok || choiceItem.choice.ID == 0 {
+			return m, nil
+		}
+
+		m.confirmAction = "delete_choice"
+		m.confirmMenuID = int64(choiceItem.choice.ID)
+		m.confirmPromptText = fmt.Sprintf("Delete choice '%s' and its %d vote(s)? This action cannot be undone.", choiceItem.choice.Text, choiceItem.choice.Votes)
+		m.savePrompt = true
+		m.savePromptSelection = 0
+		m.navMode = DeleteConfirmPrompt
+		m.returnToMode = VotingChoicesMode
+		return m, nil
+	case "f1":
+		m.message = "Keys: N New   ENTER Edit   +/- Move   D Delete   ESC Back"
+		m.messageTime = time.Now()
+		m.messageType = InfoMessage
+		return m, nil
+	case "esc":
+		// Vote counts may have changed the list; reload it on the way back
+		if err := m.loadVotingTopics(); err != nil {
+			m.message = fmt.Sprintf("Error reloading voting topics: %v", err)
+			m.messageTime = time.Now()
+			m.messageType = ErrorMessage
+		} else {
+			for idx, item := range m.votingListUI.Items() {
+				if topicItem, ok := item.(votingTopicListItem); ok && topicItem.topic.ID == m.votingTopic.ID {
+					m.votingListUI.Select(idx)
+					break
+				}
+			}
+			m.message = ""
+		}
+		m.navMode = VotingManagementMode
+		return m, nil
+	}
+
+	m.choiceListUI, cmd = m.choiceListUI.Update(msg)
+	return m, cmd
+}
+
+// moveVotingChoice moves the choice at idx up (-1) or down (+1) and
+// renumbers the topic's choices so positions stay 1..n
+func (m *Model) moveVotingChoice(idx, offset int) error {
+	target := idx + offset
+	if idx < 0 || target < 0 || idx >= len(m.votingChoices) || target >= len(m.votingChoices) {
+		return nil
+	}
+
+	choices := m.votingChoices
+	movedID := choices[idx].ID
+	choices[idx], choices[target] = choices[target], choices[idx]
+	for i := range choices {
+		if choices[i].Position == i+1 {
+			continue
+		}
+		choices[i].Position = i + 1
+		if err := m.db.UpdateVotingChoice(&choices[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := m.loadVotingChoices(); err != nil {
+		return err
+	}
+	m.selectVotingChoice(movedID)
+	return nil
+}
+
+// handleValidationQueue processes input in the new user validation queue
+func (m Model) handleValidationQueue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.upgradeLevels != nil {
+		return m.handleUpgradeLevel(msg)
+	}
+
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "up", "k":
+		idx := m.validationListUI.Index()
+		if idx > 0 {
+			m.validationListUI.Select(idx - 1)
+		}
+		return m, nil
+	case "down", "j":
+		idx := m.validationListUI.Index()
+		items := m.validationListUI.Items()
+		if idx < len(items)-1 {
+			m.validationListUI.Select(idx + 1)
+		}
+		return m, nil
+	case "home":
+		m.validationListUI.Select(0)
+		return m, nil
+	case "end":
+		items := m.validationListUI.Items()
+		if len(items) > 0 {
+			m.validationListUI.Select(len(items) - 1)
+		}
+		return m, nil
+	case "a", "A":
+		validation, ok := m.selectedValidation()
+		if !ok {
+			return m, nil
+		}
+		level := m.config.Configuration.NewUsers.ValidatedLevel
+		if level <= 0 {
+			level = config.SecurityLevelRegular
+		}
+		m.resolveValidation(validation, database.ValidationApproved, level)
+		return m, nil
+	case "r", "R":
+		validation, ok := m.selectedValidation()
+		if !ok {
+			return m, nil
+		}
+		m.resolveValidation(validation, database.ValidationRejected, 0)
+		return m, nil
+	case "u", "U":
+		validation, ok := m.selectedValidation()
+		if !ok {
+			return m, nil
+		}
+		var levels []SelectOption
+		for _, option := range m.getSecurityLevelSelectOptions() {
+			if level, err := strconv.Atoi(option.Value); err == nil && level > validation.Level {
+				levels = append(levels, option)
+			}
+		}
+		if len(levels) == 0 {
+			m.message = fmt.Sprintf("No security level is above %s's level %d", validation.Username, validation.Level)
+			m.messageTime = time.Now()
+			m.messageType = WarningMessage
+			return m, nil
+		}
+		m.upgradeLevels = levels
+		m.upgradeLevelIndex = 0
+		m.message = ""
+		return m, nil
+	case "f1":
+		m.message = "Keys: A Approve   R Reject   U Upgrade   ESC Back"
+		m.messageTime = time.Now()
+		m.messageType = InfoMessage
+		return m, nil
+	case "esc":
+		// Approvals change security levels; reload the users on the way back
+		if err := m.loadUsers(); err != nil {
+			m.message = fmt.Sprintf("Error reloading users: %v", err)
+			m.messageTime = time.Now()
+			m.messageType = ErrorMessage
+		} else {
+			m.message = ""
+		}
+		m.navMode = UserManagementMode
+		return m, nil
+	}
+
+	m.validationListUI, cmd = m.validationListUI.Update(msg)
+	return m, cmd
+}
+
+// handleUpgradeLevel processes input while choosing the level to upgrade the
+// selected user to
+func (m Model) handleUpgradeLevel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k", "left", "h":
+		if m.upgradeLevelIndex > 0 {
+			m.upgradeLevelIndex--
+		}
+	case "down", "j", "right", "l":
+		if m.upgradeLevelIndex < len(m.upgradeLevels)-1 {
+			m.upgradeLevelIndex++
+		}
+	case "enter":
+		level, _ := strconv.Atoi(m.upgradeLevels[m.upgradeLevelIndex].Value)
+		m.upgradeLevels = nil
+		if validation, ok := m.selectedValidation(); ok {
+			m.resolveValidation(validation, database.ValidationApproved, level)
+		}
+	case "esc":
+		m.upgradeLevels = nil
+		m.message = ""
+	}
+	return m, nil
+}
+
+// selectedValidation returns the highlighted entry of the validation queue
+func (m Model) selectedValidation() (database.ValidationRecord, bool) {
+	item, ok := m.validationListUI.SelectedItem().(validationListItem)
+	if !ok {
+		return database.ValidationRecord{}, false
+	}
+	return item.validation, true
+}
+
+// resolveValidation approves or rejects a user, logs the decision and tells
+// the user by private mail. Approvals raise the user to level but never
+// lower them.
+func (m *Model) resolveValidation(validation database.ValidationRecord, status string, level int) {
+	sysop := strings.TrimSpace(m.config.Configuration.General.SysOpName)
+	if sysop == "" {
+		sysop = "SysOp"
+	}
+
+	newLevel := validation.Level
+	if level > validation.Level {
+		newLevel = level
+	} else {
+		level = 0
+	}
+
+	if err := m.db.ResolveValidation(validation.UserID, status, sysop, level); err != nil {
+		m.message = fmt.Sprintf("Error updating %s: %v", validation.Username, err)
+		m.messageTime = time.Now()
+		m.messageType = ErrorMessage
+		return
+	}
+
+	verb := "Approved"
+	if status == database.ValidationApproved {
+		logging.LogApplicationApproval(0, validation.Username, "", "new user", sysop)
+	} else {
+		verb = "Rejected"
+		logging.LogAdminAction(0, sysop, "", "REJECT_APPLICATION", fmt.Sprintf("rejected new user application for user %s", validation.Username))
+	}
+	m.message = fmt.Sprintf("%s %s at security level %d", verb, validation.Username, newLevel)
+	m.messageTime = time.Now()
+	m.messageType = SuccessMessage
+	if err := menu.SendValidationMail(m.db, m.config, validation.Username, status, newLevel); err != nil {
+		m.message += fmt.Sprintf(" (mail not sent: %v)", err)
+		m.messageType = WarningMessage
+	}
+
+	idx := m.validationListUI.Index()
+	if err := m.loadValidations(); err != nil {
+		m.message = fmt.Sprintf("Error reloading validations: %v", err)
+		m.messageTime = time.Now()
+		m.messageType = ErrorMessage
+		return
+	}
+	if count := len(m.validationListUI.Items()); idx >= count && count > 0 {
+		idx = count - 1
+	}
+	m.validationListUI.Select(idx)
+}
+
+// selectVotingChoice highlights the choice with the given ID
+func (m *Model) selectVotingChoice(id int) {
+	for idx, item := range m.choiceListUI.Items() {
+		if choiceItem, ok := item.(votingChoiceListItem); ok && choiceItem.choice.ID == id {
+			m.choiceListUI.Select(idx)
+			return
+		}
+	}
+}
+
+// Update this helper function
+func (m Model) returnToMenuModifyOrModal() NavigationMode {
+	// If we're editing a menu command, return to command edit mode
+	if m.editingMenuCommand != nil {
+		return MenuEditCommandMode
+	}
+	// If we have editingMenu set, we're in MenuModifyMode
+	if m.editingMenu != nil {
+		return MenuModifyMode
+	}
+	// Otherwise return to Level4ModalNavigation
+	return Level4ModalNavigation
+}
+
+func (m *Model) defaultMessageBasePath() string {
+	if m.config != nil {
+		if path := strings.TrimSpace(m.config.Configuration.Paths.MessageBase); path != "" {
+			return path
+		}
+	}
+
+	if m.db != nil {
+		if value, err := m.db.GetConfig("Configuration.Paths", "", "Message_Base"); err == nil {
+			if path := strings.TrimSpace(value); path != "" {
+				return path
+			}
+		}
+	}
+
+	return "messages"
+}
+
+func (m *Model) conferenceNameByID(id int) string {
+	if id <= 0 {
+		return ""
+	}
+
+	for _, conf := range m.conferenceList {
+		if conf.ID == id {
+			return conf.Name
+		}
+	}
+
+	if m.db != nil {
+		if conferences, err := m.db.GetAllConferences(); err == nil {
+			m.conferenceList = conferences
+			for _, conf := range conferences {
+				if conf.ID == id {
+					return conf.Name
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func (m *Model) beginConferenceEdit(conf *database.Conference, isNew bool) {
+	m.editingConference = conf
+	m.conferenceIsNew = isNew
+	m.modalSectionName = "Conference"
+	m.modalFieldIndex = 0
 
 	m.modalFields = []SubmenuItem{
 		{
@@ -3676,6 +4812,34 @@ func (m *Model) beginAreaEdit(area *database.MessageArea, isNew bool) {
 				HelpText: "Network tag for echomail areas",
 			},
 		},
+		{
+			ID:       "area-qwk-conference",
+			Label:    "QWK Conference",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "area-qwk-conference",
+				Label:     "QWK Conference",
+				ValueType: IntValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return area.QWKConference },
+					SetValue: func(v interface{}) error {
+						area.QWKConference = v.(int)
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					conf := v.(int)
+					if conf < 0 || conf > 65535 {
+						return fmt.Errorf("conference number must be between 0 and 65535")
+					}
+					if strings.EqualFold(area.AreaType, "qwknet") && conf == 0 {
+						return fmt.Errorf("conference number is required for QWKnet areas")
+					}
+					return nil
+				},
+				HelpText: "Hub conference number this area maps to (QWKnet areas)",
+			},
+		},
 		{
 			ID:       "area-real-names",
 			Label:    "Real Names",
@@ -3717,3 +4881,462 @@ func (m *Model) beginAreaEdit(area *database.MessageArea, isNew bool) {
 	m.navMode = Level4ModalNavigation
 	m.message = ""
 }
+
+func (m *Model) beginStepEdit(step *database.SequenceStep, isNew bool) {
+	m.editingStep = step
+	m.stepIsNew = isNew
+	m.modalSectionName = "Logon Step"
+	if step.Sequence == database.SequenceLogoff {
+		m.modalSectionName = "Logoff Step"
+	}
+	m.modalFieldIndex = 0
+
+	m.modalFields = []SubmenuItem{
+		{
+			ID:       "step-type",
+			Label:    "Step",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "step-type",
+				Label:     "Step",
+				ValueType: SelectValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return step.StepType },
+					SetValue: func(v interface{}) error {
+						step.StepType = strings.TrimSpace(v.(string))
+						return nil
+					},
+				},
+				SelectOptions: getSequenceStepOptions(),
+				HelpText:      "What this step shows or does",
+			},
+		},
+		{
+			ID:       "step-options",
+			Label:    "Options",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "step-options",
+				Label:     "Options",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return step.Options },
+					SetValue: func(v interface{}) error {
+						step.Options = strings.TrimSpace(v.(string))
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					value := strings.TrimSpace(v.(string))
+					switch step.StepType {
+					case database.StepDisplayFile:
+						if value == "" {
+							return fmt.Errorf("display steps need a file name")
+						}
+					case database.StepCommand:
+						if value == "" {
+							return fmt.Errorf("command steps need a command key")
+						}
+					}
+					return nil
+				},
+				HelpText: "File name for Display, \"KEY options\" for Command, question for Newscan",
+			},
+		},
+		{
+			ID:       "step-acs",
+			Label:    "ACS",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "step-acs",
+				Label:     "ACS",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return step.ACS },
+					SetValue: func(v interface{}) error {
+						step.ACS = strings.TrimSpace(v.(string))
+						return nil
+					},
+				},
+				HelpText: "Access needed for the step to run (blank for everyone)",
+			},
+		},
+		{
+			ID:       "step-once-per-day",
+			Label:    "Once Per Day",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "step-once-per-day",
+				Label:     "Once Per Day",
+				ValueType: BoolValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return step.OncePerDay },
+					SetValue: func(v interface{}) error {
+						step.OncePerDay = v.(bool)
+						return nil
+					},
+				},
+				HelpText: "Run only on the caller's first call of the day",
+			},
+		},
+		{
+			ID:       "step-enabled",
+			Label:    "Enabled",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "step-enabled",
+				Label:     "Enabled",
+				ValueType: BoolValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return step.Enabled },
+					SetValue: func(v interface{}) error {
+						step.Enabled = v.(bool)
+						return nil
+					},
+				},
+				HelpText: "Include this step in the sequence",
+			},
+		},
+	}
+
+	m.navMode = Level4ModalNavigation
+	m.message = ""
+}
+
+func (m *Model) beginTopicEdit(topic *database.VotingTopic, isNew bool) {
+	m.editingTopic = topic
+	m.topicIsNew = isNew
+	m.modalSectionName = "Voting Topic"
+	m.modalFieldIndex = 0
+
+	m.modalFields = []SubmenuItem{
+		{
+			ID:       "topic-title",
+			Label:    "Question",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "topic-title",
+				Label:     "Question",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return topic.Title },
+					SetValue: func(v interface{}) error {
+						value := strings.TrimSpace(v.(string))
+						if value == "" {
+							return fmt.Errorf("question cannot be empty")
+						}
+						topic.Title = value
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					if strings.TrimSpace(v.(string)) == "" {
+						return fmt.Errorf("question is required")
+					}
+					return nil
+				},
+				HelpText: "The question callers vote on",
+			},
+		},
+		{
+			ID:       "topic-description",
+			Label:    "Details",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "topic-description",
+				Label:     "Details",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return topic.Description },
+					SetValue: func(v interface{}) error {
+						topic.Description = strings.TrimSpace(v.(string))
+						return nil
+					},
+				},
+				HelpText: "Optional line shown under the question",
+			},
+		},
+		{
+			ID:       "topic-vote-acs",
+			Label:    "Vote ACS",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "topic-vote-acs",
+				Label:     "Vote ACS",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return topic.VoteACS },
+					SetValue: func(v interface{}) error {
+						topic.VoteACS = strings.TrimSpace(v.(string))
+						return nil
+					},
+				},
+				HelpText: "Access needed to vote (blank for everyone)",
+			},
+		},
+		{
+			ID:       "topic-add-acs",
+			Label:    "Add Choice ACS",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "topic-add-acs",
+				Label:     "Add Choice ACS",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return topic.AddACS },
+					SetValue: func(v interface{}) error {
+						topic.AddACS = strings.TrimSpace(v.(string))
+						return nil
+					},
+				},
+				HelpText: "Access needed for callers to add their own choice (blank for nobody)",
+			},
+		},
+		{
+			ID:       "topic-active",
+			Label:    "Open",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "topic-active",
+				Label:     "Open",
+				ValueType: BoolValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return topic.Active },
+					SetValue: func(v interface{}) error {
+						topic.Active = v.(bool)
+						return nil
+					},
+				},
+				HelpText: "Closed topics keep their results but take no votes",
+			},
+		},
+	}
+
+	m.navMode = Level4ModalNavigation
+	m.message = ""
+}
+
+func (m *Model) beginChoiceEdit(choice *database.VotingChoice, isNew bool) {
+	m.editingChoice = choice
+	m.choiceIsNew = isNew
+	m.modalSectionName = "Voting Choice"
+	m.modalFieldIndex = 0
+
+	m.modalFields = []SubmenuItem{
+		{
+			ID:       "choice-text",
+			Label:    "Choice",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "choice-text",
+				Label:     "Choice",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return choice.Text },
+					SetValue: func(v interface{}) error {
+						value := strings.TrimSpace(v.(string))
+						if value == "" {
+							return fmt.Errorf("choice cannot be empty")
+						}
+						choice.Text = value
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					if strings.TrimSpace(v.(string)) == "" {
+						return fmt.Errorf("choice is required")
+					}
+					return nil
+				},
+				HelpText: "Answer shown to callers",
+			},
+		},
+	}
+
+	m.navMode = Level4ModalNavigation
+	m.message = ""
+}
+
+func (m *Model) beginEventEdit(event *database.TimedEvent, isNew bool) {
+	m.editingEvent = event
+	m.eventIsNew = isNew
+	m.modalSectionName = "Timed Event"
+	m.modalFieldIndex = 0
+
+	m.modalFields = []SubmenuItem{
+		{
+			ID:       "event-name",
+			Label:    "Name",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "event-name",
+				Label:     "Name",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return event.Name },
+					SetValue: func(v interface{}) error {
+						value := strings.TrimSpace(v.(string))
+						if value == "" {
+							return fmt.Errorf("name cannot be empty")
+						}
+						event.Name = value
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					if strings.TrimSpace(v.(string)) == "" {
+						return fmt.Errorf("name is required")
+					}
+					return nil
+				},
+				HelpText: "Name shown in warnings and the event log",
+			},
+		},
+		{
+			ID:       "event-schedule",
+			Label:    "Schedule",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "event-schedule",
+				Label:     "Schedule",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return event.Schedule },
+					SetValue: func(v interface{}) error {
+						value := strings.TrimSpace(v.(string))
+						if _, err := events.ParseSchedule(value); err != nil {
+							return err
+						}
+						event.Schedule = value
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					_, err := events.ParseSchedule(v.(string))
+					return err
+				},
+				HelpText: "daily HH:MM, weekly sun,wed HH:MM, or cron (min hour day month weekday)",
+			},
+		},
+		{
+			ID:       "event-action",
+			Label:    "Action",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "event-action",
+				Label:     "Action",
+				ValueType: SelectValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return event.Action },
+					SetValue: func(v interface{}) error {
+						event.Action = strings.TrimSpace(v.(string))
+						return nil
+					},
+				},
+				SelectOptions: getEventActionOptions(),
+				HelpText:      "What the event does when it runs",
+			},
+		},
+		{
+			ID:       "event-options",
+			Label:    "Options",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "event-options",
+				Label:     "Options",
+				ValueType: StringValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return event.Options },
+					SetValue: func(v interface{}) error {
+						event.Options = strings.TrimSpace(v.(string))
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					value := strings.TrimSpace(v.(string))
+					switch event.Action {
+					case database.EventActionCommand:
+						if value == "" {
+							return fmt.Errorf("command events need a command line")
+						}
+					case database.EventActionMessage:
+						if parts := strings.SplitN(value, ";", 3); len(parts) != 3 {
+							return fmt.Errorf("message events need area;subject;text")
+						}
+					}
+					return nil
+				},
+				HelpText: "Command line, backup directory, or area;subject;text (~ for a new line)",
+			},
+		},
+		{
+			ID:       "event-lockout",
+			Label:    "Lockout Minutes",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "event-lockout",
+				Label:     "Lockout Minutes",
+				ValueType: IntValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return event.LockoutMinutes },
+					SetValue: func(v interface{}) error {
+						event.LockoutMinutes = v.(int)
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					if minutes := v.(int); minutes < 0 || minutes > 1440 {
+						return fmt.Errorf("lockout must be between 0 and 1440 minutes")
+					}
+					return nil
+				},
+				HelpText: "Refuse new callers this many minutes before and during the event (0 = never)",
+			},
+		},
+		{
+			ID:       "event-warn",
+			Label:    "Warn Minutes",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "event-warn",
+				Label:     "Warn Minutes",
+				ValueType: IntValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return event.WarnMinutes },
+					SetValue: func(v interface{}) error {
+						event.WarnMinutes = v.(int)
+						return nil
+					},
+				},
+				Validation: func(v interface{}) error {
+					if minutes := v.(int); minutes < 0 || minutes > 1440 {
+						return fmt.Errorf("warning must be between 0 and 1440 minutes")
+					}
+					return nil
+				},
+				HelpText: "Broadcast a warning to online callers this many minutes ahead (0 = none)",
+			},
+		},
+		{
+			ID:       "event-enabled",
+			Label:    "Enabled",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "event-enabled",
+				Label:     "Enabled",
+				ValueType: BoolValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return event.Enabled },
+					SetValue: func(v interface{}) error {
+						event.Enabled = v.(bool)
+						return nil
+					},
+				},
+				HelpText: "Run this event on its schedule",
+			},
+		},
+	}
+
+	m.navMode = Level4ModalNavigation
+	m.message = ""
+}
@@ -21,6 +21,15 @@ func sanitizeBracketInput(value, fallback string) string {
 	return string(runes)
 }
 
+func sanitizeInputModeValue(value string) string {
+	switch value {
+	case database.InputModeLine, database.InputModeLightbar:
+		return value
+	default:
+		return database.InputModeHotkey
+	}
+}
+
 func sanitizeDisplayModeValue(value string) string {
 	switch value {
 	case database.DisplayModeHeaderGenerated, database.DisplayModeThemeOnly:
@@ -121,6 +130,10 @@ func (m Model) View() string {
 	if m.navMode == UserManagementMode {
 		userManagementStr := m.renderUserManagement()
 		m.overlayStringCenteredWithClear(canvas, userManagementStr)
+
+		footer := m.renderFooter()
+		m.overlayString(canvas, footer, m.screenHeight-1, 0)
+
 		return m.canvasToString(canvas)
 	}
 
@@ -153,6 +166,69 @@ func (m Model) View() string {
 		return m.canvasToString(canvas)
 	}
 
+	// Layer 1.72: Timed Event Management
+	if m.navMode == EventManagementMode {
+		eventStr := m.renderEventManagement()
+		m.overlayStringCenteredWithClear(canvas, eventStr)
+
+		footer := m.renderFooter()
+		m.overlayString(canvas, footer, m.screenHeight-1, 0)
+
+		return m.canvasToString(canvas)
+	}
+
+	// Layer 1.73: Logon/Logoff Sequence Management
+	if m.navMode == SequenceManagementMode {
+		sequenceStr := m.renderSequenceManagement()
+		m.overlayStringCenteredWithClear(canvas, sequenceStr)
+
+		footer := m.renderFooter()
+		m.overlayString(canvas, footer, m.screenHeight-1, 0)
+
+		return m.canvasToString(canvas)
+	}
+
+	// Layer 1.74: Voting topics and their choices
+	if m.navMode == VotingManagementMode || m.navMode == VotingChoicesMode {
+		votingStr := m.renderVotingManagement()
+		if m.navMode == VotingChoicesMode {
+			votingStr = m.renderVotingChoices()
+		}
+		m.overlayStringCenteredWithClear(canvas, votingStr)
+
+		footer := m.renderFooter()
+		m.overlayString(canvas, footer, m.screenHeight-1, 0)
+
+		return m.canvasToString(canvas)
+	}
+
+	// Layer 1.745: New user validation queue
+	if m.navMode == ValidationQueueMode {
+		validationStr := m.renderValidationQueue()
+		m.overlayStringCenteredWithClear(canvas, validationStr)
+
+		if m.message != "" && time.Since(m.messageTime) < 3*time.Second {
+			msgStr := m.renderStatusMessage()
+			m.overlayString(canvas, msgStr, m.screenHeight-4, 2)
+		}
+
+		footer := m.renderFooter()
+		m.overlayString(canvas, footer, m.screenHeight-1, 0)
+
+		return m.canvasToString(canvas)
+	}
+
+	// Layer 1.75: Theme Art browser
+	if m.navMode == ThemeArtMode {
+		themeArtStr := m.renderThemeArt()
+		m.overlayStringCenteredWithClear(canvas, themeArtStr)
+
+		footer := m.renderFooter()
+		m.overlayString(canvas, footer, m.screenHeight-1, 0)
+
+		return m.canvasToString(canvas)
+	}
+
 	// Layer 1.7: Menu Management (full screen mode)
 	if m.navMode == MenuManagementMode {
 		menuManagementStr := m.renderMenuManagement()
@@ -1103,6 +1179,71 @@ func (m Model) renderSecurityLevelsManagement() string {
 	return securityLevelsBox
 }
 
+// renderThemeArt renders the theme art browser with the selected file's SAUCE details
+func (m Model) renderThemeArt() string {
+	const width = 76
+	if len(m.themeArtUI.Items()) == 0 {
+		emptyMsg := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextDim)).
+			Italic(true).
+			Render("No theme art found")
+
+		return lipgloss.NewStyle().
+			Background(lipgloss.Color(ColorBgMedium)).
+			Padding(2, 4).
+			Render(emptyMsg)
+	}
+
+	header := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorPrimary)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(width).
+		Align(lipgloss.Center).
+		Render(fmt.Sprintf("[ Theme Art (%d files) ]", len(m.themeArtUI.Items())))
+
+	separator := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorPrimary)).
+		Width(width).
+		Render(strings.Repeat("-", width))
+
+	lineStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Width(width)
+
+	columnHeaders := lineStyle.Bold(true).
+		Render(fmt.Sprintf(" %-20s %-22s %-18s %-7s %-3s", "File", "Title", "Author/Group", "Size", "iCE"))
+
+	listView := strings.TrimSpace(m.themeArtUI.View())
+
+	allLines := []string{header, separator, columnHeaders, separator, listView, separator}
+
+	// Details for the selected file
+	detailStyle := lineStyle.Foreground(lipgloss.Color(ColorTextNormal))
+	if item, ok := m.themeArtUI.SelectedItem().(themeArtListItem); ok {
+		switch {
+		case item.icyDraw:
+			allLines = append(allLines, detailStyle.Render(" Icy Draw document. Export it to .ans to use it on the board."))
+		case item.sauce != nil:
+			s := item.sauce
+			allLines = append(allLines, detailStyle.Render(truncateText(" "+s.Credits(), width)))
+			details := fmt.Sprintf(" Date: %s   Font: %s   File size: %d", s.Date, s.Font, s.FileSize)
+			allLines = append(allLines, detailStyle.Render(truncateText(details, width)))
+			for _, comment := range s.Comments {
+				allLines = append(allLines, detailStyle.Render(truncateText(" "+comment, width)))
+			}
+		default:
+			allLines = append(allLines, detailStyle.Render(" No SAUCE record"))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Render(strings.Join(allLines, "\n"))
+}
+
 // renderConferenceManagement renders the conference management interface
 func (m Model) renderConferenceManagement() string {
 	if len(m.conferenceListUI.Items()) == 0 {
@@ -1154,6 +1295,283 @@ func (m Model) renderConferenceManagement() string {
 	return box
 }
 
+// renderEventManagement renders the timed event management interface
+func (m Model) renderEventManagement() string {
+	if len(m.eventListUI.Items()) == 0 {
+		emptyMsg := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextDim)).
+			Italic(true).
+			Render("No timed events found (N to add one)")
+
+		emptyBox := lipgloss.NewStyle().
+			Background(lipgloss.Color(ColorBgMedium)).
+			Padding(2, 4).
+			Render(emptyMsg)
+
+		return emptyBox
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorPrimary)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Align(lipgloss.Center)
+
+	header := headerStyle.Render(fmt.Sprintf("[ Timed Events (%d events) ]", len(m.eventList)))
+
+	separatorStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorPrimary)).
+		Width(55)
+	separator := separatorStyle.Render(strings.Repeat("-", 55))
+
+	columnHeaders := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Render(fmt.Sprintf(" %-20s %-18s %-8s %-3s", "Name", "Schedule", "Action", "On"))
+
+	listView := strings.TrimSpace(m.eventListUI.View())
+
+	allLines := []string{header, separator, columnHeaders, separator, listView, separator}
+
+	combined := strings.Join(allLines, "\n")
+
+	box := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Render(combined)
+
+	return box
+}
+
+// renderSequenceManagement renders the logon/logoff sequence interface
+func (m Model) renderSequenceManagement() string {
+	title := "Logon Sequence"
+	if m.sequenceName == database.SequenceLogoff {
+		title = "Logoff Sequence"
+	}
+
+	if len(m.sequenceListUI.Items()) == 0 {
+		emptyMsg := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextDim)).
+			Italic(true).
+			Render(fmt.Sprintf("The %s has no steps (N to add one)", strings.ToLower(title)))
+
+		emptyBox := lipgloss.NewStyle().
+			Background(lipgloss.Color(ColorBgMedium)).
+			Padding(2, 4).
+			Render(emptyMsg)
+
+		return emptyBox
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorPrimary)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Align(lipgloss.Center)
+
+	header := headerStyle.Render(fmt.Sprintf("[ %s (%d steps) ]", title, len(m.sequenceSteps)))
+
+	separatorStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorPrimary)).
+		Width(55)
+	separator := separatorStyle.Render(strings.Repeat("-", 55))
+
+	columnHeaders := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Render(fmt.Sprintf(" %2s %-12s %-18s %-5s %-5s %-3s", "#", "Step", "Options", "ACS", "Daily", "On"))
+
+	listView := strings.TrimSpace(m.sequenceListUI.View())
+
+	allLines := []string{header, separator, columnHeaders, separator, listView, separator}
+
+	combined := strings.Join(allLines, "\n")
+
+	box := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Render(combined)
+
+	return box
+}
+
+// renderVotingManagement renders the voting topic list
+func (m Model) renderVotingManagement() string {
+	if len(m.votingListUI.Items()) == 0 {
+		emptyMsg := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextDim)).
+			Italic(true).
+			Render("No voting topics (N to add one)")
+
+		emptyBox := lipgloss.NewStyle().
+			Background(lipgloss.Color(ColorBgMedium)).
+			Padding(2, 4).
+			Render(emptyMsg)
+
+		return emptyBox
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorPrimary)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Align(lipgloss.Center)
+
+	header := headerStyle.Render(fmt.Sprintf("[ Voting Topics (%d) ]", len(m.votingTopics)))
+
+	separatorStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorPrimary)).
+		Width(55)
+	separator := separatorStyle.Render(strings.Repeat("-", 55))
+
+	columnHeaders := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Render(fmt.Sprintf(" %2s %-34s %-5s %-5s %-3s", "#", "Question", "Vote", "Add", "On"))
+
+	listView := strings.TrimSpace(m.votingListUI.View())
+
+	allLines := []string{header, separator, columnHeaders, separator, listView, separator}
+
+	combined := strings.Join(allLines, "\n")
+
+	box := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Render(combined)
+
+	return box
+}
+
+// renderVotingChoices renders the choices of the selected voting topic
+func (m Model) renderVotingChoices() string {
+	if len(m.choiceListUI.Items()) == 0 {
+		emptyMsg := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextDim)).
+			Italic(true).
+			Render(fmt.Sprintf("'%s' has no choices (N to add one)", m.votingTopic.Title))
+
+		emptyBox := lipgloss.NewStyle().
+			Background(lipgloss.Color(ColorBgMedium)).
+			Padding(2, 4).
+			Render(emptyMsg)
+
+		return emptyBox
+	}
+
+	total := 0
+	for _, choice := range m.votingChoices {
+		total += choice.Votes
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorPrimary)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Align(lipgloss.Center)
+
+	header := headerStyle.Render(fmt.Sprintf("[ %.36s (%d votes) ]", m.votingTopic.Title, total))
+
+	separatorStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorPrimary)).
+		Width(55)
+	separator := separatorStyle.Render(strings.Repeat("-", 55))
+
+	columnHeaders := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Render(fmt.Sprintf(" %2s %-36s %-8s %5s", "#", "Choice", "Added By", "Votes"))
+
+	listView := strings.TrimSpace(m.choiceListUI.View())
+
+	allLines := []string{header, separator, columnHeaders, separator, listView, separator}
+
+	combined := strings.Join(allLines, "\n")
+
+	box := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Render(combined)
+
+	return box
+}
+
+// renderValidationQueue renders the users waiting for validation
+func (m Model) renderValidationQueue() string {
+	if len(m.validationListUI.Items()) == 0 {
+		emptyMsg := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorTextDim)).
+			Italic(true).
+			Render("No users are waiting for validation")
+
+		emptyBox := lipgloss.NewStyle().
+			Background(lipgloss.Color(ColorBgMedium)).
+			Padding(2, 4).
+			Render(emptyMsg)
+
+		return emptyBox
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorPrimary)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Align(lipgloss.Center)
+
+	header := headerStyle.Render(fmt.Sprintf("[ Validation Queue (%d waiting) ]", len(m.validations)))
+
+	separatorStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorPrimary)).
+		Width(55)
+	separator := separatorStyle.Render(strings.Repeat("-", 55))
+
+	columnHeaders := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Foreground(lipgloss.Color(ColorTextBright)).
+		Bold(true).
+		Width(55).
+		Render(fmt.Sprintf(" %-15s %-22s %5s %-8s", "Username", "Location", "Level", "Applied"))
+
+	listView := strings.TrimSpace(m.validationListUI.View())
+
+	allLines := []string{header, separator, columnHeaders, separator, listView, separator}
+
+	// While upgrading, show the level being chosen under the list
+	if m.upgradeLevels != nil {
+		option := m.upgradeLevels[m.upgradeLevelIndex]
+		upgradeLine := lipgloss.NewStyle().
+			Background(lipgloss.Color(ColorBgMedium)).
+			Foreground(lipgloss.Color(ColorTextBright)).
+			Bold(true).
+			Width(55).
+			Render(fmt.Sprintf(" Upgrade to: < %s (%s) >", option.Value, option.Label))
+		allLines = append(allLines, upgradeLine, separator)
+	}
+
+	combined := strings.Join(allLines, "\n")
+
+	box := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorBgMedium)).
+		Render(combined)
+
+	return box
+}
+
 // renderAreaManagement renders the message area management interface
 func (m Model) renderAreaManagement() string {
 	if len(m.areaListUI.Items()) == 0 {
@@ -1653,7 +2071,7 @@ func (m *Model) setupMenuEditCommandModal() {
 		HelpText: "Command options/parameters",
 	}
 
-	if strings.EqualFold(m.editingMenuCommand.CmdKeys, "-^") {
+	if isMenuTargetCmdKey(m.editingMenuCommand.CmdKeys) {
 		menuOptions := m.getMenuSelectOptions()
 		if len(menuOptions) > 0 {
 			optionsItem.ValueType = SelectValue
@@ -2076,6 +2494,25 @@ func (m *Model) setupMenuEditDataModal() {
 				HelpText:      "Choose how this menu is rendered",
 			},
 		},
+		{
+			ID:       "menu-input-mode",
+			Label:    "Input Mode",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "menu-input-mode",
+				Label:     "Input Mode",
+				ValueType: SelectValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return m.editingMenu.InputMode },
+					SetValue: func(v interface{}) error {
+						m.editingMenu.InputMode = sanitizeInputModeValue(v.(string))
+						return nil
+					},
+				},
+				SelectOptions: getMenuInputModeOptions(),
+				HelpText:      "How callers pick commands from this menu",
+			},
+		},
 		{
 			ID:       "menu-prompt",
 			Label:    "Prompt",
@@ -2220,6 +2657,50 @@ func (m *Model) setupMenuEditDataModal() {
 				HelpText: "Color for descriptions in generic display",
 			},
 		},
+		{
+			ID:       "menu-lightbar-fg-color",
+			Label:    "Lightbar Color",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "menu-lightbar-fg-color",
+				Label:     "Lightbar Color",
+				ValueType: IntValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return m.editingMenu.LightbarFgColor },
+					SetValue: func(v interface{}) error {
+						color := v.(int)
+						if color < 0 || color > 15 {
+							return fmt.Errorf("lightbar color must be 0-15")
+						}
+						m.editingMenu.LightbarFgColor = color
+						return nil
+					},
+				},
+				HelpText: "Text color of the highlighted lightbar item (0-15)",
+			},
+		},
+		{
+			ID:       "menu-lightbar-bg-color",
+			Label:    "Lightbar Background",
+			ItemType: EditableField,
+			EditableItem: &MenuItem{
+				ID:        "menu-lightbar-bg-color",
+				Label:     "Lightbar Background",
+				ValueType: IntValue,
+				Field: ConfigField{
+					GetValue: func() interface{} { return m.editingMenu.LightbarBgColor },
+					SetValue: func(v interface{}) error {
+						color := v.(int)
+						if color < 0 || color > 7 {
+							return fmt.Errorf("lightbar background must be 0-7")
+						}
+						m.editingMenu.LightbarBgColor = color
+						return nil
+					},
+				},
+				HelpText: "Background color of the highlighted lightbar item (0-7)",
+			},
+		},
 		{
 			ID:       "menu-clear-screen",
 			Label:    "Clear Screen",
@@ -2292,13 +2773,29 @@ func (m Model) renderFooter() string {
 	case SelectingValue:
 		footerText = "  Up/Down Navigate   PgUp/PgDn Jump   ENTER Select   ESC Cancel"
 	case UserManagementMode:
-		footerText = "  Up/Down Navigate   ENTER Edit   ESC Back"
+		footerText = "  Up/Down Navigate   ENTER Edit   V Validation Queue   ESC Back"
 	case SecurityLevelsMode:
 		footerText = "  Up/Down Navigate   ENTER Edit   ESC Back"
 	case ConferenceManagementMode:
 		footerText = "  Up/Down Navigate   ENTER Edit   N New   D Delete   ESC Back"
 	case AreaManagementMode:
 		footerText = "  Up/Down Navigate   ENTER Edit   N New   D Delete   ESC Back"
+	case EventManagementMode:
+		footerText = "  Up/Down Navigate   ENTER Edit   N New   D Delete   ESC Back"
+	case SequenceManagementMode:
+		footerText = "  Up/Down Navigate   ENTER Edit   N New   +/- Move   D Delete   ESC Back"
+	case VotingManagementMode:
+		footerText = "  Up/Down Navigate   ENTER Edit   N New   C Choices   D Delete   ESC Back"
+	case VotingChoicesMode:
+		footerText = "  Up/Down Navigate   ENTER Edit   N New   +/- Move   D Delete   ESC Back"
+	case ValidationQueueMode:
+		if m.upgradeLevels != nil {
+			footerText = "  Left/Right Choose Level   ENTER Upgrade   ESC Cancel"
+		} else {
+			footerText = "  Up/Down Navigate   A Approve   R Reject   U Upgrade   ESC Back"
+		}
+	case ThemeArtMode:
+		footerText = "  Up/Down Navigate   / Filter   ESC Back"
 	case MenuManagementMode:
 		footerText = "  Up/Down Navigate   ENTER/M Modify   I Insert   D Delete   ESC Back"
 	case MenuModifyMode:
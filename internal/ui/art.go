@@ -7,7 +7,6 @@ import (
 	"strings"
 	"sync"
 	"time"
-	"unicode/utf8"
 )
 
 // AnsiArtConfig holds configuration for ANSI art loading
@@ -64,16 +63,40 @@ func PrintAnsiTerminal(term InteractiveTerminal, artName string, delay, height i
 
 // PrintAnsiArt loads and displays an ANSI art file to the terminal
 func PrintAnsiArt(term InteractiveTerminal, filename string) error {
+	content, err := LoadAnsiArt(filename)
+	if err != nil {
+		return err
+	}
+	if err := term.Print(content); err != nil {
+		return fmt.Errorf("failed to print ANSI art: %w", err)
+	}
+	return nil
+}
+
+// LoadAnsiArt reads an ANSI art file from the theme directory with its SAUCE
+// record removed. Names without an extension try .ans and .asc.
+func LoadAnsiArt(filename string) (string, error) {
+	content, _, err := LoadArt(filename, false)
+	return content, err
+}
+
+// LoadArt reads an art file from the theme directory and returns its content
+// and SAUCE record. When ice is set, names without an extension try the .icy
+// variant first. Icy Draw documents are skipped since terminals can't show them.
+func LoadArt(filename string, ice bool) (string, *Sauce, error) {
 	themeDir := getThemeDirectory()
 
 	if themeDir == "" {
-		return fmt.Errorf("theme directory not configured")
+		return "", nil, fmt.Errorf("theme directory not configured")
 	}
 
 	// Try multiple file extensions if no extension provided
 	candidates := buildFileCandidates(filename)
+	if ice && filepath.Ext(strings.TrimSpace(filename)) == "" {
+		candidates = append([]string{strings.TrimSpace(filename) + IcyExtension}, candidates...)
+	}
 
-	var lastErr error
+	lastErr := os.ErrNotExist
 	for _, candidate := range candidates {
 		artPath := filepath.Join(themeDir, candidate)
 
@@ -83,19 +106,15 @@ func PrintAnsiArt(term InteractiveTerminal, filename string) error {
 			lastErr = err
 			continue // Try next candidate
 		}
-
-		// Remove SAUCE metadata
-		content := stripSauce(string(data))
-
-		// Print to terminal
-		if err := term.Print(content); err != nil {
-			return fmt.Errorf("failed to print ANSI art: %w", err)
+		if IsIcyDrawDocument(data) {
+			continue
 		}
 
-		return nil // Success!
+		sauce, content := ParseSauce(data)
+		return string(content), sauce, nil
 	}
 
-	return fmt.Errorf("failed to load ANSI art '%s' from '%s': %w", filename, themeDir, lastErr)
+	return "", nil, fmt.Errorf("failed to load ANSI art '%s' from '%s': %w", filename, themeDir, lastErr)
 }
 
 // buildFileCandidates returns a list of filenames to try
@@ -133,7 +152,8 @@ func LoadAnsiLines(artName string) ([]string, error) {
 	}
 
 	// Strip SAUCE and split into lines
-	content := stripSauce(string(data))
+	_, stripped := ParseSauce(data)
+	content := string(stripped)
 	rawLines := strings.Split(content, "\n")
 
 	lines := make([]string, 0, len(rawLines))
@@ -144,32 +164,8 @@ func LoadAnsiLines(artName string) ([]string, error) {
 	return lines, nil
 }
 
-// stripSauce removes SAUCE metadata from ANSI art content
-func stripSauce(content string) string {
-	// SAUCE records start with "SAUCE00" marker
-	if idx := strings.Index(content, "SAUCE00"); idx != -1 {
-		// Trim the last character before SAUCE (usually EOF marker)
-		return trimLastChar(content[:idx])
-	}
-
-	// Also check for COMNT blocks
-	if idx := strings.Index(content, "COMNT"); idx != -1 {
-		return trimLastChar(content[:idx])
-	}
-
-	return content
-}
-
-// StripSauce removes SAUCE metadata (exported helper)
+// StripSauce removes a trailing SAUCE record, its comments and the EOF marker
 func StripSauce(content string) string {
-	return stripSauce(content)
-}
-
-// trimLastChar removes the last character from a string
-func trimLastChar(s string) string {
-	if len(s) > 0 {
-		_, size := utf8.DecodeLastRuneInString(s)
-		return s[:len(s)-size]
-	}
-	return s
+	_, data := ParseSauce([]byte(content))
+	return string(data)
 }
@@ -142,6 +142,17 @@ var ansiColorTable = []string{
 	Ansi.WhiteHi,   // 15
 }
 
+var ansiBackgroundTable = []string{
+	Ansi.BgBlack,   // 16
+	Ansi.BgRed,     // 17
+	Ansi.BgGreen,   // 18
+	Ansi.BgYellow,  // 19
+	Ansi.BgBlue,    // 20
+	Ansi.BgMagenta, // 21
+	Ansi.BgCyan,    // 22
+	Ansi.BgWhite,   // 23
+}
+
 const (
 	Esc = "\u001B[" // ANSI escape sequence prefix
 	Osc = "\u001B]" // Operating System Command prefix
@@ -165,13 +176,23 @@ func ColorFromNumber(code int) string {
 	return Ansi.White
 }
 
+// BackgroundFromNumber maps a 0-7 color code to the corresponding ANSI
+// background sequence.
+func BackgroundFromNumber(code int) string {
+	if code >= 0 && code < len(ansiBackgroundTable) {
+		return ansiBackgroundTable[code]
+	}
+	return Ansi.BgBlack
+}
+
 // ParsePipeColorCodes converts Renegade-style pipe color codes (like |01, |02) to ANSI escape sequences.
-// Pipe codes are in the format |XX where XX is a two-digit number from 00-15.
+// Pipe codes are in the format |XX where XX is a two-digit number: 00-15 set the
+// foreground color and 16-23 set the background color.
 // Returns the string with pipe codes replaced by ANSI color sequences.
 func ParsePipeColorCodes(input string) string {
 	// Use regex to find pipe codes like |01, |02, etc.
 	re := regexp.MustCompile(`\|(\d{2})`)
-	isFirst := true
+	var colors PipeColors
 	return re.ReplaceAllStringFunc(input, func(match string) string {
 		// Extract the two-digit number
 		codeStr := match[1:] // Remove the |
@@ -185,16 +206,40 @@ func ParsePipeColorCodes(input string) string {
 			return match // Invalid number, return as-is
 		}
 
-		// Convert to ANSI color, adding reset before each color code except the first
-		color := ColorFromNumber(code)
-		if !isFirst {
-			color = Ansi.Reset + color
+		if seq, ok := colors.Code(code); ok {
+			return seq
 		}
-		isFirst = false
-		return color
+		return match
 	})
 }
 
+// PipeColors converts a run of pipe color codes to ANSI sequences. Every
+// foreground code after the first resets attributes, so the current
+// background is re-applied to keep it across foreground changes.
+type PipeColors struct {
+	started bool
+	bg      string
+}
+
+// Code returns the ANSI sequence for pipe color code 0-23, or false for any
+// other number
+func (p *PipeColors) Code(code int) (string, bool) {
+	switch {
+	case code >= 0 && code < len(ansiColorTable):
+		color := ColorFromNumber(code)
+		if p.started {
+			color = Ansi.Reset + p.bg + color
+		}
+		p.started = true
+		return color, true
+	case code >= 16 && code < 16+len(ansiBackgroundTable):
+		p.bg = ansiBackgroundTable[code-16]
+		p.started = true
+		return p.bg, true
+	}
+	return "", false
+}
+
 // StripPipeCodes removes pipe color codes from a string to get the visible text.
 func StripPipeCodes(s string) string {
 	re := regexp.MustCompile(`\|(\d{2})`)
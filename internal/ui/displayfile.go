@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Terminal emulations used to pick display file variants
+const (
+	EmulationANSI  = "ansi"
+	EmulationASCII = "ascii"
+	EmulationRIP   = "rip"
+	EmulationUTF8  = "utf8"
+)
+
+// displayExtensions are the extensions recognised as display file variants
+var displayExtensions = map[string]bool{".ans": true, ".asc": true, ".rip": true, ".utf8": true, ".txt": true, IcyExtension: true}
+
+var securityVariant = regexp.MustCompile(`(?i)^\.s(\d+)$`)
+
+// DisplayExtensions returns the file extensions to try for an emulation, in
+// order of preference
+func DisplayExtensions(emulation string) []string {
+	switch strings.ToLower(emulation) {
+	case EmulationRIP:
+		return []string{".rip", ".ans", ".asc", ".txt"}
+	case EmulationUTF8:
+		return []string{".utf8", ".ans", ".asc", ".txt"}
+	case EmulationASCII:
+		return []string{".asc", ".txt"}
+	default:
+		return []string{".ans", ".asc", ".txt"}
+	}
+}
+
+// DisplayFileQuery describes a display file lookup
+type DisplayFileQuery struct {
+	Name          string   // Base name, optionally with an extension
+	Dirs          []string // Searched in order; the first directory with a match wins
+	Extensions    []string // Emulation variants in order of preference
+	SecurityLevel int      // Selects NAME.Snn variants; negative disables them
+}
+
+// ResolveDisplayFile finds the file to show for a display name. Names are
+// matched without regard to case. Within each directory it tries, in order:
+//
+//   - the name itself, when it already has a display or .Snn extension
+//   - NAME.Snn, using the highest nn not above the user's security level
+//   - NAMEn with an emulation extension, picking one of 1..N at random
+//   - NAME with an emulation extension
+//
+// Icy Draw documents are never returned, so a theme may keep its .icy
+// sources next to the exported art.
+func ResolveDisplayFile(q DisplayFileQuery) (string, bool) {
+	name := strings.TrimSpace(q.Name)
+	if name == "" {
+		return "", false
+	}
+	if filepath.IsAbs(name) {
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			return name, true
+		}
+		return "", false
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	explicit := displayExtensions[ext] || securityVariant.MatchString(ext)
+
+	for _, dir := range q.Dirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		files := listFiles(filepath.Join(dir, filepath.Dir(name)))
+		if len(files) == 0 {
+			continue
+		}
+		base := strings.ToLower(filepath.Base(name))
+		found := func(candidate string) (string, bool) {
+			actual, ok := files[strings.ToLower(candidate)]
+			if !ok {
+				return "", false
+			}
+			path := filepath.Join(dir, filepath.Dir(name), actual)
+			if strings.EqualFold(filepath.Ext(actual), IcyExtension) && isIcyDrawFile(path) {
+				return "", false
+			}
+			return path, true
+		}
+
+		if explicit {
+			if path, ok := found(base); ok {
+				return path, true
+			}
+			continue
+		}
+
+		if q.SecurityLevel >= 0 {
+			best, bestName := -1, ""
+			for lower := range files {
+				if !strings.HasPrefix(lower, base) {
+					continue
+				}
+				if m := securityVariant.FindStringSubmatch(lower[len(base):]); m != nil {
+					if level, err := strconv.Atoi(m[1]); err == nil && level <= q.SecurityLevel && level > best {
+						best, bestName = level, lower
+					}
+				}
+			}
+			if best >= 0 {
+				return found(bestName)
+			}
+		}
+
+		var numbers []int
+		for n := 1; ; n++ {
+			if _, ok := firstVariant(found, base+strconv.Itoa(n), q.Extensions); !ok {
+				break
+			}
+			numbers = append(numbers, n)
+		}
+		if len(numbers) > 0 {
+			n := numbers[rand.Intn(len(numbers))]
+			return firstVariant(found, base+strconv.Itoa(n), q.Extensions)
+		}
+
+		if path, ok := firstVariant(found, base, q.Extensions); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func firstVariant(found func(string) (string, bool), base string, extensions []string) (string, bool) {
+	for _, ext := range extensions {
+		if path, ok := found(base + ext); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// listFiles maps lower-cased file names in dir to their real names
+func listFiles(dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	// Sorted so the same file wins every time when names differ only by case
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	files := make(map[string]string, len(names))
+	for _, name := range names {
+		files[strings.ToLower(name)] = name
+	}
+	return files
+}
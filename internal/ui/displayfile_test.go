@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestResolveDisplayFileOrder(t *testing.T) {
+	text, theme := t.TempDir(), t.TempDir()
+	writeFiles(t, theme, "MainMenu.ans", "MainMenu.asc", "MainMenu.hdr.ans", "news.asc", "BULLETIN.ANS", "BULLETIN.S20", "BULLETIN.S50")
+	writeFiles(t, text, "NEWS.ANS")
+
+	query := func(name, emulation string, level int) string {
+		path, _ := ResolveDisplayFile(DisplayFileQuery{
+			Name:          name,
+			Dirs:          []string{text, theme},
+			Extensions:    DisplayExtensions(emulation),
+			SecurityLevel: level,
+		})
+		return filepath.Base(path)
+	}
+
+	cases := []struct {
+		name, emulation string
+		level           int
+		want            string
+	}{
+		{"mainmenu", EmulationANSI, 10, "MainMenu.ans"},
+		{"mainmenu", EmulationASCII, 10, "MainMenu.asc"},
+		{"MainMenu.hdr", EmulationANSI, 10, "MainMenu.hdr.ans"},
+		{"news", EmulationASCII, 10, "news.asc"},
+		{"news", EmulationANSI, 10, "NEWS.ANS"},
+		{"bulletin", EmulationANSI, 10, "BULLETIN.ANS"},
+		{"bulletin", EmulationANSI, 20, "BULLETIN.S20"},
+		{"bulletin", EmulationANSI, 99, "BULLETIN.S50"},
+		{"bulletin", EmulationANSI, -1, "BULLETIN.ANS"},
+		{"bulletin.s20", EmulationANSI, 99, "BULLETIN.S20"},
+		{"missing", EmulationANSI, 10, "."},
+	}
+	for _, c := range cases {
+		if got := query(c.name, c.emulation, c.level); got != c.want {
+			t.Fatalf("%s (%s, level %d): got %s, want %s", c.name, c.emulation, c.level, got, c.want)
+		}
+	}
+}
+
+func TestResolveDisplayFileRandomVariants(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "LOGON.ANS", "LOGON1.ANS", "LOGON2.ANS", "LOGON3.ASC", "LOGON5.ANS")
+
+	seen := make(map[string]bool)
+	for i := 0; i < 200; i++ {
+		path, ok := ResolveDisplayFile(DisplayFileQuery{Name: "logon", Dirs: []string{dir}, Extensions: DisplayExtensions(EmulationANSI)})
+		if !ok {
+			t.Fatalf("expected a logon variant")
+		}
+		seen[filepath.Base(path)] = true
+	}
+	// Numbering stops at the first gap, so LOGON5 is never chosen
+	for _, want := range []string{"LOGON1.ANS", "LOGON2.ANS", "LOGON3.ASC"} {
+		if !seen[want] {
+			t.Fatalf("expected %s to be picked at least once, saw %v", want, seen)
+		}
+	}
+	for name := range seen {
+		if !strings.HasPrefix(name, "LOGON") || name == "LOGON5.ANS" || name == "LOGON.ANS" {
+			t.Fatalf("unexpected variant %s", name)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// SAUCE record layout (https://www.acid.org/info/sauce/sauce.htm)
+const (
+	sauceRecordSize  = 128
+	sauceCommentSize = 64
+	sauceEOF         = 0x1A
+)
+
+// SAUCE data types used by ANSI art
+const (
+	SauceDataCharacter  = 1
+	SauceDataBinaryText = 5
+	SauceDataXBin       = 6
+)
+
+// ICEColorsOn and ICEColorsOff switch a terminal between blinking text and
+// bright backgrounds (iCE colors). SyncTERM and most modern BBS clients
+// understand them; other terminals ignore them.
+const (
+	ICEColorsOn  = "\x1b[?33h"
+	ICEColorsOff = "\x1b[?33l"
+)
+
+// IcyExtension is the extension of the iCE color variant of a display file
+const IcyExtension = ".icy"
+
+var (
+	sauceID        = []byte("SAUCE00")
+	sauceCommentID = []byte("COMNT")
+	pngSignature   = []byte("\x89PNG\r\n\x1a\n")
+)
+
+// Sauce is the metadata record appended to ANSI art files
+type Sauce struct {
+	Title    string
+	Author   string
+	Group    string
+	Date     string // CCYYMMDD
+	FileSize uint32
+	DataType byte
+	FileType byte
+	TInfo1   uint16
+	TInfo2   uint16
+	TInfo3   uint16
+	TInfo4   uint16
+	Flags    byte
+	Font     string // TInfoS, e.g. "IBM VGA"
+	Comments []string
+}
+
+// ParseSauce splits a file into its content and SAUCE record. Only a valid
+// record at the very end of the file is recognised, so art that happens to
+// contain the word SAUCE is left intact. The returned content never includes
+// the comment block or the EOF marker. The record is nil when there is none.
+func ParseSauce(data []byte) (*Sauce, []byte) {
+	n := len(data)
+	if n < sauceRecordSize || !bytes.Equal(data[n-sauceRecordSize:n-sauceRecordSize+len(sauceID)], sauceID) {
+		return nil, trimEOF(data)
+	}
+	rec := data[n-sauceRecordSize:]
+	s := &Sauce{
+		Title:    sauceString(rec[7:42]),
+		Author:   sauceString(rec[42:62]),
+		Group:    sauceString(rec[62:82]),
+		Date:     sauceString(rec[82:90]),
+		FileSize: binary.LittleEndian.Uint32(rec[90:94]),
+		DataType: rec[94],
+		FileType: rec[95],
+		TInfo1:   binary.LittleEndian.Uint16(rec[96:98]),
+		TInfo2:   binary.LittleEndian.Uint16(rec[98:100]),
+		TInfo3:   binary.LittleEndian.Uint16(rec[100:102]),
+		TInfo4:   binary.LittleEndian.Uint16(rec[102:104]),
+		Flags:    rec[105],
+		Font:     sauceString(rec[106:128]),
+	}
+
+	end := n - sauceRecordSize
+	if count := int(rec[104]); count > 0 {
+		start := end - len(sauceCommentID) - count*sauceCommentSize
+		if start >= 0 && bytes.Equal(data[start:start+len(sauceCommentID)], sauceCommentID) {
+			lines := data[start+len(sauceCommentID) : end]
+			for i := 0; i < count; i++ {
+				s.Comments = append(s.Comments, sauceString(lines[i*sauceCommentSize:(i+1)*sauceCommentSize]))
+			}
+			end = start
+		}
+	}
+	return s, trimEOF(data[:end])
+}
+
+// ReadSauceFile reads the SAUCE record of a file, returning nil when it has none
+func ReadSauceFile(path string) (*Sauce, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sauce, _ := ParseSauce(data)
+	return sauce, nil
+}
+
+// Width returns the art width in columns, or 0 when the record doesn't say
+func (s *Sauce) Width() int {
+	if s == nil {
+		return 0
+	}
+	switch s.DataType {
+	case SauceDataCharacter, SauceDataXBin:
+		return int(s.TInfo1)
+	case SauceDataBinaryText:
+		return int(s.FileType) * 2
+	}
+	return 0
+}
+
+// Height returns the art height in lines, or 0 when the record doesn't say
+func (s *Sauce) Height() int {
+	if s == nil {
+		return 0
+	}
+	switch s.DataType {
+	case SauceDataCharacter, SauceDataXBin:
+		return int(s.TInfo2)
+	}
+	return 0
+}
+
+// ICEColors reports whether the art uses bright backgrounds instead of blink
+func (s *Sauce) ICEColors() bool {
+	if s == nil {
+		return false
+	}
+	switch s.DataType {
+	case SauceDataCharacter, SauceDataBinaryText:
+		return s.Flags&0x01 != 0
+	}
+	return false
+}
+
+// Credits returns a one-line "title by author/group" summary
+func (s *Sauce) Credits() string {
+	if s == nil {
+		return ""
+	}
+	credits := s.Title
+	by := s.Author
+	if s.Group != "" {
+		if by != "" {
+			by += "/"
+		}
+		by += s.Group
+	}
+	if by != "" {
+		if credits != "" {
+			credits += " by "
+		}
+		credits += by
+	}
+	return credits
+}
+
+// WithICEColors wraps art that needs bright backgrounds in the iCE color
+// switch when the terminal supports it
+func WithICEColors(content string, sauce *Sauce, supported bool) string {
+	if !supported || !sauce.ICEColors() {
+		return content
+	}
+	return ICEColorsOn + content + ICEColorsOff
+}
+
+// IsIcyDrawDocument reports whether data is an Icy Draw native document (a
+// PNG preview carrying the drawing in ICED chunks) rather than ANSI text
+func IsIcyDrawDocument(data []byte) bool {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return false
+	}
+	// The ICED header is the first chunk after IHDR
+	header := data[len(pngSignature):min(len(data), 128)]
+	return bytes.Contains(header, []byte("ICED"))
+}
+
+// isIcyDrawFile is IsIcyDrawDocument for a file on disk
+func isIcyDrawFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 128)
+	n, _ := f.Read(head)
+	return IsIcyDrawDocument(head[:n])
+}
+
+func trimEOF(data []byte) []byte {
+	return bytes.TrimRight(data, "\x1a")
+}
+
+// sauceString decodes a CP437 SAUCE field, dropping NUL and space padding
+func sauceString(field []byte) string {
+	field = bytes.TrimRight(field, "\x00 ")
+	decoded, err := charmap.CodePage437.NewDecoder().Bytes(field)
+	if err != nil {
+		return strings.TrimSpace(string(field))
+	}
+	return strings.TrimSpace(string(decoded))
+}
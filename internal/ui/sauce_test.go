@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildSauce appends a comment block and SAUCE record to art
+func buildSauce(art string, flags byte, comments ...string) []byte {
+	field := func(s string, n int) []byte {
+		b := bytes.Repeat([]byte(" "), n)
+		copy(b, s)
+		return b
+	}
+	var buf bytes.Buffer
+	buf.WriteString(art)
+	buf.WriteByte(sauceEOF)
+	if len(comments) > 0 {
+		buf.WriteString("COMNT")
+		for _, c := range comments {
+			buf.Write(field(c, sauceCommentSize))
+		}
+	}
+	buf.WriteString("SAUCE00")
+	buf.Write(field("Main Menu", 35))
+	buf.Write(field("Artist", 20))
+	buf.Write(field("Group", 20))
+	buf.WriteString("20251017")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(art)))
+	buf.WriteByte(SauceDataCharacter)
+	buf.WriteByte(1)
+	binary.Write(&buf, binary.LittleEndian, []uint16{80, 25, 0, 0})
+	buf.WriteByte(byte(len(comments)))
+	buf.WriteByte(flags)
+	font := make([]byte, 22)
+	copy(font, "IBM VGA")
+	buf.Write(font)
+	return buf.Bytes()
+}
+
+func TestParseSauce(t *testing.T) {
+	art := "\x1b[1mSAUCE00 and COMNT are just words here\x1b[0m"
+	sauce, content := ParseSauce(buildSauce(art, 0x01, "Drawn for the BBS", "Greets"))
+	if sauce == nil {
+		t.Fatalf("expected a SAUCE record")
+	}
+	if string(content) != art {
+		t.Fatalf("content = %q, want %q", content, art)
+	}
+	if sauce.Title != "Main Menu" || sauce.Author != "Artist" || sauce.Group != "Group" || sauce.Date != "20251017" {
+		t.Fatalf("unexpected text fields: %+v", sauce)
+	}
+	if sauce.Width() != 80 || sauce.Height() != 25 || sauce.Font != "IBM VGA" || !sauce.ICEColors() {
+		t.Fatalf("unexpected layout fields: %+v", sauce)
+	}
+	if len(sauce.Comments) != 2 || sauce.Comments[0] != "Drawn for the BBS" || sauce.Comments[1] != "Greets" {
+		t.Fatalf("unexpected comments: %q", sauce.Comments)
+	}
+	if got := sauce.Credits(); got != "Main Menu by Artist/Group" {
+		t.Fatalf("Credits() = %q", got)
+	}
+}
+
+func TestParseSauceWithoutRecord(t *testing.T) {
+	art := "plain SAUCE00 text with COMNT inside"
+	for _, data := range []string{art, art + "\x1a"} {
+		sauce, content := ParseSauce([]byte(data))
+		if sauce != nil || string(content) != art {
+			t.Fatalf("ParseSauce(%q) = %v, %q", data, sauce, content)
+		}
+	}
+	if StripSauce(art) != art {
+		t.Fatalf("StripSauce truncated art without a record")
+	}
+}
+
+func TestWithICEColors(t *testing.T) {
+	ice, _ := ParseSauce(buildSauce("x", 0x01))
+	plain, _ := ParseSauce(buildSauce("x", 0))
+	if got := WithICEColors("x", ice, true); got != ICEColorsOn+"x"+ICEColorsOff {
+		t.Fatalf("got %q", got)
+	}
+	if WithICEColors("x", ice, false) != "x" || WithICEColors("x", plain, true) != "x" || WithICEColors("x", nil, true) != "x" {
+		t.Fatalf("iCE switch sent when it shouldn't be")
+	}
+}
+
+func TestResolveDisplayFileSkipsIcyDrawDocuments(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "welcome.ans", "welcome.icy", "login.ans")
+	icy := append(append([]byte{}, pngSignature...), []byte("\x00\x00\x00\x0dIHDR0000000000000\x00\x00\x00\x13ICED")...)
+	if err := os.WriteFile(filepath.Join(dir, "login.icy"), icy, 0644); err != nil {
+		t.Fatalf("failed to write login.icy: %v", err)
+	}
+
+	extensions := append([]string{IcyExtension}, DisplayExtensions(EmulationANSI)...)
+	for name, want := range map[string]string{"welcome": "welcome.icy", "login": "login.ans"} {
+		path, _ := ResolveDisplayFile(DisplayFileQuery{Name: name, Dirs: []string{dir}, Extensions: extensions})
+		if filepath.Base(path) != want {
+			t.Fatalf("%s resolved to %s, want %s", name, path, want)
+		}
+	}
+}